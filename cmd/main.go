@@ -1,22 +1,42 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"go-twitter/internal/config"
+	auditHandler "go-twitter/internal/handler/audit"
 	commentHandler "go-twitter/internal/handler/comment"
+	exportHandler "go-twitter/internal/handler/export"
+	importHandler "go-twitter/internal/handler/importer"
 	likeHandler "go-twitter/internal/handler/like"
+	mediaHandler "go-twitter/internal/handler/media"
+	oauthHandler "go-twitter/internal/handler/oauth"
 	postHandler "go-twitter/internal/handler/post"
 	userHandler "go-twitter/internal/handler/user"
 	"go-twitter/internal/middleware"
+	auditRepo "go-twitter/internal/repository/audit"
 	commentRepo "go-twitter/internal/repository/comment"
+	exportRepo "go-twitter/internal/repository/export"
+	importRepo "go-twitter/internal/repository/importer"
 	likeRepo "go-twitter/internal/repository/like"
+	mediaRepo "go-twitter/internal/repository/media"
+	oauthRepo "go-twitter/internal/repository/oauth"
 	postRepo "go-twitter/internal/repository/post"
 	userRepo "go-twitter/internal/repository/user"
+	auditService "go-twitter/internal/service/audit"
 	commentService "go-twitter/internal/service/comment"
+	exportService "go-twitter/internal/service/export"
+	importService "go-twitter/internal/service/importer"
 	likeService "go-twitter/internal/service/like"
+	mediaService "go-twitter/internal/service/media"
+	oauthService "go-twitter/internal/service/oauth"
 	postService "go-twitter/internal/service/post"
 	"go-twitter/internal/service/user"
+	"go-twitter/pkg/blobstore"
 	"go-twitter/pkg/internalsql"
+	"go-twitter/pkg/mailer"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -40,32 +60,196 @@ func main() {
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
-	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.SecreetJwt)
-
 	// Initialize repositories
 	userRepository := userRepo.NewRepository(db)
 	postRepository := postRepo.NewRepository(db)
 	commentRepository := commentRepo.NewRepository(db)
 	likeRepository := likeRepo.NewRepository(db)
+	auditRepository := auditRepo.NewRepository(db)
+	oauthRepository := oauthRepo.NewRepository(db)
+	exportRepository := exportRepo.NewRepository(db)
+	importRepository := importRepo.NewRepository(db)
+	mediaRepository := mediaRepo.NewRepository(db)
+
+	// Initialize mailer, logging mail to stdout when no SMTP relay is set
+	var mail mailer.Mailer = mailer.NewLogMailer(os.Stdout)
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+
+	// Initialize blob store for archives and media
+	var blobStore blobstore.Store = blobstore.NewFileStore(cfg.BlobStoreDir)
+	if cfg.BlobStoreBackend == config.BlobStoreS3 {
+		blobStore = blobstore.NewS3Store(blobstore.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		})
+	}
+
+	// Initialize middleware
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTOptions(), userRepository, oauthRepository)
 
 	// Initialize services
-	userService := user.NewService(cfg, userRepository)
+	userService := user.NewService(cfg, userRepository, mail)
 	postSvc := postService.NewService(cfg, postRepository, db)
-	commentSvc := commentService.NewService(commentRepository, userRepository)
+	commentSvc := commentService.NewService(cfg, commentRepository, userRepository)
 	likeSvc := likeService.NewService(likeRepository)
+	auditSvc := auditService.NewService(auditRepository)
+	oauthSvc := oauthService.NewService(cfg, oauthRepository, userRepository)
+	exportSvc := exportService.NewService(cfg, exportRepository, userRepository, blobStore)
+	importSvc := importService.NewService(cfg, importRepository, userRepository, blobStore)
+	mediaSvc := mediaService.NewService(cfg, mediaRepository, blobStore)
+
+	// Purge deactivated accounts once their grace period is over
+	_, purgeInterval := cfg.AccountDeletion()
+	go func() {
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			purged, err := userService.PurgeDeactivatedAccounts(context.Background())
+			if err != nil {
+				fmt.Printf("Account purge failed: %v\n", err)
+			}
+			if purged > 0 {
+				fmt.Printf("Purged %d deactivated accounts\n", purged)
+			}
+		}
+	}()
+
+	// Build queued data exports and delete expired archives
+	_, _, exportPollInterval := cfg.DataExport()
+	go func() {
+		ticker := time.NewTicker(exportPollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			built, err := exportSvc.ProcessDataExports(context.Background())
+			if err != nil {
+				fmt.Printf("Data export job failed: %v\n", err)
+			}
+			if built > 0 {
+				fmt.Printf("Built %d data exports\n", built)
+			}
+		}
+	}()
+
+	// Run queued archive imports
+	_, importPollInterval := cfg.Import()
+	go func() {
+		ticker := time.NewTicker(importPollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			completed, err := importSvc.ProcessImportJobs(context.Background())
+			if err != nil {
+				fmt.Printf("Import job failed: %v\n", err)
+			}
+			if completed > 0 {
+				fmt.Printf("Completed %d imports\n", completed)
+			}
+		}
+	}()
+
+	// Delete uploads that were never attached to a post
+	_, _, _, mediaGCInterval := cfg.Media()
+	go func() {
+		ticker := time.NewTicker(mediaGCInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			collected, err := mediaSvc.CollectOrphanedMedia(context.Background())
+			if err != nil {
+				fmt.Printf("Media cleanup failed: %v\n", err)
+			}
+			if collected > 0 {
+				fmt.Printf("Deleted %d orphaned uploads\n", collected)
+			}
+		}
+	}()
+
+	// Process uploaded images in the background, and queue again those the
+	// workers never picked up
+	mediaSvc.StartProcessing(context.Background(), func(err error) {
+		fmt.Printf("Media processing failed: %v\n", err)
+	})
+	_, _, mediaProcessingPollInterval := cfg.MediaProcessing()
+	go func() {
+		ticker := time.NewTicker(mediaProcessingPollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := mediaSvc.QueuePendingMedia(context.Background()); err != nil {
+				fmt.Printf("Media processing queue failed: %v\n", err)
+			}
+		}
+	}()
+
+	// Freeze the results of polls once they close
+	go func() {
+		ticker := time.NewTicker(cfg.PollFinalization())
+		defer ticker.Stop()
+		for range ticker.C {
+			finalized, err := postSvc.FinalizeExpiredPolls(context.Background())
+			if err != nil {
+				fmt.Printf("Poll finalization failed: %v\n", err)
+			}
+			if finalized > 0 {
+				fmt.Printf("Finalized %d polls\n", finalized)
+			}
+		}
+	}()
+
+	// Publish scheduled posts once they are due
+	go func() {
+		ticker := time.NewTicker(cfg.ScheduledPublishing())
+		defer ticker.Stop()
+		for range ticker.C {
+			published, err := postSvc.PublishScheduledPosts(context.Background())
+			if err != nil {
+				fmt.Printf("Scheduled post publishing failed: %v\n", err)
+			}
+			if published > 0 {
+				fmt.Printf("Published %d scheduled posts\n", published)
+			}
+		}
+	}()
+
+	// Hard-delete posts and comments that were in the trash too long
+	_, trashPurgeInterval := cfg.Trash()
+	go func() {
+		ticker := time.NewTicker(trashPurgeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			purged, err := postSvc.PurgeTrash(context.Background())
+			if err != nil {
+				fmt.Printf("Trash purge failed: %v\n", err)
+			}
+			if purged > 0 {
+				fmt.Printf("Purged %d deleted posts and comments\n", purged)
+			}
+		}
+	}()
 
 	// Initialize handlers
-	userHandlerInstance := userHandler.NewHandler(r, validate, userService)
+	userHandlerInstance := userHandler.NewHandler(r, validate, userService, postSvc, authMiddleware)
 	postHandlerInstance := postHandler.NewHandler(r, validate, postSvc, authMiddleware)
 	commentHandlerInstance := commentHandler.NewHandler(r, validate, commentSvc, authMiddleware)
 	likeHandlerInstance := likeHandler.NewHandler(r, likeSvc, authMiddleware)
+	auditHandlerInstance := auditHandler.NewHandler(r, auditSvc, authMiddleware)
+	oauthHandlerInstance := oauthHandler.NewHandler(r, validate, oauthSvc, authMiddleware)
+	exportHandlerInstance := exportHandler.NewHandler(r, exportSvc, authMiddleware)
+	importHandlerInstance := importHandler.NewHandler(r, cfg, importSvc, authMiddleware)
+	mediaHandlerInstance := mediaHandler.NewHandler(r, cfg, validate, mediaSvc, authMiddleware)
 
 	// Register routes
 	userHandlerInstance.RouteList()
 	postHandlerInstance.RouteList()
 	commentHandlerInstance.RouteList()
 	likeHandlerInstance.RouteList()
+	auditHandlerInstance.RouteList()
+	oauthHandlerInstance.RouteList()
+	exportHandlerInstance.RouteList()
+	importHandlerInstance.RouteList()
+	mediaHandlerInstance.RouteList()
 
 	server := fmt.Sprintf("127.0.0.1:%s", cfg.Port)
 	fmt.Printf("Server starting on %s\n", server)
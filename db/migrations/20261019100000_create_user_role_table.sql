@@ -0,0 +1,12 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS user_roles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_user_roles FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT uq_user_roles_user_id_role UNIQUE (user_id, role)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS user_roles;
@@ -0,0 +1,16 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NOT NULL,
+    target_user_id INT NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_actor_id_audit_logs FOREIGN KEY (actor_id) REFERENCES users(id),
+    CONSTRAINT fk_target_user_id_audit_logs FOREIGN KEY (target_user_id) REFERENCES users(id),
+    INDEX idx_audit_logs_created_at (created_at)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS audit_logs;
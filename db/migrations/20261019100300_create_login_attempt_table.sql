@@ -0,0 +1,17 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL DEFAULT NULL,
+    email VARCHAR(100) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_login_attempts FOREIGN KEY (user_id) REFERENCES users(id),
+    INDEX idx_login_attempts_email_created_at (email, created_at),
+    INDEX idx_login_attempts_ip_address_created_at (ip_address, created_at),
+    INDEX idx_login_attempts_user_id_created_at (user_id, created_at)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS login_attempts;
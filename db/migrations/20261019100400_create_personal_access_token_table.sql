@@ -0,0 +1,18 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_personal_access_tokens FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_personal_access_tokens_token_hash (token_hash),
+    INDEX idx_personal_access_tokens_user_id (user_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS personal_access_tokens;
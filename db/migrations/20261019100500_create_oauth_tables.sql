@@ -0,0 +1,54 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    client_id VARCHAR(64) NOT NULL,
+    client_secret_hash CHAR(64) NULL DEFAULT NULL,
+    name VARCHAR(100) NOT NULL,
+    redirect_uris TEXT NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    owner_user_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_owner_user_id_oauth_clients FOREIGN KEY (owner_user_id) REFERENCES users(id),
+    UNIQUE KEY uq_oauth_clients_client_id (client_id)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code_hash CHAR(64) NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    redirect_uri VARCHAR(2048) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_oauth_authorization_codes FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_oauth_authorization_codes_code_hash (code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    user_id INT NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_oauth_refresh_tokens FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_oauth_refresh_tokens_token_hash (token_hash)
+);
+
+CREATE TABLE IF NOT EXISTS oauth_revoked_access_tokens (
+    jti CHAR(32) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_oauth_revoked_access_tokens_expires_at (expires_at)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS oauth_revoked_access_tokens;
+DROP TABLE IF EXISTS oauth_refresh_tokens;
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_clients;
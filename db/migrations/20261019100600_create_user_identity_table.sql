@@ -0,0 +1,26 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS user_identities (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_user_identities FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_user_identities_provider_subject (provider, subject),
+    INDEX idx_user_identities_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    state_hash CHAR(64) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_oidc_login_states_state_hash (state_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
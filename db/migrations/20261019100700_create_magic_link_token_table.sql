@@ -0,0 +1,17 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL,
+    email VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    fingerprint_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_magic_link_tokens FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_magic_link_tokens_token_hash (token_hash),
+    INDEX idx_magic_link_tokens_email_created_at (email, created_at)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS magic_link_tokens;
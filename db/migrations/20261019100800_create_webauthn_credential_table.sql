@@ -0,0 +1,29 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    credential_id VARBINARY(1023) NOT NULL,
+    public_key BLOB NOT NULL,
+    sign_count INT UNSIGNED NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_webauthn_credentials FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_webauthn_credentials_credential_id (credential_id),
+    INDEX idx_webauthn_credentials_user_id (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS webauthn_challenges (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    challenge_hash CHAR(64) NOT NULL,
+    ceremony VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_webauthn_challenges FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_webauthn_challenges_challenge_hash (challenge_hash)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS webauthn_challenges;
+DROP TABLE IF EXISTS webauthn_credentials;
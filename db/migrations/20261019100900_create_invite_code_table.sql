@@ -0,0 +1,32 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS invite_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    created_by INT NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    code_prefix VARCHAR(10) NOT NULL,
+    max_uses INT NOT NULL DEFAULT 1,
+    uses INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_created_by_invite_codes FOREIGN KEY (created_by) REFERENCES users(id),
+    UNIQUE KEY uq_invite_codes_code_hash (code_hash),
+    INDEX idx_invite_codes_created_by (created_by)
+);
+
+CREATE TABLE IF NOT EXISTS invite_redemptions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    invite_code_id INT NOT NULL,
+    inviter_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_invite_code_id_invite_redemptions FOREIGN KEY (invite_code_id) REFERENCES invite_codes(id),
+    CONSTRAINT fk_inviter_id_invite_redemptions FOREIGN KEY (inviter_id) REFERENCES users(id),
+    CONSTRAINT fk_user_id_invite_redemptions FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_invite_redemptions_user_id (user_id),
+    INDEX idx_invite_redemptions_inviter_id (inviter_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS invite_redemptions;
+DROP TABLE IF EXISTS invite_codes;
@@ -0,0 +1,11 @@
+-- migrate:up
+ALTER TABLE users
+    ADD COLUMN deactivated_at TIMESTAMP NULL,
+    ADD COLUMN purge_after TIMESTAMP NULL,
+    ADD INDEX idx_users_purge_after (purge_after);
+
+-- migrate:down
+ALTER TABLE users
+    DROP INDEX idx_users_purge_after,
+    DROP COLUMN purge_after,
+    DROP COLUMN deactivated_at;
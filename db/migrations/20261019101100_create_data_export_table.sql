@@ -0,0 +1,18 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS data_exports (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    blob_key VARCHAR(255) NULL DEFAULT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NULL DEFAULT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_data_exports FOREIGN KEY (user_id) REFERENCES users(id),
+    INDEX idx_data_exports_user_id_created_at (user_id, created_at),
+    INDEX idx_data_exports_status_created_at (status, created_at)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS data_exports;
@@ -0,0 +1,48 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    blob_key VARCHAR(255) NULL DEFAULT NULL,
+    total_items INT NOT NULL DEFAULT 0,
+    processed_items INT NOT NULL DEFAULT 0,
+    imported_items INT NOT NULL DEFAULT 0,
+    skipped_items INT NOT NULL DEFAULT 0,
+    failed_items INT NOT NULL DEFAULT 0,
+    error VARCHAR(255) NULL DEFAULT NULL,
+    started_at TIMESTAMP NULL DEFAULT NULL,
+    completed_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_import_jobs FOREIGN KEY (user_id) REFERENCES users(id),
+    INDEX idx_import_jobs_user_id_created_at (user_id, created_at),
+    INDEX idx_import_jobs_status_created_at (status, created_at)
+);
+
+CREATE TABLE IF NOT EXISTS import_job_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    job_id INT NOT NULL,
+    source_id VARCHAR(64) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_job_id_import_job_items FOREIGN KEY (job_id) REFERENCES import_jobs(id),
+    INDEX idx_import_job_items_job_id (job_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS imported_posts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    source_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_imported_posts FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_post_id_imported_posts FOREIGN KEY (post_id) REFERENCES posts(id),
+    UNIQUE KEY uq_imported_posts_source (user_id, source, source_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS imported_posts;
+DROP TABLE IF EXISTS import_job_items;
+DROP TABLE IF EXISTS import_jobs;
@@ -0,0 +1,26 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS media (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    alt_text VARCHAR(1000) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_media FOREIGN KEY (user_id) REFERENCES users(id),
+    INDEX idx_media_created_at (created_at)
+);
+
+CREATE TABLE IF NOT EXISTS post_media (
+    post_id INT NOT NULL,
+    media_id INT NOT NULL,
+    position TINYINT NOT NULL,
+    PRIMARY KEY (post_id, position),
+    CONSTRAINT fk_post_id_post_media FOREIGN KEY (post_id) REFERENCES posts(id),
+    CONSTRAINT fk_media_id_post_media FOREIGN KEY (media_id) REFERENCES media(id),
+    UNIQUE KEY uq_post_media_media_id (media_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS post_media;
+DROP TABLE IF EXISTS media;
@@ -0,0 +1,33 @@
+-- migrate:up
+ALTER TABLE media
+    ADD COLUMN processing_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN processing_started_at TIMESTAMP NULL,
+    ADD COLUMN width INT NOT NULL DEFAULT 0,
+    ADD COLUMN height INT NOT NULL DEFAULT 0,
+    ADD COLUMN blurhash VARCHAR(100) NOT NULL DEFAULT '',
+    ADD INDEX idx_media_processing_status (processing_status);
+
+-- videos are served as uploaded
+UPDATE media SET processing_status = 'ready' WHERE content_type LIKE 'video/%';
+
+CREATE TABLE IF NOT EXISTS media_variants (
+    media_id INT NOT NULL,
+    name VARCHAR(20) NOT NULL,
+    blob_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    PRIMARY KEY (media_id, name),
+    CONSTRAINT fk_media_id_media_variants FOREIGN KEY (media_id) REFERENCES media(id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS media_variants;
+ALTER TABLE media
+    DROP INDEX idx_media_processing_status,
+    DROP COLUMN blurhash,
+    DROP COLUMN height,
+    DROP COLUMN width,
+    DROP COLUMN processing_started_at,
+    DROP COLUMN processing_status;
@@ -0,0 +1,47 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS polls (
+    post_id INT PRIMARY KEY,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMP NOT NULL,
+    finalized_at TIMESTAMP NULL,
+    total_voters INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_post_id_polls FOREIGN KEY (post_id) REFERENCES posts(id),
+    INDEX idx_polls_finalized_at_closes_at (finalized_at, closes_at)
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id INT NOT NULL,
+    position TINYINT NOT NULL,
+    text VARCHAR(25) NOT NULL,
+    vote_count INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_post_id_poll_options FOREIGN KEY (post_id) REFERENCES polls(post_id),
+    UNIQUE KEY uq_poll_options_post_id_position (post_id, position)
+);
+
+-- one row per voter, so nobody can vote twice even in multiple choice polls
+CREATE TABLE IF NOT EXISTS poll_votes (
+    post_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, user_id),
+    CONSTRAINT fk_post_id_poll_votes FOREIGN KEY (post_id) REFERENCES polls(post_id),
+    CONSTRAINT fk_user_id_poll_votes FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS poll_vote_options (
+    post_id INT NOT NULL,
+    user_id INT NOT NULL,
+    option_id INT NOT NULL,
+    PRIMARY KEY (post_id, user_id, option_id),
+    CONSTRAINT fk_vote_poll_vote_options FOREIGN KEY (post_id, user_id) REFERENCES poll_votes(post_id, user_id),
+    CONSTRAINT fk_option_id_poll_vote_options FOREIGN KEY (option_id) REFERENCES poll_options(id),
+    INDEX idx_poll_vote_options_option_id (option_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS poll_vote_options;
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,27 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS bookmark_folders (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_bookmark_folders FOREIGN KEY (user_id) REFERENCES users(id),
+    UNIQUE KEY uq_bookmark_folders_user_id_name (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    post_id INT NOT NULL,
+    folder_id INT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_bookmarks FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_post_id_bookmarks FOREIGN KEY (post_id) REFERENCES posts(id),
+    CONSTRAINT fk_folder_id_bookmarks FOREIGN KEY (folder_id) REFERENCES bookmark_folders(id),
+    UNIQUE KEY uq_bookmarks_user_id_post_id (user_id, post_id),
+    INDEX idx_bookmarks_user_id_id (user_id, id),
+    INDEX idx_bookmarks_folder_id_id (folder_id, id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS bookmark_folders;
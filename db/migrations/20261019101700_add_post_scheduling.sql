@@ -0,0 +1,14 @@
+-- migrate:up
+ALTER TABLE posts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published',
+    ADD COLUMN publish_at TIMESTAMP NULL,
+    ADD INDEX idx_posts_status_publish_at (status, publish_at);
+
+-- existing posts went public when they were created
+UPDATE posts SET publish_at = created_at;
+
+-- migrate:down
+ALTER TABLE posts
+    DROP INDEX idx_posts_status_publish_at,
+    DROP COLUMN publish_at,
+    DROP COLUMN status;
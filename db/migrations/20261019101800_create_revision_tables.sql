@@ -0,0 +1,29 @@
+-- migrate:up
+ALTER TABLE posts ADD COLUMN edit_count INT NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN edit_count INT NOT NULL DEFAULT 0;
+
+-- each row is a version that an edit replaced, written at created_at
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    post_id INT NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    content LONGTEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_post_id_post_revisions FOREIGN KEY (post_id) REFERENCES posts(id),
+    INDEX idx_post_revisions_post_id_id (post_id, id)
+);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    comment_id INT NOT NULL,
+    content LONGTEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_comment_id_comment_revisions FOREIGN KEY (comment_id) REFERENCES comments(id),
+    INDEX idx_comment_revisions_comment_id_id (comment_id, id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS comment_revisions;
+DROP TABLE IF EXISTS post_revisions;
+ALTER TABLE comments DROP COLUMN edit_count;
+ALTER TABLE posts DROP COLUMN edit_count;
@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE posts ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE comments ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+-- migrate:down
+ALTER TABLE comments DROP COLUMN version;
+ALTER TABLE posts DROP COLUMN version;
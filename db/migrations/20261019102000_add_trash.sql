@@ -0,0 +1,33 @@
+-- migrate:up
+-- deleted_by tells posts and comments their authors deleted, which they can
+-- restore, from ones removed by moderators
+ALTER TABLE posts
+    ADD COLUMN deleted_by INT NULL,
+    ADD CONSTRAINT fk_deleted_by_posts FOREIGN KEY (deleted_by) REFERENCES users(id),
+    ADD INDEX idx_posts_deleted_at (deleted_at);
+ALTER TABLE comments
+    ADD COLUMN deleted_by INT NULL,
+    ADD CONSTRAINT fk_deleted_by_comments FOREIGN KEY (deleted_by) REFERENCES users(id),
+    ADD INDEX idx_comments_deleted_at (deleted_at);
+
+-- moderator deletions were audited; the rest were the author's
+UPDATE posts SET deleted_by = user_id WHERE deleted_at IS NOT NULL;
+UPDATE posts p
+JOIN audit_logs a ON a.entity_type = 'post' AND a.entity_id = p.id AND a.action = 'post.delete'
+SET p.deleted_by = a.actor_id
+WHERE p.deleted_at IS NOT NULL;
+UPDATE comments SET deleted_by = user_id WHERE deleted_at IS NOT NULL;
+UPDATE comments c
+JOIN audit_logs a ON a.entity_type = 'comment' AND a.entity_id = c.id AND a.action = 'comment.delete'
+SET c.deleted_by = a.actor_id
+WHERE c.deleted_at IS NOT NULL;
+
+-- migrate:down
+ALTER TABLE comments
+    DROP FOREIGN KEY fk_deleted_by_comments,
+    DROP INDEX idx_comments_deleted_at,
+    DROP COLUMN deleted_by;
+ALTER TABLE posts
+    DROP FOREIGN KEY fk_deleted_by_posts,
+    DROP INDEX idx_posts_deleted_at,
+    DROP COLUMN deleted_by;
@@ -0,0 +1,13 @@
+-- migrate:up
+-- a user pins at most one of their own published posts
+CREATE TABLE IF NOT EXISTS pinned_posts (
+    user_id INT PRIMARY KEY,
+    post_id INT NOT NULL,
+    pinned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_id_pinned_posts FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT fk_post_id_pinned_posts FOREIGN KEY (post_id) REFERENCES posts(id),
+    UNIQUE KEY uq_pinned_posts_post_id (post_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS pinned_posts;
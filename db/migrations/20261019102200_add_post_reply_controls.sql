@@ -0,0 +1,9 @@
+-- migrate:up
+ALTER TABLE posts
+    ADD COLUMN reply_policy VARCHAR(20) NOT NULL DEFAULT 'everyone',
+    ADD COLUMN replies_locked BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- migrate:down
+ALTER TABLE posts
+    DROP COLUMN replies_locked,
+    DROP COLUMN reply_policy;
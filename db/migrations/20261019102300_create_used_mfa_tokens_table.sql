@@ -0,0 +1,11 @@
+-- migrate:up
+-- login challenge tokens already spent on a second-factor attempt, kept
+-- until they expire
+CREATE TABLE IF NOT EXISTS used_mfa_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL,
+    INDEX idx_used_mfa_tokens_expires_at (expires_at)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS used_mfa_tokens;
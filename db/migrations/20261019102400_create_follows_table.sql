@@ -0,0 +1,13 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INT NOT NULL,
+    followee_id INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT fk_follower_id_follows FOREIGN KEY (follower_id) REFERENCES users(id),
+    CONSTRAINT fk_followee_id_follows FOREIGN KEY (followee_id) REFERENCES users(id),
+    INDEX idx_follows_followee_id (followee_id)
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS follows;
@@ -2,11 +2,87 @@ package config
 
 import (
 	"fmt"
+	"go-twitter/pkg/jwt"
+	"go-twitter/pkg/oidc"
+	"go-twitter/pkg/webauthn"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+const (
+	DefaultJwtIssuer       = "go-twitter"
+	DefaultJwtAudience     = "go-twitter-api"
+	DefaultAccessTokenTTL  = 60 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	DefaultMFAChallengeTTL = 5 * time.Minute
+	DefaultTOTPIssuer      = "go-twitter"
+
+	DefaultLoginMaxAttempts     = 5
+	DefaultLoginIPMaxAttempts   = 20
+	DefaultLoginLockoutDuration = 15 * time.Minute
+
+	DefaultMagicLinkURL         = "http://localhost:3000/login/magic-link"
+	DefaultMagicLinkTTL         = 15 * time.Minute
+	DefaultMagicLinkMaxRequests = 3
+	DefaultMagicLinkWindow      = time.Hour
+	DefaultMailFrom             = "no-reply@go-twitter.local"
+	DefaultSMTPPort             = "587"
+
+	DefaultWebAuthnRPID   = "localhost"
+	DefaultWebAuthnRPName = "go-twitter"
+	DefaultWebAuthnOrigin = "http://localhost:3000"
+
+	DefaultInviteQuota = 5
+
+	DefaultAccountDeletionGrace = 30 * 24 * time.Hour
+	DefaultAccountPurgeInterval = time.Hour
+
+	DefaultBlobStoreDir           = "data/blobs"
+	DefaultDataExportLinkTTL      = 15 * time.Minute
+	DefaultDataExportRetention    = 7 * 24 * time.Hour
+	DefaultDataExportPollInterval = 30 * time.Second
+
+	DefaultImportMaxArchiveMB = 256
+	DefaultImportPollInterval = 30 * time.Second
+
+	DefaultS3Region = "us-east-1"
+
+	DefaultMediaMaxImageMB = 5
+	DefaultMediaMaxVideoMB = 50
+	DefaultMediaOrphanTTL  = 24 * time.Hour
+	DefaultMediaGCInterval = time.Hour
+
+	DefaultMediaProcessingWorkers      = 2
+	DefaultMediaMaxImagePixels         = 25_000_000
+	DefaultMediaProcessingPollInterval = time.Minute
+
+	DefaultPollFinalizeInterval = time.Minute
+
+	DefaultScheduledPostsInterval = 30 * time.Second
+
+	DefaultEditWindow = time.Hour
+
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+)
+
+// Blob store backends for BLOB_STORE_BACKEND.
+const (
+	BlobStoreFile = "file"
+	BlobStoreS3   = "s3"
+)
+
+// Registration modes for POST /auth/register.
+const (
+	RegistrationOpen       = "open"
+	RegistrationInviteOnly = "invite_only"
+	RegistrationClosed     = "closed"
+)
+
 type Config struct {
 	Port string
 	DBUrlMigration string
@@ -17,6 +93,73 @@ type Config struct {
 	DBUser string
 	DBPassword string
 	DBName string
+
+	JwtIssuer string
+	JwtAudience string
+	AccessTokenTTL time.Duration
+	RefreshTokenTTL time.Duration
+	MFAChallengeTTL time.Duration
+	TOTPIssuer string
+
+	LoginMaxAttempts int
+	LoginIPMaxAttempts int
+	LoginLockoutDuration time.Duration
+
+	OIDCProviders []oidc.Config
+
+	MagicLinkURL string
+	MagicLinkTTL time.Duration
+	MagicLinkMaxRequests int
+	MagicLinkWindow time.Duration
+
+	MailFrom string
+	SMTPHost string
+	SMTPPort string
+	SMTPUsername string
+	SMTPPassword string
+
+	WebAuthnRPID string
+	WebAuthnRPName string
+	WebAuthnOrigin string
+
+	RegistrationMode string
+	InviteQuota int
+
+	AccountDeletionGrace time.Duration
+	AccountPurgeInterval time.Duration
+
+	BlobStoreDir string
+	DataExportLinkTTL time.Duration
+	DataExportRetention time.Duration
+	DataExportPollInterval time.Duration
+
+	ImportMaxArchiveMB int
+	ImportPollInterval time.Duration
+
+	BlobStoreBackend string
+	S3Endpoint string
+	S3Region string
+	S3Bucket string
+	S3AccessKeyID string
+	S3SecretAccessKey string
+
+	MediaMaxImageMB int
+	MediaMaxVideoMB int
+	MediaOrphanTTL time.Duration
+	MediaGCInterval time.Duration
+
+	MediaProcessingWorkers int
+	MediaMaxImagePixels int
+	MediaProcessingPollInterval time.Duration
+
+	PollFinalizeInterval time.Duration
+
+	ScheduledPostsInterval time.Duration
+
+	EditWindow time.Duration
+
+	TrashRetention time.Duration
+	TrashPurgeInterval time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -30,16 +173,530 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	accessTokenTTL, err := getDuration("ACCESS_TOKEN_TTL", DefaultAccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenTTL, err := getDuration("REFRESH_TOKEN_TTL", DefaultRefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaChallengeTTL, err := getDuration("MFA_CHALLENGE_TTL", DefaultMFAChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	loginMaxAttempts, err := getInt("LOGIN_MAX_ATTEMPTS", DefaultLoginMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	loginIPMaxAttempts, err := getInt("LOGIN_IP_MAX_ATTEMPTS", DefaultLoginIPMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	loginLockoutDuration, err := getDuration("LOGIN_LOCKOUT_DURATION", DefaultLoginLockoutDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProviders, err := getOIDCProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	registrationMode := strings.ToLower(getString("REGISTRATION_MODE", RegistrationOpen))
+	switch registrationMode {
+	case RegistrationOpen, RegistrationInviteOnly, RegistrationClosed:
+	default:
+		return nil, fmt.Errorf("invalid REGISTRATION_MODE %q: must be %s, %s or %s", registrationMode, RegistrationOpen, RegistrationInviteOnly, RegistrationClosed)
+	}
+
+	inviteQuota, err := getInt("INVITE_QUOTA", DefaultInviteQuota)
+	if err != nil {
+		return nil, err
+	}
+
+	accountDeletionGrace, err := getDuration("ACCOUNT_DELETION_GRACE", DefaultAccountDeletionGrace)
+	if err != nil {
+		return nil, err
+	}
+
+	accountPurgeInterval, err := getDuration("ACCOUNT_PURGE_INTERVAL", DefaultAccountPurgeInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	dataExportLinkTTL, err := getDuration("DATA_EXPORT_LINK_TTL", DefaultDataExportLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	dataExportRetention, err := getDuration("DATA_EXPORT_RETENTION", DefaultDataExportRetention)
+	if err != nil {
+		return nil, err
+	}
+
+	dataExportPollInterval, err := getDuration("DATA_EXPORT_POLL_INTERVAL", DefaultDataExportPollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	importMaxArchiveMB, err := getInt("IMPORT_MAX_ARCHIVE_MB", DefaultImportMaxArchiveMB)
+	if err != nil {
+		return nil, err
+	}
+
+	importPollInterval, err := getDuration("IMPORT_POLL_INTERVAL", DefaultImportPollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	blobStoreBackend := strings.ToLower(getString("BLOB_STORE_BACKEND", BlobStoreFile))
+	switch blobStoreBackend {
+	case BlobStoreFile:
+	case BlobStoreS3:
+		if os.Getenv("S3_ENDPOINT") == "" || os.Getenv("S3_BUCKET") == "" {
+			return nil, fmt.Errorf("BLOB_STORE_BACKEND %s needs S3_ENDPOINT and S3_BUCKET", BlobStoreS3)
+		}
+	default:
+		return nil, fmt.Errorf("invalid BLOB_STORE_BACKEND %q: must be %s or %s", blobStoreBackend, BlobStoreFile, BlobStoreS3)
+	}
+
+	mediaMaxImageMB, err := getInt("MEDIA_MAX_IMAGE_MB", DefaultMediaMaxImageMB)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaMaxVideoMB, err := getInt("MEDIA_MAX_VIDEO_MB", DefaultMediaMaxVideoMB)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaOrphanTTL, err := getDuration("MEDIA_ORPHAN_TTL", DefaultMediaOrphanTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaGCInterval, err := getDuration("MEDIA_GC_INTERVAL", DefaultMediaGCInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaProcessingWorkers, err := getInt("MEDIA_PROCESSING_WORKERS", DefaultMediaProcessingWorkers)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaMaxImagePixels, err := getInt("MEDIA_MAX_IMAGE_PIXELS", DefaultMediaMaxImagePixels)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaProcessingPollInterval, err := getDuration("MEDIA_PROCESSING_POLL_INTERVAL", DefaultMediaProcessingPollInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	pollFinalizeInterval, err := getDuration("POLL_FINALIZE_INTERVAL", DefaultPollFinalizeInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledPostsInterval, err := getDuration("SCHEDULED_POSTS_INTERVAL", DefaultScheduledPostsInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	editWindow, err := getDuration("EDIT_WINDOW", DefaultEditWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	trashRetention, err := getDuration("TRASH_RETENTION", DefaultTrashRetention)
+	if err != nil {
+		return nil, err
+	}
+
+	trashPurgeInterval, err := getDuration("TRASH_PURGE_INTERVAL", DefaultTrashPurgeInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	magicLinkTTL, err := getDuration("MAGIC_LINK_TTL", DefaultMagicLinkTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	magicLinkMaxRequests, err := getInt("MAGIC_LINK_MAX_REQUESTS", DefaultMagicLinkMaxRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	magicLinkWindow, err := getDuration("MAGIC_LINK_WINDOW", DefaultMagicLinkWindow)
+	if err != nil {
+		return nil, err
+	}
+
 	fmt.Println("Environment variables loaded successfully")
 	return &Config{
-		Port:           os.Getenv("PORT"),
-		DBUrlMigration: os.Getenv("DATABASE_URL"),
-		SecreetJwt:     os.Getenv("JWT_SECRET"),
-		DBHost:         os.Getenv("DB_HOST"),
-		DBPort:         os.Getenv("DB_PORT"),
-		DBUser:         os.Getenv("DB_USER"),
-		DBPassword:     os.Getenv("DB_PASSWORD"),
-		DBName:         os.Getenv("DB_NAME"),
+		Port:            os.Getenv("PORT"),
+		DBUrlMigration:  os.Getenv("DATABASE_URL"),
+		SecreetJwt:      os.Getenv("JWT_SECRET"),
+		DBHost:          os.Getenv("DB_HOST"),
+		DBPort:          os.Getenv("DB_PORT"),
+		DBUser:          os.Getenv("DB_USER"),
+		DBPassword:      os.Getenv("DB_PASSWORD"),
+		DBName:          os.Getenv("DB_NAME"),
+		JwtIssuer:       getString("JWT_ISSUER", DefaultJwtIssuer),
+		JwtAudience:     getString("JWT_AUDIENCE", DefaultJwtAudience),
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+		MFAChallengeTTL: mfaChallengeTTL,
+		TOTPIssuer:      getString("TOTP_ISSUER", DefaultTOTPIssuer),
+
+		LoginMaxAttempts:     loginMaxAttempts,
+		LoginIPMaxAttempts:   loginIPMaxAttempts,
+		LoginLockoutDuration: loginLockoutDuration,
+
+		OIDCProviders: oidcProviders,
+
+		MagicLinkURL:         getString("MAGIC_LINK_URL", DefaultMagicLinkURL),
+		MagicLinkTTL:         magicLinkTTL,
+		MagicLinkMaxRequests: magicLinkMaxRequests,
+		MagicLinkWindow:      magicLinkWindow,
+
+		MailFrom:     getString("MAIL_FROM", DefaultMailFrom),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getString("SMTP_PORT", DefaultSMTPPort),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+
+		WebAuthnRPID:   getString("WEBAUTHN_RP_ID", DefaultWebAuthnRPID),
+		WebAuthnRPName: getString("WEBAUTHN_RP_NAME", DefaultWebAuthnRPName),
+		WebAuthnOrigin: getString("WEBAUTHN_ORIGIN", DefaultWebAuthnOrigin),
+
+		RegistrationMode: registrationMode,
+		InviteQuota:      inviteQuota,
+
+		AccountDeletionGrace: accountDeletionGrace,
+		AccountPurgeInterval: accountPurgeInterval,
+
+		BlobStoreDir:           getString("BLOB_STORE_DIR", DefaultBlobStoreDir),
+		DataExportLinkTTL:      dataExportLinkTTL,
+		DataExportRetention:    dataExportRetention,
+		DataExportPollInterval: dataExportPollInterval,
+
+		ImportMaxArchiveMB: importMaxArchiveMB,
+		ImportPollInterval: importPollInterval,
+
+		BlobStoreBackend:  blobStoreBackend,
+		S3Endpoint:        getString("S3_ENDPOINT", ""),
+		S3Region:          getString("S3_REGION", DefaultS3Region),
+		S3Bucket:          getString("S3_BUCKET", ""),
+		S3AccessKeyID:     getString("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getString("S3_SECRET_ACCESS_KEY", ""),
+
+		MediaMaxImageMB: mediaMaxImageMB,
+		MediaMaxVideoMB: mediaMaxVideoMB,
+		MediaOrphanTTL:  mediaOrphanTTL,
+		MediaGCInterval: mediaGCInterval,
+
+		MediaProcessingWorkers:      mediaProcessingWorkers,
+		MediaMaxImagePixels:         mediaMaxImagePixels,
+		MediaProcessingPollInterval: mediaProcessingPollInterval,
+
+		PollFinalizeInterval: pollFinalizeInterval,
+
+		ScheduledPostsInterval: scheduledPostsInterval,
+
+		EditWindow: editWindow,
+
+		TrashRetention: trashRetention,
+		TrashPurgeInterval: trashPurgeInterval,
 	}, nil
 
-}
\ No newline at end of file
+}
+
+// JWTOptions returns the signing and validation settings for access tokens.
+func (c *Config) JWTOptions() jwt.Options {
+	return jwt.Options{
+		Secret:   c.SecreetJwt,
+		Issuer:   c.JwtIssuer,
+		Audience: c.JwtAudience,
+		TTL:      c.AccessTokenTTL,
+	}
+}
+
+// RefreshTokenExpiry returns the refresh token lifetime, falling back to the
+// default when the config was built without one.
+func (c *Config) RefreshTokenExpiry() time.Duration {
+	if c.RefreshTokenTTL <= 0 {
+		return DefaultRefreshTokenTTL
+	}
+	return c.RefreshTokenTTL
+}
+
+// MFAChallengeExpiry returns how long the second login step may take.
+func (c *Config) MFAChallengeExpiry() time.Duration {
+	if c.MFAChallengeTTL <= 0 {
+		return DefaultMFAChallengeTTL
+	}
+	return c.MFAChallengeTTL
+}
+
+// LoginThrottle returns the per-account and per-IP failure thresholds and
+// the lockout duration, falling back to defaults for unset values.
+func (c *Config) LoginThrottle() (int, int, time.Duration) {
+	maxAttempts, ipMaxAttempts, lockout := c.LoginMaxAttempts, c.LoginIPMaxAttempts, c.LoginLockoutDuration
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultLoginMaxAttempts
+	}
+	if ipMaxAttempts <= 0 {
+		ipMaxAttempts = DefaultLoginIPMaxAttempts
+	}
+	if lockout <= 0 {
+		lockout = DefaultLoginLockoutDuration
+	}
+	return maxAttempts, ipMaxAttempts, lockout
+}
+
+// MagicLinkExpiry returns how long an emailed sign-in link stays valid.
+func (c *Config) MagicLinkExpiry() time.Duration {
+	if c.MagicLinkTTL <= 0 {
+		return DefaultMagicLinkTTL
+	}
+	return c.MagicLinkTTL
+}
+
+// MagicLinkRateLimit returns how many sign-in links one email may request
+// per window, falling back to defaults for unset values.
+func (c *Config) MagicLinkRateLimit() (int, time.Duration) {
+	maxRequests, window := c.MagicLinkMaxRequests, c.MagicLinkWindow
+	if maxRequests <= 0 {
+		maxRequests = DefaultMagicLinkMaxRequests
+	}
+	if window <= 0 {
+		window = DefaultMagicLinkWindow
+	}
+	return maxRequests, window
+}
+
+// WebAuthn returns the relying party settings for passkeys, falling back to
+// defaults for unset values.
+func (c *Config) WebAuthn() webauthn.Config {
+	cfg := webauthn.Config{RPID: c.WebAuthnRPID, RPName: c.WebAuthnRPName, Origin: c.WebAuthnOrigin}
+	if cfg.RPID == "" {
+		cfg.RPID = DefaultWebAuthnRPID
+	}
+	if cfg.RPName == "" {
+		cfg.RPName = DefaultWebAuthnRPName
+	}
+	if cfg.Origin == "" {
+		cfg.Origin = DefaultWebAuthnOrigin
+	}
+	return cfg
+}
+
+// Registration returns the registration mode, open unless configured.
+func (c *Config) Registration() string {
+	if c.RegistrationMode == "" {
+		return RegistrationOpen
+	}
+	return c.RegistrationMode
+}
+
+// InviteCodeQuota returns how many invite codes a user without the
+// invites:manage permission may create. A negative INVITE_QUOTA leaves
+// invites to admins only.
+func (c *Config) InviteCodeQuota() int {
+	if c.InviteQuota < 0 {
+		return 0
+	}
+	if c.InviteQuota == 0 {
+		return DefaultInviteQuota
+	}
+	return c.InviteQuota
+}
+
+// AccountDeletion returns how long a deactivated account can be restored by
+// logging in, and how often the purge job looks for accounts past that grace
+// period.
+func (c *Config) AccountDeletion() (time.Duration, time.Duration) {
+	grace, interval := c.AccountDeletionGrace, c.AccountPurgeInterval
+	if grace <= 0 {
+		grace = DefaultAccountDeletionGrace
+	}
+	if interval <= 0 {
+		interval = DefaultAccountPurgeInterval
+	}
+	return grace, interval
+}
+
+// DataExport returns how long a download link stays valid, how long a
+// finished archive is kept, and how often the export job looks for queued
+// exports.
+func (c *Config) DataExport() (time.Duration, time.Duration, time.Duration) {
+	linkTTL, retention, pollInterval := c.DataExportLinkTTL, c.DataExportRetention, c.DataExportPollInterval
+	if linkTTL <= 0 {
+		linkTTL = DefaultDataExportLinkTTL
+	}
+	if retention <= 0 {
+		retention = DefaultDataExportRetention
+	}
+	if pollInterval <= 0 {
+		pollInterval = DefaultDataExportPollInterval
+	}
+	return linkTTL, retention, pollInterval
+}
+
+// Import returns the largest archive an import accepts, in bytes, and how
+// often the import job looks for queued imports.
+func (c *Config) Import() (int64, time.Duration) {
+	maxArchiveMB, pollInterval := c.ImportMaxArchiveMB, c.ImportPollInterval
+	if maxArchiveMB <= 0 {
+		maxArchiveMB = DefaultImportMaxArchiveMB
+	}
+	if pollInterval <= 0 {
+		pollInterval = DefaultImportPollInterval
+	}
+	return int64(maxArchiveMB) << 20, pollInterval
+}
+
+// Media returns the largest image and video upload in bytes, how long an
+// upload may stay unattached to a post before it is deleted, and how often
+// the cleanup job runs.
+func (c *Config) Media() (int64, int64, time.Duration, time.Duration) {
+	maxImageMB, maxVideoMB, orphanTTL, gcInterval := c.MediaMaxImageMB, c.MediaMaxVideoMB, c.MediaOrphanTTL, c.MediaGCInterval
+	if maxImageMB <= 0 {
+		maxImageMB = DefaultMediaMaxImageMB
+	}
+	if maxVideoMB <= 0 {
+		maxVideoMB = DefaultMediaMaxVideoMB
+	}
+	if orphanTTL <= 0 {
+		orphanTTL = DefaultMediaOrphanTTL
+	}
+	if gcInterval <= 0 {
+		gcInterval = DefaultMediaGCInterval
+	}
+	return int64(maxImageMB) << 20, int64(maxVideoMB) << 20, orphanTTL, gcInterval
+}
+
+// MediaProcessing returns how many workers process uploaded images, the
+// most pixels an image may decode to, and how often pending images are
+// queued again in case the queue was full or the server restarted.
+func (c *Config) MediaProcessing() (int, int, time.Duration) {
+	workers, maxPixels, pollInterval := c.MediaProcessingWorkers, c.MediaMaxImagePixels, c.MediaProcessingPollInterval
+	if workers <= 0 {
+		workers = DefaultMediaProcessingWorkers
+	}
+	if maxPixels <= 0 {
+		maxPixels = DefaultMediaMaxImagePixels
+	}
+	if pollInterval <= 0 {
+		pollInterval = DefaultMediaProcessingPollInterval
+	}
+	return workers, maxPixels, pollInterval
+}
+
+// PollFinalization returns how often the job freezing the results of
+// closed polls runs.
+func (c *Config) PollFinalization() time.Duration {
+	if c.PollFinalizeInterval <= 0 {
+		return DefaultPollFinalizeInterval
+	}
+	return c.PollFinalizeInterval
+}
+
+// ScheduledPublishing returns how often the job publishing scheduled posts
+// that are due runs; posts go out up to this long after their time.
+func (c *Config) ScheduledPublishing() time.Duration {
+	if c.ScheduledPostsInterval <= 0 {
+		return DefaultScheduledPostsInterval
+	}
+	return c.ScheduledPostsInterval
+}
+
+// EditingWindow returns how long after publishing a post, or writing a
+// comment, its author may still edit it.
+func (c *Config) EditingWindow() time.Duration {
+	if c.EditWindow <= 0 {
+		return DefaultEditWindow
+	}
+	return c.EditWindow
+}
+
+// Trash returns how long deleted posts and comments can be restored, and
+// how often the job hard-deleting those past it runs.
+func (c *Config) Trash() (time.Duration, time.Duration) {
+	retention, interval := c.TrashRetention, c.TrashPurgeInterval
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	if interval <= 0 {
+		interval = DefaultTrashPurgeInterval
+	}
+	return retention, interval
+}
+
+// getOIDCProviders reads the providers listed in OIDC_PROVIDERS, e.g.
+// "google,acme", each configured by OIDC_<NAME>_ISSUER, _CLIENT_ID,
+// _CLIENT_SECRET and _REDIRECT_URL.
+func getOIDCProviders() ([]oidc.Config, error) {
+	var providers []oidc.Config
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("OIDC provider %s needs %sISSUER, %sCLIENT_ID and %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+func getString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return n, nil
+}
+
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return d, nil
+}
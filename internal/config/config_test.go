@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig_Success(t *testing.T) {
@@ -357,3 +358,587 @@ func TestConfig_StructFields(t *testing.T) {
 		t.Errorf("DBName field not working correctly")
 	}
 }
+
+func TestLoadConfig_TokenSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	envContent := `JWT_SECRET=secret
+JWT_ISSUER=issuer-test
+JWT_AUDIENCE=audience-test
+ACCESS_TOKEN_TTL=15m
+REFRESH_TOKEN_TTL=48h
+`
+
+	err := os.WriteFile(envFile, []byte(envContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	opts := cfg.JWTOptions()
+	if opts.Secret != "secret" || opts.Issuer != "issuer-test" || opts.Audience != "audience-test" {
+		t.Errorf("Unexpected JWT options: %+v", opts)
+	}
+
+	if cfg.AccessTokenTTL != 15*time.Minute {
+		t.Errorf("Expected AccessTokenTTL to be 15m, got %v", cfg.AccessTokenTTL)
+	}
+
+	if cfg.RefreshTokenExpiry() != 48*time.Hour {
+		t.Errorf("Expected RefreshTokenTTL to be 48h, got %v", cfg.RefreshTokenExpiry())
+	}
+}
+
+func TestLoadConfig_TokenSettingsDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("JWT_SECRET=secret\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.JwtIssuer != DefaultJwtIssuer || cfg.JwtAudience != DefaultJwtAudience {
+		t.Errorf("Expected default issuer and audience, got %q and %q", cfg.JwtIssuer, cfg.JwtAudience)
+	}
+
+	if cfg.AccessTokenTTL != DefaultAccessTokenTTL || cfg.RefreshTokenTTL != DefaultRefreshTokenTTL {
+		t.Errorf("Expected default TTLs, got %v and %v", cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	}
+
+	if cfg.LoginMaxAttempts != DefaultLoginMaxAttempts || cfg.LoginIPMaxAttempts != DefaultLoginIPMaxAttempts || cfg.LoginLockoutDuration != DefaultLoginLockoutDuration {
+		t.Errorf("Expected default login throttle, got %d, %d and %v", cfg.LoginMaxAttempts, cfg.LoginIPMaxAttempts, cfg.LoginLockoutDuration)
+	}
+}
+
+func TestLoadConfig_LoginThrottleSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	envContent := "LOGIN_MAX_ATTEMPTS=3\nLOGIN_IP_MAX_ATTEMPTS=50\nLOGIN_LOCKOUT_DURATION=30m\n"
+	err := os.WriteFile(envFile, []byte(envContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	maxAttempts, ipMaxAttempts, lockout := cfg.LoginThrottle()
+	if maxAttempts != 3 || ipMaxAttempts != 50 || lockout != 30*time.Minute {
+		t.Errorf("Expected 3, 50 and 30m, got %d, %d and %v", maxAttempts, ipMaxAttempts, lockout)
+	}
+}
+
+func TestLoadConfig_InvalidLoginMaxAttempts(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("LOGIN_MAX_ATTEMPTS=many\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for invalid LOGIN_MAX_ATTEMPTS, got nil")
+	}
+}
+
+func TestLoadConfig_InvalidTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("ACCESS_TOKEN_TTL=soon\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err == nil {
+		t.Error("Expected error for invalid ACCESS_TOKEN_TTL, got nil")
+	}
+
+	if cfg != nil {
+		t.Error("Expected nil config when error occurs, got non-nil")
+	}
+}
+
+func TestLoadConfig_OIDCProviders(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	envContent := `OIDC_PROVIDERS=Acme, other
+OIDC_ACME_ISSUER=https://id.acme.example
+OIDC_ACME_CLIENT_ID=acme-client
+OIDC_ACME_CLIENT_SECRET=acme-secret
+OIDC_ACME_REDIRECT_URL=https://app.example.com/auth/acme
+OIDC_OTHER_ISSUER=https://other.example
+OIDC_OTHER_CLIENT_ID=other-client
+OIDC_OTHER_REDIRECT_URL=https://app.example.com/auth/other
+`
+	err := os.WriteFile(envFile, []byte(envContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(cfg.OIDCProviders) != 2 {
+		t.Fatalf("Expected 2 OIDC providers, got %d", len(cfg.OIDCProviders))
+	}
+	acme := cfg.OIDCProviders[0]
+	if acme.Name != "acme" || acme.Issuer != "https://id.acme.example" || acme.ClientSecret != "acme-secret" {
+		t.Errorf("Unexpected provider config: %+v", acme)
+	}
+}
+
+func TestLoadConfig_IncompleteOIDCProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("OIDC_PROVIDERS=acme\nOIDC_ACME_CLIENT_ID=acme-client\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for provider without issuer, got nil")
+	}
+}
+
+func TestLoadConfig_MagicLinkSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	envContent := "MAGIC_LINK_URL=https://app.example.com/magic\nMAGIC_LINK_TTL=5m\nMAGIC_LINK_MAX_REQUESTS=2\nSMTP_HOST=smtp.example.com\n"
+	err := os.WriteFile(envFile, []byte(envContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.MagicLinkURL != "https://app.example.com/magic" || cfg.MagicLinkExpiry() != 5*time.Minute {
+		t.Errorf("Unexpected magic link config: %q, %v", cfg.MagicLinkURL, cfg.MagicLinkExpiry())
+	}
+	maxRequests, window := cfg.MagicLinkRateLimit()
+	if maxRequests != 2 || window != DefaultMagicLinkWindow {
+		t.Errorf("Expected 2 requests per %v, got %d per %v", DefaultMagicLinkWindow, maxRequests, window)
+	}
+	if cfg.SMTPHost != "smtp.example.com" || cfg.SMTPPort != DefaultSMTPPort || cfg.MailFrom != DefaultMailFrom {
+		t.Errorf("Unexpected mail config: %q, %q, %q", cfg.SMTPHost, cfg.SMTPPort, cfg.MailFrom)
+	}
+}
+
+func TestLoadConfig_RegistrationMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("REGISTRATION_MODE=Invite_Only\nINVITE_QUOTA=2\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.Registration() != RegistrationInviteOnly || cfg.InviteCodeQuota() != 2 {
+		t.Errorf("Expected invite-only with quota 2, got %q and %d", cfg.Registration(), cfg.InviteCodeQuota())
+	}
+
+	if (&Config{}).Registration() != RegistrationOpen {
+		t.Error("Expected registration to default to open")
+	}
+}
+
+func TestLoadConfig_InvalidRegistrationMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("REGISTRATION_MODE=private\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for invalid REGISTRATION_MODE, got nil")
+	}
+}
+
+func TestLoadConfig_AccountDeletion(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("ACCOUNT_DELETION_GRACE=168h\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	grace, interval := cfg.AccountDeletion()
+	if grace != 7*24*time.Hour || interval != DefaultAccountPurgeInterval {
+		t.Errorf("Expected 168h grace and default interval, got %v and %v", grace, interval)
+	}
+
+	if grace, _ := (&Config{}).AccountDeletion(); grace != DefaultAccountDeletionGrace {
+		t.Errorf("Expected grace to default to %v, got %v", DefaultAccountDeletionGrace, grace)
+	}
+}
+
+func TestLoadConfig_DataExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("DATA_EXPORT_LINK_TTL=5m\nDATA_EXPORT_RETENTION=48h\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	linkTTL, retention, pollInterval := cfg.DataExport()
+	if linkTTL != 5*time.Minute || retention != 48*time.Hour || pollInterval != DefaultDataExportPollInterval {
+		t.Errorf("Unexpected data export settings: %v, %v, %v", linkTTL, retention, pollInterval)
+	}
+	if cfg.BlobStoreDir != DefaultBlobStoreDir {
+		t.Errorf("Expected blob store dir %q, got %q", DefaultBlobStoreDir, cfg.BlobStoreDir)
+	}
+}
+
+func TestLoadConfig_InvalidDataExportLinkTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("DATA_EXPORT_LINK_TTL=soon\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	if _, err := LoadConfig(); err == nil {
+		t.Error("Expected error for invalid DATA_EXPORT_LINK_TTL, got nil")
+	}
+}
+
+func TestLoadConfig_Import(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("IMPORT_MAX_ARCHIVE_MB=10\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	maxArchiveSize, pollInterval := cfg.Import()
+	if maxArchiveSize != 10<<20 || pollInterval != DefaultImportPollInterval {
+		t.Errorf("Unexpected import settings: %d, %v", maxArchiveSize, pollInterval)
+	}
+}
+
+func TestLoadConfig_S3BlobStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("BLOB_STORE_BACKEND=S3\nS3_ENDPOINT=http://localhost:9000\nS3_BUCKET=media\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if cfg.BlobStoreBackend != BlobStoreS3 || cfg.S3Bucket != "media" || cfg.S3Region != DefaultS3Region {
+		t.Errorf("Unexpected blob store settings: %q, %q, %q", cfg.BlobStoreBackend, cfg.S3Bucket, cfg.S3Region)
+	}
+}
+
+func TestLoadConfig_InvalidBlobStore(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+	}{
+		{"unknown backend", "BLOB_STORE_BACKEND=ftp\n"},
+		{"s3 without bucket", "BLOB_STORE_BACKEND=s3\nS3_ENDPOINT=http://localhost:9000\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			envFile := filepath.Join(tmpDir, ".env")
+
+			err := os.WriteFile(envFile, []byte(tt.env), 0644)
+			if err != nil {
+				t.Fatalf("Failed to create test .env file: %v", err)
+			}
+
+			os.Clearenv()
+			originalWd, _ := os.Getwd()
+			defer os.Chdir(originalWd)
+			os.Chdir(tmpDir)
+
+			if _, err := LoadConfig(); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Media(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("MEDIA_MAX_IMAGE_MB=2\nMEDIA_ORPHAN_TTL=6h\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	maxImageSize, maxVideoSize, orphanTTL, gcInterval := cfg.Media()
+	if maxImageSize != 2<<20 || maxVideoSize != DefaultMediaMaxVideoMB<<20 || orphanTTL != 6*time.Hour || gcInterval != DefaultMediaGCInterval {
+		t.Errorf("Unexpected media settings: %d, %d, %v, %v", maxImageSize, maxVideoSize, orphanTTL, gcInterval)
+	}
+}
+
+func TestLoadConfig_MediaProcessing(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("MEDIA_PROCESSING_WORKERS=4\nMEDIA_MAX_IMAGE_PIXELS=1000000\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	workers, maxPixels, pollInterval := cfg.MediaProcessing()
+	if workers != 4 || maxPixels != 1000000 || pollInterval != DefaultMediaProcessingPollInterval {
+		t.Errorf("Unexpected media processing settings: %d, %d, %v", workers, maxPixels, pollInterval)
+	}
+}
+
+func TestLoadConfig_PollFinalization(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("POLL_FINALIZE_INTERVAL=5m\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if interval := cfg.PollFinalization(); interval != 5*time.Minute {
+		t.Errorf("Expected 5m, got %v", interval)
+	}
+	if interval := (&Config{}).PollFinalization(); interval != DefaultPollFinalizeInterval {
+		t.Errorf("Expected the default interval, got %v", interval)
+	}
+}
+
+func TestLoadConfig_ScheduledPublishing(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("SCHEDULED_POSTS_INTERVAL=10s\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if interval := cfg.ScheduledPublishing(); interval != 10*time.Second {
+		t.Errorf("Expected 10s, got %v", interval)
+	}
+	if interval := (&Config{}).ScheduledPublishing(); interval != DefaultScheduledPostsInterval {
+		t.Errorf("Expected the default interval, got %v", interval)
+	}
+}
+
+func TestLoadConfig_EditingWindow(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("EDIT_WINDOW=15m\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if window := cfg.EditingWindow(); window != 15*time.Minute {
+		t.Errorf("Expected 15m, got %v", window)
+	}
+	if window := (&Config{}).EditingWindow(); window != DefaultEditWindow {
+		t.Errorf("Expected the default window, got %v", window)
+	}
+}
+
+func TestLoadConfig_Trash(t *testing.T) {
+	tmpDir := t.TempDir()
+	envFile := filepath.Join(tmpDir, ".env")
+
+	err := os.WriteFile(envFile, []byte("TRASH_RETENTION=168h\nTRASH_PURGE_INTERVAL=10m\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create test .env file: %v", err)
+	}
+
+	os.Clearenv()
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	retention, interval := cfg.Trash()
+	if retention != 7*24*time.Hour {
+		t.Errorf("Expected 168h, got %v", retention)
+	}
+	if interval != 10*time.Minute {
+		t.Errorf("Expected 10m, got %v", interval)
+	}
+	retention, interval = (&Config{}).Trash()
+	if retention != DefaultTrashRetention || interval != DefaultTrashPurgeInterval {
+		t.Errorf("Expected the defaults, got %v and %v", retention, interval)
+	}
+}
@@ -0,0 +1,21 @@
+package dto
+
+type (
+	AuditLogResponse struct {
+		ID           int64  `json:"id"`
+		ActorID      int64  `json:"actor_id"`
+		TargetUserID int64  `json:"target_user_id"`
+		Action       string `json:"action"`
+		EntityType   string `json:"entity_type"`
+		EntityID     int64  `json:"entity_id"`
+		CreatedAt    string `json:"created_at"`
+	}
+
+	AuditLogsResponse struct {
+		AuditLogs  []AuditLogResponse `json:"audit_logs"`
+		TotalCount int64              `json:"total_count"`
+		Page       int                `json:"page"`
+		PageSize   int                `json:"page_size"`
+		TotalPages int                `json:"total_pages"`
+	}
+)
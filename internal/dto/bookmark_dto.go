@@ -0,0 +1,33 @@
+package dto
+
+type (
+	// BookmarkPostRequest files the bookmark in FolderID, or outside any
+	// folder when it is omitted. Bookmarking a post again moves it.
+	BookmarkPostRequest struct {
+		FolderID *int64 `json:"folder_id"`
+	}
+
+	BookmarkResponse struct {
+		Message string `json:"message"`
+	}
+
+	// BookmarksResponse lists bookmarked posts, newest bookmark first.
+	// Pass NextCursor as cursor to get the next page; it is empty on the
+	// last page.
+	BookmarksResponse struct {
+		Posts      []PostResponse `json:"posts"`
+		NextCursor string         `json:"next_cursor"`
+	}
+)
+
+type (
+	CreateBookmarkFolderRequest struct {
+		Name string `json:"name" validate:"required,min=1,max=50"`
+	}
+
+	BookmarkFolderResponse struct {
+		ID        int64  `json:"id"`
+		Name      string `json:"name"`
+		CreatedAt string `json:"created_at"`
+	}
+)
@@ -13,6 +13,9 @@ type (
 type (
 	UpdateCommentRequest struct {
 		Content string `json:"content" validate:"required,min=1"`
+		// IfMatch is the request's If-Match header. When set, the update
+		// only applies to a comment whose ETag it matches.
+		IfMatch string `json:"-"`
 	}
 
 	UpdateCommentResponse struct {
@@ -28,6 +31,9 @@ type (
 		Username   string `json:"username"`
 		Content    string `json:"content"`
 		LikesCount int    `json:"likes_count"`
+		Edited     bool   `json:"edited"`
+		EditCount  int    `json:"edit_count"`
+		Version    int    `json:"version"`
 		CreatedAt  string `json:"created_at"`
 		UpdatedAt  string `json:"updated_at"`
 	}
@@ -40,3 +46,17 @@ type (
 		TotalPages int               `json:"total_pages"`
 	}
 )
+
+type (
+	// CommentRevisionResponse is one version of a comment, numbered from 1;
+	// the last is the current one.
+	CommentRevisionResponse struct {
+		Revision  int    `json:"revision"`
+		Content   string `json:"content"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	CommentRevisionsResponse struct {
+		Revisions []CommentRevisionResponse `json:"revisions"`
+	}
+)
@@ -0,0 +1,193 @@
+package dto
+
+type (
+	DataExportResponse struct {
+		ID          int64   `json:"id"`
+		Status      string  `json:"status"`
+		SizeBytes   int64   `json:"size_bytes,omitempty"`
+		CreatedAt   string  `json:"created_at"`
+		CompletedAt *string `json:"completed_at"`
+		// ExpiresAt is when the archive is deleted.
+		ExpiresAt *string `json:"expires_at"`
+		// DownloadURL is a signed link to the archive, only set once it is
+		// ready. It stops working at DownloadURLExpiresAt; fetch the export
+		// again for a fresh link.
+		DownloadURL          string `json:"download_url,omitempty"`
+		DownloadURLExpiresAt string `json:"download_url_expires_at,omitempty"`
+	}
+)
+
+// The archive's data.json. Timestamps use the same format as the API.
+type (
+	DataExportArchive struct {
+		GeneratedAt string           `json:"generated_at"`
+		Profile     ExportProfile    `json:"profile"`
+		Posts       []ExportPost     `json:"posts"`
+		Comments    []ExportComment  `json:"comments"`
+		Likes       ExportLikes      `json:"likes"`
+		Sessions    ExportSessions   `json:"sessions"`
+		Follows     ExportFollows    `json:"follows"`
+		Revisions   ExportRevisions  `json:"revisions"`
+		Bookmarks   ExportBookmarks  `json:"bookmarks"`
+		PollVotes   []ExportPollVote `json:"poll_votes"`
+		// Media lists the user's uploads; File is where the upload is in
+		// the archive, empty if it could not be copied.
+		Media      []ExportMedia       `json:"media"`
+		ImportJobs []ImportJobResponse `json:"import_jobs"`
+		Invites    ExportInvites       `json:"invites"`
+		OAuth      ExportOAuth         `json:"oauth"`
+	}
+
+	ExportProfile struct {
+		ID               int64             `json:"id"`
+		Username         string            `json:"username"`
+		Email            string            `json:"email"`
+		Roles            []string          `json:"roles"`
+		TwoFactorEnabled bool              `json:"two_factor_enabled"`
+		Passkeys         []PasskeyResponse `json:"passkeys"`
+		// Identities are the accounts at external providers the user signs
+		// in with.
+		Identities   []ExportIdentity `json:"identities"`
+		PinnedPostID *int64           `json:"pinned_post_id"`
+		CreatedAt    string           `json:"created_at"`
+		UpdatedAt    string           `json:"updated_at"`
+	}
+
+	ExportIdentity struct {
+		Provider  string `json:"provider"`
+		Subject   string `json:"subject"`
+		Email     string `json:"email"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	ExportPost struct {
+		ID        int64   `json:"id"`
+		Title     string  `json:"title"`
+		Content   string  `json:"content"`
+		CreatedAt string  `json:"created_at"`
+		UpdatedAt string  `json:"updated_at"`
+		DeletedAt *string `json:"deleted_at"`
+	}
+
+	ExportComment struct {
+		ID        int64   `json:"id"`
+		PostID    int64   `json:"post_id"`
+		Content   string  `json:"content"`
+		CreatedAt string  `json:"created_at"`
+		UpdatedAt string  `json:"updated_at"`
+		DeletedAt *string `json:"deleted_at"`
+	}
+
+	ExportLikes struct {
+		Posts    []ExportLike `json:"posts"`
+		Comments []ExportLike `json:"comments"`
+	}
+
+	// ExportLike names the liked post or comment by ID.
+	ExportLike struct {
+		ID        int64  `json:"id"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	ExportSessions struct {
+		RefreshTokens        []ExportRefreshToken          `json:"refresh_tokens"`
+		PersonalAccessTokens []PersonalAccessTokenResponse `json:"personal_access_tokens"`
+		LoginAttempts        []LoginAttemptResponse        `json:"login_attempts"`
+	}
+
+	ExportRefreshToken struct {
+		ID        int64  `json:"id"`
+		CreatedAt string `json:"created_at"`
+		ExpiresAt string `json:"expires_at"`
+	}
+
+	ExportFollows struct {
+		Following []ExportFollow `json:"following"`
+		Followers []ExportFollow `json:"followers"`
+	}
+
+	// ExportFollow names the other user.
+	ExportFollow struct {
+		UserID    int64  `json:"user_id"`
+		Username  string `json:"username"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	// ExportRevisions are the earlier versions of the user's edited posts
+	// and comments.
+	ExportRevisions struct {
+		Posts    []ExportPostRevision    `json:"posts"`
+		Comments []ExportCommentRevision `json:"comments"`
+	}
+
+	ExportPostRevision struct {
+		ID        int64  `json:"id"`
+		PostID    int64  `json:"post_id"`
+		Title     string `json:"title"`
+		Content   string `json:"content"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	ExportCommentRevision struct {
+		ID        int64  `json:"id"`
+		CommentID int64  `json:"comment_id"`
+		Content   string `json:"content"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	ExportBookmarks struct {
+		Folders []BookmarkFolderResponse `json:"folders"`
+		Posts   []ExportBookmark         `json:"posts"`
+	}
+
+	ExportBookmark struct {
+		PostID    int64  `json:"post_id"`
+		FolderID  *int64 `json:"folder_id"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	// ExportPollVote lists the options the user picked, in poll order.
+	ExportPollVote struct {
+		PostID    int64    `json:"post_id"`
+		Options   []string `json:"options"`
+		CreatedAt string   `json:"created_at"`
+	}
+
+	ExportMedia struct {
+		ID               int64  `json:"id"`
+		File             string `json:"file"`
+		ContentType      string `json:"content_type"`
+		SizeBytes        int64  `json:"size_bytes"`
+		AltText          string `json:"alt_text"`
+		ProcessingStatus string `json:"processing_status"`
+		CreatedAt        string `json:"created_at"`
+	}
+
+	// ExportInvites are the user's invite codes, with who joined through
+	// them, and the invite the user registered with, if any.
+	ExportInvites struct {
+		Codes     []InviteResponse `json:"codes"`
+		InvitedBy *ExportInvitedBy `json:"invited_by"`
+	}
+
+	ExportInvitedBy struct {
+		InviteCodeID int64  `json:"invite_code_id"`
+		UserID       int64  `json:"user_id"`
+		JoinedAt     string `json:"joined_at"`
+	}
+
+	// ExportOAuth covers the OAuth clients the user registered and the
+	// access the user granted to clients, without any secrets.
+	ExportOAuth struct {
+		Clients []OAuthClientResponse `json:"clients"`
+		Grants  []ExportOAuthGrant    `json:"grants"`
+	}
+
+	ExportOAuthGrant struct {
+		ClientID  string   `json:"client_id"`
+		Scopes    []string `json:"scopes"`
+		CreatedAt string   `json:"created_at"`
+		ExpiresAt string   `json:"expires_at"`
+		RevokedAt *string  `json:"revoked_at"`
+	}
+)
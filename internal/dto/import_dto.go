@@ -0,0 +1,36 @@
+package dto
+
+type (
+	ImportJobResponse struct {
+		ID     int64  `json:"id"`
+		Source string `json:"source"`
+		Status string `json:"status"`
+		// TotalItems is known once the archive has been read; until then
+		// it is 0.
+		TotalItems     int     `json:"total_items"`
+		ProcessedItems int     `json:"processed_items"`
+		ImportedItems  int     `json:"imported_items"`
+		SkippedItems   int     `json:"skipped_items"`
+		FailedItems    int     `json:"failed_items"`
+		Error          string  `json:"error,omitempty"`
+		CreatedAt      string  `json:"created_at"`
+		StartedAt      *string `json:"started_at"`
+		CompletedAt    *string `json:"completed_at"`
+		// Items lists the skipped and failed items, a page at a time.
+		Items *ImportJobItemsResponse `json:"items,omitempty"`
+	}
+
+	ImportJobItemsResponse struct {
+		Items      []ImportJobItemResponse `json:"items"`
+		TotalCount int64                   `json:"total_count"`
+		Page       int                     `json:"page"`
+		PageSize   int                     `json:"page_size"`
+		TotalPages int                     `json:"total_pages"`
+	}
+
+	ImportJobItemResponse struct {
+		SourceID string `json:"source_id"`
+		Outcome  string `json:"outcome"`
+		Reason   string `json:"reason"`
+	}
+)
@@ -0,0 +1,30 @@
+package dto
+
+type (
+	UploadMediaRequest struct {
+		AltText string `form:"alt_text" validate:"max=1000"`
+	}
+
+	// MediaResponse describes an upload. URL serves the file once a post
+	// links it and, for images, once processing_status is ready; until then
+	// clients can show the blurhash placeholder.
+	MediaResponse struct {
+		ID               int64                  `json:"id"`
+		URL              string                 `json:"url"`
+		ContentType      string                 `json:"content_type"`
+		SizeBytes        int64                  `json:"size_bytes"`
+		AltText          string                 `json:"alt_text"`
+		ProcessingStatus string                 `json:"processing_status"`
+		Width            int                    `json:"width"`
+		Height           int                    `json:"height"`
+		Blurhash         string                 `json:"blurhash"`
+		Variants         []MediaVariantResponse `json:"variants"`
+	}
+
+	MediaVariantResponse struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+)
@@ -0,0 +1,99 @@
+package dto
+
+type (
+	RegisterOAuthClientRequest struct {
+		Name         string   `json:"name" validate:"required,max=100"`
+		RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,max=10,dive,url"`
+		Scopes       []string `json:"scopes" validate:"required,min=1,dive,oneof=read posts:write comments:write likes:write bookmarks"`
+		// Confidential clients get a secret; public clients (SPAs, mobile
+		// apps) rely on PKCE alone.
+		Confidential bool `json:"confidential"`
+	}
+
+	OAuthClientResponse struct {
+		ClientID     string   `json:"client_id"`
+		ClientSecret string   `json:"client_secret,omitempty"`
+		Name         string   `json:"name"`
+		RedirectURIs []string `json:"redirect_uris"`
+		Scopes       []string `json:"scopes"`
+		CreatedAt    string   `json:"created_at"`
+	}
+)
+
+type (
+	AuthorizeRequest struct {
+		ResponseType        string `form:"response_type" json:"response_type" validate:"required"`
+		ClientID            string `form:"client_id" json:"client_id" validate:"required"`
+		RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+		Scope               string `form:"scope" json:"scope"`
+		State               string `form:"state" json:"state"`
+		CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+		CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+	}
+
+	AuthorizeDecisionRequest struct {
+		AuthorizeRequest
+		Approved bool `json:"approved"`
+	}
+
+	AuthorizeConsentResponse struct {
+		ClientID    string   `json:"client_id"`
+		ClientName  string   `json:"client_name"`
+		RedirectURI string   `json:"redirect_uri"`
+		Scopes      []string `json:"scopes"`
+		State       string   `json:"state,omitempty"`
+	}
+
+	AuthorizeDecisionResponse struct {
+		RedirectTo string `json:"redirect_to"`
+	}
+)
+
+// OAuthClientCredentials are taken from HTTP Basic auth or, failing that,
+// the client_id and client_secret form fields.
+type OAuthClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+type (
+	TokenRequest struct {
+		GrantType    string `form:"grant_type" validate:"required"`
+		Code         string `form:"code"`
+		RedirectURI  string `form:"redirect_uri"`
+		CodeVerifier string `form:"code_verifier"`
+		RefreshToken string `form:"refresh_token"`
+		Scope        string `form:"scope"`
+	}
+
+	TokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int64  `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+	}
+)
+
+type (
+	// TokenActionRequest is the body of the introspection (RFC 7662) and
+	// revocation (RFC 7009) endpoints.
+	TokenActionRequest struct {
+		Token         string `form:"token" validate:"required"`
+		TokenTypeHint string `form:"token_type_hint"`
+	}
+
+	IntrospectionResponse struct {
+		Active    bool   `json:"active"`
+		Scope     string `json:"scope,omitempty"`
+		ClientID  string `json:"client_id,omitempty"`
+		Username  string `json:"username,omitempty"`
+		TokenType string `json:"token_type,omitempty"`
+		Exp       int64  `json:"exp,omitempty"`
+		Iat       int64  `json:"iat,omitempty"`
+		Sub       string `json:"sub,omitempty"`
+		Iss       string `json:"iss,omitempty"`
+		Aud       string `json:"aud,omitempty"`
+		Jti       string `json:"jti,omitempty"`
+	}
+)
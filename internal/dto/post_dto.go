@@ -1,9 +1,30 @@
 package dto
 
+import "time"
+
 type (
 	CreatePostRequest struct {
 		Title   string `json:"title" validate:"required,min=1,max=100"`
 		Content string `json:"content" validate:"required,min=1"`
+		// MediaIDs are uploads from POST /media, attached in this order.
+		MediaIDs []int64 `json:"media_ids" validate:"max=4,unique"`
+		// Poll is optional; a post can have media or a poll, not both.
+		Poll *CreatePollRequest `json:"poll"`
+		// Status defaults to published. Scheduled posts need a PublishAt
+		// in the future and are published by the scheduler.
+		Status    string     `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+		PublishAt *time.Time `json:"publish_at"`
+		// ReplyPolicy is who besides the author can comment; it defaults
+		// to everyone.
+		ReplyPolicy string `json:"reply_policy" validate:"omitempty,oneof=everyone followers mentioned-only nobody"`
+	}
+
+	CreatePollRequest struct {
+		Options []string `json:"options" validate:"required,min=2,max=4,unique,dive,required,max=25"`
+		// DurationMinutes is how long the poll is open, from five minutes
+		// to seven days.
+		DurationMinutes int  `json:"duration_minutes" validate:"required,min=5,max=10080"`
+		MultipleChoice  bool `json:"multiple_choice"`
 	}
 
 	CreatePostResponse struct {
@@ -15,6 +36,16 @@ type (
 	UpdatePostRequest struct {
 		Title   string `json:"title" validate:"required,min=1,max=100"`
 		Content string `json:"content" validate:"required,min=1"`
+		// Status and PublishAt reschedule or publish a draft or scheduled
+		// post; published posts cannot be unpublished.
+		Status    string     `json:"status" validate:"omitempty,oneof=draft scheduled published"`
+		PublishAt *time.Time `json:"publish_at"`
+		// ReplyPolicy changes who can comment; the current policy is kept
+		// when it is empty.
+		ReplyPolicy string `json:"reply_policy" validate:"omitempty,oneof=everyone followers mentioned-only nobody"`
+		// IfMatch is the request's If-Match header. When set, the update
+		// only applies to a post whose ETag it matches.
+		IfMatch string `json:"-"`
 	}
 
 	UpdatePostResponse struct {
@@ -31,15 +62,102 @@ type (
 		Content   string `json:"content"`
 		LikesCount int   `json:"likes_count"`
 		CommentsCount int `json:"comments_count"`
+		Media []MediaResponse `json:"media"`
+		Poll *PollResponse `json:"poll"`
+		// Bookmarked is whether the authenticated caller bookmarked the
+		// post; always false for anonymous requests.
+		Bookmarked bool `json:"bookmarked"`
+		Status string `json:"status"`
+		// PublishAt is when the post went or goes public; null for drafts.
+		PublishAt *string `json:"publish_at"`
+		// Edited is whether the post changed after publishing; its earlier
+		// versions are listed by GET /posts/:post_id/revisions.
+		Edited    bool `json:"edited"`
+		EditCount int  `json:"edit_count"`
+		// Version is what the post's ETag is made of.
+		Version int `json:"version"`
+		// ReplyPolicy is who besides the author can comment; a locked
+		// thread takes no replies but the author's. CanReply is whether
+		// the caller can comment now; always false for anonymous requests.
+		ReplyPolicy   string `json:"reply_policy"`
+		RepliesLocked bool   `json:"replies_locked"`
+		CanReply      bool   `json:"can_reply"`
 		CreatedAt string `json:"created_at"`
 		UpdatedAt string `json:"updated_at"`
 	}
 
 	PostsResponse struct {
+		// PinnedPost is set on the first page of a user's posts when they
+		// pinned one; Posts and TotalCount leave it out.
+		PinnedPost *PostResponse  `json:"pinned_post,omitempty"`
 		Posts      []PostResponse `json:"posts"`
 		TotalCount int64          `json:"total_count"`
 		Page       int            `json:"page"`
 		PageSize   int            `json:"page_size"`
 		TotalPages int            `json:"total_pages"`
 	}
+
+	PinPostRequest struct {
+		PostID int64 `json:"post_id" validate:"required,min=1"`
+	}
+)
+
+type (
+	VotePollRequest struct {
+		OptionIDs []int64 `json:"option_ids" validate:"required,min=1,max=4,unique"`
+	}
+
+	// PollResponse describes a post's poll. Votes and TotalVoters are null
+	// until the viewer has voted or the poll has closed.
+	PollResponse struct {
+		Options        []PollOptionResponse `json:"options"`
+		MultipleChoice bool                 `json:"multiple_choice"`
+		ClosesAt       string               `json:"closes_at"`
+		Closed         bool                 `json:"closed"`
+		VotedOptionIDs []int64              `json:"voted_option_ids"`
+		TotalVoters    *int                 `json:"total_voters"`
+	}
+
+	PollOptionResponse struct {
+		ID    int64  `json:"id"`
+		Text  string `json:"text"`
+		Votes *int   `json:"votes"`
+	}
+)
+
+type (
+	// PostRevisionResponse is one version of a post. Revisions are numbered
+	// from 1, the version first published; the last is the current one.
+	PostRevisionResponse struct {
+		Revision  int    `json:"revision"`
+		Title     string `json:"title"`
+		Content   string `json:"content"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	PostRevisionsResponse struct {
+		Revisions []PostRevisionResponse `json:"revisions"`
+	}
+)
+
+type (
+	// TrashItemResponse is a post or comment the caller deleted. PostID is
+	// the post a comment was on; title is only set for posts.
+	TrashItemResponse struct {
+		Type            string `json:"type"`
+		ID              int64  `json:"id"`
+		PostID          int64  `json:"post_id"`
+		Title           string `json:"title,omitempty"`
+		Content         string `json:"content"`
+		DeletedAt       string `json:"deleted_at"`
+		RestorableUntil string `json:"restorable_until"`
+	}
+
+	TrashResponse struct {
+		Items      []TrashItemResponse `json:"items"`
+		TotalCount int64               `json:"total_count"`
+		Page       int                 `json:"page"`
+		PageSize   int                 `json:"page_size"`
+		TotalPages int                 `json:"total_pages"`
+	}
 )
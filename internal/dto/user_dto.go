@@ -1,5 +1,6 @@
 package dto
 
+import "time"
 
 type (
 	RegisterRequest struct {
@@ -7,6 +8,8 @@ type (
 		Username string `json:"username" validate:"required,min=3"`
 		Password string `json:"password" validate:"required"`
 		PasswordConfirm string `json:"password_confirm" validate:"required,eqfield=Password"`
+		// InviteCode is required when registration is invite-only.
+		InviteCode string `json:"invite_code"`
 	}
 	
 	RegisterResponse struct {
@@ -17,11 +20,31 @@ type (
 type (
 	LoginRequest struct {
 		Email string `json:"email" validate:"required,email"`
-		Password string `json:"password" validate:"required"`
+		Password string `json:"password" validate:"required_without=Passkey"`
+		// Passkey signs in with a passkey instead of the password.
+		Passkey *PasskeyAssertion `json:"passkey"`
+
+		// set by the handler from the request, not the body
+		IPAddress string `json:"-"`
+		UserAgent string `json:"-"`
 	}
 	LoginResponse struct {
-		Token string `json:"token"`
-		RefreshToken string `json:"refresh_token"`
+		Token string `json:"token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		MFARequired bool `json:"mfa_required,omitempty"`
+		MFAToken string `json:"mfa_token,omitempty"`
+		// Reactivated is set when logging in restored a deactivated account.
+		Reactivated bool `json:"reactivated,omitempty"`
+	}
+
+	LoginMFARequest struct {
+		MFAToken string `json:"mfa_token" validate:"required"`
+		Code string `json:"code" validate:"required_without=Passkey"`
+		// Passkey completes the second step with a passkey instead of a code.
+		Passkey *PasskeyAssertion `json:"passkey"`
+
+		IPAddress string `json:"-"`
+		UserAgent string `json:"-"`
 	}
 )
 
@@ -47,5 +70,230 @@ type (
 		Username string `json:"username"`
 		Email string `json:"email"`
 		CreatedAt string `json:"created_at"`
+		// PinnedPost is the post shown at the top of the user's posts;
+		// null when none is pinned.
+		PinnedPost *PostResponse `json:"pinned_post"`
+		// LastModified is when the profile last changed, sent as the
+		// Last-Modified header.
+		LastModified time.Time `json:"-"`
+	}
+)
+
+type (
+	AssignRoleRequest struct {
+		Role string `json:"role" validate:"required,oneof=admin moderator"`
+	}
+
+	UserRolesResponse struct {
+		Roles []string `json:"roles"`
+	}
+)
+
+type (
+	EnrollTOTPResponse struct {
+		Secret string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
+	ConfirmTOTPRequest struct {
+		Code string `json:"code" validate:"required,len=6,numeric"`
+	}
+
+	ConfirmTOTPResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	DisableTOTPRequest struct {
+		Code string `json:"code" validate:"required"`
+	}
+)
+
+type (
+	LoginAttemptResponse struct {
+		ID int64 `json:"id"`
+		IPAddress string `json:"ip_address"`
+		UserAgent string `json:"user_agent"`
+		Success bool `json:"success"`
+		CreatedAt string `json:"created_at"`
+	}
+
+	LoginAttemptsResponse struct {
+		LoginAttempts []LoginAttemptResponse `json:"login_attempts"`
+		TotalCount int64 `json:"total_count"`
+		Page int `json:"page"`
+		PageSize int `json:"page_size"`
+		TotalPages int `json:"total_pages"`
+	}
+)
+
+type (
+	CreatePersonalAccessTokenRequest struct {
+		Name string `json:"name" validate:"required,max=100"`
+		Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=read posts:write comments:write likes:write bookmarks"`
+		ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+	}
+
+	PersonalAccessTokenResponse struct {
+		ID int64 `json:"id"`
+		Name string `json:"name"`
+		TokenPrefix string `json:"token_prefix"`
+		Scopes []string `json:"scopes"`
+		ExpiresAt string `json:"expires_at"`
+		LastUsedAt *string `json:"last_used_at"`
+		CreatedAt string `json:"created_at"`
 	}
-)
\ No newline at end of file
+
+	CreatePersonalAccessTokenResponse struct {
+		PersonalAccessTokenResponse
+		// Token is only returned once, at creation.
+		Token string `json:"token"`
+	}
+)
+
+type (
+	OIDCAuthorizationResponse struct {
+		AuthorizationURL string `json:"authorization_url"`
+	}
+
+	OIDCCallbackRequest struct {
+		Code string `json:"code" validate:"required"`
+		State string `json:"state" validate:"required"`
+
+		IPAddress string `json:"-"`
+		UserAgent string `json:"-"`
+	}
+)
+
+type (
+	MagicLinkRequest struct {
+		Email string `json:"email" validate:"required,email"`
+
+		// Fingerprint identifies the requesting browser; the link only
+		// works when consumed with the same fingerprint. Set by the handler.
+		Fingerprint string `json:"-"`
+	}
+
+	ConsumeMagicLinkRequest struct {
+		Token string `json:"token" validate:"required"`
+
+		Fingerprint string `json:"-"`
+		IPAddress   string `json:"-"`
+		UserAgent   string `json:"-"`
+	}
+)
+
+// Passkey ceremonies. Binary values are unpadded base64url strings, as in the
+// browser's WebAuthn JSON serialization.
+type (
+	PasskeyRelyingParty struct {
+		ID string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	PasskeyUser struct {
+		ID string `json:"id"`
+		Name string `json:"name"`
+		DisplayName string `json:"displayName"`
+	}
+
+	PasskeyCredentialParameter struct {
+		Type string `json:"type"`
+		Alg int `json:"alg"`
+	}
+
+	PasskeyCredentialDescriptor struct {
+		Type string `json:"type"`
+		ID string `json:"id"`
+	}
+
+	PasskeyAuthenticatorSelection struct {
+		ResidentKey string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	}
+
+	// PasskeyCreationOptions is passed to navigator.credentials.create.
+	PasskeyCreationOptions struct {
+		Challenge string `json:"challenge"`
+		RP PasskeyRelyingParty `json:"rp"`
+		User PasskeyUser `json:"user"`
+		PubKeyCredParams []PasskeyCredentialParameter `json:"pubKeyCredParams"`
+		ExcludeCredentials []PasskeyCredentialDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection PasskeyAuthenticatorSelection `json:"authenticatorSelection"`
+		Attestation string `json:"attestation"`
+		Timeout int64 `json:"timeout"`
+	}
+
+	// PasskeyRequestOptions is passed to navigator.credentials.get.
+	PasskeyRequestOptions struct {
+		Challenge string `json:"challenge"`
+		RPID string `json:"rpId"`
+		AllowCredentials []PasskeyCredentialDescriptor `json:"allowCredentials"`
+		UserVerification string `json:"userVerification"`
+		Timeout int64 `json:"timeout"`
+	}
+
+	BeginPasskeyLoginRequest struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	FinishPasskeyRegistrationRequest struct {
+		Name string `json:"name" validate:"required,max=100"`
+		ID string `json:"id" validate:"required"`
+		ClientDataJSON string `json:"client_data_json" validate:"required"`
+		AttestationObject string `json:"attestation_object" validate:"required"`
+	}
+
+	PasskeyAssertion struct {
+		ID string `json:"id" validate:"required"`
+		ClientDataJSON string `json:"client_data_json" validate:"required"`
+		AuthenticatorData string `json:"authenticator_data" validate:"required"`
+		Signature string `json:"signature" validate:"required"`
+	}
+
+	PasskeyResponse struct {
+		ID int64 `json:"id"`
+		Name string `json:"name"`
+		LastUsedAt *string `json:"last_used_at"`
+		CreatedAt string `json:"created_at"`
+	}
+)
+
+type (
+	CreateInviteRequest struct {
+		// MaxUses above 1 is reserved for admins.
+		MaxUses int `json:"max_uses" validate:"omitempty,min=1,max=1000"`
+		ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=90"`
+	}
+
+	InviteeResponse struct {
+		UserID int64 `json:"user_id"`
+		Username string `json:"username"`
+		JoinedAt string `json:"joined_at"`
+	}
+
+	InviteResponse struct {
+		ID int64 `json:"id"`
+		CodePrefix string `json:"code_prefix"`
+		MaxUses int `json:"max_uses"`
+		Uses int `json:"uses"`
+		ExpiresAt string `json:"expires_at"`
+		RevokedAt *string `json:"revoked_at"`
+		CreatedAt string `json:"created_at"`
+		Invitees []InviteeResponse `json:"invitees"`
+	}
+
+	CreateInviteResponse struct {
+		InviteResponse
+		// Code is only returned once, at creation.
+		Code string `json:"code"`
+	}
+)
+
+type (
+	DeactivateAccountResponse struct {
+		DeactivatedAt string `json:"deactivated_at"`
+		// PurgeAfter is when the account and its content are deleted for
+		// good, unless the user logs in again before then.
+		PurgeAfter string `json:"purge_after"`
+	}
+)
@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
+	"go-twitter/internal/service/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	api            *gin.Engine
+	auditService   audit.AuditService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewHandler(api *gin.Engine, auditService audit.AuditService, authMiddleware *middleware.AuthMiddleware) *Handler {
+	return &Handler{
+		api:            api,
+		auditService:   auditService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+func (h *Handler) RouteList() {
+	auditGroup := h.api.Group("/admin/audit-logs")
+	auditGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopeRead), h.authMiddleware.RequirePermission(model.PermissionReadAuditLog))
+	{
+		auditGroup.GET("", h.GetAuditLogs)
+	}
+}
@@ -22,7 +22,7 @@ func (h *Handler) DeleteComment(c *gin.Context) {
 		return
 	}
 
-	status, err := h.commentService.DeleteComment(c.Request.Context(), int64(userID), commentID)
+	status, err := h.commentService.DeleteComment(c.Request.Context(), int64(userID), commentID, middleware.GetRoles(c))
 	if err != nil {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
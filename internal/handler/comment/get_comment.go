@@ -1,6 +1,7 @@
 package comment
 
 import (
+	"go-twitter/pkg/etag"
 	"net/http"
 	"strconv"
 
@@ -26,5 +27,6 @@ func (h *Handler) GetComment(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", etag.FromVersion(comment.Version))
 	c.JSON(http.StatusOK, comment)
 }
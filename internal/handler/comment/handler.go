@@ -2,7 +2,9 @@ package comment
 
 import (
 	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
 	"go-twitter/internal/service/comment"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -27,9 +29,9 @@ func NewHandler(api *gin.Engine, validate *validator.Validate, commentService co
 func (h *Handler) RouteList() {
 	postCommentsGroup := h.api.Group("/posts/:post_id/comments")
 	{
-		postCommentsGroup.GET("", h.GetComments)
+		postCommentsGroup.GET("", middleware.Cache(middleware.CachePolicy{MaxAge: 10 * time.Second}), h.GetComments)
 
-		postCommentsGroup.Use(h.authMiddleware.RequireAuth())
+		postCommentsGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopeCommentsWrite))
 		{
 			postCommentsGroup.POST("", h.CreateComment)
 		}
@@ -37,12 +39,14 @@ func (h *Handler) RouteList() {
 
 	commentsGroup := h.api.Group("/comments")
 	{
-		commentsGroup.GET("/:comment_id", h.GetComment)
+		commentsGroup.GET("/:comment_id", middleware.Cache(middleware.CachePolicy{MaxAge: 30 * time.Second}), h.GetComment)
+		commentsGroup.GET("/:comment_id/revisions", h.GetCommentRevisions)
 
-		commentsGroup.Use(h.authMiddleware.RequireAuth())
+		commentsGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopeCommentsWrite))
 		{
 			commentsGroup.PUT("/:comment_id", h.UpdateComment)
 			commentsGroup.DELETE("/:comment_id", h.DeleteComment)
+			commentsGroup.POST("/:comment_id/restore", h.RestoreComment)
 		}
 	}
 }
@@ -3,6 +3,7 @@ package comment
 import (
 	"go-twitter/internal/dto"
 	"go-twitter/internal/middleware"
+	"go-twitter/pkg/etag"
 	"net/http"
 	"strconv"
 
@@ -23,6 +24,13 @@ func (h *Handler) UpdateComment(c *gin.Context) {
 		return
 	}
 
+	// edits must name the version they are based on
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the comment's ETag is required"})
+		return
+	}
+
 	var req dto.UpdateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -34,7 +42,12 @@ func (h *Handler) UpdateComment(c *gin.Context) {
 		return
 	}
 
+	req.IfMatch = ifMatch
 	status, err := h.commentService.UpdateComment(c.Request.Context(), int64(userID), commentID, req)
+	if status == http.StatusPreconditionFailed {
+		h.respondCurrentComment(c, commentID)
+		return
+	}
 	if err != nil {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -52,3 +65,20 @@ func (h *Handler) UpdateComment(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.UpdateCommentResponse{ID: commentID})
 }
+
+// respondCurrentComment answers a failed If-Match with the comment as it
+// is now, so the client can merge its changes and retry with the new ETag.
+func (h *Handler) respondCurrentComment(c *gin.Context, commentID int64) {
+	comment, status, err := h.commentService.GetCommentByID(c.Request.Context(), commentID)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{"error": "comment not found"})
+		return
+	}
+
+	c.Header("ETag", etag.FromVersion(comment.Version))
+	c.JSON(http.StatusPreconditionFailed, comment)
+}
@@ -0,0 +1,34 @@
+package export
+
+import (
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/service/export"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	api            *gin.Engine
+	exportService  export.ExportService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewHandler(api *gin.Engine, exportService export.ExportService, authMiddleware *middleware.AuthMiddleware) *Handler {
+	return &Handler{
+		api:            api,
+		exportService:  exportService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+func (h *Handler) RouteList() {
+	exportGroup := h.api.Group("/users/me/export")
+	exportGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+	{
+		exportGroup.POST("", h.RequestDataExport)
+		exportGroup.GET("/:id", h.GetDataExport)
+	}
+
+	// download links are signed, so they work without a session
+	h.api.GET("/exports/:id/download", h.DownloadDataExport)
+}
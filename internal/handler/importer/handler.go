@@ -0,0 +1,34 @@
+package importer
+
+import (
+	"go-twitter/internal/config"
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/service/importer"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	api            *gin.Engine
+	cfg            *config.Config
+	importService  importer.ImportService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewHandler(api *gin.Engine, cfg *config.Config, importService importer.ImportService, authMiddleware *middleware.AuthMiddleware) *Handler {
+	return &Handler{
+		api:            api,
+		cfg:            cfg,
+		importService:  importService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+func (h *Handler) RouteList() {
+	importGroup := h.api.Group("/imports")
+	importGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+	{
+		importGroup.POST("/twitter", h.StartTwitterImport)
+		importGroup.GET("/:id", h.GetImportJob)
+	}
+}
@@ -2,6 +2,7 @@ package like
 
 import (
 	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
 	"go-twitter/internal/service/like"
 
 	"github.com/gin-gonic/gin"
@@ -25,16 +26,20 @@ func (h *Handler) RouteList() {
 	postLikesGroup := h.api.Group("/posts/:post_id/likes")
 	postLikesGroup.Use(h.authMiddleware.RequireAuth())
 	{
+		postLikesGroup.GET("/count", h.authMiddleware.RequireScope(model.ScopeRead), h.GetPostLikesCount)
+
+		postLikesGroup.Use(h.authMiddleware.RequireScope(model.ScopeLikesWrite))
 		postLikesGroup.POST("", h.LikePost)
 		postLikesGroup.DELETE("", h.UnlikePost)
-		postLikesGroup.GET("/count", h.GetPostLikesCount)
 	}
 
 	commentLikesGroup := h.api.Group("/comments/:comment_id/likes")
 	commentLikesGroup.Use(h.authMiddleware.RequireAuth())
 	{
+		commentLikesGroup.GET("/count", h.authMiddleware.RequireScope(model.ScopeRead), h.GetCommentLikesCount)
+
+		commentLikesGroup.Use(h.authMiddleware.RequireScope(model.ScopeLikesWrite))
 		commentLikesGroup.POST("", h.LikeComment)
 		commentLikesGroup.DELETE("", h.UnlikeComment)
-		commentLikesGroup.GET("/count", h.GetCommentLikesCount)
 	}
 }
@@ -0,0 +1,29 @@
+package media
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetMedia(c *gin.Context) {
+	mediaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid media id"})
+		return
+	}
+
+	file, media, status, err := h.mediaService.OpenMedia(c.Request.Context(), mediaID, c.Query("variant"))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	// the content type was sniffed on upload; don't let browsers guess
+	// another one
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.DataFromReader(http.StatusOK, media.SizeBytes, media.ContentType, file, nil)
+}
@@ -0,0 +1,37 @@
+package media
+
+import (
+	"go-twitter/internal/config"
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
+	"go-twitter/internal/service/media"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type Handler struct {
+	api            *gin.Engine
+	cfg            *config.Config
+	validate       *validator.Validate
+	mediaService   media.MediaService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewHandler(api *gin.Engine, cfg *config.Config, validate *validator.Validate, mediaService media.MediaService, authMiddleware *middleware.AuthMiddleware) *Handler {
+	return &Handler{
+		api:            api,
+		cfg:            cfg,
+		validate:       validate,
+		mediaService:   mediaService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+func (h *Handler) RouteList() {
+	mediaGroup := h.api.Group("/media")
+	{
+		mediaGroup.GET("/:id", h.GetMedia)
+		mediaGroup.POST("", h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopePostsWrite), h.UploadMedia)
+	}
+}
@@ -0,0 +1,57 @@
+package media
+
+import (
+	"errors"
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) UploadMedia(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// the service checks the per-type limit; this caps the request at the
+	// largest of them, with room for the multipart framing
+	maxImageSize, maxVideoSize, _, _ := h.cfg.Media()
+	maxSize := max(maxImageSize, maxVideoSize)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("file is larger than %d MB", maxSize>>20)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	req := dto.UploadMediaRequest{AltText: c.PostForm("alt_text")}
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	defer file.Close()
+
+	response, status, err := h.mediaService.UploadMedia(c.Request.Context(), int64(userID), file, header.Size, req)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
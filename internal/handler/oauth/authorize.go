@@ -0,0 +1,61 @@
+package oauth
+
+import (
+	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuthorization validates the query of an authorization request and
+// returns the consent details for the signed-in user to approve or deny.
+func (h *Handler) GetAuthorization(c *gin.Context) {
+	var req dto.AuthorizeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	response, status, err := h.oauthService.GetAuthorization(c.Request.Context(), req)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Authorize records the user's decision and returns the URL to redirect the
+// user agent to.
+func (h *Handler) Authorize(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req dto.AuthorizeDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	response, status, err := h.oauthService.Authorize(c.Request.Context(), int64(userID), req)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
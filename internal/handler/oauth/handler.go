@@ -0,0 +1,73 @@
+package oauth
+
+import (
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/service/oauth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type Handler struct {
+	api            *gin.Engine
+	validate       *validator.Validate
+	oauthService   oauth.OAuthService
+	authMiddleware *middleware.AuthMiddleware
+}
+
+func NewHandler(api *gin.Engine, validate *validator.Validate, oauthService oauth.OAuthService, authMiddleware *middleware.AuthMiddleware) *Handler {
+	return &Handler{
+		api:            api,
+		validate:       validate,
+		oauthService:   oauthService,
+		authMiddleware: authMiddleware,
+	}
+}
+
+func (h *Handler) RouteList() {
+	oauthGroup := h.api.Group("/oauth")
+	{
+		// client-authenticated endpoints, called by the third-party app
+		oauthGroup.POST("/token", h.Token)
+		oauthGroup.POST("/introspect", h.Introspect)
+		oauthGroup.POST("/revoke", h.Revoke)
+
+		oauthGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+		{
+			oauthGroup.POST("/clients", h.RegisterClient)
+			oauthGroup.GET("/authorize", h.GetAuthorization)
+			oauthGroup.POST("/authorize", h.Authorize)
+		}
+	}
+}
+
+// clientCredentials prefers HTTP Basic auth (client_secret_basic) and falls
+// back to the client_id and client_secret form fields (client_secret_post).
+func clientCredentials(c *gin.Context) dto.OAuthClientCredentials {
+	if clientID, clientSecret, ok := c.Request.BasicAuth(); ok {
+		return dto.OAuthClientCredentials{ClientID: clientID, ClientSecret: clientSecret}
+	}
+	return dto.OAuthClientCredentials{
+		ClientID:     c.PostForm("client_id"),
+		ClientSecret: c.PostForm("client_secret"),
+	}
+}
+
+func writeError(c *gin.Context, status int, err error) {
+	var oauthErr *oauth.Error
+	if errors.As(err, &oauthErr) {
+		if oauthErr.Code == oauth.ErrInvalidClient && status == http.StatusUnauthorized {
+			c.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		c.JSON(status, gin.H{"error": oauthErr.Code, "error_description": oauthErr.Description})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func invalidRequest(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": oauth.ErrInvalidRequest, "error_description": err.Error()})
+}
@@ -0,0 +1,425 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
+	oauthRepo "go-twitter/internal/repository/oauth"
+	userRepo "go-twitter/internal/repository/user"
+	oauthService "go-twitter/internal/service/oauth"
+	"go-twitter/pkg/jwt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type memoryOAuthRepository struct {
+	clients       []*model.OAuthClientModel
+	codes         []*model.OAuthAuthorizationCodeModel
+	refreshTokens []*model.OAuthRefreshTokenModel
+	revoked       map[string]time.Time
+}
+
+func (r *memoryOAuthRepository) CreateClient(ctx context.Context, client *model.OAuthClientModel) (int64, error) {
+	r.clients = append(r.clients, client)
+	return int64(len(r.clients)), nil
+}
+
+func (r *memoryOAuthRepository) GetClientByClientID(ctx context.Context, clientID string) (*model.OAuthClientModel, error) {
+	for _, c := range r.clients {
+		if c.ClientID == clientID {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryOAuthRepository) CreateAuthorizationCode(ctx context.Context, code *model.OAuthAuthorizationCodeModel) error {
+	r.codes = append(r.codes, code)
+	return nil
+}
+
+func (r *memoryOAuthRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string, now time.Time) (*model.OAuthAuthorizationCodeModel, error) {
+	for _, c := range r.codes {
+		if c.CodeHash == codeHash && !c.UsedAt.Valid && now.Before(c.ExpiresAt) {
+			c.UsedAt.Time, c.UsedAt.Valid = now, true
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryOAuthRepository) CreateRefreshToken(ctx context.Context, token *model.OAuthRefreshTokenModel) error {
+	token.ID = int64(len(r.refreshTokens) + 1)
+	r.refreshTokens = append(r.refreshTokens, token)
+	return nil
+}
+
+func (r *memoryOAuthRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.OAuthRefreshTokenModel, error) {
+	for _, t := range r.refreshTokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryOAuthRepository) RevokeRefreshToken(ctx context.Context, id int64, now time.Time) (bool, error) {
+	for _, t := range r.refreshTokens {
+		if t.ID == id && !t.RevokedAt.Valid {
+			t.RevokedAt.Time, t.RevokedAt.Valid = now, true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryOAuthRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *memoryOAuthRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
+
+var _ oauthRepo.OAuthRepository = (*memoryOAuthRepository)(nil)
+
+// memoryUserRepository serves the single user the tests act as. Other
+// methods fall through to the embedded nil interface.
+type memoryUserRepository struct {
+	userRepo.UserRepository
+	deactivated bool
+}
+
+func (r *memoryUserRepository) GetUserByID(ctx context.Context, id int64) (*model.UserModel, error) {
+	if id != 1 {
+		return nil, nil
+	}
+	user := &model.UserModel{ID: 1, Username: "alice", Email: "alice@example.com"}
+	user.DeactivatedAt = sql.NullTime{Time: time.Now(), Valid: r.deactivated}
+	return user, nil
+}
+
+type testServer struct {
+	router  *gin.Engine
+	repo    *memoryOAuthRepository
+	users   *memoryUserRepository
+	session string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	cfg := &config.Config{SecreetJwt: "test-secret", JwtIssuer: config.DefaultJwtIssuer, JwtAudience: config.DefaultJwtAudience}
+	repo := &memoryOAuthRepository{revoked: map[string]time.Time{}}
+	users := &memoryUserRepository{}
+
+	r := gin.New()
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTOptions(), nil, repo)
+	NewHandler(r, validator.New(), oauthService.NewService(cfg, repo, users), authMiddleware).RouteList()
+
+	// stands in for a resource route such as POST /posts
+	r.POST("/resource", authMiddleware.RequireAuth(), authMiddleware.RequireScope(model.ScopePostsWrite), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	session, err := jwt.CreateToken(1, "alice", nil, cfg.JWTOptions())
+	if err != nil {
+		t.Fatalf("Failed to create session token: %v", err)
+	}
+	return &testServer{router: r, repo: repo, users: users, session: session}
+}
+
+func (s *testServer) doJSON(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// doForm authenticates confidential clients with HTTP Basic auth and public
+// clients with the client_id form field.
+func (s *testServer) doForm(path string, creds dto.OAuthClientCredentials, form url.Values) *httptest.ResponseRecorder {
+	if creds.ClientSecret == "" {
+		form.Set("client_id", creds.ClientID)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if creds.ClientSecret != "" {
+		req.SetBasicAuth(creds.ClientID, creds.ClientSecret)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("Failed to decode %s: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+const redirectURI = "https://app.example.com/callback"
+
+func (s *testServer) registerClient(t *testing.T, confidential bool) dto.OAuthClientCredentials {
+	t.Helper()
+	w := s.doJSON(http.MethodPost, "/oauth/clients", s.session, dto.RegisterOAuthClientRequest{
+		Name:         "Scheduler",
+		RedirectURIs: []string{redirectURI},
+		Scopes:       []string{model.ScopeRead, model.ScopePostsWrite},
+		Confidential: confidential,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	client := decode[dto.OAuthClientResponse](t, w)
+	return dto.OAuthClientCredentials{ClientID: client.ClientID, ClientSecret: client.ClientSecret}
+}
+
+func pkcePair() (string, string) {
+	verifier := strings.Repeat("v", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// authorize runs the consent step and returns the authorization code.
+func (s *testServer) authorize(t *testing.T, clientID, challenge, scope string) string {
+	t.Helper()
+	w := s.doJSON(http.MethodPost, "/oauth/authorize", s.session, dto.AuthorizeDecisionRequest{
+		AuthorizeRequest: dto.AuthorizeRequest{
+			ResponseType:        "code",
+			ClientID:            clientID,
+			RedirectURI:         redirectURI,
+			Scope:               scope,
+			State:               "xyz",
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: "S256",
+		},
+		Approved: true,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	redirect, err := url.Parse(decode[dto.AuthorizeDecisionResponse](t, w).RedirectTo)
+	if err != nil {
+		t.Fatalf("Failed to parse redirect: %v", err)
+	}
+	if redirect.Query().Get("state") != "xyz" {
+		t.Errorf("Expected state to round-trip, got %q", redirect.Query().Get("state"))
+	}
+	return redirect.Query().Get("code")
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	s := newTestServer(t)
+	client := s.registerClient(t, true)
+	verifier, challenge := pkcePair()
+
+	w := s.doJSON(http.MethodGet, "/oauth/authorize?"+url.Values{
+		"response_type":         {"code"},
+		"client_id":             {client.ClientID},
+		"redirect_uri":          {redirectURI},
+		"scope":                 {"posts:write"},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}.Encode(), s.session, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if consent := decode[dto.AuthorizeConsentResponse](t, w); consent.ClientName != "Scheduler" || len(consent.Scopes) != 1 {
+		t.Errorf("Unexpected consent details: %+v", consent)
+	}
+
+	code := s.authorize(t, client.ClientID, challenge, "posts:write")
+
+	w = s.doForm("/oauth/token", client, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Error("Expected token response not to be cached")
+	}
+	tokens := decode[dto.TokenResponse](t, w)
+	if tokens.Scope != "posts:write" || tokens.TokenType != "Bearer" || tokens.RefreshToken == "" {
+		t.Errorf("Unexpected token response: %+v", tokens)
+	}
+
+	if w := s.doJSON(http.MethodPost, "/resource", tokens.AccessToken, nil); w.Code != http.StatusOK {
+		t.Errorf("Expected access token to reach the resource, got %d", w.Code)
+	}
+	if w := s.doJSON(http.MethodPost, "/oauth/clients", tokens.AccessToken, dto.RegisterOAuthClientRequest{}); w.Code != http.StatusForbidden {
+		t.Errorf("Expected OAuth access token to be refused on session routes, got %d", w.Code)
+	}
+
+	// codes are single use
+	w = s.doForm("/oauth/token", client, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+	if w.Code != http.StatusBadRequest || decode[map[string]string](t, w)["error"] != "invalid_grant" {
+		t.Errorf("Expected reused code to be rejected with invalid_grant, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTokenRejectsWrongVerifier(t *testing.T) {
+	s := newTestServer(t)
+	client := s.registerClient(t, false)
+	_, challenge := pkcePair()
+	code := s.authorize(t, client.ClientID, challenge, "")
+
+	w := s.doForm("/oauth/token", client, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {strings.Repeat("x", 43)},
+	})
+	if w.Code != http.StatusBadRequest || decode[map[string]string](t, w)["error"] != "invalid_grant" {
+		t.Errorf("Expected invalid_grant, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTokenRejectsDeactivatedUser(t *testing.T) {
+	s := newTestServer(t)
+	client := s.registerClient(t, false)
+	verifier, challenge := pkcePair()
+	code := s.authorize(t, client.ClientID, challenge, "")
+	s.users.deactivated = true
+
+	w := s.doForm("/oauth/token", client, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+	if w.Code != http.StatusBadRequest || decode[map[string]string](t, w)["error"] != "invalid_grant" {
+		t.Errorf("Expected invalid_grant for a deactivated user, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAuthorizeRequiresPKCE(t *testing.T) {
+	s := newTestServer(t)
+	client := s.registerClient(t, false)
+
+	w := s.doJSON(http.MethodGet, "/oauth/authorize?"+url.Values{
+		"response_type": {"code"},
+		"client_id":     {client.ClientID},
+		"redirect_uri":  {redirectURI},
+	}.Encode(), s.session, nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRefreshIntrospectAndRevoke(t *testing.T) {
+	s := newTestServer(t)
+	client := s.registerClient(t, true)
+	verifier, challenge := pkcePair()
+	code := s.authorize(t, client.ClientID, challenge, "read posts:write")
+
+	tokens := decode[dto.TokenResponse](t, s.doForm("/oauth/token", client, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	}))
+
+	// refresh, narrowing the access token to read
+	w := s.doForm("/oauth/token", client, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {tokens.RefreshToken},
+		"scope":         {"read"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	refreshed := decode[dto.TokenResponse](t, w)
+	if refreshed.Scope != "read" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Errorf("Expected narrowed scope and a rotated refresh token, got %+v", refreshed)
+	}
+	if w := s.doForm("/oauth/token", client, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tokens.RefreshToken}}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected rotated-out refresh token to be rejected, got %d", w.Code)
+	}
+
+	introspect := func(token string) dto.IntrospectionResponse {
+		w := s.doForm("/oauth/introspect", client, url.Values{"token": {token}})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		return decode[dto.IntrospectionResponse](t, w)
+	}
+
+	if info := introspect(refreshed.AccessToken); !info.Active || info.Scope != "read" || info.ClientID != client.ClientID || info.Username != "alice" {
+		t.Errorf("Unexpected access token introspection: %+v", info)
+	}
+	if info := introspect(refreshed.RefreshToken); !info.Active || info.TokenType != "refresh_token" || info.Scope != "read posts:write" {
+		t.Errorf("Unexpected refresh token introspection: %+v", info)
+	}
+	if info := introspect("not-a-token"); info.Active {
+		t.Error("Expected unknown token to be inactive")
+	}
+
+	for _, token := range []string{refreshed.AccessToken, refreshed.RefreshToken} {
+		if w := s.doForm("/oauth/revoke", client, url.Values{"token": {token}}); w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if info := introspect(token); info.Active {
+			t.Errorf("Expected revoked token to be inactive, got %+v", info)
+		}
+	}
+
+	if w := s.doJSON(http.MethodPost, "/resource", refreshed.AccessToken, nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked access token to be rejected, got %d", w.Code)
+	}
+}
+
+func TestIntrospectRequiresConfidentialClient(t *testing.T) {
+	s := newTestServer(t)
+	public := s.registerClient(t, false)
+
+	w := s.doForm("/oauth/introspect", public, url.Values{"token": {"anything"}})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	wrongSecret := s.registerClient(t, true)
+	wrongSecret.ClientSecret = "wrong"
+	if w := s.doForm("/oauth/introspect", wrongSecret, url.Values{"token": {"anything"}}); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,36 @@
+package oauth
+
+import (
+	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) RegisterClient(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req dto.RegisterOAuthClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, status, err := h.oauthService.RegisterClient(c.Request.Context(), int64(userID), req)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"go-twitter/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) Token(c *gin.Context) {
+	// token responses must never be cached (RFC 6749 section 5.1)
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+
+	var req dto.TokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	response, status, err := h.oauthService.Token(c.Request.Context(), clientCredentials(c), req)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) Introspect(c *gin.Context) {
+	var req dto.TokenActionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	response, status, err := h.oauthService.Introspect(c.Request.Context(), clientCredentials(c), req)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) Revoke(c *gin.Context) {
+	var req dto.TokenActionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	status, err := h.oauthService.Revoke(c.Request.Context(), clientCredentials(c), req)
+	if err != nil {
+		writeError(c, status, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -0,0 +1,94 @@
+package post
+
+import (
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) BookmarkPost(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	// the body is optional
+	var req dto.BookmarkPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.postService.BookmarkPost(c.Request.Context(), int64(userID), postID, req)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(status, dto.BookmarkResponse{Message: "post bookmarked successfully"})
+}
+
+func (h *Handler) RemoveBookmark(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	postID, err := strconv.ParseInt(c.Param("post_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	status, err := h.postService.RemoveBookmark(c.Request.Context(), int64(userID), postID)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.BookmarkResponse{Message: "bookmark removed successfully"})
+}
+
+func (h *Handler) GetBookmarks(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var folderID int64
+	if folderIDStr := c.Query("folder_id"); folderIDStr != "" {
+		var err error
+		folderID, err = strconv.ParseInt(folderIDStr, 10, 64)
+		if err != nil || folderID < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid folder_id"})
+			return
+		}
+	}
+
+	bookmarks, status, err := h.postService.GetBookmarks(c.Request.Context(), int64(userID), folderID, c.Query("cursor"), limit)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, bookmarks)
+}
@@ -22,7 +22,7 @@ func (h *Handler) DeletePost(c *gin.Context) {
 		return
 	}
 
-	status, err := h.postService.DeletePost(c.Request.Context(), int64(userID), postID)
+	status, err := h.postService.DeletePost(c.Request.Context(), int64(userID), postID, middleware.GetRoles(c))
 	if err != nil {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -1,6 +1,8 @@
 package post
 
 import (
+	"go-twitter/internal/middleware"
+	"go-twitter/pkg/etag"
 	"net/http"
 	"strconv"
 
@@ -15,7 +17,9 @@ func (h *Handler) GetPost(c *gin.Context) {
 		return
 	}
 
-	post, status, err := h.postService.GetPostByID(c.Request.Context(), postID)
+	// anonymous viewers get user ID 0
+	viewerID, _ := middleware.GetUserID(c)
+	post, status, err := h.postService.GetPostByID(c.Request.Context(), postID, int64(viewerID))
 	if err != nil {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -26,5 +30,6 @@ func (h *Handler) GetPost(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", etag.FromVersion(post.Version))
 	c.JSON(http.StatusOK, post)
 }
@@ -2,6 +2,7 @@ package post
 
 import (
 	"go-twitter/internal/dto"
+	"go-twitter/internal/middleware"
 	"net/http"
 	"strconv"
 
@@ -23,6 +24,9 @@ func (h *Handler) GetPosts(c *gin.Context) {
 		pageSize = 10
 	}
 
+	// anonymous viewers get user ID 0
+	viewerID, _ := middleware.GetUserID(c)
+
 	var posts *dto.PostsResponse
 	var status int
 	var err error
@@ -33,9 +37,9 @@ func (h *Handler) GetPosts(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id"})
 			return
 		}
-		posts, status, err = h.postService.GetPostsByUserID(c.Request.Context(), userID, page, pageSize)
+		posts, status, err = h.postService.GetPostsByUserID(c.Request.Context(), userID, int64(viewerID), page, pageSize)
 	} else {
-		posts, status, err = h.postService.GetPosts(c.Request.Context(), page, pageSize)
+		posts, status, err = h.postService.GetPosts(c.Request.Context(), int64(viewerID), page, pageSize)
 	}
 
 	if err != nil {
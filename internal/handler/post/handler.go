@@ -2,7 +2,9 @@ package post
 
 import (
 	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
 	"go-twitter/internal/service/post"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -27,14 +29,41 @@ func NewHandler(api *gin.Engine, validate *validator.Validate, postService post.
 func (h *Handler) RouteList() {
 	postGroup := h.api.Group("/posts")
 	{
-		postGroup.GET("", h.GetPosts)
-		postGroup.GET("/:post_id", h.GetPost)
+		// feeds change with every new post, single posts mostly with likes
+		postGroup.GET("", h.authMiddleware.OptionalAuth(), middleware.Cache(middleware.CachePolicy{MaxAge: 10 * time.Second}), h.GetPosts)
+		postGroup.GET("/:post_id", h.authMiddleware.OptionalAuth(), middleware.Cache(middleware.CachePolicy{MaxAge: 30 * time.Second}), h.GetPost)
+		postGroup.GET("/:post_id/revisions", h.GetPostRevisions)
 
-		postGroup.Use(h.authMiddleware.RequireAuth())
+		postGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopePostsWrite))
 		{
 			postGroup.POST("", h.CreatePost)
+			postGroup.GET("/drafts", h.GetDrafts)
 			postGroup.PUT("/:post_id", h.UpdatePost)
 			postGroup.DELETE("/:post_id", h.DeletePost)
+			postGroup.POST("/:post_id/restore", h.RestorePost)
+			postGroup.POST("/:post_id/lock", h.LockReplies)
+			postGroup.DELETE("/:post_id/lock", h.UnlockReplies)
+			postGroup.POST("/:post_id/poll/votes", h.VotePoll)
 		}
 	}
+
+	h.api.GET("/users/me/trash", h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopeRead), h.GetTrash)
+
+	requirePostsWrite := []gin.HandlerFunc{h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopePostsWrite)}
+	h.api.POST("/users/me/pinned-post", append(requirePostsWrite, h.PinPost)...)
+	h.api.DELETE("/users/me/pinned-post", append(requirePostsWrite, h.UnpinPost)...)
+
+	// bookmarks are private, so tokens need their own scope
+	requireBookmarks := []gin.HandlerFunc{h.authMiddleware.RequireAuth(), h.authMiddleware.RequireScope(model.ScopeBookmarks)}
+	h.api.POST("/posts/:post_id/bookmark", append(requireBookmarks, h.BookmarkPost)...)
+	h.api.DELETE("/posts/:post_id/bookmark", append(requireBookmarks, h.RemoveBookmark)...)
+
+	bookmarkGroup := h.api.Group("/bookmarks")
+	bookmarkGroup.Use(requireBookmarks...)
+	{
+		bookmarkGroup.GET("", h.GetBookmarks)
+		bookmarkGroup.GET("/folders", h.GetBookmarkFolders)
+		bookmarkGroup.POST("/folders", h.CreateBookmarkFolder)
+		bookmarkGroup.DELETE("/folders/:folder_id", h.DeleteBookmarkFolder)
+	}
 }
@@ -0,0 +1,40 @@
+package post
+
+import (
+	"go-twitter/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) LockReplies(c *gin.Context) {
+	h.setRepliesLocked(c, true, "replies locked successfully")
+}
+
+func (h *Handler) UnlockReplies(c *gin.Context) {
+	h.setRepliesLocked(c, false, "replies unlocked successfully")
+}
+
+func (h *Handler) setRepliesLocked(c *gin.Context, locked bool, message string) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	postIDStr := c.Param("post_id")
+	postID, err := strconv.ParseInt(postIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid post id"})
+		return
+	}
+
+	status, err := h.postService.SetRepliesLocked(c.Request.Context(), int64(userID), postID, locked)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
@@ -3,6 +3,7 @@ package post
 import (
 	"go-twitter/internal/dto"
 	"go-twitter/internal/middleware"
+	"go-twitter/pkg/etag"
 	"net/http"
 	"strconv"
 
@@ -23,6 +24,13 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	// edits must name the version they are based on
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header with the post's ETag is required"})
+		return
+	}
+
 	var req dto.UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -34,7 +42,12 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	req.IfMatch = ifMatch
 	status, err := h.postService.UpdatePost(c.Request.Context(), int64(userID), postID, req)
+	if status == http.StatusPreconditionFailed {
+		h.respondCurrentPost(c, postID, int64(userID))
+		return
+	}
 	if err != nil {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
@@ -52,3 +65,20 @@ func (h *Handler) UpdatePost(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.UpdatePostResponse{ID: postID})
 }
+
+// respondCurrentPost answers a failed If-Match with the post as it is now,
+// so the client can merge its changes and retry with the new ETag.
+func (h *Handler) respondCurrentPost(c *gin.Context, postID, viewerID int64) {
+	post, status, err := h.postService.GetPostByID(c.Request.Context(), postID, viewerID)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{"error": "post not found"})
+		return
+	}
+
+	c.Header("ETag", etag.FromVersion(post.Version))
+	c.JSON(http.StatusPreconditionFailed, post)
+}
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"go-twitter/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) DeactivateAccount(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	response, status, err := h.userService.DeactivateAccount(c.Request.Context(), int64(userID))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"go-twitter/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) FollowUser(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	followeeIDStr := c.Param("id")
+	followeeID, err := strconv.ParseInt(followeeIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	status, err := h.userService.FollowUser(c.Request.Context(), int64(userID), followeeID)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status == http.StatusConflict {
+		c.JSON(status, gin.H{"error": "user already followed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "user followed successfully"})
+}
+
+func (h *Handler) UnfollowUser(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	followeeIDStr := c.Param("id")
+	followeeID, err := strconv.ParseInt(followeeIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	status, err := h.userService.UnfollowUser(c.Request.Context(), int64(userID), followeeID)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status == http.StatusNotFound {
+		c.JSON(status, gin.H{"error": "user not followed yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unfollowed successfully"})
+}
@@ -26,5 +26,19 @@ func (h *Handler) GetUser(c *gin.Context) {
 		return
 	}
 
+	// the profile is cached for everyone, so the pinned post is shown as
+	// anonymous visitors see it
+	pinnedPost, status, err := h.postService.GetPinnedPost(c.Request.Context(), userID, 0)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	user.PinnedPost = pinnedPost
+
+	// likes and comments change the pinned post without a timestamp, so
+	// only the ETag tells whether it changed
+	if pinnedPost == nil {
+		c.Header("Last-Modified", user.LastModified.UTC().Format(http.TimeFormat))
+	}
 	c.JSON(http.StatusOK, user)
 }
@@ -1,7 +1,11 @@
 package user
 
 import (
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
+	"go-twitter/internal/service/post"
 	"go-twitter/internal/service/user"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -11,12 +15,16 @@ type Handler struct {
 	api *gin.Engine
 	validate *validator.Validate
 	userService user.UserService
+	postService post.PostService
+	authMiddleware *middleware.AuthMiddleware
 }
-func NewHandler(api *gin.Engine, validate *validator.Validate, userService user.UserService) *Handler {
+func NewHandler(api *gin.Engine, validate *validator.Validate, userService user.UserService, postService post.PostService, authMiddleware *middleware.AuthMiddleware) *Handler {
 	return &Handler{
 		api:         api,
 		validate:    validate,
 		userService: userService,
+		postService: postService,
+		authMiddleware: authMiddleware,
 	}
 }
 
@@ -27,10 +35,64 @@ func (h *Handler) RouteList() {
 		authGroup.POST("/login", h.Login)
 		authGroup.POST("/refresh", h.RefreshToken)
 		authGroup.POST("/logout", h.Logout)
+		authGroup.POST("/login/mfa", h.LoginMFA)
+		authGroup.GET("/oidc/:provider", h.StartOIDCLogin)
+		authGroup.POST("/oidc/:provider/callback", h.CompleteOIDCLogin)
+		authGroup.POST("/magic-link", h.RequestMagicLink)
+		authGroup.POST("/magic-link/consume", h.ConsumeMagicLink)
+		authGroup.POST("/passkeys/login/begin", h.BeginPasskeyLogin)
+
+		twoFactorGroup := authGroup.Group("/2fa")
+		twoFactorGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+		{
+			twoFactorGroup.POST("/enroll", h.EnrollTOTP)
+			twoFactorGroup.POST("/confirm", h.ConfirmTOTP)
+			twoFactorGroup.POST("/disable", h.DisableTOTP)
+		}
+
+		tokenGroup := authGroup.Group("/tokens")
+		tokenGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+		{
+			tokenGroup.POST("", h.CreatePersonalAccessToken)
+			tokenGroup.GET("", h.GetPersonalAccessTokens)
+			tokenGroup.DELETE("/:id", h.DeletePersonalAccessToken)
+		}
+
+		passkeyGroup := authGroup.Group("/passkeys")
+		passkeyGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+		{
+			passkeyGroup.POST("/register/begin", h.BeginPasskeyRegistration)
+			passkeyGroup.POST("/register/finish", h.FinishPasskeyRegistration)
+			passkeyGroup.GET("", h.GetPasskeys)
+			passkeyGroup.DELETE("/:id", h.DeletePasskey)
+		}
+	}
+
+	inviteGroup := h.api.Group("/invites")
+	inviteGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+	{
+		inviteGroup.POST("", h.CreateInvite)
+		inviteGroup.GET("", h.GetInvites)
+		inviteGroup.DELETE("/:id", h.RevokeInvite)
+	}
+
+	accountGroup := h.api.Group("/users/me")
+	accountGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession())
+	{
+		accountGroup.GET("/login-attempts", h.GetLoginAttempts)
+		accountGroup.POST("/deactivate", h.DeactivateAccount)
 	}
 
 	userGroup := h.api.Group("/users")
 	{
-		userGroup.GET("/:id", h.GetUser)
+		userGroup.GET("/:id", middleware.Cache(middleware.CachePolicy{MaxAge: time.Minute}), h.GetUser)
+		userGroup.POST("/:id/follow", h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession(), h.FollowUser)
+		userGroup.DELETE("/:id/follow", h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession(), h.UnfollowUser)
+
+		userGroup.Use(h.authMiddleware.RequireAuth(), h.authMiddleware.RequireSession(), h.authMiddleware.RequirePermission(model.PermissionManageRoles))
+		{
+			userGroup.POST("/:id/roles", h.AssignRole)
+			userGroup.DELETE("/:id/roles/:role", h.RemoveRole)
+		}
 	}
 }
\ No newline at end of file
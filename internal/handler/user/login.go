@@ -23,14 +23,42 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	token, refreshToken, status, err := h.userService.Login(ctx, req)
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	response, status, err := h.userService.Login(ctx, req)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) LoginMFA(c *gin.Context) {
+	var (
+		ctx = c.Request.Context()
+		req dto.LoginMFARequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	response, status, err := h.userService.LoginMFA(ctx, req)
 	if err != nil {
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.LoginResponse{
-		Token: token,
-		RefreshToken: refreshToken,
-	})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,865 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	postHandler "go-twitter/internal/handler/post"
+	"go-twitter/internal/middleware"
+	"go-twitter/internal/model"
+	postRepo "go-twitter/internal/repository/post"
+	userRepo "go-twitter/internal/repository/user"
+	postService "go-twitter/internal/service/post"
+	userService "go-twitter/internal/service/user"
+	tokenjwt "go-twitter/pkg/jwt"
+	"go-twitter/pkg/mailer"
+	"go-twitter/pkg/oidc"
+	"go-twitter/pkg/oidc/oidctest"
+	"go-twitter/pkg/webauthn"
+	"go-twitter/pkg/webauthn/webauthntest"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// memoryUserRepository keeps users and refresh tokens in memory. Methods the
+// login flow does not touch fall through to the embedded nil interface.
+type memoryUserRepository struct {
+	userRepo.UserRepository
+	users         []*model.UserModel
+	refreshTokens []*model.RefreshTokenModel
+	loginAttempts []*model.LoginAttemptModel
+	accessTokens  []*model.PersonalAccessTokenModel
+	identities    []*model.UserIdentityModel
+	oidcStates    []*model.OIDCLoginStateModel
+	magicLinks    []*model.MagicLinkTokenModel
+	passkeys      []*model.WebAuthnCredentialModel
+	challenges    []*model.WebAuthnChallengeModel
+}
+
+func (r *memoryUserRepository) GetUserByEmailOrUsername(ctx context.Context, email, username string) (*model.UserModel, error) {
+	for _, u := range r.users {
+		if u.Email == email || u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) CreateUser(ctx context.Context, user *model.UserModel) (int64, error) {
+	user.ID = int64(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return user.ID, nil
+}
+
+func (r *memoryUserRepository) GetUserRoles(ctx context.Context, userID int64) ([]string, error) {
+	return nil, nil
+}
+
+func (r *memoryUserRepository) GetUserTOTP(ctx context.Context, userID int64) (*model.UserTOTPModel, error) {
+	return nil, nil
+}
+
+func (r *memoryUserRepository) GetRefreshToken(ctx context.Context, id int64, now time.Time) (*model.RefreshTokenModel, error) {
+	for _, t := range r.refreshTokens {
+		if t.UserID == id && !t.ExpiresAt.Before(now) {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) StoreRefreshToken(ctx context.Context, token *model.RefreshTokenModel) error {
+	r.refreshTokens = append(r.refreshTokens, token)
+	return nil
+}
+
+func (r *memoryUserRepository) CreateLoginAttempt(ctx context.Context, attempt *model.LoginAttemptModel) error {
+	attempt.ID = int64(len(r.loginAttempts) + 1)
+	r.loginAttempts = append(r.loginAttempts, attempt)
+	return nil
+}
+
+func (r *memoryUserRepository) GetFailedLoginsByEmail(ctx context.Context, email string, since time.Time) (int, time.Time, error) {
+	return r.failedLogins(since, func(a *model.LoginAttemptModel) bool { return a.Email == email }, true)
+}
+
+func (r *memoryUserRepository) GetFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int, time.Time, error) {
+	return r.failedLogins(since, func(a *model.LoginAttemptModel) bool { return a.IPAddress == ip }, false)
+}
+
+func (r *memoryUserRepository) failedLogins(since time.Time, match func(*model.LoginAttemptModel) bool, resetOnSuccess bool) (int, time.Time, error) {
+	var count int
+	var last time.Time
+	for _, a := range r.loginAttempts {
+		if !match(a) || a.CreatedAt.Before(since) {
+			continue
+		}
+		if a.Success {
+			if resetOnSuccess {
+				count, last = 0, time.Time{}
+			}
+			continue
+		}
+		count, last = count+1, a.CreatedAt
+	}
+	return count, last, nil
+}
+
+func (r *memoryUserRepository) GetLoginAttemptsByUserID(ctx context.Context, userID int64, limit, offset int) ([]*model.LoginAttemptModel, int64, error) {
+	var attempts []*model.LoginAttemptModel
+	for i := len(r.loginAttempts) - 1; i >= 0; i-- {
+		if a := r.loginAttempts[i]; a.UserID.Valid && a.UserID.Int64 == userID {
+			attempts = append(attempts, a)
+		}
+	}
+	total := int64(len(attempts))
+	attempts = attempts[min(offset, len(attempts)):min(offset+limit, len(attempts))]
+	return attempts, total, nil
+}
+
+func (r *memoryUserRepository) CreatePersonalAccessToken(ctx context.Context, token *model.PersonalAccessTokenModel) (int64, error) {
+	token.ID = int64(len(r.accessTokens) + 1)
+	r.accessTokens = append(r.accessTokens, token)
+	return token.ID, nil
+}
+
+func (r *memoryUserRepository) GetPersonalAccessTokensByUserID(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error) {
+	var tokens []*model.PersonalAccessTokenModel
+	for _, t := range r.accessTokens {
+		if t.UserID == userID {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *memoryUserRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error) {
+	for _, t := range r.accessTokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) DeletePersonalAccessToken(ctx context.Context, userID, tokenID int64) (bool, error) {
+	for i, t := range r.accessTokens {
+		if t.ID == tokenID && t.UserID == userID {
+			r.accessTokens = append(r.accessTokens[:i], r.accessTokens[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUserRepository) TouchPersonalAccessToken(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	for _, t := range r.accessTokens {
+		if t.ID == tokenID {
+			t.LastUsedAt.Time, t.LastUsedAt.Valid = usedAt, true
+		}
+	}
+	return nil
+}
+
+func (r *memoryUserRepository) GetUserByID(ctx context.Context, id int64) (*model.UserModel, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*model.UserIdentityModel, error) {
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) CreateUserIdentity(ctx context.Context, identity *model.UserIdentityModel) error {
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *memoryUserRepository) CreateOIDCLoginState(ctx context.Context, state *model.OIDCLoginStateModel) error {
+	r.oidcStates = append(r.oidcStates, state)
+	return nil
+}
+
+func (r *memoryUserRepository) ConsumeOIDCLoginState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginStateModel, error) {
+	for i, s := range r.oidcStates {
+		if s.StateHash == stateHash {
+			r.oidcStates = append(r.oidcStates[:i], r.oidcStates[i+1:]...)
+			if !now.Before(s.ExpiresAt) {
+				return nil, nil
+			}
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) CreateMagicLinkToken(ctx context.Context, token *model.MagicLinkTokenModel) error {
+	r.magicLinks = append(r.magicLinks, token)
+	return nil
+}
+
+func (r *memoryUserRepository) CountMagicLinkTokensByEmail(ctx context.Context, email string, since time.Time) (int, error) {
+	count := 0
+	for _, t := range r.magicLinks {
+		if t.Email == email && !t.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryUserRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*model.MagicLinkTokenModel, error) {
+	for _, t := range r.magicLinks {
+		if t.TokenHash == tokenHash && !t.UsedAt.Valid {
+			t.UsedAt.Time, t.UsedAt.Valid = now, true
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) CreateWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredentialModel) (int64, error) {
+	credential.ID = int64(len(r.passkeys) + 1)
+	r.passkeys = append(r.passkeys, credential)
+	return credential.ID, nil
+}
+
+func (r *memoryUserRepository) GetWebAuthnCredentialsByUserID(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error) {
+	var credentials []*model.WebAuthnCredentialModel
+	for _, c := range r.passkeys {
+		if c.UserID == userID {
+			credentials = append(credentials, c)
+		}
+	}
+	return credentials, nil
+}
+
+func (r *memoryUserRepository) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (*model.WebAuthnCredentialModel, error) {
+	for _, c := range r.passkeys {
+		if bytes.Equal(c.CredentialID, credentialID) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) UpdateWebAuthnSignCount(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error {
+	for _, c := range r.passkeys {
+		if c.ID == id {
+			c.SignCount = signCount
+			c.LastUsedAt.Time, c.LastUsedAt.Valid = usedAt, true
+		}
+	}
+	return nil
+}
+
+func (r *memoryUserRepository) DeleteWebAuthnCredential(ctx context.Context, userID, id int64) (bool, error) {
+	for i, c := range r.passkeys {
+		if c.ID == id && c.UserID == userID {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUserRepository) CreateWebAuthnChallenge(ctx context.Context, challenge *model.WebAuthnChallengeModel) error {
+	r.challenges = append(r.challenges, challenge)
+	return nil
+}
+
+func (r *memoryUserRepository) ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string, now time.Time) (*model.WebAuthnChallengeModel, error) {
+	for i, c := range r.challenges {
+		if c.ChallengeHash == challengeHash {
+			r.challenges = append(r.challenges[:i], r.challenges[i+1:]...)
+			if !now.Before(c.ExpiresAt) {
+				return nil, nil
+			}
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// recordingMailer keeps sent messages for assertions.
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+type memoryPostRepository struct {
+	postRepo.PostRepository
+	created []*model.PostModel
+}
+
+func (r *memoryPostRepository) CreatePost(ctx context.Context, post *model.PostModel) (int64, error) {
+	r.created = append(r.created, post)
+	return int64(len(r.created)), nil
+}
+
+func newTestServer(cfg *config.Config) (*gin.Engine, *memoryPostRepository) {
+	r, posts, _ := newTestServerWithUsers(cfg, nil)
+	return r, posts
+}
+
+func newTestServerWithUsers(cfg *config.Config, mail mailer.Mailer) (*gin.Engine, *memoryPostRepository, *memoryUserRepository) {
+	r := gin.New()
+	validate := validator.New()
+	users := &memoryUserRepository{}
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTOptions(), users, nil)
+
+	posts := &memoryPostRepository{}
+	postSvc := postService.NewService(cfg, posts, nil)
+	NewHandler(r, validate, userService.NewService(cfg, users, mail), postSvc, authMiddleware).RouteList()
+	postHandler.NewHandler(r, validate, postSvc, authMiddleware).RouteList()
+
+	return r, posts, users
+}
+
+func doJSON(r *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestLogin_TokenAcceptedByProtectedRoute(t *testing.T) {
+	cfg := &config.Config{
+		SecreetJwt:     "test-secret",
+		JwtIssuer:      config.DefaultJwtIssuer,
+		JwtAudience:    config.DefaultJwtAudience,
+		AccessTokenTTL: time.Minute,
+	}
+	r, posts := newTestServer(cfg)
+
+	w := doJSON(r, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "test@example.com",
+		Username:        "testuser",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected register status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected login status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var login dto.LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &login); err != nil {
+		t.Fatalf("Failed to decode login response: %v", err)
+	}
+
+	w = doJSON(r, http.MethodPost, "/posts", login.Token, dto.CreatePostRequest{
+		Title:   "Hello",
+		Content: "First post",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected create post status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	if len(posts.created) != 1 || posts.created[0].UserID != 1 {
+		t.Errorf("Expected post to be created for user 1, got %+v", posts.created)
+	}
+}
+
+func TestLogin_TokenFromOtherAudienceRejected(t *testing.T) {
+	issuing := &config.Config{SecreetJwt: "test-secret", JwtIssuer: "go-twitter", JwtAudience: "other-api"}
+	verifying := &config.Config{SecreetJwt: "test-secret", JwtIssuer: "go-twitter", JwtAudience: "go-twitter-api"}
+
+	issuer, _ := newTestServer(issuing)
+	r, posts := newTestServer(verifying)
+
+	doJSON(issuer, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "test@example.com",
+		Username:        "testuser",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+	w := doJSON(issuer, http.MethodPost, "/auth/login", "", dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	var login dto.LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &login); err != nil {
+		t.Fatalf("Failed to decode login response: %v", err)
+	}
+
+	w = doJSON(r, http.MethodPost, "/posts", login.Token, dto.CreatePostRequest{
+		Title:   "Hello",
+		Content: "First post",
+	})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	if len(posts.created) != 0 {
+		t.Error("Expected no post to be created")
+	}
+}
+
+func TestLogin_FailedAttemptsBackOffAndShowInHistory(t *testing.T) {
+	cfg := &config.Config{SecreetJwt: "test-secret", JwtIssuer: config.DefaultJwtIssuer, JwtAudience: config.DefaultJwtAudience}
+	r, _ := newTestServer(cfg)
+
+	doJSON(r, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "test@example.com",
+		Username:        "testuser",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+	w := doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	var login dto.LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &login); err != nil {
+		t.Fatalf("Failed to decode login response: %v", err)
+	}
+
+	w = doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "test@example.com", Password: "wrongpassword"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// the retry lands inside the one second backoff, even with the right password
+	w = doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusTooManyRequests, w.Code, w.Body.String())
+	}
+
+	w = doJSON(r, http.MethodGet, "/users/me/login-attempts", login.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var history dto.LoginAttemptsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("Failed to decode login attempts: %v", err)
+	}
+	if history.TotalCount != 2 || len(history.LoginAttempts) != 2 {
+		t.Fatalf("Expected 2 login attempts, got %+v", history)
+	}
+	if history.LoginAttempts[0].Success || !history.LoginAttempts[1].Success {
+		t.Errorf("Expected newest failed attempt first, got %+v", history.LoginAttempts)
+	}
+}
+
+func TestPersonalAccessToken_ScopedAccess(t *testing.T) {
+	cfg := &config.Config{SecreetJwt: "test-secret", JwtIssuer: config.DefaultJwtIssuer, JwtAudience: config.DefaultJwtAudience}
+	r, posts := newTestServer(cfg)
+
+	doJSON(r, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "bot@example.com",
+		Username:        "botowner",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+	w := doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "bot@example.com", Password: "password123"})
+	var login dto.LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &login); err != nil {
+		t.Fatalf("Failed to decode login response: %v", err)
+	}
+
+	createToken := func(scopes ...string) dto.CreatePersonalAccessTokenResponse {
+		w := doJSON(r, http.MethodPost, "/auth/tokens", login.Token, dto.CreatePersonalAccessTokenRequest{Name: "bot", Scopes: scopes})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var created dto.CreatePersonalAccessTokenResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+			t.Fatalf("Failed to decode token response: %v", err)
+		}
+		return created
+	}
+	writer := createToken("posts:write")
+	reader := createToken("read")
+
+	newPost := dto.CreatePostRequest{Title: "Hello", Content: "From a bot"}
+	if w := doJSON(r, http.MethodPost, "/posts", writer.Token, newPost); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if w := doJSON(r, http.MethodPost, "/posts", reader.Token, newPost); w.Code != http.StatusForbidden {
+		t.Errorf("Expected token without posts:write to get %d, got %d", http.StatusForbidden, w.Code)
+	}
+	if len(posts.created) != 1 || posts.created[0].UserID != 1 {
+		t.Errorf("Expected one post created for user 1, got %+v", posts.created)
+	}
+
+	// tokens cannot manage tokens
+	if w := doJSON(r, http.MethodGet, "/auth/tokens", writer.Token, nil); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+
+	if w := doJSON(r, http.MethodDelete, "/auth/tokens/"+strconv.FormatInt(writer.ID, 10), login.Token, nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w := doJSON(r, http.MethodPost, "/posts", writer.Token, newPost); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected deleted token to get %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestOIDCLogin_LinksVerifiedEmailAndCreatesAccounts(t *testing.T) {
+	provider := oidctest.NewServer("go-twitter", "provider-secret")
+	defer provider.Close()
+
+	cfg := &config.Config{
+		SecreetJwt:  "test-secret",
+		JwtIssuer:   config.DefaultJwtIssuer,
+		JwtAudience: config.DefaultJwtAudience,
+		OIDCProviders: []oidc.Config{{
+			Name:         "stub",
+			Issuer:       provider.Issuer(),
+			ClientID:     "go-twitter",
+			ClientSecret: "provider-secret",
+			RedirectURL:  "https://app.example.com/auth/stub",
+		}},
+	}
+	r, _, users := newTestServerWithUsers(cfg, nil)
+
+	doJSON(r, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "test@example.com",
+		Username:        "testuser",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+
+	// signIn runs the redirect round trip through the stub provider
+	signIn := func(identity oidctest.Identity) dto.OIDCCallbackRequest {
+		provider.SignIn(identity)
+		w := doJSON(r, http.MethodGet, "/auth/oidc/stub", "", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var start dto.OIDCAuthorizationResponse
+		json.Unmarshal(w.Body.Bytes(), &start)
+
+		client := provider.Client()
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+		resp, err := client.Get(start.AuthorizationURL)
+		if err != nil {
+			t.Fatalf("Authorization request failed: %v", err)
+		}
+		resp.Body.Close()
+		callback, _ := url.Parse(resp.Header.Get("Location"))
+		return dto.OIDCCallbackRequest{Code: callback.Query().Get("code"), State: callback.Query().Get("state")}
+	}
+
+	loggedInUser := func(w *httptest.ResponseRecorder) int64 {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var login dto.LoginResponse
+		json.Unmarshal(w.Body.Bytes(), &login)
+		claims, err := tokenjwt.ParseToken(login.Token, cfg.JWTOptions())
+		if err != nil {
+			t.Fatalf("Expected a valid access token, got %v", err)
+		}
+		return claims.UserID
+	}
+
+	// a verified email links to the existing account
+	callback := signIn(oidctest.Identity{Subject: "stub-1", Email: "test@example.com", EmailVerified: true})
+	if id := loggedInUser(doJSON(r, http.MethodPost, "/auth/oidc/stub/callback", "", callback)); id != 1 {
+		t.Errorf("Expected login as existing user 1, got %d", id)
+	}
+	if len(users.identities) != 1 || users.identities[0].UserID != 1 {
+		t.Errorf("Expected identity linked to user 1, got %+v", users.identities)
+	}
+
+	// the state is single use
+	if w := doJSON(r, http.MethodPost, "/auth/oidc/stub/callback", "", callback); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected replayed callback to get %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// the linked subject keeps working even if the provider email changes
+	callback = signIn(oidctest.Identity{Subject: "stub-1", Email: "changed@example.com", EmailVerified: true})
+	if id := loggedInUser(doJSON(r, http.MethodPost, "/auth/oidc/stub/callback", "", callback)); id != 1 {
+		t.Errorf("Expected linked identity to log in as user 1, got %d", id)
+	}
+
+	// an unverified email cannot claim an account
+	callback = signIn(oidctest.Identity{Subject: "stub-2", Email: "test@example.com"})
+	if w := doJSON(r, http.MethodPost, "/auth/oidc/stub/callback", "", callback); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected unverified email to get %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// a new verified email signs up
+	callback = signIn(oidctest.Identity{Subject: "stub-3", Email: "new.person@example.com", EmailVerified: true})
+	if id := loggedInUser(doJSON(r, http.MethodPost, "/auth/oidc/stub/callback", "", callback)); id != 2 {
+		t.Errorf("Expected a new user 2, got %d", id)
+	}
+	if users.users[1].Username != "newperson" {
+		t.Errorf("Expected username derived from email, got %q", users.users[1].Username)
+	}
+
+	if w := doJSON(r, http.MethodGet, "/auth/oidc/unknown", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected unknown provider to get %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestMagicLink_SignsInFromRequestingBrowser(t *testing.T) {
+	cfg := &config.Config{
+		SecreetJwt:   "test-secret",
+		JwtIssuer:    config.DefaultJwtIssuer,
+		JwtAudience:  config.DefaultJwtAudience,
+		MagicLinkURL: "https://app.example.com/magic",
+	}
+	mail := &recordingMailer{}
+	r, _, _ := newTestServerWithUsers(cfg, mail)
+
+	doJSON(r, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "test@example.com",
+		Username:        "testuser",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+
+	// requestLink asks for a link from a browser and returns its cookie and
+	// the token from the latest email
+	requestLink := func(email string) (*http.Cookie, string) {
+		w := doJSON(r, http.MethodPost, "/auth/magic-link", "", dto.MagicLinkRequest{Email: email})
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || !cookies[0].HttpOnly {
+			t.Fatalf("Expected an HttpOnly binding cookie, got %+v", cookies)
+		}
+		if len(mail.sent) == 0 {
+			return cookies[0], ""
+		}
+		for _, line := range strings.Split(mail.sent[len(mail.sent)-1].Body, "\n") {
+			if strings.HasPrefix(line, cfg.MagicLinkURL+"?") {
+				link, _ := url.Parse(line)
+				return cookies[0], link.Query().Get("token")
+			}
+		}
+		return cookies[0], ""
+	}
+
+	consume := func(token string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(dto.ConsumeMagicLinkRequest{Token: token})
+		req := httptest.NewRequest(http.MethodPost, "/auth/magic-link/consume", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	cookie, token := requestLink("test@example.com")
+	if len(mail.sent) != 1 || mail.sent[0].To != "test@example.com" || token == "" {
+		t.Fatalf("Expected one email with a token, got %+v", mail.sent)
+	}
+
+	w := consume(token, cookie)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var login dto.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &login)
+	if login.Token == "" || login.RefreshToken == "" {
+		t.Errorf("Expected access and refresh tokens, got %+v", login)
+	}
+
+	// single use
+	if w := consume(token, cookie); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected reused link to get %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// another browser cannot use the link, and burns it
+	_, token = requestLink("test@example.com")
+	if w := consume(token, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected link from another browser to get %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := consume(token, cookie); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected burned link to get %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	// the third request in the window is the last one allowed
+	requestLink("test@example.com")
+	if w := doJSON(r, http.MethodPost, "/auth/magic-link", "", dto.MagicLinkRequest{Email: "test@example.com"}); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected rate limited request to get %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	// unknown emails look the same but get no mail
+	sent := len(mail.sent)
+	requestLink("nobody@example.com")
+	if len(mail.sent) != sent {
+		t.Errorf("Expected no email for an unknown address, got %+v", mail.sent[sent:])
+	}
+}
+
+func TestPasskey_RegisterAndSignIn(t *testing.T) {
+	cfg := &config.Config{
+		SecreetJwt:  "test-secret",
+		JwtIssuer:   config.DefaultJwtIssuer,
+		JwtAudience: config.DefaultJwtAudience,
+	}
+	r, _ := newTestServer(cfg)
+	rp := cfg.WebAuthn()
+
+	doJSON(r, http.MethodPost, "/auth/register", "", dto.RegisterRequest{
+		Email:           "test@example.com",
+		Username:        "testuser",
+		Password:        "password123",
+		PasswordConfirm: "password123",
+	})
+	w := doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	var login dto.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &login)
+
+	// registration ceremony
+	w = doJSON(r, http.MethodPost, "/auth/passkeys/register/begin", login.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var creation dto.PasskeyCreationOptions
+	json.Unmarshal(w.Body.Bytes(), &creation)
+	if creation.RP.ID != rp.RPID || creation.User.Name != "test@example.com" {
+		t.Errorf("Unexpected creation options: %+v", creation)
+	}
+
+	authenticator := webauthntest.NewAuthenticator(rp.RPID, rp.Origin)
+	challenge, _ := webauthn.DecodeString(creation.Challenge)
+	reg := authenticator.Register(challenge)
+	w = doJSON(r, http.MethodPost, "/auth/passkeys/register/finish", login.Token, dto.FinishPasskeyRegistrationRequest{
+		Name:              "Laptop",
+		ID:                webauthn.EncodeToString(reg.CredentialID),
+		ClientDataJSON:    webauthn.EncodeToString(reg.ClientDataJSON),
+		AttestationObject: webauthn.EncodeToString(reg.AttestationObject),
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// assertion ceremony through the regular login endpoint
+	assert := func(authenticator *webauthntest.Authenticator) *dto.PasskeyAssertion {
+		w := doJSON(r, http.MethodPost, "/auth/passkeys/login/begin", "", dto.BeginPasskeyLoginRequest{Email: "test@example.com"})
+		var options dto.PasskeyRequestOptions
+		json.Unmarshal(w.Body.Bytes(), &options)
+		if len(options.AllowCredentials) != 1 || options.AllowCredentials[0].ID != webauthn.EncodeToString(reg.CredentialID) {
+			t.Fatalf("Expected the registered passkey to be allowed, got %+v", options)
+		}
+		challenge, _ := webauthn.DecodeString(options.Challenge)
+		resp := authenticator.Assert(challenge)
+		return &dto.PasskeyAssertion{
+			ID:                webauthn.EncodeToString(resp.CredentialID),
+			ClientDataJSON:    webauthn.EncodeToString(resp.ClientDataJSON),
+			AuthenticatorData: webauthn.EncodeToString(resp.AuthenticatorData),
+			Signature:         webauthn.EncodeToString(resp.Signature),
+		}
+	}
+
+	assertion := assert(authenticator)
+	w = doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "test@example.com", Passkey: assertion})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var passkeyLogin dto.LoginResponse
+	json.Unmarshal(w.Body.Bytes(), &passkeyLogin)
+	if passkeyLogin.Token == "" || passkeyLogin.RefreshToken == "" {
+		t.Errorf("Expected access and refresh tokens, got %+v", passkeyLogin)
+	}
+
+	// a replayed assertion fails
+	w = doJSON(r, http.MethodPost, "/auth/login", "", dto.LoginRequest{Email: "test@example.com", Passkey: assertion})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected replayed assertion to get %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = doJSON(r, http.MethodGet, "/auth/passkeys", login.Token, nil)
+	var list struct {
+		Passkeys []dto.PasskeyResponse `json:"passkeys"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Passkeys) != 1 || list.Passkeys[0].Name != "Laptop" || list.Passkeys[0].LastUsedAt == nil {
+		t.Errorf("Expected one used passkey, got %+v", list.Passkeys)
+	}
+
+	if w := doJSON(r, http.MethodDelete, "/auth/passkeys/1", login.Token, nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+// pinnedPostService serves pinned posts by user ID. Other methods fall
+// through to the embedded nil interface.
+type pinnedPostService struct {
+	postService.PostService
+	pinned map[int64]*dto.PostResponse
+}
+
+func (s *pinnedPostService) GetPinnedPost(ctx context.Context, userID, viewerID int64) (*dto.PostResponse, int, error) {
+	return s.pinned[userID], http.StatusOK, nil
+}
+
+func TestGetUser_EmbedsPinnedPost(t *testing.T) {
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	users := &memoryUserRepository{users: []*model.UserModel{
+		{ID: 1, Username: "alice", Email: "alice@example.com"},
+		{ID: 2, Username: "bob", Email: "bob@example.com"},
+	}}
+	posts := &pinnedPostService{pinned: map[int64]*dto.PostResponse{
+		1: {ID: 7, UserID: 1, Username: "alice", Title: "Pinned"},
+	}}
+
+	r := gin.New()
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTOptions(), users, nil)
+	NewHandler(r, validator.New(), userService.NewService(cfg, users, nil), posts, authMiddleware).RouteList()
+
+	w := doJSON(r, http.MethodGet, "/users/1", "", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var profile dto.GetUserResponse
+	json.Unmarshal(w.Body.Bytes(), &profile)
+	if profile.PinnedPost == nil || profile.PinnedPost.ID != 7 || profile.PinnedPost.Title != "Pinned" {
+		t.Errorf("Expected the pinned post embedded in the profile, got %+v", profile.PinnedPost)
+	}
+
+	w = doJSON(r, http.MethodGet, "/users/2", "", nil)
+	var body map[string]json.RawMessage
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if string(body["pinned_post"]) != "null" {
+		t.Errorf("Expected pinned_post to be null without a pinned post, got %s", body["pinned_post"])
+	}
+}
@@ -0,0 +1,96 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"go-twitter/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// magicLinkCookie holds a random value that ties a sign-in link to the
+// browser that requested it. It is scoped to the magic-link endpoints.
+const (
+	magicLinkCookie       = "magic_link_binding"
+	magicLinkCookiePath   = "/auth/magic-link"
+	magicLinkCookieMaxAge = 24 * 60 * 60
+)
+
+func (h *Handler) RequestMagicLink(c *gin.Context) {
+	var (
+		ctx = c.Request.Context()
+		req dto.MagicLinkRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	binding, err := c.Cookie(magicLinkCookie)
+	if err != nil || binding == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		binding = hex.EncodeToString(b)
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkCookie, binding, magicLinkCookieMaxAge, magicLinkCookiePath, "", c.Request.TLS != nil, true)
+
+	req.Fingerprint = browserFingerprint(binding, c.Request.UserAgent())
+
+	status, err := h.userService.RequestMagicLink(ctx, req)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email belongs to an account, a sign-in link has been sent"})
+}
+
+func (h *Handler) ConsumeMagicLink(c *gin.Context) {
+	var (
+		ctx = c.Request.Context()
+		req dto.ConsumeMagicLinkRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	binding, _ := c.Cookie(magicLinkCookie)
+	req.Fingerprint = browserFingerprint(binding, c.Request.UserAgent())
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	response, status, err := h.userService.ConsumeMagicLink(ctx, req)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// browserFingerprint combines the binding cookie with the user agent. A
+// missing cookie yields a fingerprint no request can match.
+func browserFingerprint(binding, userAgent string) string {
+	if binding == "" {
+		return ""
+	}
+	return binding + "\n" + userAgent
+}
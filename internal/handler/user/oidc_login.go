@@ -0,0 +1,46 @@
+package user
+
+import (
+	"go-twitter/internal/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) StartOIDCLogin(c *gin.Context) {
+	response, status, err := h.userService.StartOIDCLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) CompleteOIDCLogin(c *gin.Context) {
+	var (
+		ctx = c.Request.Context()
+		req dto.OIDCCallbackRequest
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	response, status, err := h.userService.CompleteOIDCLogin(ctx, c.Param("provider"), req)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,64 @@
+package user
+
+import (
+	"go-twitter/internal/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AssignRole(c *gin.Context) {
+	userIDStr := c.Param("id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roles, status, err := h.userService.AssignRole(c.Request.Context(), userID, req.Role)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+func (h *Handler) RemoveRole(c *gin.Context) {
+	userIDStr := c.Param("id")
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	roles, status, err := h.userService.RemoveRole(c.Request.Context(), userID, c.Param("role"))
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{"error": "user not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
@@ -1,20 +1,48 @@
 package middleware
 
 import (
+	"context"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/jwt"
+	"go-twitter/pkg/pat"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+// lastUsedResolution limits how often a personal access token's last-used
+// time is written back, so busy bots do not update the row on every request.
+const lastUsedResolution = time.Minute
+
+// PersonalAccessTokenStore is the subset of the user repository the
+// middleware needs to authenticate personal access tokens.
+type PersonalAccessTokenStore interface {
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error)
+	TouchPersonalAccessToken(ctx context.Context, tokenID int64, usedAt time.Time) error
+}
+
+// AccessTokenRevocationStore reports whether an access token issued to an
+// OAuth client was revoked before it expired.
+type AccessTokenRevocationStore interface {
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
 type AuthMiddleware struct {
-	jwtSecret string
+	tokenOptions jwt.Options
+	tokenStore   PersonalAccessTokenStore
+	revocations  AccessTokenRevocationStore
 }
 
-func NewAuthMiddleware(jwtSecret string) *AuthMiddleware {
+// NewAuthMiddleware builds the middleware. tokenStore may be nil, in which
+// case personal access tokens are rejected; revocations may be nil, in which
+// case OAuth access tokens are trusted until they expire.
+func NewAuthMiddleware(tokenOptions jwt.Options, tokenStore PersonalAccessTokenStore, revocations AccessTokenRevocationStore) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtSecret: jwtSecret,
+		tokenOptions: tokenOptions,
+		tokenStore:   tokenStore,
+		revocations:  revocations,
 	}
 }
 
@@ -36,34 +64,128 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(m.jwtSecret), nil
-		})
+		if pat.IsPersonalAccessToken(tokenString) {
+			m.authenticatePersonalAccessToken(c, tokenString)
+			return
+		}
 
-		if err != nil || !token.Valid {
+		claims, err := jwt.ParseToken(tokenString, m.tokenOptions)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		// tokens issued to OAuth clients are limited to their granted scopes
+		if claims.ClientID != "" {
+			if m.revocations != nil {
+				revoked, err := m.revocations.IsAccessTokenRevoked(c.Request.Context(), claims.ID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+					c.Abort()
+					return
+				}
+				if revoked {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+					c.Abort()
+					return
+				}
+			}
+			c.Set("scopes", strings.Fields(claims.Scope))
+		}
+
+		c.Set("user_id", int(claims.UserID))
+		c.Set("claims", claims)
+		c.Next()
+	}
+}
+
+// OptionalAuth authenticates requests that carry an Authorization header,
+// like RequireAuth, and lets anonymous requests through, for public routes
+// whose response depends on the viewer.
+func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
+	requireAuth := m.RequireAuth()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		requireAuth(c)
+	}
+}
+
+func (m *AuthMiddleware) authenticatePersonalAccessToken(c *gin.Context, tokenString string) {
+	if m.tokenStore == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := m.tokenStore.GetPersonalAccessTokenByHash(ctx, pat.Hash(tokenString))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Abort()
+		return
+	}
+
+	now := time.Now()
+	if token == nil || !now.Before(token.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return
+	}
+
+	if !token.LastUsedAt.Valid || now.Sub(token.LastUsedAt.Time) >= lastUsedResolution {
+		if err := m.tokenStore.TouchPersonalAccessToken(ctx, token.ID, now); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			c.Abort()
 			return
 		}
+	}
 
-		userID, ok := claims["user_id"].(float64)
-		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user_id in token"})
+	c.Set("user_id", int(token.UserID))
+	c.Set("scopes", token.Scopes)
+	c.Next()
+}
+
+// RequireScope must be chained after RequireAuth. Requests authenticated with
+// a personal access token or an OAuth access token must carry the scope;
+// login sessions always pass.
+func (m *AuthMiddleware) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes, ok := GetScopes(c); ok && !model.HasScope(scopes, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "token is missing the " + scope + " scope"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSession must be chained after RequireAuth. It rejects personal access
+// tokens and OAuth access tokens on account-security routes such as managing
+// tokens or 2FA.
+func (m *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetScopes(c); ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "personal access tokens cannot be used for this endpoint"})
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		c.Set("user_id", int(userID))
+// RequirePermission must be chained after RequireAuth. It rejects callers
+// whose token roles do not grant the permission.
+func (m *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !model.HasPermission(GetRoles(c), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -76,3 +198,31 @@ func GetUserID(c *gin.Context) (int, bool) {
 	id, ok := userID.(int)
 	return id, ok
 }
+
+func GetClaims(c *gin.Context) (*jwt.Claims, bool) {
+	value, exists := c.Get("claims")
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*jwt.Claims)
+	return claims, ok
+}
+
+func GetRoles(c *gin.Context) []string {
+	claims, ok := GetClaims(c)
+	if !ok {
+		return nil
+	}
+	return claims.Roles
+}
+
+// GetScopes returns the scopes of the personal access token or OAuth access
+// token that authenticated the request. ok is false for login sessions.
+func GetScopes(c *gin.Context) ([]string, bool) {
+	value, exists := c.Get("scopes")
+	if !exists {
+		return nil, false
+	}
+	scopes, ok := value.([]string)
+	return scopes, ok
+}
@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"context"
+	"go-twitter/internal/model"
+	tokenjwt "go-twitter/pkg/jwt"
+	"go-twitter/pkg/pat"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
@@ -16,9 +21,14 @@ func init() {
 
 // Helper function to create a valid JWT token for testing
 func createTestToken(userID int, secretKey string, expiry time.Duration) (string, error) {
+	now := time.Now()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": float64(userID),
-		"exp":     time.Now().Add(expiry).Unix(),
+		"user_id":   float64(userID),
+		"sub":       strconv.Itoa(userID),
+		"token_use": "access",
+		"iat":       now.Unix(),
+		"nbf":       now.Unix(),
+		"exp":       now.Add(expiry).Unix(),
 	})
 	return token.SignedString([]byte(secretKey))
 }
@@ -26,14 +36,14 @@ func createTestToken(userID int, secretKey string, expiry time.Duration) (string
 func TestNewAuthMiddleware(t *testing.T) {
 	secretKey := "test-secret"
 
-	middleware := NewAuthMiddleware(secretKey)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 
 	if middleware == nil {
 		t.Fatal("Expected middleware instance, got nil")
 	}
 
-	if middleware.jwtSecret != secretKey {
-		t.Errorf("Expected jwtSecret to be %s, got %s", secretKey, middleware.jwtSecret)
+	if middleware.tokenOptions.Secret != secretKey {
+		t.Errorf("Expected secret to be %s, got %s", secretKey, middleware.tokenOptions.Secret)
 	}
 }
 
@@ -51,7 +61,7 @@ func TestRequireAuth_ValidToken(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, router := gin.CreateTestContext(w)
 
-	middleware := NewAuthMiddleware(secretKey)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 
 	// Add middleware and handler
 	router.GET("/test", middleware.RequireAuth(), func(c *gin.Context) {
@@ -97,7 +107,7 @@ func TestRequireAuth_MissingAuthorizationHeader(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/test", nil)
 	// No Authorization header set
 
-	middleware := NewAuthMiddleware(secretKey)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 
 	// Act
 	handler := middleware.RequireAuth()
@@ -149,7 +159,7 @@ func TestRequireAuth_InvalidHeaderFormat(t *testing.T) {
 			c.Request = httptest.NewRequest("GET", "/test", nil)
 			c.Request.Header.Set("Authorization", tt.header)
 
-			middleware := NewAuthMiddleware(secretKey)
+			middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 			handler := middleware.RequireAuth()
 			handler(c)
 
@@ -192,7 +202,7 @@ func TestRequireAuth_InvalidToken(t *testing.T) {
 			c.Request = httptest.NewRequest("GET", "/test", nil)
 			c.Request.Header.Set("Authorization", "Bearer "+tt.token)
 
-			middleware := NewAuthMiddleware(secretKey)
+			middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 			handler := middleware.RequireAuth()
 			handler(c)
 
@@ -223,7 +233,7 @@ func TestRequireAuth_ExpiredToken(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer "+token)
 
-	middleware := NewAuthMiddleware(secretKey)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 	handler := middleware.RequireAuth()
 	handler(c)
 
@@ -254,7 +264,7 @@ func TestRequireAuth_WrongSecretKey(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer "+token)
 
-	middleware := NewAuthMiddleware(wrongSecret)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: wrongSecret}, nil, nil)
 	handler := middleware.RequireAuth()
 	handler(c)
 
@@ -284,7 +294,7 @@ func TestRequireAuth_MissingUserIDClaim(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
 
-	middleware := NewAuthMiddleware(secretKey)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 	handler := middleware.RequireAuth()
 	handler(c)
 
@@ -313,7 +323,7 @@ func TestRequireAuth_DifferentUserIDs(t *testing.T) {
 			w := httptest.NewRecorder()
 			_, router := gin.CreateTestContext(w)
 
-			middleware := NewAuthMiddleware(secretKey)
+			middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 
 			router.GET("/test", middleware.RequireAuth(), func(c *gin.Context) {
 				value, exists := c.Get("user_id")
@@ -432,7 +442,7 @@ func TestRequireAuth_WrongSigningMethod(t *testing.T) {
 	c.Request = httptest.NewRequest("GET", "/test", nil)
 	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
 
-	middleware := NewAuthMiddleware(secretKey)
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
 	handler := middleware.RequireAuth()
 	handler(c)
 
@@ -441,3 +451,320 @@ func TestRequireAuth_WrongSigningMethod(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
 	}
 }
+
+func TestRequireAuth_AcceptsIssuedToken(t *testing.T) {
+	opts := tokenjwt.Options{Secret: "test-secret-key", Issuer: "go-twitter", Audience: "go-twitter-api"}
+
+	token, err := tokenjwt.CreateToken(77, "testuser", nil, opts)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	_, router := gin.CreateTestContext(w)
+
+	middleware := NewAuthMiddleware(opts, nil, nil)
+	router.GET("/test", middleware.RequireAuth(), func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok {
+			t.Fatal("Expected claims to be set in context")
+		}
+
+		if claims.UserID != 77 || claims.Username != "testuser" {
+			t.Errorf("Unexpected claims: %+v", claims)
+		}
+
+		if id, ok := GetUserID(c); !ok || id != 77 {
+			t.Errorf("Expected user_id to be 77, got %v", id)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestRequireAuth_WrongAudience(t *testing.T) {
+	issued := tokenjwt.Options{Secret: "test-secret-key", Issuer: "go-twitter", Audience: "other-api"}
+	expected := tokenjwt.Options{Secret: "test-secret-key", Issuer: "go-twitter", Audience: "go-twitter-api"}
+
+	token, err := tokenjwt.CreateToken(1, "testuser", nil, issued)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+token)
+
+	middleware := NewAuthMiddleware(expected, nil, nil)
+	handler := middleware.RequireAuth()
+	handler(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	opts := tokenjwt.Options{Secret: "test-secret-key"}
+
+	tests := []struct {
+		name       string
+		roles      []string
+		wantStatus int
+	}{
+		{name: "No roles", roles: nil, wantStatus: http.StatusForbidden},
+		{name: "Unknown role", roles: []string{"editor"}, wantStatus: http.StatusForbidden},
+		{name: "Moderator", roles: []string{"moderator"}, wantStatus: http.StatusOK},
+		{name: "Admin", roles: []string{"admin"}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tokenjwt.CreateToken(1, "testuser", tt.roles, opts)
+			if err != nil {
+				t.Fatalf("Failed to create token: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			middleware := NewAuthMiddleware(opts, nil, nil)
+			router.GET("/test", middleware.RequireAuth(), middleware.RequirePermission("moderate:posts"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+type fakeTokenStore struct {
+	tokens  map[string]*model.PersonalAccessTokenModel
+	touched []int64
+}
+
+func (s *fakeTokenStore) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error) {
+	return s.tokens[tokenHash], nil
+}
+
+func (s *fakeTokenStore) TouchPersonalAccessToken(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	s.touched = append(s.touched, tokenID)
+	return nil
+}
+
+func TestRequireAuth_PersonalAccessToken(t *testing.T) {
+	active, _ := pat.Generate()
+	expired, _ := pat.Generate()
+	unknown, _ := pat.Generate()
+
+	store := &fakeTokenStore{tokens: map[string]*model.PersonalAccessTokenModel{
+		pat.Hash(active):  {ID: 1, UserID: 42, Scopes: []string{"read"}, ExpiresAt: time.Now().Add(time.Hour)},
+		pat.Hash(expired): {ID: 2, UserID: 42, Scopes: []string{"read"}, ExpiresAt: time.Now().Add(-time.Hour)},
+	}}
+
+	tests := []struct {
+		name       string
+		token      string
+		store      PersonalAccessTokenStore
+		wantStatus int
+	}{
+		{name: "Active token", token: active, store: store, wantStatus: http.StatusOK},
+		{name: "Expired token", token: expired, store: store, wantStatus: http.StatusUnauthorized},
+		{name: "Unknown token", token: unknown, store: store, wantStatus: http.StatusUnauthorized},
+		{name: "No token store", token: active, store: nil, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			middleware := NewAuthMiddleware(tokenjwt.Options{Secret: "test-secret-key"}, tt.store, nil)
+			router.GET("/test", middleware.RequireAuth(), func(c *gin.Context) {
+				userID, _ := GetUserID(c)
+				scopes, ok := GetScopes(c)
+				if userID != 42 || !ok || len(scopes) != 1 {
+					t.Errorf("Expected user 42 with scopes, got %d and %v", userID, scopes)
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+
+	if len(store.touched) != 1 || store.touched[0] != 1 {
+		t.Errorf("Expected only the active token to be marked used, got %v", store.touched)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	readToken, _ := pat.Generate()
+	store := &fakeTokenStore{tokens: map[string]*model.PersonalAccessTokenModel{
+		pat.Hash(readToken): {ID: 1, UserID: 1, Scopes: []string{"read"}, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	opts := tokenjwt.Options{Secret: "test-secret-key"}
+	session, _ := tokenjwt.CreateToken(1, "testuser", nil, opts)
+
+	tests := []struct {
+		name       string
+		token      string
+		scope      string
+		wantStatus int
+	}{
+		{name: "Token with scope", token: readToken, scope: "read", wantStatus: http.StatusOK},
+		{name: "Token without scope", token: readToken, scope: "posts:write", wantStatus: http.StatusForbidden},
+		{name: "Session is not scoped", token: session, scope: "posts:write", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			middleware := NewAuthMiddleware(opts, store, nil)
+			router.GET("/test", middleware.RequireAuth(), middleware.RequireScope(tt.scope), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	token, _ := pat.Generate()
+	store := &fakeTokenStore{tokens: map[string]*model.PersonalAccessTokenModel{
+		pat.Hash(token): {ID: 1, UserID: 1, Scopes: model.Scopes, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+
+	w := httptest.NewRecorder()
+	_, router := gin.CreateTestContext(w)
+
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: "test-secret-key"}, store, nil)
+	router.GET("/test", middleware.RequireAuth(), middleware.RequireSession(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+type fakeRevocationStore map[string]bool
+
+func (s fakeRevocationStore) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s[jti], nil
+}
+
+func TestRequireAuth_OAuthAccessToken(t *testing.T) {
+	opts := tokenjwt.Options{Secret: "test-secret-key"}
+	active, _ := tokenjwt.CreateOAuthToken(1, "testuser", "client-abc", []string{"read"}, opts)
+	revoked, _ := tokenjwt.CreateOAuthToken(1, "testuser", "client-abc", []string{"read"}, opts)
+	revokedClaims, _ := tokenjwt.ParseToken(revoked, opts)
+	revocations := fakeRevocationStore{revokedClaims.ID: true}
+
+	tests := []struct {
+		name       string
+		token      string
+		scope      string
+		wantStatus int
+	}{
+		{name: "Granted scope", token: active, scope: "read", wantStatus: http.StatusOK},
+		{name: "Scope not granted", token: active, scope: "posts:write", wantStatus: http.StatusForbidden},
+		{name: "Revoked token", token: revoked, scope: "read", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, router := gin.CreateTestContext(w)
+
+			middleware := NewAuthMiddleware(opts, nil, revocations)
+			router.GET("/test", middleware.RequireAuth(), middleware.RequireScope(tt.scope), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestOptionalAuth(t *testing.T) {
+	secretKey := "test-secret-key"
+	token, err := createTestToken(123, secretKey, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create test token: %v", err)
+	}
+
+	_, router := gin.CreateTestContext(httptest.NewRecorder())
+	middleware := NewAuthMiddleware(tokenjwt.Options{Secret: secretKey}, nil, nil)
+	router.GET("/test", middleware.OptionalAuth(), func(c *gin.Context) {
+		userID, _ := GetUserID(c)
+		c.String(http.StatusOK, "%d", userID)
+	})
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+		wantBody   string
+	}{
+		{"anonymous", "", http.StatusOK, "0"},
+		{"valid token", "Bearer " + token, http.StatusOK, "123"},
+		{"invalid token", "Bearer invalid", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("Expected user %s, got %s", tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"go-twitter/pkg/etag"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CachePolicy is how long clients and shared caches may reuse a route's
+// responses before revalidating them.
+type CachePolicy struct {
+	MaxAge time.Duration
+}
+
+// Cache makes successful GET responses conditional: it tags each one with an
+// ETag of its body, which extends the version ETag a handler may have set,
+// and answers If-None-Match, or If-Modified-Since when the handler set
+// Last-Modified, with 304 Not Modified. Responses depend on the viewer, so
+// they vary on Authorization, and authenticated ones are private.
+func Cache(policy CachePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Authorization")
+		if writer.status != http.StatusOK {
+			header.Set("Cache-Control", "no-store")
+			writer.flush()
+			return
+		}
+
+		tag := etag.WithDigest(header.Get("ETag"), writer.body.Bytes())
+		header.Set("ETag", tag)
+		header.Set("Cache-Control", cacheControl(c, policy))
+
+		if notModified(c.Request, tag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		writer.flush()
+	}
+}
+
+func cacheControl(c *gin.Context, policy CachePolicy) string {
+	maxAge := "max-age=" + strconv.Itoa(int(policy.MaxAge.Seconds()))
+	if c.GetHeader("Authorization") != "" {
+		return "private, " + maxAge + ", must-revalidate"
+	}
+	return "public, " + maxAge + ", must-revalidate"
+}
+
+// notModified evaluates the request's preconditions as RFC 9110 orders
+// them: If-Modified-Since only counts when If-None-Match is absent.
+func notModified(r *http.Request, tag, lastModified string) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return etag.NoneMatch(header, tag)
+	}
+	header := r.Header.Get("If-Modified-Since")
+	if header == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// bufferedWriter holds back the response so its ETag can be computed and
+// compared before anything is sent.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return false
+}
+
+// flush sends the held back response as it was written.
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	w.ResponseWriter.Write(w.body.Bytes())
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCacheRouter(handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.GET("/resource", Cache(CachePolicy{MaxAge: 30 * time.Second}), handler)
+	router.PUT("/resource", Cache(CachePolicy{MaxAge: 30 * time.Second}), handler)
+	return router
+}
+
+func serveCached(router *gin.Engine, method string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/resource", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCache_ETagAndNotModified(t *testing.T) {
+	likes := 1
+	router := newCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"likes_count": likes})
+	})
+
+	w := serveCached(router, http.MethodGet, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	tag := w.Header().Get("ETag")
+	if tag == "" {
+		t.Fatal("Expected an ETag")
+	}
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=30, must-revalidate" {
+		t.Errorf("Expected public Cache-Control, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Authorization" {
+		t.Errorf("Expected Vary: Authorization, got %q", got)
+	}
+
+	w = serveCached(router, http.MethodGet, map[string]string{"If-None-Match": tag})
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("Expected status 304, got %d", w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected an empty body, got %q", w.Body.String())
+	}
+	if w.Header().Get("ETag") != tag {
+		t.Errorf("Expected the ETag %s on the 304, got %s", tag, w.Header().Get("ETag"))
+	}
+
+	likes = 2
+	w = serveCached(router, http.MethodGet, map[string]string{"If-None-Match": tag})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 after the body changed, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") == tag {
+		t.Error("Expected a new ETag after the body changed")
+	}
+}
+
+func TestCache_ExtendsVersionETag(t *testing.T) {
+	router := newCacheRouter(func(c *gin.Context) {
+		c.Header("ETag", `"4"`)
+		c.JSON(http.StatusOK, gin.H{"version": 4})
+	})
+
+	w := serveCached(router, http.MethodGet, nil)
+	if tag := w.Header().Get("ETag"); !strings.HasPrefix(tag, `"4.`) {
+		t.Errorf("Expected the version ETag with a digest, got %s", tag)
+	}
+}
+
+func TestCache_AuthenticatedIsPrivate(t *testing.T) {
+	router := newCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"bookmarked": true})
+	})
+
+	w := serveCached(router, http.MethodGet, map[string]string{"Authorization": "Bearer token"})
+	if got := w.Header().Get("Cache-Control"); got != "private, max-age=30, must-revalidate" {
+		t.Errorf("Expected private Cache-Control, got %q", got)
+	}
+}
+
+func TestCache_IfModifiedSince(t *testing.T) {
+	modified := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	router := newCacheRouter(func(c *gin.Context) {
+		c.Header("Last-Modified", modified.Format(http.TimeFormat))
+		c.JSON(http.StatusOK, gin.H{"id": 1})
+	})
+
+	w := serveCached(router, http.MethodGet, map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+
+	w = serveCached(router, http.MethodGet, map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)})
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+
+	// If-None-Match takes precedence
+	w = serveCached(router, http.MethodGet, map[string]string{
+		"If-Modified-Since": modified.Format(http.TimeFormat),
+		"If-None-Match":     `"stale"`,
+	})
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	router := newCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	})
+
+	w := serveCached(router, http.MethodGet, map[string]string{"If-None-Match": "*"})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") != "" {
+		t.Errorf("Expected no ETag, got %s", w.Header().Get("ETag"))
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Expected no-store, got %q", got)
+	}
+	if !strings.Contains(w.Body.String(), "post not found") {
+		t.Errorf("Expected the error body, got %q", w.Body.String())
+	}
+}
+
+func TestCache_IgnoresWrites(t *testing.T) {
+	router := newCacheRouter(func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": 1})
+	})
+
+	w := serveCached(router, http.MethodPut, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+		t.Error("Expected no caching headers on writes")
+	}
+}
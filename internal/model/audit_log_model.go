@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+	AuditActionDeletePost    = "post.delete"
+	AuditActionDeleteComment = "comment.delete"
+)
+
+type AuditLogModel struct {
+	ID           int64
+	ActorID      int64
+	TargetUserID int64
+	Action       string
+	EntityType   string
+	EntityID     int64
+	CreatedAt    time.Time
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BookmarkModel is a post a user saved privately, optionally filed in one
+// of their folders.
+type BookmarkModel struct {
+	ID        int64
+	UserID    int64
+	PostID    int64
+	FolderID  sql.NullInt64
+	CreatedAt time.Time
+}
+
+type BookmarkFolderModel struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	CreatedAt time.Time
+}
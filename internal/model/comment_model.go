@@ -10,6 +10,8 @@ type CommentModel struct {
 	PostID    int64        `db:"post_id"`
 	UserID    int64        `db:"user_id"`
 	Content   string       `db:"content"`
+	EditCount int          `db:"edit_count"`
+	Version   int          `db:"version"`
 	DeletedAt sql.NullTime `db:"deleted_at"`
 	CreatedAt time.Time    `db:"created_at"`
 	UpdatedAt time.Time    `db:"updated_at"`
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Data export states. A pending export waits for the background job; a
+// ready one can be downloaded until it expires and its archive is deleted.
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+	DataExportExpired    = "expired"
+)
+
+type DataExportModel struct {
+	ID          int64
+	UserID      int64
+	Status      string
+	BlobKey     sql.NullString
+	SizeBytes   int64
+	StartedAt   sql.NullTime
+	CompletedAt sql.NullTime
+	ExpiresAt   sql.NullTime
+	CreatedAt   time.Time
+}
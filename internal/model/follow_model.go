@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// FollowModel records that one user follows another. Username is the other
+// user's, filled in by listings.
+type FollowModel struct {
+	FollowerID int64
+	FolloweeID int64
+	Username   string
+	CreatedAt  time.Time
+}
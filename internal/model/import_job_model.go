@@ -0,0 +1,52 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Import sources.
+const (
+	ImportSourceTwitter = "twitter"
+)
+
+// Import job states. A pending job waits for the background job, which
+// deletes the uploaded archive once the job completes or fails.
+const (
+	ImportPending    = "pending"
+	ImportProcessing = "processing"
+	ImportCompleted  = "completed"
+	ImportFailed     = "failed"
+)
+
+// Outcomes recorded for items that were not imported.
+const (
+	ImportItemSkipped = "skipped"
+	ImportItemFailed  = "failed"
+)
+
+type ImportJobModel struct {
+	ID             int64
+	UserID         int64
+	Source         string
+	Status         string
+	BlobKey        sql.NullString
+	TotalItems     int
+	ProcessedItems int
+	ImportedItems  int
+	SkippedItems   int
+	FailedItems    int
+	Error          sql.NullString
+	StartedAt      sql.NullTime
+	CompletedAt    sql.NullTime
+	CreatedAt      time.Time
+}
+
+type ImportJobItemModel struct {
+	ID        int64
+	JobID     int64
+	SourceID  string
+	Outcome   string
+	Reason    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// InviteCodeModel is a registration invite. Only the code's hash is stored;
+// CodePrefix keeps its first characters so owners can recognise it.
+type InviteCodeModel struct {
+	ID         int64
+	CreatedBy  int64
+	CodeHash   string
+	CodePrefix string
+	MaxUses    int
+	Uses       int
+	ExpiresAt  time.Time
+	RevokedAt  sql.NullTime
+	CreatedAt  time.Time
+}
+
+// InviteRedemptionModel records who invited whom. Username is the invited
+// user's, filled in by listings.
+type InviteRedemptionModel struct {
+	ID           int64
+	InviteCodeID int64
+	InviterID    int64
+	UserID       int64
+	Username     string
+	CreatedAt    time.Time
+}
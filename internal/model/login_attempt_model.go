@@ -0,0 +1,16 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type LoginAttemptModel struct {
+	ID        int64
+	UserID    sql.NullInt64
+	Email     string
+	IPAddress string
+	UserAgent string
+	Success   bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// MagicLinkTokenModel is an emailed one-time sign-in link. Requests for
+// unknown emails are stored without a user so they count towards the rate
+// limit but can never be consumed.
+type MagicLinkTokenModel struct {
+	ID              int64
+	UserID          sql.NullInt64
+	Email           string
+	TokenHash       string
+	FingerprintHash string
+	ExpiresAt       time.Time
+	UsedAt          sql.NullTime
+	CreatedAt       time.Time
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Processing states of uploaded media. Images are pending until a worker
+// strips their metadata and renders the variants; videos are ready on
+// upload.
+const (
+	MediaPending    = "pending"
+	MediaProcessing = "processing"
+	MediaReady      = "ready"
+	MediaFailed     = "failed"
+)
+
+// MediaModel is an uploaded file. It is an orphan until a post links it
+// through post_media; UserID is cleared when the uploader's account is
+// purged.
+type MediaModel struct {
+	ID               int64
+	UserID           sql.NullInt64
+	BlobKey          string
+	ContentType      string
+	SizeBytes        int64
+	AltText          string
+	ProcessingStatus string
+	Width            int
+	Height           int
+	Blurhash         string
+	Variants         []MediaVariantModel
+	CreatedAt        time.Time
+}
+
+// MediaVariantModel is a resized copy of an image, named by its size.
+type MediaVariantModel struct {
+	MediaID     int64
+	Name        string
+	BlobKey     string
+	ContentType string
+	Width       int
+	Height      int
+	SizeBytes   int64
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OAuthClientModel struct {
+	ID       int64
+	ClientID string
+	// ClientSecretHash is empty for public clients, which must use PKCE
+	// and cannot call the introspection endpoint.
+	ClientSecretHash string
+	Name             string
+	RedirectURIs     []string
+	Scopes           []string
+	OwnerUserID      int64
+	CreatedAt        time.Time
+}
+
+func (c *OAuthClientModel) IsConfidential() bool {
+	return c.ClientSecretHash != ""
+}
+
+type OAuthAuthorizationCodeModel struct {
+	ID            int64
+	CodeHash      string
+	ClientID      string
+	UserID        int64
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	UsedAt        sql.NullTime
+	CreatedAt     time.Time
+}
+
+type OAuthRefreshTokenModel struct {
+	ID        int64
+	TokenHash string
+	ClientID  string
+	UserID    int64
+	Scopes    []string
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Scopes a personal access token may be granted. JWT sessions are not
+// scoped and pass every scope check.
+const (
+	ScopeRead          = "read"
+	ScopePostsWrite    = "posts:write"
+	ScopeCommentsWrite = "comments:write"
+	ScopeLikesWrite    = "likes:write"
+	// ScopeBookmarks reads and changes the user's private bookmarks.
+	ScopeBookmarks = "bookmarks"
+)
+
+var Scopes = []string{ScopeRead, ScopePostsWrite, ScopeCommentsWrite, ScopeLikesWrite, ScopeBookmarks}
+
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// UniqueScopes drops repeated scopes, keeping the first occurrence.
+func UniqueScopes(scopes []string) []string {
+	var result []string
+	for _, scope := range scopes {
+		if !HasScope(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+type PersonalAccessTokenModel struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	TokenHash   string
+	TokenPrefix string
+	Scopes      []string
+	ExpiresAt   time.Time
+	LastUsedAt  sql.NullTime
+	CreatedAt   time.Time
+}
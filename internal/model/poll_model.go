@@ -0,0 +1,36 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PollModel is a poll attached to a post. Until FinalizedAt is set the
+// option counts are computed from the votes; afterwards they are the
+// results frozen when the poll closed.
+type PollModel struct {
+	PostID         int64
+	MultipleChoice bool
+	ClosesAt       time.Time
+	FinalizedAt    sql.NullTime
+	TotalVoters    int
+	Options        []PollOptionModel
+	CreatedAt      time.Time
+}
+
+type PollOptionModel struct {
+	ID        int64
+	PostID    int64
+	Position  int
+	Text      string
+	VoteCount int
+}
+
+// PollVoteModel is a user's vote in a poll, with the text of the options
+// they picked in poll order.
+type PollVoteModel struct {
+	PostID    int64
+	UserID    int64
+	Options   []string
+	CreatedAt time.Time
+}
@@ -2,15 +2,89 @@ package model
 
 import (
 	"database/sql"
+	"regexp"
 	"time"
 )
 
+// Publication states of posts. Drafts and scheduled posts are only visible
+// to their author; a scheduled post is published once PublishAt passes.
+// Published posts cannot go back to either state.
+const (
+	PostDraft     = "draft"
+	PostScheduled = "scheduled"
+	PostPublished = "published"
+)
+
+// Reply policies of posts, deciding who besides the author can comment.
+const (
+	ReplyEveryone      = "everyone"
+	ReplyFollowers     = "followers"
+	ReplyMentionedOnly = "mentioned-only"
+	ReplyNobody        = "nobody"
+)
+
 type PostModel struct {
-	ID        int64
-	UserID    int64
-	Title     string
-	Content   string
-	DeletedAt sql.NullTime
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID      int64
+	UserID  int64
+	Title   string
+	Content string
+	Status  string
+	// PublishAt is when a scheduled post goes public, or when a published
+	// one did; it is null for drafts.
+	PublishAt sql.NullTime
+	// EditCount is how many times the post was edited after publishing.
+	EditCount int
+	// Version goes up with every change of the title, content or status,
+	// and backs the post's ETag.
+	Version     int
+	ReplyPolicy string
+	// RepliesLocked closes the thread to everyone but the author,
+	// whatever the reply policy.
+	RepliesLocked bool
+	DeletedAt     sql.NullTime
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CanReply reports whether the user, known by ID and username and by
+// whether they follow the author, may comment on the post. The author
+// always can; anonymous users never can.
+func (p *PostModel) CanReply(userID int64, username string, followsAuthor bool) bool {
+	if userID == 0 {
+		return false
+	}
+	if p.UserID == userID {
+		return true
+	}
+	if p.RepliesLocked {
+		return false
+	}
+	switch p.ReplyPolicy {
+	case ReplyEveryone:
+		return true
+	case ReplyFollowers:
+		return followsAuthor
+	case ReplyMentionedOnly:
+		return p.Mentions(username)
+	default:
+		return false
+	}
+}
+
+// Mentions reports whether the title or content @mentions the username,
+// ignoring case. Usernames may contain dots and hyphens, so those end a
+// mention only when trailing it, like the period ending a sentence.
+func (p *PostModel) Mentions(username string) bool {
+	if username == "" {
+		return false
+	}
+	mention := regexp.MustCompile(`(?i)@` + regexp.QuoteMeta(username) + `[.-]*(?:$|[^\w.-])`)
+	return mention.MatchString(p.Title) || mention.MatchString(p.Content)
+}
+
+// PinnedPostModel is the post a user pinned to their profile.
+type PinnedPostModel struct {
+	UserID   int64
+	PostID   int64
+	PinnedAt time.Time
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PostRevisionModel is a version of a published post that an edit
+// replaced; CreatedAt is when that version was written.
+type PostRevisionModel struct {
+	ID        int64
+	PostID    int64
+	Title     string
+	Content   string
+	CreatedAt time.Time
+}
+
+// CommentRevisionModel is a version of a comment that an edit replaced.
+type CommentRevisionModel struct {
+	ID        int64
+	CommentID int64
+	Content   string
+	CreatedAt time.Time
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+const (
+	RoleAdmin     = "admin"
+	RoleModerator = "moderator"
+)
+
+const (
+	PermissionModeratePosts    = "moderate:posts"
+	PermissionModerateComments = "moderate:comments"
+	PermissionReadAuditLog     = "audit:read"
+	PermissionManageRoles      = "roles:manage"
+	PermissionManageInvites    = "invites:manage"
+)
+
+// RolePermissions lists what each role may do beyond acting on the user's
+// own content. Users without a role only get ownership-based access.
+var RolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermissionModeratePosts,
+		PermissionModerateComments,
+		PermissionReadAuditLog,
+		PermissionManageRoles,
+		PermissionManageInvites,
+	},
+	RoleModerator: {
+		PermissionModeratePosts,
+		PermissionModerateComments,
+		PermissionReadAuditLog,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+func HasPermission(roles []string, permission string) bool {
+	for _, role := range roles {
+		for _, p := range RolePermissions[role] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type UserRoleModel struct {
+	ID        int64
+	UserID    int64
+	Role      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Kinds of items in a user's trash.
+const (
+	TrashPost    = "post"
+	TrashComment = "comment"
+)
+
+// TrashItemModel is a post or comment its author deleted. Title is empty
+// for comments; PostID is the post a comment was on, or the post itself.
+type TrashItemModel struct {
+	Type      string
+	ID        int64
+	PostID    int64
+	Title     string
+	Content   string
+	DeletedAt time.Time
+}
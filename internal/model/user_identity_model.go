@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// UserIdentityModel links a user to an account at an external OpenID
+// Connect provider. A user may have one per provider.
+type UserIdentityModel struct {
+	ID        int64
+	UserID    int64
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCLoginStateModel remembers an outstanding provider redirect so the
+// callback can check its state and the ID token's nonce.
+type OIDCLoginStateModel struct {
+	ID        int64
+	StateHash string
+	Provider  string
+	Nonce     string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
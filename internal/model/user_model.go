@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type (
 	UserModel struct {
@@ -8,6 +11,11 @@ type (
 		Username string
 		Email string
 		Password string
+		// DeactivatedAt is set while the account is deactivated and stays set
+		// once it is purged. PurgeAfter ends the grace period for restoring it
+		// and is cleared by the purge.
+		DeactivatedAt sql.NullTime
+		PurgeAfter sql.NullTime
 		CreatedAt time.Time
 		UpdatedAt time.Time
 	}
@@ -19,5 +27,15 @@ type (
 		ExpiresAt time.Time
 		CreatedAt time.Time
 		UpdatedAt time.Time
-	}	
+	}
+
+	UserTOTPModel struct {
+		ID int64
+		UserID int64
+		Secret string
+		LastUsedStep int64
+		ConfirmedAt sql.NullTime
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
 )
\ No newline at end of file
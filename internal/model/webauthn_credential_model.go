@@ -0,0 +1,36 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WebAuthn ceremonies a challenge may be used for.
+const (
+	WebAuthnCeremonyRegistration   = "registration"
+	WebAuthnCeremonyAuthentication = "authentication"
+)
+
+// WebAuthnCredentialModel is a passkey registered by a user. SignCount is
+// the authenticator's last reported signature counter.
+type WebAuthnCredentialModel struct {
+	ID           int64
+	UserID       int64
+	Name         string
+	CredentialID []byte
+	PublicKey    []byte
+	SignCount    uint32
+	LastUsedAt   sql.NullTime
+	CreatedAt    time.Time
+}
+
+// WebAuthnChallengeModel is an outstanding ceremony challenge. It is bound
+// to the user it was issued for and can be consumed once.
+type WebAuthnChallengeModel struct {
+	ID            int64
+	UserID        int64
+	ChallengeHash string
+	Ceremony      string
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"context"
+	"go-twitter/internal/model"
+)
+
+func (r *auditRepository) CreateAuditLog(ctx context.Context, log *model.AuditLogModel) error {
+	query := `INSERT INTO audit_logs (actor_id, target_user_id, action, entity_type, entity_id, created_at) VALUES (?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query, log.ActorID, log.TargetUserID, log.Action, log.EntityType, log.EntityID)
+	return err
+}
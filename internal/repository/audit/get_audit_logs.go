@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"go-twitter/internal/model"
+)
+
+func (r *auditRepository) GetAuditLogs(ctx context.Context, limit, offset int) ([]*model.AuditLogModel, int64, error) {
+	query := `SELECT id, actor_id, target_user_id, action, entity_type, entity_id, created_at
+	          FROM audit_logs
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var logs []*model.AuditLogModel
+	for rows.Next() {
+		var log model.AuditLogModel
+		err := rows.Scan(&log.ID, &log.ActorID, &log.TargetUserID, &log.Action, &log.EntityType, &log.EntityID, &log.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, &log)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM audit_logs`
+	var totalCount int64
+	err = r.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, totalCount, nil
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+)
+
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, log *model.AuditLogModel) error
+	GetAuditLogs(ctx context.Context, limit, offset int) ([]*model.AuditLogModel, int64, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
@@ -2,20 +2,52 @@ package comment
 
 import (
 	"context"
+	"database/sql"
 	"go-twitter/internal/model"
 )
 
-func (r *commentRepository) CreateComment(ctx context.Context, comment *model.CommentModel) (int64, error) {
-	query := `INSERT INTO comments (post_id, user_id, content, created_at, updated_at) VALUES (?, ?, ?, NOW(), NOW())`
-	result, err := r.db.ExecContext(ctx, query, comment.PostID, comment.UserID, comment.Content)
+// CreateComment adds the comment to a published post. It returns false,
+// creating nothing, if the post does not exist, is deleted, or is a draft
+// or scheduled, so unpublished posts never gain comments.
+func (r *commentRepository) CreateComment(ctx context.Context, comment *model.CommentModel) (int64, bool, error) {
+	query := `INSERT INTO comments (post_id, user_id, content, created_at, updated_at)
+	          SELECT id, ?, ?, NOW(), NOW() FROM posts WHERE id = ? AND status = ? AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, comment.UserID, comment.Content, comment.PostID, model.PostPublished)
 	if err != nil {
-		return 0, err
+		return 0, false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+	if affected == 0 {
+		return 0, false, nil
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return 0, err
+		return 0, false, err
 	}
 
-	return id, nil
+	return id, true, nil
+}
+
+// GetPostForReply returns the author, title, content and reply controls of
+// the post if it can gain comments: it is published, not deleted, and its
+// author is active. It returns nil otherwise.
+func (r *commentRepository) GetPostForReply(ctx context.Context, postID int64) (*model.PostModel, error) {
+	query := `SELECT p.id, p.user_id, p.title, p.content, p.reply_policy, p.replies_locked
+	          FROM posts p
+	          JOIN users u ON p.user_id = u.id
+	          WHERE p.id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL`
+	var post model.PostModel
+	err := r.db.QueryRowContext(ctx, query, postID, model.PostPublished).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.ReplyPolicy, &post.RepliesLocked)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &post, nil
 }
@@ -6,12 +6,20 @@ import (
 	"go-twitter/internal/model"
 )
 
+// GetCommentByID returns nil for comments of deleted posts, which are hidden
+// with the post until it is restored.
 func (r *commentRepository) GetCommentByID(ctx context.Context, id int64) (*model.CommentModel, error) {
-	query := `SELECT id, post_id, user_id, content, deleted_at, created_at, updated_at FROM comments WHERE id = ? AND deleted_at IS NULL`
+	query := `
+		SELECT c.id, c.post_id, c.user_id, c.content, c.edit_count, c.version, c.deleted_at, c.created_at, c.updated_at
+		FROM comments c
+		JOIN users u ON c.user_id = u.id
+		JOIN posts p ON c.post_id = p.id
+		WHERE c.id = ? AND c.deleted_at IS NULL AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
+	`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var comment model.CommentModel
-	err := row.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.DeletedAt, &comment.CreatedAt, &comment.UpdatedAt)
+	err := row.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.EditCount, &comment.Version, &comment.DeletedAt, &comment.CreatedAt, &comment.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -23,7 +31,7 @@ func (r *commentRepository) GetCommentByID(ctx context.Context, id int64) (*mode
 }
 
 func (r *commentRepository) GetCommentLikesCount(ctx context.Context, commentID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM comment_likes WHERE comment_id = ?`
+	query := `SELECT COUNT(*) FROM comment_likes cl JOIN users u ON cl.user_id = u.id WHERE cl.comment_id = ? AND u.deactivated_at IS NULL`
 	var count int
 	err := r.db.QueryRowContext(ctx, query, commentID).Scan(&count)
 	return count, err
@@ -6,10 +6,11 @@ import (
 )
 
 func (r *commentRepository) GetCommentsByPostID(ctx context.Context, postID int64, offset, limit int) ([]*model.CommentModel, int64, error) {
-	query := `SELECT id, post_id, user_id, content, deleted_at, created_at, updated_at
-	          FROM comments
-	          WHERE post_id = ? AND deleted_at IS NULL
-	          ORDER BY created_at DESC
+	query := `SELECT c.id, c.post_id, c.user_id, c.content, c.edit_count, c.version, c.deleted_at, c.created_at, c.updated_at
+	          FROM comments c
+	          JOIN users u ON c.user_id = u.id
+	          WHERE c.post_id = ? AND c.deleted_at IS NULL AND u.deactivated_at IS NULL
+	          ORDER BY c.created_at DESC
 	          LIMIT ? OFFSET ?`
 
 	rows, err := r.db.QueryContext(ctx, query, postID, limit, offset)
@@ -21,14 +22,14 @@ func (r *commentRepository) GetCommentsByPostID(ctx context.Context, postID int6
 	var comments []*model.CommentModel
 	for rows.Next() {
 		var comment model.CommentModel
-		err := rows.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.DeletedAt, &comment.CreatedAt, &comment.UpdatedAt)
+		err := rows.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.EditCount, &comment.Version, &comment.DeletedAt, &comment.CreatedAt, &comment.UpdatedAt)
 		if err != nil {
 			return nil, 0, err
 		}
 		comments = append(comments, &comment)
 	}
 
-	countQuery := `SELECT COUNT(*) FROM comments WHERE post_id = ? AND deleted_at IS NULL`
+	countQuery := `SELECT COUNT(*) FROM comments c JOIN users u ON c.user_id = u.id WHERE c.post_id = ? AND c.deleted_at IS NULL AND u.deactivated_at IS NULL`
 	var totalCount int64
 	err = r.db.QueryRowContext(ctx, countQuery, postID).Scan(&totalCount)
 	if err != nil {
@@ -37,3 +38,16 @@ func (r *commentRepository) GetCommentsByPostID(ctx context.Context, postID int6
 
 	return comments, totalCount, nil
 }
+
+// IsPostPublished reports whether the post exists and its comments can be
+// read: it is published, not deleted, and its author is active.
+func (r *commentRepository) IsPostPublished(ctx context.Context, postID int64) (bool, error) {
+	query := `SELECT EXISTS (
+	              SELECT 1 FROM posts p
+	              JOIN users u ON p.user_id = u.id
+	              WHERE p.id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
+	          )`
+	var published bool
+	err := r.db.QueryRowContext(ctx, query, postID, model.PostPublished).Scan(&published)
+	return published, err
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"go-twitter/internal/model"
+	"time"
 )
 
 type CommentRepository interface {
-	CreateComment(ctx context.Context, comment *model.CommentModel) (int64, error)
+	CreateComment(ctx context.Context, comment *model.CommentModel) (int64, bool, error)
+	GetPostForReply(ctx context.Context, postID int64) (*model.PostModel, error)
 	GetCommentByID(ctx context.Context, id int64) (*model.CommentModel, error)
 	GetCommentsByPostID(ctx context.Context, postID int64, offset, limit int) ([]*model.CommentModel, int64, error)
-	UpdateComment(ctx context.Context, comment *model.CommentModel) error
-	DeleteComment(ctx context.Context, id int64) error
+	IsPostPublished(ctx context.Context, postID int64) (bool, error)
+	UpdateComment(ctx context.Context, comment *model.CommentModel) (bool, error)
+	GetCommentRevisions(ctx context.Context, commentID int64) ([]*model.CommentRevisionModel, error)
+	DeleteComment(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error
+	RestoreComment(ctx context.Context, id, userID int64, since time.Time) (bool, error)
 	GetCommentLikesCount(ctx context.Context, commentID int64) (int, error)
 }
 
@@ -0,0 +1,25 @@
+package comment
+
+import (
+	"context"
+	"time"
+)
+
+// RestoreComment takes the comment out of the trash if the user deleted it
+// after since and its post is not deleted. It returns false, changing
+// nothing, otherwise.
+func (r *commentRepository) RestoreComment(ctx context.Context, id, userID int64, since time.Time) (bool, error) {
+	query := `UPDATE comments c
+	          JOIN posts p ON p.id = c.post_id
+	          SET c.deleted_at = NULL, c.deleted_by = NULL, c.version = c.version + 1
+	          WHERE c.id = ? AND c.user_id = ? AND c.deleted_by = ? AND c.deleted_at > ? AND p.deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, userID, userID, since)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
@@ -2,11 +2,71 @@ package comment
 
 import (
 	"context"
+	"database/sql"
 	"go-twitter/internal/model"
 )
 
-func (r *commentRepository) UpdateComment(ctx context.Context, comment *model.CommentModel) error {
-	query := `UPDATE comments SET content = ?, updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, comment.Content, comment.ID)
-	return err
+// UpdateComment replaces the content if the comment is still at
+// comment.Version, returning false otherwise. The version it replaces is
+// kept in comment_revisions.
+func (r *commentRepository) UpdateComment(ctx context.Context, comment *model.CommentModel) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var current model.CommentModel
+	query := `SELECT content, version, updated_at FROM comments WHERE id = ? AND deleted_at IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, comment.ID).Scan(&current.Content, &current.Version, &current.UpdatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if current.Version != comment.Version {
+		return false, nil
+	}
+
+	if current.Content == comment.Content {
+		query = `UPDATE comments SET version = version + 1, updated_at = NOW() WHERE id = ?`
+		if _, err := tx.ExecContext(ctx, query, comment.ID); err != nil {
+			return false, err
+		}
+	} else {
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_revisions (comment_id, content, created_at) VALUES (?, ?, ?)`,
+			comment.ID, current.Content, current.UpdatedAt)
+		if err != nil {
+			return false, err
+		}
+		query = `UPDATE comments SET content = ?, edit_count = edit_count + 1, version = version + 1, updated_at = NOW() WHERE id = ?`
+		if _, err := tx.ExecContext(ctx, query, comment.Content, comment.ID); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetCommentRevisions returns the versions edits of the comment replaced,
+// oldest first.
+func (r *commentRepository) GetCommentRevisions(ctx context.Context, commentID int64) ([]*model.CommentRevisionModel, error) {
+	query := `SELECT id, comment_id, content, created_at FROM comment_revisions WHERE comment_id = ? ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*model.CommentRevisionModel
+	for rows.Next() {
+		var revision model.CommentRevisionModel
+		if err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Content, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
 }
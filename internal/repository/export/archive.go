@@ -0,0 +1,434 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+)
+
+// The queries below read everything the archive covers, including content
+// the user has deleted but which is still stored.
+
+func (r *exportRepository) GetPostsByUserID(ctx context.Context, userID int64) ([]*model.PostModel, error) {
+	query := `SELECT id, user_id, title, content, deleted_at, created_at, updated_at FROM posts WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*model.PostModel
+	for rows.Next() {
+		var post model.PostModel
+		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, &post)
+	}
+	return posts, rows.Err()
+}
+
+func (r *exportRepository) GetCommentsByUserID(ctx context.Context, userID int64) ([]*model.CommentModel, error) {
+	query := `SELECT id, post_id, user_id, content, deleted_at, created_at, updated_at FROM comments WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*model.CommentModel
+	for rows.Next() {
+		var comment model.CommentModel
+		err := rows.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.DeletedAt, &comment.CreatedAt, &comment.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+	return comments, rows.Err()
+}
+
+func (r *exportRepository) GetPostLikesByUserID(ctx context.Context, userID int64) ([]*model.PostLikeModel, error) {
+	query := `SELECT id, post_id, user_id, created_at, updated_at FROM post_likes WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var likes []*model.PostLikeModel
+	for rows.Next() {
+		var like model.PostLikeModel
+		if err := rows.Scan(&like.ID, &like.PostID, &like.UserID, &like.CreatedAt, &like.UpdatedAt); err != nil {
+			return nil, err
+		}
+		likes = append(likes, &like)
+	}
+	return likes, rows.Err()
+}
+
+func (r *exportRepository) GetCommentLikesByUserID(ctx context.Context, userID int64) ([]*model.CommentLikeModel, error) {
+	query := `SELECT id, comment_id, user_id, created_at, updated_at FROM comment_likes WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var likes []*model.CommentLikeModel
+	for rows.Next() {
+		var like model.CommentLikeModel
+		if err := rows.Scan(&like.ID, &like.CommentID, &like.UserID, &like.CreatedAt, &like.UpdatedAt); err != nil {
+			return nil, err
+		}
+		likes = append(likes, &like)
+	}
+	return likes, rows.Err()
+}
+
+// GetRefreshTokensByUserID leaves out the token itself, which is a
+// credential rather than data about the user.
+func (r *exportRepository) GetRefreshTokensByUserID(ctx context.Context, userID int64) ([]*model.RefreshTokenModel, error) {
+	query := `SELECT id, user_id, expires_at, created_at, updated_at FROM refresh_tokens WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*model.RefreshTokenModel
+	for rows.Next() {
+		var token model.RefreshTokenModel
+		if err := rows.Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.CreatedAt, &token.UpdatedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, &token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *exportRepository) GetLoginAttemptsByUserID(ctx context.Context, userID int64) ([]*model.LoginAttemptModel, error) {
+	query := `SELECT id, user_id, email, ip_address, user_agent, success, created_at FROM login_attempts WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*model.LoginAttemptModel
+	for rows.Next() {
+		var attempt model.LoginAttemptModel
+		err := rows.Scan(&attempt.ID, &attempt.UserID, &attempt.Email, &attempt.IPAddress, &attempt.UserAgent, &attempt.Success, &attempt.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, rows.Err()
+}
+
+func (r *exportRepository) GetUserIdentitiesByUserID(ctx context.Context, userID int64) ([]*model.UserIdentityModel, error) {
+	query := `SELECT id, user_id, provider, subject, email, created_at FROM user_identities WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []*model.UserIdentityModel
+	for rows.Next() {
+		var identity model.UserIdentityModel
+		if err := rows.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, &identity)
+	}
+	return identities, rows.Err()
+}
+
+// GetInviteRedemptionsByUserID returns both the redemptions of the user's
+// invite codes and the one that let the user register. Username is always
+// the invited user's.
+func (r *exportRepository) GetInviteRedemptionsByUserID(ctx context.Context, userID int64) ([]*model.InviteRedemptionModel, error) {
+	query := `
+		SELECT r.id, r.invite_code_id, r.inviter_id, r.user_id, u.username, r.created_at
+		FROM invite_redemptions r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.inviter_id = ? OR r.user_id = ?
+		ORDER BY r.created_at, r.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redemptions []*model.InviteRedemptionModel
+	for rows.Next() {
+		var redemption model.InviteRedemptionModel
+		err := rows.Scan(&redemption.ID, &redemption.InviteCodeID, &redemption.InviterID, &redemption.UserID, &redemption.Username, &redemption.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, &redemption)
+	}
+	return redemptions, rows.Err()
+}
+
+func (r *exportRepository) GetOAuthClientsByOwner(ctx context.Context, userID int64) ([]*model.OAuthClientModel, error) {
+	query := `SELECT id, client_id, client_secret_hash, name, redirect_uris, scopes, owner_user_id, created_at FROM oauth_clients WHERE owner_user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []*model.OAuthClientModel
+	for rows.Next() {
+		var client model.OAuthClientModel
+		var secretHash sql.NullString
+		var redirectURIs, scopes string
+		err := rows.Scan(&client.ID, &client.ClientID, &secretHash, &client.Name, &redirectURIs, &scopes, &client.OwnerUserID, &client.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		client.ClientSecretHash = secretHash.String
+		client.RedirectURIs = strings.Split(redirectURIs, "\n")
+		client.Scopes = strings.Fields(scopes)
+		clients = append(clients, &client)
+	}
+	return clients, rows.Err()
+}
+
+// GetOAuthGrantsByUserID returns the refresh tokens the user granted to
+// OAuth clients, without the tokens themselves.
+func (r *exportRepository) GetOAuthGrantsByUserID(ctx context.Context, userID int64) ([]*model.OAuthRefreshTokenModel, error) {
+	query := `SELECT id, client_id, user_id, scopes, expires_at, revoked_at, created_at FROM oauth_refresh_tokens WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []*model.OAuthRefreshTokenModel
+	for rows.Next() {
+		var grant model.OAuthRefreshTokenModel
+		var scopes string
+		err := rows.Scan(&grant.ID, &grant.ClientID, &grant.UserID, &scopes, &grant.ExpiresAt, &grant.RevokedAt, &grant.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		grant.Scopes = strings.Fields(scopes)
+		grants = append(grants, &grant)
+	}
+	return grants, rows.Err()
+}
+
+func (r *exportRepository) GetBookmarkFoldersByUserID(ctx context.Context, userID int64) ([]*model.BookmarkFolderModel, error) {
+	query := `SELECT id, user_id, name, created_at FROM bookmark_folders WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []*model.BookmarkFolderModel
+	for rows.Next() {
+		var folder model.BookmarkFolderModel
+		if err := rows.Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.CreatedAt); err != nil {
+			return nil, err
+		}
+		folders = append(folders, &folder)
+	}
+	return folders, rows.Err()
+}
+
+func (r *exportRepository) GetBookmarksByUserID(ctx context.Context, userID int64) ([]*model.BookmarkModel, error) {
+	query := `SELECT id, user_id, post_id, folder_id, created_at FROM bookmarks WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookmarks []*model.BookmarkModel
+	for rows.Next() {
+		var bookmark model.BookmarkModel
+		if err := rows.Scan(&bookmark.ID, &bookmark.UserID, &bookmark.PostID, &bookmark.FolderID, &bookmark.CreatedAt); err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, &bookmark)
+	}
+	return bookmarks, rows.Err()
+}
+
+func (r *exportRepository) GetPollVotesByUserID(ctx context.Context, userID int64) ([]*model.PollVoteModel, error) {
+	query := `
+		SELECT v.post_id, v.user_id, v.created_at, o.text
+		FROM poll_votes v
+		JOIN poll_vote_options vo ON vo.post_id = v.post_id AND vo.user_id = v.user_id
+		JOIN poll_options o ON o.id = vo.option_id
+		WHERE v.user_id = ?
+		ORDER BY v.created_at, v.post_id, o.position
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var votes []*model.PollVoteModel
+	for rows.Next() {
+		var vote model.PollVoteModel
+		var option string
+		if err := rows.Scan(&vote.PostID, &vote.UserID, &vote.CreatedAt, &option); err != nil {
+			return nil, err
+		}
+		// a multiple choice vote spans one row per option
+		if n := len(votes); n > 0 && votes[n-1].PostID == vote.PostID {
+			votes[n-1].Options = append(votes[n-1].Options, option)
+			continue
+		}
+		vote.Options = []string{option}
+		votes = append(votes, &vote)
+	}
+	return votes, rows.Err()
+}
+
+func (r *exportRepository) GetMediaByUserID(ctx context.Context, userID int64) ([]*model.MediaModel, error) {
+	query := `SELECT id, user_id, blob_key, content_type, size_bytes, alt_text, processing_status, width, height, blurhash, created_at FROM media WHERE user_id = ? ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*model.MediaModel
+	for rows.Next() {
+		var m model.MediaModel
+		err := rows.Scan(&m.ID, &m.UserID, &m.BlobKey, &m.ContentType, &m.SizeBytes, &m.AltText, &m.ProcessingStatus, &m.Width, &m.Height, &m.Blurhash, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, &m)
+	}
+	return media, rows.Err()
+}
+
+func (r *exportRepository) GetImportJobsByUserID(ctx context.Context, userID int64) ([]*model.ImportJobModel, error) {
+	query := `
+		SELECT id, user_id, source, status, blob_key, total_items, processed_items, imported_items, skipped_items, failed_items, error, started_at, completed_at, created_at
+		FROM import_jobs WHERE user_id = ? ORDER BY created_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*model.ImportJobModel
+	for rows.Next() {
+		var job model.ImportJobModel
+		err := rows.Scan(&job.ID, &job.UserID, &job.Source, &job.Status, &job.BlobKey, &job.TotalItems, &job.ProcessedItems, &job.ImportedItems,
+			&job.SkippedItems, &job.FailedItems, &job.Error, &job.StartedAt, &job.CompletedAt, &job.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+func (r *exportRepository) GetPostRevisionsByUserID(ctx context.Context, userID int64) ([]*model.PostRevisionModel, error) {
+	query := `
+		SELECT pr.id, pr.post_id, pr.title, pr.content, pr.created_at
+		FROM post_revisions pr
+		JOIN posts p ON p.id = pr.post_id
+		WHERE p.user_id = ?
+		ORDER BY pr.post_id, pr.created_at, pr.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*model.PostRevisionModel
+	for rows.Next() {
+		var revision model.PostRevisionModel
+		if err := rows.Scan(&revision.ID, &revision.PostID, &revision.Title, &revision.Content, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *exportRepository) GetCommentRevisionsByUserID(ctx context.Context, userID int64) ([]*model.CommentRevisionModel, error) {
+	query := `
+		SELECT cr.id, cr.comment_id, cr.content, cr.created_at
+		FROM comment_revisions cr
+		JOIN comments c ON c.id = cr.comment_id
+		WHERE c.user_id = ?
+		ORDER BY cr.comment_id, cr.created_at, cr.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*model.CommentRevisionModel
+	for rows.Next() {
+		var revision model.CommentRevisionModel
+		if err := rows.Scan(&revision.ID, &revision.CommentID, &revision.Content, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
+}
+
+// GetPinnedPost returns the user's pin, or nil if they have none.
+func (r *exportRepository) GetPinnedPost(ctx context.Context, userID int64) (*model.PinnedPostModel, error) {
+	query := `SELECT user_id, post_id, pinned_at FROM pinned_posts WHERE user_id = ?`
+
+	var pin model.PinnedPostModel
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&pin.UserID, &pin.PostID, &pin.PinnedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &pin, nil
+}
+
+// GetFollowsByUserID returns who the user follows and who follows them.
+// Username is the other user's.
+func (r *exportRepository) GetFollowsByUserID(ctx context.Context, userID int64) ([]*model.FollowModel, error) {
+	query := `
+		SELECT f.follower_id, f.followee_id, u.username, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = IF(f.follower_id = ?, f.followee_id, f.follower_id)
+		WHERE f.follower_id = ? OR f.followee_id = ?
+		ORDER BY f.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var follows []*model.FollowModel
+	for rows.Next() {
+		var follow model.FollowModel
+		if err := rows.Scan(&follow.FollowerID, &follow.FolloweeID, &follow.Username, &follow.CreatedAt); err != nil {
+			return nil, err
+		}
+		follows = append(follows, &follow)
+	}
+	return follows, rows.Err()
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const dataExportColumns = `id, user_id, status, blob_key, size_bytes, started_at, completed_at, expires_at, created_at`
+
+func scanDataExport(row rowScanner) (*model.DataExportModel, error) {
+	var export model.DataExportModel
+	err := row.Scan(&export.ID, &export.UserID, &export.Status, &export.BlobKey, &export.SizeBytes,
+		&export.StartedAt, &export.CompletedAt, &export.ExpiresAt, &export.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *exportRepository) CreateDataExport(ctx context.Context, export *model.DataExportModel) (int64, error) {
+	query := `INSERT INTO data_exports (user_id, status, created_at) VALUES (?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, export.UserID, export.Status, export.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *exportRepository) GetDataExportByID(ctx context.Context, id int64) (*model.DataExportModel, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE id = ?`
+	export, err := scanDataExport(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// GetActiveDataExport returns the user's export that is still being built,
+// if any.
+func (r *exportRepository) GetActiveDataExport(ctx context.Context, userID int64) (*model.DataExportModel, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+	          WHERE user_id = ? AND status IN (?, ?)
+	          ORDER BY created_at DESC LIMIT 1`
+	export, err := scanDataExport(r.db.QueryRowContext(ctx, query, userID, model.DataExportPending, model.DataExportProcessing))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// ClaimDataExport marks the oldest pending export as processing and returns
+// it. Exports left processing since before staleBefore, e.g. by a crashed
+// server, are claimed again.
+func (r *exportRepository) ClaimDataExport(ctx context.Context, now, staleBefore time.Time) (*model.DataExportModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+	          WHERE status = ? OR (status = ? AND started_at < ?)
+	          ORDER BY created_at LIMIT 1 FOR UPDATE`
+	export, err := scanDataExport(tx.QueryRowContext(ctx, query, model.DataExportPending, model.DataExportProcessing, staleBefore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE data_exports SET status = ?, started_at = ? WHERE id = ?`, model.DataExportProcessing, now, export.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	export.Status = model.DataExportProcessing
+	export.StartedAt = sql.NullTime{Time: now, Valid: true}
+	return export, nil
+}
+
+func (r *exportRepository) CompleteDataExport(ctx context.Context, id int64, blobKey string, sizeBytes int64, completedAt, expiresAt time.Time) error {
+	query := `UPDATE data_exports SET status = ?, blob_key = ?, size_bytes = ?, completed_at = ?, expires_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, model.DataExportReady, blobKey, sizeBytes, completedAt, expiresAt, id)
+	return err
+}
+
+func (r *exportRepository) FailDataExport(ctx context.Context, id int64, now time.Time) error {
+	query := `UPDATE data_exports SET status = ?, completed_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, model.DataExportFailed, now, id)
+	return err
+}
+
+func (r *exportRepository) GetExpiredDataExports(ctx context.Context, now time.Time, limit int) ([]*model.DataExportModel, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+	          WHERE status = ? AND expires_at <= ?
+	          ORDER BY expires_at LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, model.DataExportReady, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []*model.DataExportModel
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	return exports, rows.Err()
+}
+
+// ExpireDataExport records that the export's archive has been deleted.
+func (r *exportRepository) ExpireDataExport(ctx context.Context, id int64) error {
+	query := `UPDATE data_exports SET status = ?, blob_key = NULL WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, model.DataExportExpired, id)
+	return err
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+type ExportRepository interface {
+	CreateDataExport(ctx context.Context, export *model.DataExportModel) (int64, error)
+	GetDataExportByID(ctx context.Context, id int64) (*model.DataExportModel, error)
+	GetActiveDataExport(ctx context.Context, userID int64) (*model.DataExportModel, error)
+	ClaimDataExport(ctx context.Context, now, staleBefore time.Time) (*model.DataExportModel, error)
+	CompleteDataExport(ctx context.Context, id int64, blobKey string, sizeBytes int64, completedAt, expiresAt time.Time) error
+	FailDataExport(ctx context.Context, id int64, now time.Time) error
+	GetExpiredDataExports(ctx context.Context, now time.Time, limit int) ([]*model.DataExportModel, error)
+	ExpireDataExport(ctx context.Context, id int64) error
+
+	GetPostsByUserID(ctx context.Context, userID int64) ([]*model.PostModel, error)
+	GetCommentsByUserID(ctx context.Context, userID int64) ([]*model.CommentModel, error)
+	GetPostLikesByUserID(ctx context.Context, userID int64) ([]*model.PostLikeModel, error)
+	GetCommentLikesByUserID(ctx context.Context, userID int64) ([]*model.CommentLikeModel, error)
+	GetRefreshTokensByUserID(ctx context.Context, userID int64) ([]*model.RefreshTokenModel, error)
+	GetLoginAttemptsByUserID(ctx context.Context, userID int64) ([]*model.LoginAttemptModel, error)
+	GetUserIdentitiesByUserID(ctx context.Context, userID int64) ([]*model.UserIdentityModel, error)
+	GetInviteRedemptionsByUserID(ctx context.Context, userID int64) ([]*model.InviteRedemptionModel, error)
+	GetOAuthClientsByOwner(ctx context.Context, userID int64) ([]*model.OAuthClientModel, error)
+	GetOAuthGrantsByUserID(ctx context.Context, userID int64) ([]*model.OAuthRefreshTokenModel, error)
+	GetBookmarkFoldersByUserID(ctx context.Context, userID int64) ([]*model.BookmarkFolderModel, error)
+	GetBookmarksByUserID(ctx context.Context, userID int64) ([]*model.BookmarkModel, error)
+	GetPollVotesByUserID(ctx context.Context, userID int64) ([]*model.PollVoteModel, error)
+	GetMediaByUserID(ctx context.Context, userID int64) ([]*model.MediaModel, error)
+	GetImportJobsByUserID(ctx context.Context, userID int64) ([]*model.ImportJobModel, error)
+	GetPostRevisionsByUserID(ctx context.Context, userID int64) ([]*model.PostRevisionModel, error)
+	GetCommentRevisionsByUserID(ctx context.Context, userID int64) ([]*model.CommentRevisionModel, error)
+	GetPinnedPost(ctx context.Context, userID int64) (*model.PinnedPostModel, error)
+	GetFollowsByUserID(ctx context.Context, userID int64) ([]*model.FollowModel, error)
+}
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{
+		db: db,
+	}
+}
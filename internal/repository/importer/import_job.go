@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+const importJobColumns = `id, user_id, source, status, blob_key, total_items, processed_items, imported_items,
+	skipped_items, failed_items, error, started_at, completed_at, created_at`
+
+func scanImportJob(row rowScanner) (*model.ImportJobModel, error) {
+	var job model.ImportJobModel
+	err := row.Scan(&job.ID, &job.UserID, &job.Source, &job.Status, &job.BlobKey, &job.TotalItems, &job.ProcessedItems,
+		&job.ImportedItems, &job.SkippedItems, &job.FailedItems, &job.Error, &job.StartedAt, &job.CompletedAt, &job.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importRepository) CreateImportJob(ctx context.Context, job *model.ImportJobModel) (int64, error) {
+	query := `INSERT INTO import_jobs (user_id, source, status, blob_key, created_at) VALUES (?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, job.UserID, job.Source, job.Status, job.BlobKey, job.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *importRepository) GetImportJobByID(ctx context.Context, id int64) (*model.ImportJobModel, error) {
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs WHERE id = ?`
+	job, err := scanImportJob(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetActiveImportJob returns the user's import that is still running, if
+// any.
+func (r *importRepository) GetActiveImportJob(ctx context.Context, userID int64) (*model.ImportJobModel, error) {
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs
+	          WHERE user_id = ? AND status IN (?, ?)
+	          ORDER BY created_at DESC LIMIT 1`
+	job, err := scanImportJob(r.db.QueryRowContext(ctx, query, userID, model.ImportPending, model.ImportProcessing))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// ClaimImportJob marks the oldest pending import as processing and returns
+// it. Imports left processing since before staleBefore are claimed again
+// from the start: their progress and items are reset, and tweets already
+// imported are skipped the second time round.
+func (r *importRepository) ClaimImportJob(ctx context.Context, now, staleBefore time.Time) (*model.ImportJobModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + importJobColumns + ` FROM import_jobs
+	          WHERE status = ? OR (status = ? AND started_at < ?)
+	          ORDER BY created_at LIMIT 1 FOR UPDATE`
+	job, err := scanImportJob(tx.QueryRowContext(ctx, query, model.ImportPending, model.ImportProcessing, staleBefore))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM import_job_items WHERE job_id = ?`, job.ID); err != nil {
+		return nil, err
+	}
+	update := `UPDATE import_jobs SET status = ?, started_at = ?, total_items = 0, processed_items = 0,
+	           imported_items = 0, skipped_items = 0, failed_items = 0 WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, update, model.ImportProcessing, now, job.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	job.Status = model.ImportProcessing
+	job.StartedAt = sql.NullTime{Time: now, Valid: true}
+	job.TotalItems, job.ProcessedItems, job.ImportedItems, job.SkippedItems, job.FailedItems = 0, 0, 0, 0, 0
+	return job, nil
+}
+
+// UpdateImportJobProgress saves the job's item counters. The started_at
+// bump keeps a long running import from being claimed as stale.
+func (r *importRepository) UpdateImportJobProgress(ctx context.Context, job *model.ImportJobModel) error {
+	query := `UPDATE import_jobs SET total_items = ?, processed_items = ?, imported_items = ?, skipped_items = ?,
+	          failed_items = ?, started_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, job.TotalItems, job.ProcessedItems, job.ImportedItems, job.SkippedItems,
+		job.FailedItems, time.Now(), job.ID)
+	return err
+}
+
+// FinishImportJob saves the job's final status, counters and error, and
+// forgets the uploaded archive.
+func (r *importRepository) FinishImportJob(ctx context.Context, job *model.ImportJobModel, now time.Time) error {
+	query := `UPDATE import_jobs SET status = ?, blob_key = NULL, total_items = ?, processed_items = ?, imported_items = ?,
+	          skipped_items = ?, failed_items = ?, error = ?, completed_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, job.Status, job.TotalItems, job.ProcessedItems, job.ImportedItems,
+		job.SkippedItems, job.FailedItems, job.Error, now, job.ID)
+	return err
+}
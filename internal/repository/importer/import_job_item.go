@@ -0,0 +1,45 @@
+package importer
+
+import (
+	"context"
+	"go-twitter/internal/model"
+)
+
+func (r *importRepository) CreateImportJobItem(ctx context.Context, item *model.ImportJobItemModel) error {
+	query := `INSERT INTO import_job_items (job_id, source_id, outcome, reason, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, item.JobID, item.SourceID, item.Outcome, item.Reason, item.CreatedAt)
+	return err
+}
+
+func (r *importRepository) GetImportJobItems(ctx context.Context, jobID int64, limit, offset int) ([]*model.ImportJobItemModel, int64, error) {
+	query := `SELECT id, job_id, source_id, outcome, reason, created_at
+	          FROM import_job_items
+	          WHERE job_id = ?
+	          ORDER BY id
+	          LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, jobID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*model.ImportJobItemModel
+	for rows.Next() {
+		var item model.ImportJobItemModel
+		if err := rows.Scan(&item.ID, &item.JobID, &item.SourceID, &item.Outcome, &item.Reason, &item.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM import_job_items WHERE job_id = ?`, jobID).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, totalCount, nil
+}
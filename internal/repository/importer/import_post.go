@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+)
+
+// ImportPost creates the post with its original timestamps and records
+// where it came from. It returns false, creating nothing, if the user
+// already imported sourceID from source.
+func (r *importRepository) ImportPost(ctx context.Context, post *model.PostModel, source, sourceID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var existing int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM imported_posts WHERE user_id = ? AND source = ? AND source_id = ? FOR UPDATE`,
+		post.UserID, source, sourceID).Scan(&existing)
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO posts (user_id, title, content, status, publish_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		post.UserID, post.Title, post.Content, model.PostPublished, post.CreatedAt, post.CreatedAt, post.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	postID, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO imported_posts (user_id, post_id, source, source_id) VALUES (?, ?, ?, ?)`,
+		post.UserID, postID, source, sourceID)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	post.ID = postID
+	return true, nil
+}
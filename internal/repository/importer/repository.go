@@ -0,0 +1,32 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+type ImportRepository interface {
+	CreateImportJob(ctx context.Context, job *model.ImportJobModel) (int64, error)
+	GetImportJobByID(ctx context.Context, id int64) (*model.ImportJobModel, error)
+	GetActiveImportJob(ctx context.Context, userID int64) (*model.ImportJobModel, error)
+	ClaimImportJob(ctx context.Context, now, staleBefore time.Time) (*model.ImportJobModel, error)
+	UpdateImportJobProgress(ctx context.Context, job *model.ImportJobModel) error
+	FinishImportJob(ctx context.Context, job *model.ImportJobModel, now time.Time) error
+
+	CreateImportJobItem(ctx context.Context, item *model.ImportJobItemModel) error
+	GetImportJobItems(ctx context.Context, jobID int64, limit, offset int) ([]*model.ImportJobItemModel, int64, error)
+
+	ImportPost(ctx context.Context, post *model.PostModel, source, sourceID string) (bool, error)
+}
+
+type importRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) ImportRepository {
+	return &importRepository{
+		db: db,
+	}
+}
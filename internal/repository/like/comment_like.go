@@ -5,10 +5,22 @@ import (
 	"database/sql"
 )
 
-func (r *likeRepository) LikeComment(ctx context.Context, commentID, userID int64) error {
-	query := `INSERT INTO comment_likes (comment_id, user_id, created_at, updated_at) VALUES (?, ?, NOW(), NOW())`
-	_, err := r.db.ExecContext(ctx, query, commentID, userID)
-	return err
+// LikeComment likes a comment. It returns false, recording nothing, if the
+// comment does not exist or it or its post is deleted.
+func (r *likeRepository) LikeComment(ctx context.Context, commentID, userID int64) (bool, error) {
+	query := `INSERT INTO comment_likes (comment_id, user_id, created_at, updated_at)
+	          SELECT c.id, ?, NOW(), NOW() FROM comments c
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE c.id = ? AND c.deleted_at IS NULL AND p.deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID, commentID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
 func (r *likeRepository) UnlikeComment(ctx context.Context, commentID, userID int64) error {
@@ -17,8 +29,13 @@ func (r *likeRepository) UnlikeComment(ctx context.Context, commentID, userID in
 	return err
 }
 
+// IsCommentLiked ignores likes on deleted comments and on comments of
+// deleted posts.
 func (r *likeRepository) IsCommentLiked(ctx context.Context, commentID, userID int64) (bool, error) {
-	query := `SELECT COUNT(*) FROM comment_likes WHERE comment_id = ? AND user_id = ?`
+	query := `SELECT COUNT(*) FROM comment_likes l
+	          JOIN comments c ON c.id = l.comment_id
+	          JOIN posts p ON p.id = c.post_id
+	          WHERE l.comment_id = ? AND l.user_id = ? AND c.deleted_at IS NULL AND p.deleted_at IS NULL`
 	var count int
 	err := r.db.QueryRowContext(ctx, query, commentID, userID).Scan(&count)
 	if err != nil {
@@ -30,9 +47,23 @@ func (r *likeRepository) IsCommentLiked(ctx context.Context, commentID, userID i
 	return count > 0, nil
 }
 
-func (r *likeRepository) GetCommentLikesCount(ctx context.Context, commentID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM comment_likes WHERE comment_id = ?`
+// GetCommentLikesCount returns false if the comment does not exist or it
+// or its post is deleted.
+func (r *likeRepository) GetCommentLikesCount(ctx context.Context, commentID int64) (int, bool, error) {
+	query := `SELECT COUNT(u.id)
+	          FROM comments c
+	          JOIN posts p ON p.id = c.post_id
+	          LEFT JOIN comment_likes l ON l.comment_id = c.id
+	          LEFT JOIN users u ON u.id = l.user_id AND u.deactivated_at IS NULL
+	          WHERE c.id = ? AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+	          GROUP BY c.id`
 	var count int
 	err := r.db.QueryRowContext(ctx, query, commentID).Scan(&count)
-	return count, err
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return count, true, nil
 }
@@ -3,12 +3,23 @@ package like
 import (
 	"context"
 	"database/sql"
+	"go-twitter/internal/model"
 )
 
-func (r *likeRepository) LikePost(ctx context.Context, postID, userID int64) error {
-	query := `INSERT INTO post_likes (post_id, user_id, created_at, updated_at) VALUES (?, ?, NOW(), NOW())`
-	_, err := r.db.ExecContext(ctx, query, postID, userID)
-	return err
+// LikePost likes a published post. It returns false, recording nothing,
+// if the post does not exist, is deleted, or is a draft or scheduled.
+func (r *likeRepository) LikePost(ctx context.Context, postID, userID int64) (bool, error) {
+	query := `INSERT INTO post_likes (post_id, user_id, created_at, updated_at)
+	          SELECT id, ?, NOW(), NOW() FROM posts WHERE id = ? AND status = ? AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID, postID, model.PostPublished)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
 func (r *likeRepository) UnlikePost(ctx context.Context, postID, userID int64) error {
@@ -17,8 +28,12 @@ func (r *likeRepository) UnlikePost(ctx context.Context, postID, userID int64) e
 	return err
 }
 
+// IsPostLiked ignores likes on deleted posts, which are hidden along with
+// the post until it is restored.
 func (r *likeRepository) IsPostLiked(ctx context.Context, postID, userID int64) (bool, error) {
-	query := `SELECT COUNT(*) FROM post_likes WHERE post_id = ? AND user_id = ?`
+	query := `SELECT COUNT(*) FROM post_likes l
+	          JOIN posts p ON p.id = l.post_id
+	          WHERE l.post_id = ? AND l.user_id = ? AND p.deleted_at IS NULL`
 	var count int
 	err := r.db.QueryRowContext(ctx, query, postID, userID).Scan(&count)
 	if err != nil {
@@ -30,9 +45,22 @@ func (r *likeRepository) IsPostLiked(ctx context.Context, postID, userID int64)
 	return count > 0, nil
 }
 
-func (r *likeRepository) GetPostLikesCount(ctx context.Context, postID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM post_likes WHERE post_id = ?`
+// GetPostLikesCount returns false if the post does not exist, is deleted,
+// or is a draft or scheduled.
+func (r *likeRepository) GetPostLikesCount(ctx context.Context, postID int64) (int, bool, error) {
+	query := `SELECT COUNT(u.id)
+	          FROM posts p
+	          LEFT JOIN post_likes l ON l.post_id = p.id
+	          LEFT JOIN users u ON u.id = l.user_id AND u.deactivated_at IS NULL
+	          WHERE p.id = ? AND p.status = ? AND p.deleted_at IS NULL
+	          GROUP BY p.id`
 	var count int
-	err := r.db.QueryRowContext(ctx, query, postID).Scan(&count)
-	return count, err
+	err := r.db.QueryRowContext(ctx, query, postID, model.PostPublished).Scan(&count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return count, true, nil
 }
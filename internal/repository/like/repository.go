@@ -6,15 +6,15 @@ import (
 )
 
 type LikeRepository interface {
-	LikePost(ctx context.Context, postID, userID int64) error
+	LikePost(ctx context.Context, postID, userID int64) (bool, error)
 	UnlikePost(ctx context.Context, postID, userID int64) error
 	IsPostLiked(ctx context.Context, postID, userID int64) (bool, error)
-	GetPostLikesCount(ctx context.Context, postID int64) (int, error)
+	GetPostLikesCount(ctx context.Context, postID int64) (int, bool, error)
 
-	LikeComment(ctx context.Context, commentID, userID int64) error
+	LikeComment(ctx context.Context, commentID, userID int64) (bool, error)
 	UnlikeComment(ctx context.Context, commentID, userID int64) error
 	IsCommentLiked(ctx context.Context, commentID, userID int64) (bool, error)
-	GetCommentLikesCount(ctx context.Context, commentID int64) (int, error)
+	GetCommentLikesCount(ctx context.Context, commentID int64) (int, bool, error)
 }
 
 type likeRepository struct {
@@ -0,0 +1,146 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+	"time"
+)
+
+const mediaColumns = `m.id, m.user_id, m.blob_key, m.content_type, m.size_bytes, m.alt_text,
+	m.processing_status, m.width, m.height, m.blurhash, m.created_at`
+
+func scanMedia(scanner interface{ Scan(...any) error }, media *model.MediaModel) error {
+	return scanner.Scan(&media.ID, &media.UserID, &media.BlobKey, &media.ContentType, &media.SizeBytes, &media.AltText,
+		&media.ProcessingStatus, &media.Width, &media.Height, &media.Blurhash, &media.CreatedAt)
+}
+
+func (r *mediaRepository) CreateMedia(ctx context.Context, media *model.MediaModel) (int64, error) {
+	query := `INSERT INTO media (user_id, blob_key, content_type, size_bytes, alt_text, processing_status, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, media.UserID, media.BlobKey, media.ContentType, media.SizeBytes, media.AltText,
+		media.ProcessingStatus, media.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetPublishedMediaByID returns the media, with its variants, only while it
+// is attached to a post that can be read: published, not deleted, and not
+// by a deactivated user.
+func (r *mediaRepository) GetPublishedMediaByID(ctx context.Context, id int64) (*model.MediaModel, error) {
+	query := `SELECT ` + mediaColumns + `
+	          FROM media m
+	          JOIN post_media pm ON pm.media_id = m.id
+	          JOIN posts p ON p.id = pm.post_id
+	          JOIN users u ON u.id = p.user_id
+	          WHERE m.id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL`
+	var media model.MediaModel
+	if err := scanMedia(r.db.QueryRowContext(ctx, query, id, model.PostPublished), &media); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.loadVariants(ctx, []*model.MediaModel{&media}); err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetOrphanedMedia returns uploads created before createdBefore that no
+// post links to, with their variants.
+func (r *mediaRepository) GetOrphanedMedia(ctx context.Context, createdBefore time.Time, limit int) ([]*model.MediaModel, error) {
+	query := `SELECT ` + mediaColumns + `
+	          FROM media m
+	          WHERE m.created_at < ? AND NOT EXISTS (SELECT 1 FROM post_media pm WHERE pm.media_id = m.id)
+	          ORDER BY m.id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, createdBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*model.MediaModel
+	for rows.Next() {
+		var m model.MediaModel
+		if err := scanMedia(rows, &m); err != nil {
+			return nil, err
+		}
+		media = append(media, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.loadVariants(ctx, media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// DeleteOrphanedMedia deletes the media row and its variants unless a post
+// linked it since it was found orphaned.
+func (r *mediaRepository) DeleteOrphanedMedia(ctx context.Context, id int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var linked bool
+	query := `SELECT EXISTS (SELECT 1 FROM post_media WHERE media_id = ?) FROM media WHERE id = ? FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, id, id).Scan(&linked); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if linked {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM media_variants WHERE media_id = ?`, id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM media WHERE id = ?`, id); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// loadVariants fills in the variants of each media, smallest first.
+func (r *mediaRepository) loadVariants(ctx context.Context, media []*model.MediaModel) error {
+	if len(media) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*model.MediaModel, len(media))
+	args := make([]any, len(media))
+	for i, m := range media {
+		byID[m.ID] = m
+		args[i] = m.ID
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(media)), ",")
+	query := `SELECT media_id, name, blob_key, content_type, width, height, size_bytes
+	          FROM media_variants
+	          WHERE media_id IN (` + placeholders + `)
+	          ORDER BY media_id, width`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v model.MediaVariantModel
+		if err := rows.Scan(&v.MediaID, &v.Name, &v.BlobKey, &v.ContentType, &v.Width, &v.Height, &v.SizeBytes); err != nil {
+			return err
+		}
+		byID[v.MediaID].Variants = append(byID[v.MediaID].Variants, v)
+	}
+	return rows.Err()
+}
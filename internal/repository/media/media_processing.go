@@ -0,0 +1,107 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+// GetMediaToProcess returns the IDs of pending media and of media whose
+// processing started before staleBefore, oldest first.
+func (r *mediaRepository) GetMediaToProcess(ctx context.Context, staleBefore time.Time, limit int) ([]int64, error) {
+	query := `SELECT id FROM media
+	          WHERE processing_status = ? OR (processing_status = ? AND processing_started_at < ?)
+	          ORDER BY id LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, model.MediaPending, model.MediaProcessing, staleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ClaimMediaProcessing marks the media as processing and returns it, or
+// returns nil if it is not pending and no earlier claim went stale.
+func (r *mediaRepository) ClaimMediaProcessing(ctx context.Context, id int64, staleBefore time.Time) (*model.MediaModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + mediaColumns + `
+	          FROM media m
+	          WHERE m.id = ? AND (m.processing_status = ? OR (m.processing_status = ? AND m.processing_started_at < ?))
+	          FOR UPDATE`
+	var media model.MediaModel
+	if err := scanMedia(tx.QueryRowContext(ctx, query, id, model.MediaPending, model.MediaProcessing, staleBefore), &media); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE media SET processing_status = ?, processing_started_at = NOW() WHERE id = ?`, model.MediaProcessing, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	media.ProcessingStatus = model.MediaProcessing
+	return &media, nil
+}
+
+// CompleteMediaProcessing replaces the upload with its processed file and
+// stores the variants. It returns false, storing nothing, if the media was
+// deleted or another worker finished it first.
+func (r *mediaRepository) CompleteMediaProcessing(ctx context.Context, media *model.MediaModel) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE media SET blob_key = ?, size_bytes = ?, width = ?, height = ?, blurhash = ?, processing_status = ?
+	          WHERE id = ? AND processing_status = ?`
+	result, err := tx.ExecContext(ctx, query, media.BlobKey, media.SizeBytes, media.Width, media.Height, media.Blurhash,
+		model.MediaReady, media.ID, model.MediaProcessing)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	for _, v := range media.Variants {
+		query := `INSERT INTO media_variants (media_id, name, blob_key, content_type, width, height, size_bytes) VALUES (?, ?, ?, ?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, media.ID, v.Name, v.BlobKey, v.ContentType, v.Width, v.Height, v.SizeBytes); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FailMediaProcessing marks media that could not be processed as failed.
+func (r *mediaRepository) FailMediaProcessing(ctx context.Context, id int64) error {
+	query := `UPDATE media SET processing_status = ? WHERE id = ? AND processing_status = ?`
+	_, err := r.db.ExecContext(ctx, query, model.MediaFailed, id, model.MediaProcessing)
+	return err
+}
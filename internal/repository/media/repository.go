@@ -0,0 +1,29 @@
+package media
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+type MediaRepository interface {
+	CreateMedia(ctx context.Context, media *model.MediaModel) (int64, error)
+	GetPublishedMediaByID(ctx context.Context, id int64) (*model.MediaModel, error)
+	GetOrphanedMedia(ctx context.Context, createdBefore time.Time, limit int) ([]*model.MediaModel, error)
+	DeleteOrphanedMedia(ctx context.Context, id int64) (bool, error)
+	GetMediaToProcess(ctx context.Context, staleBefore time.Time, limit int) ([]int64, error)
+	ClaimMediaProcessing(ctx context.Context, id int64, staleBefore time.Time) (*model.MediaModel, error)
+	CompleteMediaProcessing(ctx context.Context, media *model.MediaModel) (bool, error)
+	FailMediaProcessing(ctx context.Context, id int64) error
+}
+
+type mediaRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) MediaRepository {
+	return &mediaRepository{
+		db: db,
+	}
+}
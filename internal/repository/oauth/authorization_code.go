@@ -0,0 +1,55 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+	"time"
+)
+
+func (r *oauthRepository) CreateAuthorizationCode(ctx context.Context, code *model.OAuthAuthorizationCodeModel) error {
+	query := `INSERT INTO oauth_authorization_codes (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, strings.Join(code.Scopes, " "), code.CodeChallenge, code.ExpiresAt, code.CreatedAt)
+	return err
+}
+
+// ConsumeAuthorizationCode marks an unexpired, unused code as used and
+// returns it. It returns nil when the code is unknown, expired or was
+// already redeemed, so a code can be exchanged at most once.
+func (r *oauthRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string, now time.Time) (*model.OAuthAuthorizationCodeModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at, used_at, created_at
+	          FROM oauth_authorization_codes
+	          WHERE code_hash = ?
+	          FOR UPDATE`
+
+	var code model.OAuthAuthorizationCodeModel
+	var scopes string
+	err = tx.QueryRowContext(ctx, query, codeHash).Scan(&code.ID, &code.CodeHash, &code.ClientID, &code.UserID, &code.RedirectURI, &scopes, &code.CodeChallenge, &code.ExpiresAt, &code.UsedAt, &code.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if code.UsedAt.Valid || !now.Before(code.ExpiresAt) {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE oauth_authorization_codes SET used_at = ? WHERE id = ?`, now, code.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	code.Scopes = strings.Fields(scopes)
+	code.UsedAt = sql.NullTime{Time: now, Valid: true}
+	return &code, nil
+}
@@ -0,0 +1,38 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+)
+
+func (r *oauthRepository) CreateClient(ctx context.Context, client *model.OAuthClientModel) (int64, error) {
+	query := `INSERT INTO oauth_clients (client_id, client_secret_hash, name, redirect_uris, scopes, owner_user_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	secretHash := sql.NullString{String: client.ClientSecretHash, Valid: client.ClientSecretHash != ""}
+	result, err := r.db.ExecContext(ctx, query, client.ClientID, secretHash, client.Name, strings.Join(client.RedirectURIs, "\n"), strings.Join(client.Scopes, " "), client.OwnerUserID, client.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *oauthRepository) GetClientByClientID(ctx context.Context, clientID string) (*model.OAuthClientModel, error) {
+	query := `SELECT id, client_id, client_secret_hash, name, redirect_uris, scopes, owner_user_id, created_at FROM oauth_clients WHERE client_id = ?`
+
+	var client model.OAuthClientModel
+	var secretHash sql.NullString
+	var redirectURIs, scopes string
+	err := r.db.QueryRowContext(ctx, query, clientID).Scan(&client.ID, &client.ClientID, &secretHash, &client.Name, &redirectURIs, &scopes, &client.OwnerUserID, &client.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	client.ClientSecretHash = secretHash.String
+	client.RedirectURIs = strings.Split(redirectURIs, "\n")
+	client.Scopes = strings.Fields(scopes)
+	return &client, nil
+}
@@ -0,0 +1,33 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+type OAuthRepository interface {
+	CreateClient(ctx context.Context, client *model.OAuthClientModel) (int64, error)
+	GetClientByClientID(ctx context.Context, clientID string) (*model.OAuthClientModel, error)
+
+	CreateAuthorizationCode(ctx context.Context, code *model.OAuthAuthorizationCodeModel) error
+	ConsumeAuthorizationCode(ctx context.Context, codeHash string, now time.Time) (*model.OAuthAuthorizationCodeModel, error)
+
+	CreateRefreshToken(ctx context.Context, token *model.OAuthRefreshTokenModel) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.OAuthRefreshTokenModel, error)
+	RevokeRefreshToken(ctx context.Context, id int64, now time.Time) (bool, error)
+
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type oauthRepository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) OAuthRepository {
+	return &oauthRepository{
+		db: db,
+	}
+}
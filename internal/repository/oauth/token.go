@@ -0,0 +1,60 @@
+package oauth
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+	"time"
+)
+
+func (r *oauthRepository) CreateRefreshToken(ctx context.Context, token *model.OAuthRefreshTokenModel) error {
+	query := `INSERT INTO oauth_refresh_tokens (token_hash, client_id, user_id, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, token.TokenHash, token.ClientID, token.UserID, strings.Join(token.Scopes, " "), token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+func (r *oauthRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*model.OAuthRefreshTokenModel, error) {
+	query := `SELECT id, token_hash, client_id, user_id, scopes, expires_at, revoked_at, created_at FROM oauth_refresh_tokens WHERE token_hash = ?`
+
+	var token model.OAuthRefreshTokenModel
+	var scopes string
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&token.ID, &token.TokenHash, &token.ClientID, &token.UserID, &scopes, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	token.Scopes = strings.Fields(scopes)
+	return &token, nil
+}
+
+// RevokeRefreshToken reports whether this call revoked the token; false
+// means it was already revoked, e.g. by a concurrent refresh.
+func (r *oauthRepository) RevokeRefreshToken(ctx context.Context, id int64, now time.Time) (bool, error) {
+	query := `UPDATE oauth_refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *oauthRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	query := `INSERT IGNORE INTO oauth_revoked_access_tokens (jti, expires_at, created_at) VALUES (?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query, jti, expiresAt)
+	return err
+}
+
+func (r *oauthRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM oauth_revoked_access_tokens WHERE jti = ?)`
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, query, jti).Scan(&revoked)
+	return revoked, err
+}
@@ -0,0 +1,116 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+)
+
+// SaveBookmark bookmarks the post for the user, filed in folderID, or moves
+// an existing bookmark there. It returns true if the bookmark is new.
+func (r *postRepository) SaveBookmark(ctx context.Context, userID, postID int64, folderID sql.NullInt64) (bool, error) {
+	query := `INSERT INTO bookmarks (user_id, post_id, folder_id, created_at) VALUES (?, ?, ?, NOW())
+	          ON DUPLICATE KEY UPDATE folder_id = VALUES(folder_id)`
+	result, err := r.db.ExecContext(ctx, query, userID, postID, folderID)
+	if err != nil {
+		return false, err
+	}
+	// MySQL reports 1 for an insert and 2 for an update
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DeleteBookmark returns false if the user had not bookmarked the post.
+func (r *postRepository) DeleteBookmark(ctx context.Context, userID, postID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM bookmarks WHERE user_id = ? AND post_id = ?`, userID, postID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// GetBookmarks returns the user's bookmarks older than beforeID, newest
+// first, with their posts and the authors' usernames. Bookmarks of deleted
+// posts and of deactivated authors are skipped; since the cursor is the
+// bookmark ID, skipping them doesn't shift later pages. A zero beforeID
+// starts from the newest, and a valid folderID limits the list to that
+// folder.
+func (r *postRepository) GetBookmarks(ctx context.Context, userID int64, folderID sql.NullInt64, beforeID int64, limit int) ([]*model.BookmarkModel, []*model.PostModel, []string, error) {
+	query := `SELECT b.id, b.user_id, b.post_id, b.folder_id, b.created_at,
+	          p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at, u.username
+	          FROM bookmarks b
+	          JOIN posts p ON p.id = b.post_id
+	          JOIN users u ON u.id = p.user_id
+	          WHERE b.user_id = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL`
+	args := []any{userID}
+	if folderID.Valid {
+		query += ` AND b.folder_id = ?`
+		args = append(args, folderID.Int64)
+	}
+	if beforeID > 0 {
+		query += ` AND b.id < ?`
+		args = append(args, beforeID)
+	}
+	query += ` ORDER BY b.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+
+	var bookmarks []*model.BookmarkModel
+	var posts []*model.PostModel
+	var usernames []string
+	for rows.Next() {
+		var bookmark model.BookmarkModel
+		var post model.PostModel
+		var username string
+		if err := rows.Scan(&bookmark.ID, &bookmark.UserID, &bookmark.PostID, &bookmark.FolderID, &bookmark.CreatedAt,
+			&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt, &username); err != nil {
+			return nil, nil, nil, err
+		}
+		bookmarks = append(bookmarks, &bookmark)
+		posts = append(posts, &post)
+		usernames = append(usernames, username)
+	}
+	return bookmarks, posts, usernames, rows.Err()
+}
+
+// GetBookmarkedPostIDs returns which of the posts the user bookmarked.
+func (r *postRepository) GetBookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	bookmarked := make(map[int64]bool)
+	if len(postIDs) == 0 {
+		return bookmarked, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(postIDs)), ",")
+	args := []any{userID}
+	for _, id := range postIDs {
+		args = append(args, id)
+	}
+	query := `SELECT post_id FROM bookmarks WHERE user_id = ? AND post_id IN (` + placeholders + `)`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID int64
+		if err := rows.Scan(&postID); err != nil {
+			return nil, err
+		}
+		bookmarked[postID] = true
+	}
+	return bookmarked, rows.Err()
+}
@@ -0,0 +1,90 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+)
+
+// CreateBookmarkFolder returns false, creating nothing, if the user already
+// has a folder with that name.
+func (r *postRepository) CreateBookmarkFolder(ctx context.Context, folder *model.BookmarkFolderModel) (int64, bool, error) {
+	query := `INSERT IGNORE INTO bookmark_folders (user_id, name, created_at) VALUES (?, ?, NOW())`
+	result, err := r.db.ExecContext(ctx, query, folder.UserID, folder.Name)
+	if err != nil {
+		return 0, false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+	if affected == 0 {
+		return 0, false, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+func (r *postRepository) GetBookmarkFolderByID(ctx context.Context, id int64) (*model.BookmarkFolderModel, error) {
+	query := `SELECT id, user_id, name, created_at FROM bookmark_folders WHERE id = ?`
+	var folder model.BookmarkFolderModel
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *postRepository) GetBookmarkFolders(ctx context.Context, userID int64) ([]*model.BookmarkFolderModel, error) {
+	query := `SELECT id, user_id, name, created_at FROM bookmark_folders WHERE user_id = ? ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []*model.BookmarkFolderModel
+	for rows.Next() {
+		var folder model.BookmarkFolderModel
+		if err := rows.Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.CreatedAt); err != nil {
+			return nil, err
+		}
+		folders = append(folders, &folder)
+	}
+	return folders, rows.Err()
+}
+
+// DeleteBookmarkFolder deletes the user's folder, keeping its bookmarks
+// outside any folder. It returns false if the user has no such folder.
+func (r *postRepository) DeleteBookmarkFolder(ctx context.Context, userID, id int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE bookmarks SET folder_id = NULL WHERE user_id = ? AND folder_id = ?`, userID, id); err != nil {
+		return false, err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM bookmark_folders WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
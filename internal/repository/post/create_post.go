@@ -6,8 +6,8 @@ import (
 )
 
 func (r *postRepository) CreatePost(ctx context.Context, post *model.PostModel) (int64, error) {
-	query := `INSERT INTO posts (user_id, title, content, created_at, updated_at) VALUES (?, ?, ?, NOW(), NOW())`
-	result, err := r.db.ExecContext(ctx, query, post.UserID, post.Title, post.Content)
+	query := `INSERT INTO posts (user_id, title, content, status, publish_at, reply_policy, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`
+	result, err := r.db.ExecContext(ctx, query, post.UserID, post.Title, post.Content, post.Status, post.PublishAt, post.ReplyPolicy)
 	if err != nil {
 		return 0, err
 	}
@@ -2,10 +2,31 @@ package post
 
 import (
 	"context"
+	"go-twitter/internal/model"
 )
 
-func (r *postRepository) DeletePost(ctx context.Context, id int64) error {
-	query := `UPDATE posts SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+// DeletePost moves the post to the trash and unpins it. deletedBy is the
+// author, who can restore it, or the moderator who removed it; a moderator's
+// removal is recorded in auditLog, which is nil for authors.
+func (r *postRepository) DeletePost(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE posts SET deleted_at = NOW(), deleted_by = ? WHERE id = ? AND deleted_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, deletedBy, id); err != nil {
+		return err
+	}
+	if err := unpinPost(ctx, tx, id); err != nil {
+		return err
+	}
+	if auditLog != nil {
+		query := `INSERT INTO audit_logs (actor_id, target_user_id, action, entity_type, entity_id, created_at) VALUES (?, ?, ?, ?, ?, NOW())`
+		if _, err := tx.ExecContext(ctx, query, auditLog.ActorID, auditLog.TargetUserID, auditLog.Action, auditLog.EntityType, auditLog.EntityID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
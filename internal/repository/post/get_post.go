@@ -6,12 +6,19 @@ import (
 	"go-twitter/internal/model"
 )
 
+// GetPostByID returns the post whatever its status, for callers that check
+// who may see it; GetPostWithUserInfo returns published posts only.
 func (r *postRepository) GetPostByID(ctx context.Context, id int64) (*model.PostModel, error) {
-	query := `SELECT id, user_id, title, content, deleted_at, created_at, updated_at FROM posts WHERE id = ? AND deleted_at IS NULL`
+	query := `
+		SELECT p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.id = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
+	`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var post model.PostModel
-	err := row.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
+	err := row.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -23,16 +30,16 @@ func (r *postRepository) GetPostByID(ctx context.Context, id int64) (*model.Post
 
 func (r *postRepository) GetPostWithUserInfo(ctx context.Context, id int64) (*model.PostModel, string, error) {
 	query := `
-		SELECT p.id, p.user_id, p.title, p.content, p.deleted_at, p.created_at, p.updated_at, u.username
+		SELECT p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at, u.username
 		FROM posts p
 		JOIN users u ON p.user_id = u.id
-		WHERE p.id = ? AND p.deleted_at IS NULL
+		WHERE p.id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
 	`
-	row := r.db.QueryRowContext(ctx, query, id)
+	row := r.db.QueryRowContext(ctx, query, id, model.PostPublished)
 
 	var post model.PostModel
 	var username string
-	err := row.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt, &username)
+	err := row.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt, &username)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, "", nil
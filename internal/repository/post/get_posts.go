@@ -6,8 +6,15 @@ import (
 )
 
 func (r *postRepository) GetPosts(ctx context.Context, limit, offset int) ([]*model.PostModel, error) {
-	query := `SELECT id, user_id, title, content, deleted_at, created_at, updated_at FROM posts WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT ? OFFSET ?`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	query := `
+		SELECT p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
+		ORDER BY p.publish_at DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, model.PostPublished, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -16,7 +23,7 @@ func (r *postRepository) GetPosts(ctx context.Context, limit, offset int) ([]*mo
 	var posts []*model.PostModel
 	for rows.Next() {
 		var post model.PostModel
-		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
+		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -27,14 +34,14 @@ func (r *postRepository) GetPosts(ctx context.Context, limit, offset int) ([]*mo
 
 func (r *postRepository) GetPostsWithUserInfo(ctx context.Context, limit, offset int) ([]*model.PostModel, []string, error) {
 	query := `
-		SELECT p.id, p.user_id, p.title, p.content, p.deleted_at, p.created_at, p.updated_at, u.username
+		SELECT p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at, u.username
 		FROM posts p
 		JOIN users u ON p.user_id = u.id
-		WHERE p.deleted_at IS NULL
-		ORDER BY p.created_at DESC
+		WHERE p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
+		ORDER BY p.publish_at DESC
 		LIMIT ? OFFSET ?
 	`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, model.PostPublished, limit, offset)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -45,7 +52,7 @@ func (r *postRepository) GetPostsWithUserInfo(ctx context.Context, limit, offset
 	for rows.Next() {
 		var post model.PostModel
 		var username string
-		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt, &username)
+		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt, &username)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -55,9 +62,19 @@ func (r *postRepository) GetPostsWithUserInfo(ctx context.Context, limit, offset
 	return posts, usernames, nil
 }
 
+// GetPostsByUserID returns the user's published posts, newest first,
+// leaving out the post they pinned, which profiles show on its own.
 func (r *postRepository) GetPostsByUserID(ctx context.Context, userID int64, limit, offset int) ([]*model.PostModel, error) {
-	query := `SELECT id, user_id, title, content, deleted_at, created_at, updated_at FROM posts WHERE user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC LIMIT ? OFFSET ?`
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	query := `
+		SELECT p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at
+		FROM posts p
+		JOIN users u ON p.user_id = u.id
+		LEFT JOIN pinned_posts pp ON pp.post_id = p.id
+		WHERE p.user_id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL AND pp.post_id IS NULL
+		ORDER BY p.publish_at DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, model.PostPublished, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +83,7 @@ func (r *postRepository) GetPostsByUserID(ctx context.Context, userID int64, lim
 	var posts []*model.PostModel
 	for rows.Next() {
 		var post model.PostModel
-		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
+		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -76,9 +93,23 @@ func (r *postRepository) GetPostsByUserID(ctx context.Context, userID int64, lim
 }
 
 func (r *postRepository) GetPostsCount(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL`
+	query := `SELECT COUNT(*) FROM posts p JOIN users u ON p.user_id = u.id WHERE p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL`
+	var count int64
+	err := r.db.QueryRowContext(ctx, query, model.PostPublished).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetPostsCountByUserID counts the posts GetPostsByUserID lists.
+func (r *postRepository) GetPostsCountByUserID(ctx context.Context, userID int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM posts p
+	          JOIN users u ON p.user_id = u.id
+	          LEFT JOIN pinned_posts pp ON pp.post_id = p.id
+	          WHERE p.user_id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL AND pp.post_id IS NULL`
 	var count int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, userID, model.PostPublished).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,119 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+)
+
+// PinPost pins the post to its author's profile in place of any post
+// pinned before. It returns false, changing nothing, unless the post is
+// the user's, published and not deleted.
+func (r *postRepository) PinPost(ctx context.Context, userID, postID int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO pinned_posts (user_id, post_id, pinned_at)
+	          SELECT user_id, id, NOW() FROM posts
+	          WHERE id = ? AND user_id = ? AND status = ? AND deleted_at IS NULL
+	          ON DUPLICATE KEY UPDATE post_id = VALUES(post_id), pinned_at = VALUES(pinned_at)`
+	result, err := tx.ExecContext(ctx, query, postID, userID, model.PostPublished)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	// the pin is part of the profile, whose Last-Modified is updated_at
+	if err := touchUser(ctx, tx, userID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnpinPost returns false if the user had no post pinned.
+func (r *postRepository) UnpinPost(ctx context.Context, userID int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM pinned_posts WHERE user_id = ?`, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	if err := touchUser(ctx, tx, userID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetPinnedPost returns the post the user pinned along with the author's
+// username, or nil if there is none.
+func (r *postRepository) GetPinnedPost(ctx context.Context, userID int64) (*model.PostModel, string, error) {
+	query := `
+		SELECT p.id, p.user_id, p.title, p.content, p.status, p.publish_at, p.edit_count, p.version, p.reply_policy, p.replies_locked, p.deleted_at, p.created_at, p.updated_at, u.username
+		FROM pinned_posts pp
+		JOIN posts p ON p.id = pp.post_id
+		JOIN users u ON p.user_id = u.id
+		WHERE pp.user_id = ? AND p.status = ? AND p.deleted_at IS NULL AND u.deactivated_at IS NULL
+	`
+	row := r.db.QueryRowContext(ctx, query, userID, model.PostPublished)
+
+	var post model.PostModel
+	var username string
+	err := row.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt, &username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	return &post, username, nil
+}
+
+// unpinPost drops any pin of the post, as part of deleting it.
+func unpinPost(ctx context.Context, tx *sql.Tx, postID int64) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM pinned_posts WHERE post_id = ?`, postID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE users u JOIN posts p ON p.user_id = u.id SET u.updated_at = NOW() WHERE p.id = ?`, postID)
+	return err
+}
+
+// touchUser marks the user's profile as changed.
+func touchUser(ctx context.Context, tx *sql.Tx, userID int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE users SET updated_at = NOW() WHERE id = ?`, userID)
+	return err
+}
@@ -0,0 +1,127 @@
+package post
+
+import (
+	"context"
+	"go-twitter/internal/model"
+	"strings"
+)
+
+// CreatePostWithMedia creates the post and links the media to it in the
+// given order. It returns false, creating nothing, unless every media item
+// was uploaded by the post's author, is not linked to another post and did
+// not fail processing. Media still being processed can be posted.
+func (r *postRepository) CreatePostWithMedia(ctx context.Context, post *model.PostModel, mediaIDs []int64) (int64, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(mediaIDs)), ",")
+	args := []any{post.UserID}
+	for _, id := range mediaIDs {
+		args = append(args, id)
+	}
+	query := `SELECT COUNT(*) FROM media m
+	          WHERE m.user_id = ? AND m.id IN (` + placeholders + `)
+	          AND m.processing_status <> ?
+	          AND NOT EXISTS (SELECT 1 FROM post_media pm WHERE pm.media_id = m.id)
+	          FOR UPDATE`
+	args = append(args, model.MediaFailed)
+	var available int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&available); err != nil {
+		return 0, false, err
+	}
+	if available != len(mediaIDs) {
+		return 0, false, nil
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO posts (user_id, title, content, status, publish_at, reply_policy, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		post.UserID, post.Title, post.Content, post.Status, post.PublishAt, post.ReplyPolicy)
+	if err != nil {
+		return 0, false, err
+	}
+	postID, err := result.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+
+	for position, mediaID := range mediaIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO post_media (post_id, media_id, position) VALUES (?, ?, ?)`, postID, mediaID, position)
+		if err != nil {
+			return 0, false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return postID, true, nil
+}
+
+// GetPostMedia returns the media linked to each of the posts, in order,
+// with their variants.
+func (r *postRepository) GetPostMedia(ctx context.Context, postIDs []int64) (map[int64][]*model.MediaModel, error) {
+	media := make(map[int64][]*model.MediaModel)
+	if len(postIDs) == 0 {
+		return media, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(postIDs)), ",")
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id
+	}
+	query := `SELECT pm.post_id, m.id, m.user_id, m.blob_key, m.content_type, m.size_bytes, m.alt_text,
+	          m.processing_status, m.width, m.height, m.blurhash, m.created_at
+	          FROM post_media pm
+	          JOIN media m ON m.id = pm.media_id
+	          WHERE pm.post_id IN (` + placeholders + `)
+	          ORDER BY pm.post_id, pm.position`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*model.MediaModel)
+	for rows.Next() {
+		var postID int64
+		var m model.MediaModel
+		if err := rows.Scan(&postID, &m.ID, &m.UserID, &m.BlobKey, &m.ContentType, &m.SizeBytes, &m.AltText,
+			&m.ProcessingStatus, &m.Width, &m.Height, &m.Blurhash, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		media[postID] = append(media[postID], &m)
+		byID[m.ID] = &m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(byID) == 0 {
+		return media, nil
+	}
+
+	placeholders = strings.TrimSuffix(strings.Repeat("?,", len(byID)), ",")
+	args = args[:0]
+	for id := range byID {
+		args = append(args, id)
+	}
+	query = `SELECT media_id, name, blob_key, content_type, width, height, size_bytes
+	         FROM media_variants
+	         WHERE media_id IN (` + placeholders + `)
+	         ORDER BY media_id, width`
+	variantRows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer variantRows.Close()
+
+	for variantRows.Next() {
+		var v model.MediaVariantModel
+		if err := variantRows.Scan(&v.MediaID, &v.Name, &v.BlobKey, &v.ContentType, &v.Width, &v.Height, &v.SizeBytes); err != nil {
+			return nil, err
+		}
+		byID[v.MediaID].Variants = append(byID[v.MediaID].Variants, v)
+	}
+	return media, variantRows.Err()
+}
@@ -0,0 +1,245 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+	"time"
+)
+
+// CreatePostWithPoll creates the post together with its poll and options.
+func (r *postRepository) CreatePostWithPoll(ctx context.Context, post *model.PostModel, poll *model.PollModel) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO posts (user_id, title, content, status, publish_at, reply_policy, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		post.UserID, post.Title, post.Content, post.Status, post.PublishAt, post.ReplyPolicy)
+	if err != nil {
+		return 0, err
+	}
+	postID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO polls (post_id, multiple_choice, closes_at, created_at) VALUES (?, ?, ?, NOW())`,
+		postID, poll.MultipleChoice, poll.ClosesAt)
+	if err != nil {
+		return 0, err
+	}
+	for position, option := range poll.Options {
+		_, err := tx.ExecContext(ctx, `INSERT INTO poll_options (post_id, position, text) VALUES (?, ?, ?)`, postID, position, option.Text)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return postID, nil
+}
+
+// GetPostPolls returns the poll of each of the posts that has one. Counts
+// of polls not yet finalized are tallied from the votes of active users.
+func (r *postRepository) GetPostPolls(ctx context.Context, postIDs []int64) (map[int64]*model.PollModel, error) {
+	polls := make(map[int64]*model.PollModel)
+	if len(postIDs) == 0 {
+		return polls, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(postIDs)), ",")
+	args := make([]any, len(postIDs))
+	for i, id := range postIDs {
+		args[i] = id
+	}
+
+	query := `SELECT p.post_id, p.multiple_choice, p.closes_at, p.finalized_at, p.created_at,
+	          CASE WHEN p.finalized_at IS NULL THEN
+	              (SELECT COUNT(*) FROM poll_votes v JOIN users u ON u.id = v.user_id
+	               WHERE v.post_id = p.post_id AND u.deactivated_at IS NULL)
+	          ELSE p.total_voters END
+	          FROM polls p
+	          WHERE p.post_id IN (` + placeholders + `)`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var poll model.PollModel
+		if err := rows.Scan(&poll.PostID, &poll.MultipleChoice, &poll.ClosesAt, &poll.FinalizedAt, &poll.CreatedAt, &poll.TotalVoters); err != nil {
+			return nil, err
+		}
+		polls[poll.PostID] = &poll
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(polls) == 0 {
+		return polls, nil
+	}
+
+	query = `SELECT o.id, o.post_id, o.position, o.text,
+	         CASE WHEN p.finalized_at IS NULL THEN
+	             (SELECT COUNT(*) FROM poll_vote_options v JOIN users u ON u.id = v.user_id
+	              WHERE v.option_id = o.id AND u.deactivated_at IS NULL)
+	         ELSE o.vote_count END
+	         FROM poll_options o
+	         JOIN polls p ON p.post_id = o.post_id
+	         WHERE o.post_id IN (` + placeholders + `)
+	         ORDER BY o.post_id, o.position`
+	optionRows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer optionRows.Close()
+
+	for optionRows.Next() {
+		var option model.PollOptionModel
+		if err := optionRows.Scan(&option.ID, &option.PostID, &option.Position, &option.Text, &option.VoteCount); err != nil {
+			return nil, err
+		}
+		polls[option.PostID].Options = append(polls[option.PostID].Options, option)
+	}
+	return polls, optionRows.Err()
+}
+
+// GetPollVotes returns the options the user chose in each of the polls
+// they voted in.
+func (r *postRepository) GetPollVotes(ctx context.Context, postIDs []int64, userID int64) (map[int64][]int64, error) {
+	votes := make(map[int64][]int64)
+	if len(postIDs) == 0 {
+		return votes, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(postIDs)), ",")
+	args := []any{userID}
+	for _, id := range postIDs {
+		args = append(args, id)
+	}
+	query := `SELECT post_id, option_id FROM poll_vote_options
+	          WHERE user_id = ? AND post_id IN (` + placeholders + `)
+	          ORDER BY post_id, option_id`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, optionID int64
+		if err := rows.Scan(&postID, &optionID); err != nil {
+			return nil, err
+		}
+		votes[postID] = append(votes[postID], optionID)
+	}
+	return votes, rows.Err()
+}
+
+// VotePoll records the user's choice of options. It returns false,
+// recording nothing, if the user already voted or the poll closed.
+func (r *postRepository) VotePoll(ctx context.Context, postID, userID int64, optionIDs []int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// reading the poll row locks it against the finalize job, so a vote
+	// is either counted in the final results or rejected
+	query := `INSERT IGNORE INTO poll_votes (post_id, user_id, created_at)
+	          SELECT post_id, ?, NOW() FROM polls
+	          WHERE post_id = ? AND closes_at > NOW() AND finalized_at IS NULL`
+	result, err := tx.ExecContext(ctx, query, userID, postID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	for _, optionID := range optionIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO poll_vote_options (post_id, user_id, option_id) VALUES (?, ?, ?)`, postID, userID, optionID)
+		if err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetExpiredPolls returns the post IDs of polls that closed before now but
+// were not finalized yet. Polls of unpublished posts are skipped; their
+// closing time moves when the post is published.
+func (r *postRepository) GetExpiredPolls(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	query := `SELECT pl.post_id FROM polls pl
+	          JOIN posts p ON p.id = pl.post_id
+	          WHERE pl.finalized_at IS NULL AND pl.closes_at <= ? AND p.status = ?
+	          ORDER BY pl.closes_at
+	          LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, now, model.PostPublished, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var postIDs []int64
+	for rows.Next() {
+		var postID int64
+		if err := rows.Scan(&postID); err != nil {
+			return nil, err
+		}
+		postIDs = append(postIDs, postID)
+	}
+	return postIDs, rows.Err()
+}
+
+// FinalizePoll freezes the results of a poll that closed before now,
+// counting the votes of users active at that point. It returns false if
+// the poll is still open or was finalized already.
+func (r *postRepository) FinalizePoll(ctx context.Context, postID int64, now time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var closesAt time.Time
+	query := `SELECT closes_at FROM polls WHERE post_id = ? AND finalized_at IS NULL AND closes_at <= ? FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, postID, now).Scan(&closesAt); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	query = `UPDATE poll_options o SET vote_count =
+	             (SELECT COUNT(*) FROM poll_vote_options v JOIN users u ON u.id = v.user_id
+	              WHERE v.option_id = o.id AND u.deactivated_at IS NULL)
+	         WHERE o.post_id = ?`
+	if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+		return false, err
+	}
+	query = `UPDATE polls SET finalized_at = ?, total_voters =
+	             (SELECT COUNT(*) FROM poll_votes v JOIN users u ON u.id = v.user_id
+	              WHERE v.post_id = ? AND u.deactivated_at IS NULL)
+	         WHERE post_id = ?`
+	if _, err := tx.ExecContext(ctx, query, now, postID, postID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
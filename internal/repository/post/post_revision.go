@@ -0,0 +1,27 @@
+package post
+
+import (
+	"context"
+	"go-twitter/internal/model"
+)
+
+// GetPostRevisions returns the versions edits of the post replaced, oldest
+// first.
+func (r *postRepository) GetPostRevisions(ctx context.Context, postID int64) ([]*model.PostRevisionModel, error) {
+	query := `SELECT id, post_id, title, content, created_at FROM post_revisions WHERE post_id = ? ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*model.PostRevisionModel
+	for rows.Next() {
+		var revision model.PostRevisionModel
+		if err := rows.Scan(&revision.ID, &revision.PostID, &revision.Title, &revision.Content, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
+}
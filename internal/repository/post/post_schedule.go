@@ -0,0 +1,106 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+// GetDrafts returns the user's drafts and scheduled posts, most recently
+// edited first, along with how many there are.
+func (r *postRepository) GetDrafts(ctx context.Context, userID int64, limit, offset int) ([]*model.PostModel, int64, error) {
+	query := `SELECT id, user_id, title, content, status, publish_at, edit_count, version, reply_policy, replies_locked, deleted_at, created_at, updated_at
+	          FROM posts
+	          WHERE user_id = ? AND status IN (?, ?) AND deleted_at IS NULL
+	          ORDER BY updated_at DESC, id DESC
+	          LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query, userID, model.PostDraft, model.PostScheduled, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var posts []*model.PostModel
+	for rows.Next() {
+		var post model.PostModel
+		err := rows.Scan(&post.ID, &post.UserID, &post.Title, &post.Content, &post.Status, &post.PublishAt, &post.EditCount, &post.Version, &post.ReplyPolicy, &post.RepliesLocked, &post.DeletedAt, &post.CreatedAt, &post.UpdatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		posts = append(posts, &post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM posts WHERE user_id = ? AND status IN (?, ?) AND deleted_at IS NULL`
+	var totalCount int64
+	if err := r.db.QueryRowContext(ctx, countQuery, userID, model.PostDraft, model.PostScheduled).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+	return posts, totalCount, nil
+}
+
+// PublishDuePosts publishes up to limit scheduled posts whose time came by
+// now, returning how many it published. Rows are claimed with SKIP LOCKED,
+// so instances running the scheduler side by side publish disjoint sets
+// and every post exactly once.
+func (r *postRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id FROM posts
+	          WHERE status = ? AND publish_at <= ? AND deleted_at IS NULL
+	          ORDER BY publish_at
+	          LIMIT ?
+	          FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, query, model.PostScheduled, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if err := publishPost(ctx, tx, id, now); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// publishPost makes the locked post public as of now.
+func publishPost(ctx context.Context, tx *sql.Tx, id int64, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE posts SET status = ?, publish_at = ?, version = version + 1, updated_at = NOW() WHERE id = ?`, model.PostPublished, now, id)
+	if err != nil {
+		return err
+	}
+	return startPoll(ctx, tx, id, now)
+}
+
+// startPoll runs a poll on a post published as of now for its full
+// duration. The poll was created with the post, so its clock started then.
+func startPoll(ctx context.Context, tx *sql.Tx, id int64, now time.Time) error {
+	query := `UPDATE polls SET closes_at = DATE_ADD(?, INTERVAL TIMESTAMPDIFF(SECOND, created_at, closes_at) SECOND)
+	          WHERE post_id = ?`
+	_, err := tx.ExecContext(ctx, query, now, id)
+	return err
+}
@@ -0,0 +1,65 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// SetRepliesLocked locks or unlocks the post's thread. It returns false if
+// the post is deleted or already in that state.
+func (r *postRepository) SetRepliesLocked(ctx context.Context, postID int64, locked bool) (bool, error) {
+	query := `UPDATE posts SET replies_locked = ? WHERE id = ? AND replies_locked <> ? AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, locked, postID, locked)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// GetUsername returns the user's username, or an empty string if there is
+// no such user.
+func (r *postRepository) GetUsername(ctx context.Context, userID int64) (string, error) {
+	var username string
+	err := r.db.QueryRowContext(ctx, `SELECT username FROM users WHERE id = ?`, userID).Scan(&username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return username, nil
+}
+
+// GetFollowedUserIDs returns which of the users the follower follows.
+func (r *postRepository) GetFollowedUserIDs(ctx context.Context, followerID int64, userIDs []int64) (map[int64]bool, error) {
+	followed := make(map[int64]bool)
+	if len(userIDs) == 0 {
+		return followed, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	args := []any{followerID}
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	query := `SELECT followee_id FROM follows WHERE follower_id = ? AND followee_id IN (` + placeholders + `)`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		followed[userID] = true
+	}
+	return followed, rows.Err()
+}
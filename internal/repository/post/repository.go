@@ -4,18 +4,48 @@ import (
 	"context"
 	"database/sql"
 	"go-twitter/internal/model"
+	"time"
 )
 
 type PostRepository interface {
 	CreatePost(ctx context.Context, post *model.PostModel) (int64, error)
+	CreatePostWithMedia(ctx context.Context, post *model.PostModel, mediaIDs []int64) (int64, bool, error)
 	GetPostByID(ctx context.Context, id int64) (*model.PostModel, error)
 	GetPosts(ctx context.Context, limit, offset int) ([]*model.PostModel, error)
 	GetPostsByUserID(ctx context.Context, userID int64, limit, offset int) ([]*model.PostModel, error)
 	GetPostsCount(ctx context.Context) (int64, error)
-	UpdatePost(ctx context.Context, post *model.PostModel) error
-	DeletePost(ctx context.Context, id int64) error
+	GetPostsCountByUserID(ctx context.Context, userID int64) (int64, error)
+	UpdatePost(ctx context.Context, post *model.PostModel) (bool, error)
+	DeletePost(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error
 	GetPostWithUserInfo(ctx context.Context, id int64) (*model.PostModel, string, error)
 	GetPostsWithUserInfo(ctx context.Context, limit, offset int) ([]*model.PostModel, []string, error)
+	GetPostMedia(ctx context.Context, postIDs []int64) (map[int64][]*model.MediaModel, error)
+	CreatePostWithPoll(ctx context.Context, post *model.PostModel, poll *model.PollModel) (int64, error)
+	GetPostPolls(ctx context.Context, postIDs []int64) (map[int64]*model.PollModel, error)
+	GetPollVotes(ctx context.Context, postIDs []int64, userID int64) (map[int64][]int64, error)
+	VotePoll(ctx context.Context, postID, userID int64, optionIDs []int64) (bool, error)
+	GetExpiredPolls(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	FinalizePoll(ctx context.Context, postID int64, now time.Time) (bool, error)
+	SaveBookmark(ctx context.Context, userID, postID int64, folderID sql.NullInt64) (bool, error)
+	DeleteBookmark(ctx context.Context, userID, postID int64) (bool, error)
+	GetBookmarks(ctx context.Context, userID int64, folderID sql.NullInt64, beforeID int64, limit int) ([]*model.BookmarkModel, []*model.PostModel, []string, error)
+	GetBookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error)
+	CreateBookmarkFolder(ctx context.Context, folder *model.BookmarkFolderModel) (int64, bool, error)
+	GetBookmarkFolderByID(ctx context.Context, id int64) (*model.BookmarkFolderModel, error)
+	GetBookmarkFolders(ctx context.Context, userID int64) ([]*model.BookmarkFolderModel, error)
+	DeleteBookmarkFolder(ctx context.Context, userID, id int64) (bool, error)
+	GetDrafts(ctx context.Context, userID int64, limit, offset int) ([]*model.PostModel, int64, error)
+	PublishDuePosts(ctx context.Context, now time.Time, limit int) (int, error)
+	GetPostRevisions(ctx context.Context, postID int64) ([]*model.PostRevisionModel, error)
+	GetTrash(ctx context.Context, userID int64, since time.Time, limit, offset int) ([]*model.TrashItemModel, int64, error)
+	RestorePost(ctx context.Context, id, userID int64, since time.Time) (bool, error)
+	PurgeTrash(ctx context.Context, before time.Time, limit int) (int, error)
+	PinPost(ctx context.Context, userID, postID int64) (bool, error)
+	UnpinPost(ctx context.Context, userID int64) (bool, error)
+	GetPinnedPost(ctx context.Context, userID int64) (*model.PostModel, string, error)
+	SetRepliesLocked(ctx context.Context, postID int64, locked bool) (bool, error)
+	GetUsername(ctx context.Context, userID int64) (string, error)
+	GetFollowedUserIDs(ctx context.Context, followerID int64, userIDs []int64) (map[int64]bool, error)
 }
 
 type postRepository struct {
@@ -0,0 +1,179 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+	"time"
+)
+
+// GetTrash returns the posts and comments the user deleted after since,
+// most recently deleted first, along with how many there are. Items removed
+// by moderators are left out.
+func (r *postRepository) GetTrash(ctx context.Context, userID int64, since time.Time, limit, offset int) ([]*model.TrashItemModel, int64, error) {
+	query := `SELECT ?, id, id, title, content, deleted_at
+	          FROM posts
+	          WHERE user_id = ? AND deleted_by = ? AND deleted_at > ?
+	          UNION ALL
+	          SELECT ?, id, post_id, '', content, deleted_at
+	          FROM comments
+	          WHERE user_id = ? AND deleted_by = ? AND deleted_at > ?
+	          ORDER BY deleted_at DESC, id DESC
+	          LIMIT ? OFFSET ?`
+	rows, err := r.db.QueryContext(ctx, query,
+		model.TrashPost, userID, userID, since,
+		model.TrashComment, userID, userID, since,
+		limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*model.TrashItemModel
+	for rows.Next() {
+		var item model.TrashItemModel
+		if err := rows.Scan(&item.Type, &item.ID, &item.PostID, &item.Title, &item.Content, &item.DeletedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := `SELECT
+	               (SELECT COUNT(*) FROM posts WHERE user_id = ? AND deleted_by = ? AND deleted_at > ?) +
+	               (SELECT COUNT(*) FROM comments WHERE user_id = ? AND deleted_by = ? AND deleted_at > ?)`
+	var totalCount int64
+	err = r.db.QueryRowContext(ctx, countQuery, userID, userID, since, userID, userID, since).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, totalCount, nil
+}
+
+// RestorePost takes the post out of the trash if the user deleted it after
+// since. It returns false, changing nothing, otherwise.
+func (r *postRepository) RestorePost(ctx context.Context, id, userID int64, since time.Time) (bool, error) {
+	query := `UPDATE posts SET deleted_at = NULL, deleted_by = NULL, version = version + 1
+	          WHERE id = ? AND user_id = ? AND deleted_by = ? AND deleted_at > ?`
+	result, err := r.db.ExecContext(ctx, query, id, userID, userID, since)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// PurgeTrash hard-deletes up to limit posts and up to limit comments that
+// were deleted before, with everything referencing them, returning how
+// many it deleted. Comments on deleted posts go with their post. Rows are
+// claimed with SKIP LOCKED, so instances running the job side by side
+// purge disjoint sets.
+func (r *postRepository) PurgeTrash(ctx context.Context, before time.Time, limit int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	postIDs, err := claimIDs(ctx, tx, `SELECT id FROM posts
+		WHERE deleted_at < ?
+		ORDER BY deleted_at
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED`, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(postIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(postIDs)), ",")
+		purge := []string{
+			`DELETE cl FROM comment_likes cl
+			 JOIN comments c ON c.id = cl.comment_id
+			 WHERE c.post_id IN (` + placeholders + `)`,
+			`DELETE cr FROM comment_revisions cr
+			 JOIN comments c ON c.id = cr.comment_id
+			 WHERE c.post_id IN (` + placeholders + `)`,
+			`DELETE FROM comments WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM poll_vote_options WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM poll_votes WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM poll_options WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM polls WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM bookmarks WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM pinned_posts WHERE post_id IN (` + placeholders + `)`,
+			// the uploads are left for the orphaned media cleanup, which
+			// also deletes their files
+			`DELETE FROM post_media WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM imported_posts WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM post_revisions WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM post_likes WHERE post_id IN (` + placeholders + `)`,
+			`DELETE FROM posts WHERE id IN (` + placeholders + `)`,
+		}
+		if err := execForIDs(ctx, tx, purge, postIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	commentIDs, err := claimIDs(ctx, tx, `SELECT c.id FROM comments c
+		JOIN posts p ON p.id = c.post_id
+		WHERE c.deleted_at < ? AND p.deleted_at IS NULL
+		ORDER BY c.deleted_at
+		LIMIT ?
+		FOR UPDATE OF c SKIP LOCKED`, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	if len(commentIDs) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(commentIDs)), ",")
+		purge := []string{
+			`DELETE FROM comment_likes WHERE comment_id IN (` + placeholders + `)`,
+			`DELETE FROM comment_revisions WHERE comment_id IN (` + placeholders + `)`,
+			`DELETE FROM comments WHERE id IN (` + placeholders + `)`,
+		}
+		if err := execForIDs(ctx, tx, purge, commentIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(postIDs) + len(commentIDs), nil
+}
+
+// claimIDs returns the IDs the query selects and locks.
+func claimIDs(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// execForIDs runs each query with the IDs as its arguments.
+func execForIDs(ctx context.Context, tx *sql.Tx, queries []string, ids []int64) error {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,11 +2,68 @@ package post
 
 import (
 	"context"
+	"database/sql"
 	"go-twitter/internal/model"
 )
 
-func (r *postRepository) UpdatePost(ctx context.Context, post *model.PostModel) error {
-	query := `UPDATE posts SET title = ?, content = ?, updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`
-	_, err := r.db.ExecContext(ctx, query, post.Title, post.Content, post.ID)
-	return err
+// UpdatePost replaces the title, content and reply policy if the post is
+// still at post.Version, returning false otherwise. A non-empty post.Status
+// moves the unpublished post to that status and post.PublishAt in the same
+// transaction; when that is published, post.PublishAt should be the current
+// time. Changing the title or content of a published post is an edit: it
+// keeps the version it replaces in post_revisions and counts in edit_count.
+// Drafts and scheduled posts, including one being published, and changes to
+// the reply policy alone, are saved in place.
+func (r *postRepository) UpdatePost(ctx context.Context, post *model.PostModel) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// locking the row keeps the scheduler from publishing it meanwhile
+	var current model.PostModel
+	query := `SELECT title, content, status, publish_at, version, updated_at FROM posts WHERE id = ? AND deleted_at IS NULL FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, post.ID).Scan(&current.Title, &current.Content, &current.Status, &current.PublishAt, &current.Version, &current.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	if current.Version != post.Version {
+		return false, nil
+	}
+
+	status, publishAt := current.Status, current.PublishAt
+	if post.Status != "" {
+		if current.Status == model.PostPublished {
+			return false, nil
+		}
+		status, publishAt = post.Status, post.PublishAt
+	}
+
+	textChanged := current.Title != post.Title || current.Content != post.Content
+	if current.Status == model.PostPublished && textChanged {
+		_, err := tx.ExecContext(ctx, `INSERT INTO post_revisions (post_id, title, content, created_at) VALUES (?, ?, ?, ?)`,
+			post.ID, current.Title, current.Content, current.UpdatedAt)
+		if err != nil {
+			return false, err
+		}
+		query = `UPDATE posts SET title = ?, content = ?, reply_policy = ?, status = ?, publish_at = ?, edit_count = edit_count + 1, version = version + 1, updated_at = NOW() WHERE id = ?`
+	} else {
+		query = `UPDATE posts SET title = ?, content = ?, reply_policy = ?, status = ?, publish_at = ?, version = version + 1, updated_at = NOW() WHERE id = ?`
+	}
+	if _, err := tx.ExecContext(ctx, query, post.Title, post.Content, post.ReplyPolicy, status, publishAt, post.ID); err != nil {
+		return false, err
+	}
+	if status == model.PostPublished && current.Status != model.PostPublished {
+		if err := startPoll(ctx, tx, post.ID, publishAt.Time); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
 }
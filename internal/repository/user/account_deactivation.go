@@ -0,0 +1,214 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// DeactivateUser hides the account until purgeAfter and signs it out
+// everywhere: refresh tokens, personal access tokens and pending OAuth
+// authorization codes are dropped and OAuth grants revoked. It returns false when the account is already deactivated.
+func (r *userRepository) DeactivateUser(ctx context.Context, userID int64, now, purgeAfter time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE users SET deactivated_at = ?, purge_after = ?, updated_at = ? WHERE id = ? AND deactivated_at IS NULL`,
+		now, purgeAfter, now, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	signOut := []string{
+		`DELETE FROM refresh_tokens WHERE user_id = ?`,
+		`DELETE FROM personal_access_tokens WHERE user_id = ?`,
+		`DELETE FROM oauth_authorization_codes WHERE user_id = ?`,
+		`UPDATE oauth_refresh_tokens SET revoked_at = NOW() WHERE user_id = ? AND revoked_at IS NULL`,
+	}
+	for _, query := range signOut {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReactivateUser restores a deactivated account whose grace period has not
+// ended yet. It returns false when there is nothing left to restore.
+func (r *userRepository) ReactivateUser(ctx context.Context, userID int64, now time.Time) (bool, error) {
+	query := `UPDATE users SET deactivated_at = NULL, purge_after = NULL, updated_at = ? WHERE id = ? AND purge_after > ?`
+	result, err := r.db.ExecContext(ctx, query, now, userID, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *userRepository) GetUsersDueForPurge(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	query := `SELECT id FROM users WHERE purge_after <= ? ORDER BY purge_after LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PurgeUser permanently removes a deactivated account's content and
+// credentials, children before parents so no foreign key is violated. The
+// user row itself is deleted, unless audit logs, invites or OAuth clients
+// other people depend on still point at it; then it is anonymized instead.
+// It returns false when the account is not due for purging.
+func (r *userRepository) PurgeUser(ctx context.Context, userID int64, now time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = ? AND purge_after <= ? FOR UPDATE`, userID, now).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	purge := []string{
+		// likes by the user, and likes on the user's comments and posts
+		`DELETE cl FROM comment_likes cl
+		 JOIN comments c ON c.id = cl.comment_id
+		 JOIN posts p ON p.id = c.post_id
+		 WHERE cl.user_id = ? OR c.user_id = ? OR p.user_id = ?`,
+		`DELETE pl FROM post_likes pl
+		 JOIN posts p ON p.id = pl.post_id
+		 WHERE pl.user_id = ? OR p.user_id = ?`,
+		// the user's comments, and other people's comments on the user's posts,
+		// with their earlier versions
+		`DELETE cr FROM comment_revisions cr
+		 JOIN comments c ON c.id = cr.comment_id
+		 JOIN posts p ON p.id = c.post_id
+		 WHERE c.user_id = ? OR p.user_id = ?`,
+		`DELETE c FROM comments c
+		 JOIN posts p ON p.id = c.post_id
+		 WHERE c.user_id = ? OR p.user_id = ?`,
+		// the user's poll votes, and the polls on the user's posts with all
+		// their votes
+		`DELETE vo FROM poll_vote_options vo
+		 JOIN posts p ON p.id = vo.post_id
+		 WHERE vo.user_id = ? OR p.user_id = ?`,
+		`DELETE v FROM poll_votes v
+		 JOIN posts p ON p.id = v.post_id
+		 WHERE v.user_id = ? OR p.user_id = ?`,
+		`DELETE o FROM poll_options o
+		 JOIN posts p ON p.id = o.post_id
+		 WHERE p.user_id = ?`,
+		`DELETE pl FROM polls pl
+		 JOIN posts p ON p.id = pl.post_id
+		 WHERE p.user_id = ?`,
+		// the user's bookmarks, other people's bookmarks of the user's posts,
+		// and the user's folders
+		`DELETE b FROM bookmarks b
+		 JOIN posts p ON p.id = b.post_id
+		 WHERE b.user_id = ? OR p.user_id = ?`,
+		`DELETE FROM bookmark_folders WHERE user_id = ?`,
+		`DELETE FROM pinned_posts WHERE user_id = ?`,
+		`DELETE FROM follows WHERE follower_id = ? OR followee_id = ?`,
+		// the uploads themselves are left for the orphaned media cleanup,
+		// which also deletes their files
+		`DELETE pm FROM post_media pm
+		 JOIN posts p ON p.id = pm.post_id
+		 WHERE p.user_id = ?`,
+		`UPDATE media SET user_id = NULL WHERE user_id = ?`,
+		`DELETE FROM imported_posts WHERE user_id = ?`,
+		`DELETE pr FROM post_revisions pr
+		 JOIN posts p ON p.id = pr.post_id
+		 WHERE p.user_id = ?`,
+		`DELETE FROM posts WHERE user_id = ?`,
+		// archives outlive their rows only when DATA_EXPORT_RETENTION is
+		// longer than the grace period
+		`DELETE FROM data_exports WHERE user_id = ?`,
+		`DELETE i FROM import_job_items i
+		 JOIN import_jobs j ON j.id = i.job_id
+		 WHERE j.user_id = ?`,
+		`DELETE FROM import_jobs WHERE user_id = ?`,
+
+		`DELETE FROM refresh_tokens WHERE user_id = ?`,
+		`DELETE FROM personal_access_tokens WHERE user_id = ?`,
+		`DELETE FROM oauth_authorization_codes WHERE user_id = ?`,
+		`DELETE FROM oauth_refresh_tokens WHERE user_id = ?`,
+		`DELETE FROM user_identities WHERE user_id = ?`,
+		`DELETE FROM magic_link_tokens WHERE user_id = ?`,
+		`DELETE FROM webauthn_challenges WHERE user_id = ?`,
+		`DELETE FROM webauthn_credentials WHERE user_id = ?`,
+		`DELETE FROM user_recovery_codes WHERE user_id = ?`,
+		`DELETE FROM user_totp WHERE user_id = ?`,
+		`DELETE FROM user_roles WHERE user_id = ?`,
+		`DELETE FROM login_attempts WHERE user_id = ?`,
+		`DELETE FROM invite_codes WHERE created_by = ? AND uses = 0`,
+	}
+	for _, query := range purge {
+		args := make([]any, strings.Count(query, "?"))
+		for i := range args {
+			args[i] = userID
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return false, err
+		}
+	}
+
+	var referenced bool
+	err = tx.QueryRowContext(ctx, `SELECT
+		EXISTS (SELECT 1 FROM audit_logs WHERE actor_id = ? OR target_user_id = ?) OR
+		EXISTS (SELECT 1 FROM invite_codes WHERE created_by = ?) OR
+		EXISTS (SELECT 1 FROM invite_redemptions WHERE user_id = ? OR inviter_id = ?) OR
+		EXISTS (SELECT 1 FROM oauth_clients WHERE owner_user_id = ?)`,
+		userID, userID, userID, userID, userID, userID).Scan(&referenced)
+	if err != nil {
+		return false, err
+	}
+
+	if referenced {
+		_, err = tx.ExecContext(ctx, `UPDATE users SET username = CONCAT('deleted-', id), email = CONCAT('deleted-', id, '@deleted.invalid'),
+			password = '', purge_after = NULL, updated_at = ? WHERE id = ?`, now, userID)
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
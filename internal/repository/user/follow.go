@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+)
+
+// FollowUser makes the follower follow an active user. It returns false,
+// recording nothing, if the followee does not exist or is deactivated.
+func (r *userRepository) FollowUser(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	query := `INSERT INTO follows (follower_id, followee_id, created_at)
+	          SELECT ?, id, NOW() FROM users WHERE id = ? AND deactivated_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *userRepository) UnfollowUser(ctx context.Context, followerID, followeeID int64) error {
+	query := `DELETE FROM follows WHERE follower_id = ? AND followee_id = ?`
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+func (r *userRepository) IsFollowing(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	query := `SELECT 1 FROM follows WHERE follower_id = ? AND followee_id = ?`
+	var found int
+	err := r.db.QueryRowContext(ctx, query, followerID, followeeID).Scan(&found)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
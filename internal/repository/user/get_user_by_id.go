@@ -7,11 +7,11 @@ import (
 )
 
 func (r *userRepository) GetUserByID(ctx context.Context, id int64) (*model.UserModel, error) {
-	query := `SELECT id, username, email, password, created_at, updated_at FROM users WHERE id = ?`
+	query := `SELECT id, username, email, password, deactivated_at, purge_after, created_at, updated_at FROM users WHERE id = ?`
 	row := r.db.QueryRowContext(ctx, query, id)
 
 	var user model.UserModel
-	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.DeactivatedAt, &user.PurgeAfter, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -0,0 +1,132 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+func (r *userRepository) CreateInviteCode(ctx context.Context, invite *model.InviteCodeModel) (int64, error) {
+	query := `INSERT INTO invite_codes (created_by, code_hash, code_prefix, max_uses, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, invite.CreatedBy, invite.CodeHash, invite.CodePrefix, invite.MaxUses, invite.ExpiresAt, invite.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *userRepository) CountInviteCodesByCreator(ctx context.Context, userID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM invite_codes WHERE created_by = ?`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userRepository) GetInviteCodesByCreator(ctx context.Context, userID int64) ([]*model.InviteCodeModel, error) {
+	query := `SELECT id, created_by, code_hash, code_prefix, max_uses, uses, expires_at, revoked_at, created_at FROM invite_codes WHERE created_by = ? ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invites []*model.InviteCodeModel
+	for rows.Next() {
+		var invite model.InviteCodeModel
+		err := rows.Scan(&invite.ID, &invite.CreatedBy, &invite.CodeHash, &invite.CodePrefix, &invite.MaxUses, &invite.Uses, &invite.ExpiresAt, &invite.RevokedAt, &invite.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, &invite)
+	}
+	return invites, rows.Err()
+}
+
+func (r *userRepository) GetInviteRedemptionsByInviter(ctx context.Context, inviterID int64) ([]*model.InviteRedemptionModel, error) {
+	query := `SELECT r.id, r.invite_code_id, r.inviter_id, r.user_id, u.username, r.created_at
+		FROM invite_redemptions r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.inviter_id = ?
+		ORDER BY r.created_at, r.id`
+
+	rows, err := r.db.QueryContext(ctx, query, inviterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var redemptions []*model.InviteRedemptionModel
+	for rows.Next() {
+		var redemption model.InviteRedemptionModel
+		err := rows.Scan(&redemption.ID, &redemption.InviteCodeID, &redemption.InviterID, &redemption.UserID, &redemption.Username, &redemption.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, &redemption)
+	}
+	return redemptions, rows.Err()
+}
+
+func (r *userRepository) RevokeInviteCode(ctx context.Context, userID, inviteID int64, now time.Time) (bool, error) {
+	query := `UPDATE invite_codes SET revoked_at = ? WHERE id = ? AND created_by = ? AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, now, inviteID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// CreateInvitedUser spends one use of an invite code and creates the user in
+// the same transaction, so a code can never be used more than its limit. It
+// returns false without creating the user when the code is unknown, expired,
+// revoked or used up.
+func (r *userRepository) CreateInvitedUser(ctx context.Context, user *model.UserModel, codeHash string, now time.Time) (int64, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var inviteID, inviterID int64
+	query := `SELECT id, created_by FROM invite_codes WHERE code_hash = ? AND uses < max_uses AND expires_at > ? AND revoked_at IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, codeHash, now).Scan(&inviteID, &inviterID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE invite_codes SET uses = uses + 1 WHERE id = ?`, inviteID); err != nil {
+		return 0, false, err
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO users (email, username, password, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		user.Email, user.Username, user.Password, user.CreatedAt, user.UpdatedAt)
+	if err != nil {
+		return 0, false, err
+	}
+	userID, err := result.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO invite_redemptions (invite_code_id, inviter_id, user_id, created_at) VALUES (?, ?, ?, ?)`,
+		inviteID, inviterID, userID, now)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return userID, true, nil
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+func (r *userRepository) CreateLoginAttempt(ctx context.Context, attempt *model.LoginAttemptModel) error {
+	query := `INSERT INTO login_attempts (user_id, email, ip_address, user_agent, success, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, attempt.UserID, attempt.Email, attempt.IPAddress, attempt.UserAgent, attempt.Success, attempt.CreatedAt)
+	return err
+}
+
+// GetFailedLoginsByEmail counts failures for email since the later of since
+// and the last successful login, and returns the time of the latest failure.
+func (r *userRepository) GetFailedLoginsByEmail(ctx context.Context, email string, since time.Time) (int, time.Time, error) {
+	query := `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+	          WHERE email = ? AND success = FALSE AND created_at >= GREATEST(?,
+	              COALESCE((SELECT MAX(created_at) FROM login_attempts WHERE email = ? AND success = TRUE), ?))`
+	return r.scanFailedLogins(r.db.QueryRowContext(ctx, query, email, since, email, since))
+}
+
+// GetFailedLoginsByIP counts failures from ip since the given time. A
+// successful login does not reset it, so logging into one account does not
+// clear the count built up by guessing at others.
+func (r *userRepository) GetFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int, time.Time, error) {
+	query := `SELECT COUNT(*), MAX(created_at) FROM login_attempts WHERE ip_address = ? AND success = FALSE AND created_at >= ?`
+	return r.scanFailedLogins(r.db.QueryRowContext(ctx, query, ip, since))
+}
+
+func (r *userRepository) scanFailedLogins(row *sql.Row) (int, time.Time, error) {
+	var count int
+	var last sql.NullTime
+	if err := row.Scan(&count, &last); err != nil {
+		return 0, time.Time{}, err
+	}
+	return count, last.Time, nil
+}
+
+func (r *userRepository) GetLoginAttemptsByUserID(ctx context.Context, userID int64, limit, offset int) ([]*model.LoginAttemptModel, int64, error) {
+	query := `SELECT id, user_id, email, ip_address, user_agent, success, created_at
+	          FROM login_attempts
+	          WHERE user_id = ?
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var attempts []*model.LoginAttemptModel
+	for rows.Next() {
+		var attempt model.LoginAttemptModel
+		err := rows.Scan(&attempt.ID, &attempt.UserID, &attempt.Email, &attempt.IPAddress, &attempt.UserAgent, &attempt.Success, &attempt.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM login_attempts WHERE user_id = ?`
+	var totalCount int64
+	err = r.db.QueryRowContext(ctx, countQuery, userID).Scan(&totalCount)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, totalCount, nil
+}
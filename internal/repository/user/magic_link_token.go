@@ -0,0 +1,59 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+func (r *userRepository) CreateMagicLinkToken(ctx context.Context, token *model.MagicLinkTokenModel) error {
+	query := `INSERT INTO magic_link_tokens (user_id, email, token_hash, fingerprint_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, token.UserID, token.Email, token.TokenHash, token.FingerprintHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+func (r *userRepository) CountMagicLinkTokensByEmail(ctx context.Context, email string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM magic_link_tokens WHERE email = ? AND created_at >= ?`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, email, since).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ConsumeMagicLinkToken marks an unused token as used and returns it, so each
+// link works at most once. It returns nil when the token is unknown or was
+// already used; expiry is left to the caller.
+func (r *userRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*model.MagicLinkTokenModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, user_id, email, token_hash, fingerprint_hash, expires_at, used_at, created_at FROM magic_link_tokens WHERE token_hash = ? FOR UPDATE`
+
+	var token model.MagicLinkTokenModel
+	err = tx.QueryRowContext(ctx, query, tokenHash).Scan(&token.ID, &token.UserID, &token.Email, &token.TokenHash, &token.FingerprintHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if token.UsedAt.Valid {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE magic_link_tokens SET used_at = ? WHERE id = ?`, now, token.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	token.UsedAt = sql.NullTime{Time: now, Valid: true}
+	return &token, nil
+}
@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"strings"
+	"time"
+)
+
+func (r *userRepository) CreatePersonalAccessToken(ctx context.Context, token *model.PersonalAccessTokenModel) (int64, error) {
+	query := `INSERT INTO personal_access_tokens (user_id, name, token_hash, token_prefix, scopes, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, token.UserID, token.Name, token.TokenHash, token.TokenPrefix, strings.Join(token.Scopes, ","), token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *userRepository) GetPersonalAccessTokensByUserID(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error) {
+	query := `SELECT id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at
+	          FROM personal_access_tokens
+	          WHERE user_id = ?
+	          ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*model.PersonalAccessTokenModel
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *userRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error) {
+	query := `SELECT id, user_id, name, token_hash, token_prefix, scopes, expires_at, last_used_at, created_at
+	          FROM personal_access_tokens
+	          WHERE token_hash = ?`
+
+	token, err := scanPersonalAccessToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// DeletePersonalAccessToken reports whether a token owned by userID was removed.
+func (r *userRepository) DeletePersonalAccessToken(ctx context.Context, userID, tokenID int64) (bool, error) {
+	query := `DELETE FROM personal_access_tokens WHERE id = ? AND user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, tokenID, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *userRepository) TouchPersonalAccessToken(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	query := `UPDATE personal_access_tokens SET last_used_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, usedAt, tokenID)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPersonalAccessToken(row rowScanner) (*model.PersonalAccessTokenModel, error) {
+	var token model.PersonalAccessTokenModel
+	var scopes string
+	err := row.Scan(&token.ID, &token.UserID, &token.Name, &token.TokenHash, &token.TokenPrefix, &scopes, &token.ExpiresAt, &token.LastUsedAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	return &token, nil
+}
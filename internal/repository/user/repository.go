@@ -17,6 +17,63 @@ type UserRepository interface {
 	GetRefreshTokenByToken(ctx context.Context, token string) (*model.RefreshTokenModel, error)
 	DeleteRefreshToken(ctx context.Context, token string) error
 	UpdateUser(ctx context.Context, user *model.UserModel) error
+
+	GetUserRoles(ctx context.Context, userID int64) ([]string, error)
+	AddUserRole(ctx context.Context, userID int64, role string) error
+	RemoveUserRole(ctx context.Context, userID int64, role string) error
+
+	GetUserTOTP(ctx context.Context, userID int64) (*model.UserTOTPModel, error)
+	UpsertUserTOTP(ctx context.Context, userID int64, secret string) error
+	ConfirmUserTOTP(ctx context.Context, userID, step int64) error
+	UseTOTPStep(ctx context.Context, userID, step int64) (bool, error)
+	DeleteUserTOTP(ctx context.Context, userID int64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error)
+	UseMFAToken(ctx context.Context, jti string, expiresAt, now time.Time) (bool, error)
+
+	CreateLoginAttempt(ctx context.Context, attempt *model.LoginAttemptModel) error
+	GetFailedLoginsByEmail(ctx context.Context, email string, since time.Time) (int, time.Time, error)
+	GetFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int, time.Time, error)
+	GetLoginAttemptsByUserID(ctx context.Context, userID int64, limit, offset int) ([]*model.LoginAttemptModel, int64, error)
+
+	CreatePersonalAccessToken(ctx context.Context, token *model.PersonalAccessTokenModel) (int64, error)
+	GetPersonalAccessTokensByUserID(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error)
+	DeletePersonalAccessToken(ctx context.Context, userID, tokenID int64) (bool, error)
+	TouchPersonalAccessToken(ctx context.Context, tokenID int64, usedAt time.Time) error
+
+	GetUserIdentity(ctx context.Context, provider, subject string) (*model.UserIdentityModel, error)
+	CreateUserIdentity(ctx context.Context, identity *model.UserIdentityModel) error
+	CreateOIDCLoginState(ctx context.Context, state *model.OIDCLoginStateModel) error
+	ConsumeOIDCLoginState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginStateModel, error)
+
+	CreateMagicLinkToken(ctx context.Context, token *model.MagicLinkTokenModel) error
+	CountMagicLinkTokensByEmail(ctx context.Context, email string, since time.Time) (int, error)
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*model.MagicLinkTokenModel, error)
+
+	CreateWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredentialModel) (int64, error)
+	GetWebAuthnCredentialsByUserID(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error)
+	GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (*model.WebAuthnCredentialModel, error)
+	UpdateWebAuthnSignCount(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error
+	DeleteWebAuthnCredential(ctx context.Context, userID, id int64) (bool, error)
+	CreateWebAuthnChallenge(ctx context.Context, challenge *model.WebAuthnChallengeModel) error
+	ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string, now time.Time) (*model.WebAuthnChallengeModel, error)
+
+	CreateInviteCode(ctx context.Context, invite *model.InviteCodeModel) (int64, error)
+	CountInviteCodesByCreator(ctx context.Context, userID int64) (int, error)
+	GetInviteCodesByCreator(ctx context.Context, userID int64) ([]*model.InviteCodeModel, error)
+	GetInviteRedemptionsByInviter(ctx context.Context, inviterID int64) ([]*model.InviteRedemptionModel, error)
+	RevokeInviteCode(ctx context.Context, userID, inviteID int64, now time.Time) (bool, error)
+	CreateInvitedUser(ctx context.Context, user *model.UserModel, codeHash string, now time.Time) (int64, bool, error)
+
+	FollowUser(ctx context.Context, followerID, followeeID int64) (bool, error)
+	UnfollowUser(ctx context.Context, followerID, followeeID int64) error
+	IsFollowing(ctx context.Context, followerID, followeeID int64) (bool, error)
+
+	DeactivateUser(ctx context.Context, userID int64, now, purgeAfter time.Time) (bool, error)
+	ReactivateUser(ctx context.Context, userID int64, now time.Time) (bool, error)
+	GetUsersDueForPurge(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	PurgeUser(ctx context.Context, userID int64, now time.Time) (bool, error)
 }
 
 type userRepository struct {
@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+// UseMFAToken spends the login challenge token with the given jti. It
+// reports false when the token was already used. Spent tokens are only
+// kept until they expire, so expired ones are cleared on the way.
+func (r *userRepository) UseMFAToken(ctx context.Context, jti string, expiresAt, now time.Time) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM used_mfa_tokens WHERE expires_at < ?`, now); err != nil {
+		return false, err
+	}
+
+	result, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO used_mfa_tokens (jti, expires_at) VALUES (?, ?)`, jti, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
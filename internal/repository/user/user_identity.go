@@ -0,0 +1,68 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+func (r *userRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*model.UserIdentityModel, error) {
+	query := `SELECT id, user_id, provider, subject, email, created_at FROM user_identities WHERE provider = ? AND subject = ?`
+
+	var identity model.UserIdentityModel
+	err := r.db.QueryRowContext(ctx, query, provider, subject).Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userRepository) CreateUserIdentity(ctx context.Context, identity *model.UserIdentityModel) error {
+	query := `INSERT INTO user_identities (user_id, provider, subject, email, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt)
+	return err
+}
+
+func (r *userRepository) CreateOIDCLoginState(ctx context.Context, state *model.OIDCLoginStateModel) error {
+	query := `INSERT INTO oidc_login_states (state_hash, provider, nonce, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, state.StateHash, state.Provider, state.Nonce, state.ExpiresAt, state.CreatedAt)
+	return err
+}
+
+// ConsumeOIDCLoginState deletes and returns an unexpired state, so each
+// provider redirect can complete at most once. It returns nil when the state
+// is unknown, expired or already used.
+func (r *userRepository) ConsumeOIDCLoginState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginStateModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, state_hash, provider, nonce, expires_at, created_at FROM oidc_login_states WHERE state_hash = ? FOR UPDATE`
+
+	var state model.OIDCLoginStateModel
+	err = tx.QueryRowContext(ctx, query, stateHash).Scan(&state.ID, &state.StateHash, &state.Provider, &state.Nonce, &state.ExpiresAt, &state.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM oidc_login_states WHERE id = ?`, state.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if !now.Before(state.ExpiresAt) {
+		return nil, nil
+	}
+	return &state, nil
+}
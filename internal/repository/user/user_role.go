@@ -0,0 +1,36 @@
+package user
+
+import (
+	"context"
+)
+
+func (r *userRepository) GetUserRoles(ctx context.Context, userID int64) ([]string, error) {
+	query := `SELECT role FROM user_roles WHERE user_id = ? ORDER BY role`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []string
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *userRepository) AddUserRole(ctx context.Context, userID int64, role string) error {
+	query := `INSERT IGNORE INTO user_roles (user_id, role, created_at) VALUES (?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query, userID, role)
+	return err
+}
+
+func (r *userRepository) RemoveUserRole(ctx context.Context, userID int64, role string) error {
+	query := `DELETE FROM user_roles WHERE user_id = ? AND role = ?`
+	_, err := r.db.ExecContext(ctx, query, userID, role)
+	return err
+}
@@ -0,0 +1,103 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+)
+
+func (r *userRepository) GetUserTOTP(ctx context.Context, userID int64) (*model.UserTOTPModel, error) {
+	query := `SELECT id, user_id, secret, last_used_step, confirmed_at, created_at, updated_at FROM user_totp WHERE user_id = ?`
+	row := r.db.QueryRowContext(ctx, query, userID)
+
+	var result model.UserTOTPModel
+	err := row.Scan(&result.ID, &result.UserID, &result.Secret, &result.LastUsedStep, &result.ConfirmedAt, &result.CreatedAt, &result.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpsertUserTOTP stores a new, unconfirmed secret, replacing any pending
+// enrollment for the user.
+func (r *userRepository) UpsertUserTOTP(ctx context.Context, userID int64, secret string) error {
+	query := `INSERT INTO user_totp (user_id, secret, last_used_step, confirmed_at, created_at, updated_at)
+	          VALUES (?, ?, 0, NULL, NOW(), NOW())
+	          ON DUPLICATE KEY UPDATE secret = VALUES(secret), last_used_step = 0, confirmed_at = NULL, updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, userID, secret)
+	return err
+}
+
+func (r *userRepository) ConfirmUserTOTP(ctx context.Context, userID, step int64) error {
+	query := `UPDATE user_totp SET confirmed_at = NOW(), last_used_step = ?, updated_at = NOW() WHERE user_id = ? AND confirmed_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, step, userID)
+	return err
+}
+
+// UseTOTPStep records step as the last accepted code. It reports false when
+// the step was already used, so a code cannot be replayed within its window.
+func (r *userRepository) UseTOTPStep(ctx context.Context, userID, step int64) (bool, error) {
+	query := `UPDATE user_totp SET last_used_step = ?, updated_at = NOW() WHERE user_id = ? AND last_used_step < ?`
+	result, err := r.db.ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *userRepository) DeleteUserTOTP(ctx context.Context, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *userRepository) ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+	for _, codeHash := range codeHashes {
+		query := `INSERT INTO user_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, NOW())`
+		if _, err := tx.ExecContext(ctx, query, userID, codeHash); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks an unused recovery code as spent and reports whether
+// one matched.
+func (r *userRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	query := `UPDATE user_recovery_codes SET used_at = NOW() WHERE user_id = ? AND code_hash = ? AND used_at IS NULL LIMIT 1`
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/model"
+	"time"
+)
+
+func (r *userRepository) CreateWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredentialModel) (int64, error) {
+	query := `INSERT INTO webauthn_credentials (user_id, name, credential_id, public_key, sign_count, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query, credential.UserID, credential.Name, credential.CredentialID, credential.PublicKey, credential.SignCount, credential.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (r *userRepository) GetWebAuthnCredentialsByUserID(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error) {
+	query := `SELECT id, user_id, name, credential_id, public_key, sign_count, last_used_at, created_at FROM webauthn_credentials WHERE user_id = ? ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credentials []*model.WebAuthnCredentialModel
+	for rows.Next() {
+		credential, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials, rows.Err()
+}
+
+func (r *userRepository) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (*model.WebAuthnCredentialModel, error) {
+	query := `SELECT id, user_id, name, credential_id, public_key, sign_count, last_used_at, created_at FROM webauthn_credentials WHERE credential_id = ?`
+
+	credential, err := scanWebAuthnCredential(r.db.QueryRowContext(ctx, query, credentialID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (r *userRepository) UpdateWebAuthnSignCount(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error {
+	query := `UPDATE webauthn_credentials SET sign_count = ?, last_used_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, signCount, usedAt, id)
+	return err
+}
+
+func (r *userRepository) DeleteWebAuthnCredential(ctx context.Context, userID, id int64) (bool, error) {
+	query := `DELETE FROM webauthn_credentials WHERE id = ? AND user_id = ?`
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *userRepository) CreateWebAuthnChallenge(ctx context.Context, challenge *model.WebAuthnChallengeModel) error {
+	query := `INSERT INTO webauthn_challenges (user_id, challenge_hash, ceremony, expires_at, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, challenge.UserID, challenge.ChallengeHash, challenge.Ceremony, challenge.ExpiresAt, challenge.CreatedAt)
+	return err
+}
+
+// ConsumeWebAuthnChallenge deletes and returns an unexpired challenge, so
+// each ceremony can complete at most once. It returns nil when the challenge
+// is unknown, expired or already used.
+func (r *userRepository) ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string, now time.Time) (*model.WebAuthnChallengeModel, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, user_id, challenge_hash, ceremony, expires_at, created_at FROM webauthn_challenges WHERE challenge_hash = ? FOR UPDATE`
+
+	var challenge model.WebAuthnChallengeModel
+	err = tx.QueryRowContext(ctx, query, challengeHash).Scan(&challenge.ID, &challenge.UserID, &challenge.ChallengeHash, &challenge.Ceremony, &challenge.ExpiresAt, &challenge.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webauthn_challenges WHERE id = ?`, challenge.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if !now.Before(challenge.ExpiresAt) {
+		return nil, nil
+	}
+	return &challenge, nil
+}
+
+func scanWebAuthnCredential(row rowScanner) (*model.WebAuthnCredentialModel, error) {
+	var credential model.WebAuthnCredentialModel
+	err := row.Scan(&credential.ID, &credential.UserID, &credential.Name, &credential.CredentialID, &credential.PublicKey, &credential.SignCount, &credential.LastUsedAt, &credential.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"math"
+	"net/http"
+)
+
+func (s *auditService) GetAuditLogs(ctx context.Context, page, pageSize int) (*dto.AuditLogsResponse, int, error) {
+	offset := (page - 1) * pageSize
+
+	logs, totalCount, err := s.auditRepo.GetAuditLogs(ctx, pageSize, offset)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	var logResponses []dto.AuditLogResponse
+	for _, log := range logs {
+		logResponses = append(logResponses, dto.AuditLogResponse{
+			ID:           log.ID,
+			ActorID:      log.ActorID,
+			TargetUserID: log.TargetUserID,
+			Action:       log.Action,
+			EntityType:   log.EntityType,
+			EntityID:     log.EntityID,
+			CreatedAt:    log.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+
+	response := &dto.AuditLogsResponse{
+		AuditLogs:  logResponses,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+
+	return response, http.StatusOK, nil
+}
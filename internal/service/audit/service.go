@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/repository/audit"
+)
+
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, page, pageSize int) (*dto.AuditLogsResponse, int, error)
+}
+
+type auditService struct {
+	auditRepo audit.AuditRepository
+}
+
+func NewService(auditRepo audit.AuditRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
@@ -0,0 +1,169 @@
+package comment
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/internal/repository/comment"
+	"go-twitter/internal/repository/post"
+	"go-twitter/internal/repository/user"
+	postService "go-twitter/internal/service/post"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// memoryStore holds posts, comments, their likes and who follows whom, and
+// answers like the repositories do: comments are only readable on
+// published, live posts.
+type memoryStore struct {
+	posts        map[int64]*model.PostModel
+	comments     []*model.CommentModel
+	commentLikes map[int64]int
+	follows      map[int64][]int64
+}
+
+type memoryCommentRepository struct {
+	comment.CommentRepository
+	*memoryStore
+}
+
+func (r *memoryCommentRepository) IsPostPublished(ctx context.Context, postID int64) (bool, error) {
+	p, ok := r.posts[postID]
+	return ok && p.Status == model.PostPublished && !p.DeletedAt.Valid, nil
+}
+
+func (r *memoryCommentRepository) GetCommentsByPostID(ctx context.Context, postID int64, offset, limit int) ([]*model.CommentModel, int64, error) {
+	var comments []*model.CommentModel
+	for _, c := range r.comments {
+		if c.PostID == postID && !c.DeletedAt.Valid {
+			comments = append(comments, c)
+		}
+	}
+	return comments, int64(len(comments)), nil
+}
+
+func (r *memoryCommentRepository) GetPostForReply(ctx context.Context, postID int64) (*model.PostModel, error) {
+	p, ok := r.posts[postID]
+	if !ok || p.Status != model.PostPublished || p.DeletedAt.Valid {
+		return nil, nil
+	}
+	return p, nil
+}
+
+func (r *memoryCommentRepository) CreateComment(ctx context.Context, comment *model.CommentModel) (int64, bool, error) {
+	r.comments = append(r.comments, comment)
+	return int64(len(r.comments)), true, nil
+}
+
+func (r *memoryCommentRepository) GetCommentLikesCount(ctx context.Context, commentID int64) (int, error) {
+	return r.commentLikes[commentID], nil
+}
+
+// memoryPostRepository only restores posts; other calls are not expected.
+type memoryPostRepository struct {
+	post.PostRepository
+	*memoryStore
+}
+
+func (r *memoryPostRepository) RestorePost(ctx context.Context, id, userID int64, since time.Time) (bool, error) {
+	p, ok := r.posts[id]
+	if !ok || p.UserID != userID || !p.DeletedAt.Valid || !p.DeletedAt.Time.After(since) {
+		return false, nil
+	}
+	p.DeletedAt = sql.NullTime{}
+	return true, nil
+}
+
+type memoryUserRepository struct {
+	user.UserRepository
+	*memoryStore
+}
+
+func (r *memoryUserRepository) GetUserByID(ctx context.Context, id int64) (*model.UserModel, error) {
+	return &model.UserModel{ID: id, Username: "user"}, nil
+}
+
+func (r *memoryUserRepository) IsFollowing(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	return slices.Contains(r.follows[followerID], followeeID), nil
+}
+
+// newDeletedPostStore has post 1 by user 1, deleted by its author, with a
+// comment liked twice, and a draft, post 2. Post 3 does not exist.
+func newDeletedPostStore() *memoryStore {
+	return &memoryStore{
+		posts: map[int64]*model.PostModel{
+			1: {
+				ID:        1,
+				UserID:    1,
+				Status:    model.PostPublished,
+				DeletedAt: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+			},
+			2: {ID: 2, UserID: 1, Status: model.PostDraft},
+		},
+		comments:     []*model.CommentModel{{ID: 10, PostID: 1, UserID: 2, Content: "first"}},
+		commentLikes: map[int64]int{10: 2},
+	}
+}
+
+func TestGetCommentsByPostID_NotFoundForHiddenPost(t *testing.T) {
+	service := NewService(&config.Config{}, &memoryCommentRepository{memoryStore: newDeletedPostStore()}, &memoryUserRepository{})
+
+	for _, postID := range []int64{1, 2, 3} {
+		response, status, _ := service.GetCommentsByPostID(context.Background(), postID, 1, 10)
+		if status != http.StatusNotFound || response != nil {
+			t.Errorf("Expected status %d listing comments of post %d, got %d", http.StatusNotFound, postID, status)
+		}
+	}
+}
+
+func TestGetCommentsByPostID_ShownAgainAfterRestorePost(t *testing.T) {
+	store := newDeletedPostStore()
+	service := NewService(&config.Config{}, &memoryCommentRepository{memoryStore: store}, &memoryUserRepository{})
+	posts := postService.NewService(&config.Config{}, &memoryPostRepository{memoryStore: store}, nil)
+	ctx := context.Background()
+
+	if status, err := posts.RestorePost(ctx, 1, 1); status != http.StatusOK {
+		t.Fatalf("Expected status %d restoring the post, got %d (%v)", http.StatusOK, status, err)
+	}
+
+	response, status, err := service.GetCommentsByPostID(ctx, 1, 1, 10)
+	if status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if len(response.Comments) != 1 || response.Comments[0].ID != 10 || response.Comments[0].LikesCount != 2 {
+		t.Errorf("Expected the comment back with its 2 likes, got %+v", response.Comments)
+	}
+}
+
+func TestCreateComment_FollowersOnly(t *testing.T) {
+	store := &memoryStore{
+		posts: map[int64]*model.PostModel{
+			1: {ID: 1, UserID: 1, Status: model.PostPublished, ReplyPolicy: model.ReplyFollowers},
+		},
+		follows: map[int64][]int64{2: {1}, 3: {4}},
+	}
+	service := NewService(&config.Config{}, &memoryCommentRepository{memoryStore: store}, &memoryUserRepository{memoryStore: store})
+	ctx := context.Background()
+
+	tests := []struct {
+		userID int64
+		want   int
+	}{
+		{1, http.StatusCreated},   // the author
+		{2, http.StatusCreated},   // follows the author
+		{3, http.StatusForbidden}, // follows someone else
+	}
+	for _, tt := range tests {
+		_, status, err := service.CreateComment(ctx, tt.userID, 1, dto.CreateCommentRequest{Content: "reply"})
+		if status != tt.want {
+			t.Errorf("Expected status %d for user %d, got %d (%v)", tt.want, tt.userID, status, err)
+		}
+	}
+	if len(store.comments) != 2 {
+		t.Errorf("Expected 2 comments created, got %d", len(store.comments))
+	}
+}
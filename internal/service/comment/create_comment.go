@@ -2,22 +2,61 @@ package comment
 
 import (
 	"context"
+	"errors"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
 	"net/http"
 )
 
 func (s *commentService) CreateComment(ctx context.Context, userID, postID int64, req dto.CreateCommentRequest) (int64, int, error) {
+	post, err := s.commentRepo.GetPostForReply(ctx, postID)
+	if err != nil {
+		return 0, http.StatusInternalServerError, err
+	}
+	if post == nil {
+		return 0, http.StatusNotFound, errors.New("post not found")
+	}
+
+	// only mentioned-only posts need to know who is replying, and only
+	// followers posts whether they follow the author
+	var username string
+	if post.ReplyPolicy == model.ReplyMentionedOnly {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		if user != nil {
+			username = user.Username
+		}
+	}
+	var followsAuthor bool
+	if post.ReplyPolicy == model.ReplyFollowers {
+		followsAuthor, err = s.userRepo.IsFollowing(ctx, userID, post.UserID)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+	}
+	if !post.CanReply(userID, username, followsAuthor) {
+		if post.RepliesLocked {
+			return 0, http.StatusForbidden, errors.New("replies to this post are locked")
+		}
+		return 0, http.StatusForbidden, errors.New("the author limited who can reply to this post")
+	}
+
 	comment := &model.CommentModel{
 		PostID:  postID,
 		UserID:  userID,
 		Content: req.Content,
 	}
 
-	commentID, err := s.commentRepo.CreateComment(ctx, comment)
+	commentID, created, err := s.commentRepo.CreateComment(ctx, comment)
 	if err != nil {
 		return 0, http.StatusInternalServerError, err
 	}
 
+	if !created {
+		return 0, http.StatusNotFound, errors.New("post not found")
+	}
+
 	return commentID, http.StatusCreated, nil
 }
@@ -2,10 +2,11 @@ package comment
 
 import (
 	"context"
+	"go-twitter/internal/model"
 	"net/http"
 )
 
-func (s *commentService) DeleteComment(ctx context.Context, userID, commentID int64) (int, error) {
+func (s *commentService) DeleteComment(ctx context.Context, userID, commentID int64, roles []string) (int, error) {
 	existingComment, err := s.commentRepo.GetCommentByID(ctx, commentID)
 	if err != nil {
 		return http.StatusInternalServerError, err
@@ -15,11 +16,24 @@ func (s *commentService) DeleteComment(ctx context.Context, userID, commentID in
 		return http.StatusNotFound, nil
 	}
 
-	if existingComment.UserID != userID {
+	moderating := existingComment.UserID != userID
+	if moderating && !model.HasPermission(roles, model.PermissionModerateComments) {
 		return http.StatusForbidden, nil
 	}
 
-	err = s.commentRepo.DeleteComment(ctx, commentID)
+	// record moderator actions on other users' content
+	var auditLog *model.AuditLogModel
+	if moderating {
+		auditLog = &model.AuditLogModel{
+			ActorID:      userID,
+			TargetUserID: existingComment.UserID,
+			Action:       model.AuditActionDeleteComment,
+			EntityType:   "comment",
+			EntityID:     commentID,
+		}
+	}
+
+	err = s.commentRepo.DeleteComment(ctx, commentID, userID, auditLog)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -33,6 +33,9 @@ func (s *commentService) GetCommentByID(ctx context.Context, id int64) (*dto.Com
 		Username:   user.Username,
 		Content:    comment.Content,
 		LikesCount: likesCount,
+		Edited:     comment.EditCount > 0,
+		EditCount:  comment.EditCount,
+		Version:    comment.Version,
 		CreatedAt:  comment.CreatedAt.Format("2006-01-02 15:04:05"),
 		UpdatedAt:  comment.UpdatedAt.Format("2006-01-02 15:04:05"),
 	}
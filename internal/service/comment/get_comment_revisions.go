@@ -0,0 +1,40 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"net/http"
+)
+
+// GetCommentRevisions returns every version of the comment, oldest first,
+// ending with the current one.
+func (s *commentService) GetCommentRevisions(ctx context.Context, commentID int64) (*dto.CommentRevisionsResponse, int, error) {
+	comment, err := s.commentRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if comment == nil {
+		return nil, http.StatusNotFound, errors.New("comment not found")
+	}
+
+	revisions, err := s.commentRepo.GetCommentRevisions(ctx, commentID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	response := &dto.CommentRevisionsResponse{}
+	for i, revision := range revisions {
+		response.Revisions = append(response.Revisions, dto.CommentRevisionResponse{
+			Revision:  i + 1,
+			Content:   revision.Content,
+			CreatedAt: revision.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	response.Revisions = append(response.Revisions, dto.CommentRevisionResponse{
+		Revision:  len(revisions) + 1,
+		Content:   comment.Content,
+		CreatedAt: comment.UpdatedAt.Format("2006-01-02 15:04:05"),
+	})
+	return response, http.StatusOK, nil
+}
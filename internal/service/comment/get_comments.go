@@ -2,6 +2,7 @@ package comment
 
 import (
 	"context"
+	"errors"
 	"go-twitter/internal/dto"
 	"math"
 	"net/http"
@@ -10,6 +11,15 @@ import (
 func (s *commentService) GetCommentsByPostID(ctx context.Context, postID int64, page, pageSize int) (*dto.CommentsResponse, int, error) {
 	offset := (page - 1) * pageSize
 
+	// comments are hidden along with their post
+	published, err := s.commentRepo.IsPostPublished(ctx, postID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !published {
+		return nil, http.StatusNotFound, errors.New("post not found")
+	}
+
 	comments, totalCount, err := s.commentRepo.GetCommentsByPostID(ctx, postID, offset, pageSize)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
@@ -34,6 +44,9 @@ func (s *commentService) GetCommentsByPostID(ctx context.Context, postID int64,
 			Username:   user.Username,
 			Content:    comment.Content,
 			LikesCount: likesCount,
+			Edited:     comment.EditCount > 0,
+			EditCount:  comment.EditCount,
+			Version:    comment.Version,
 			CreatedAt:  comment.CreatedAt.Format("2006-01-02 15:04:05"),
 			UpdatedAt:  comment.UpdatedAt.Format("2006-01-02 15:04:05"),
 		})
@@ -0,0 +1,22 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// RestoreComment takes a comment the user deleted out of the trash. Its
+// post must not be deleted.
+func (s *commentService) RestoreComment(ctx context.Context, userID, commentID int64) (int, error) {
+	retention, _ := s.cfg.Trash()
+	restored, err := s.commentRepo.RestoreComment(ctx, commentID, userID, time.Now().Add(-retention))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !restored {
+		return http.StatusNotFound, errors.New("comment not found in trash, or its post was deleted")
+	}
+	return http.StatusOK, nil
+}
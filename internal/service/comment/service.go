@@ -2,6 +2,7 @@ package comment
 
 import (
 	"context"
+	"go-twitter/internal/config"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/repository/comment"
 	"go-twitter/internal/repository/user"
@@ -12,16 +13,20 @@ type CommentService interface {
 	GetCommentByID(ctx context.Context, id int64) (*dto.CommentResponse, int, error)
 	GetCommentsByPostID(ctx context.Context, postID int64, page, pageSize int) (*dto.CommentsResponse, int, error)
 	UpdateComment(ctx context.Context, userID, commentID int64, req dto.UpdateCommentRequest) (int, error)
-	DeleteComment(ctx context.Context, userID, commentID int64) (int, error)
+	DeleteComment(ctx context.Context, userID, commentID int64, roles []string) (int, error)
+	RestoreComment(ctx context.Context, userID, commentID int64) (int, error)
+	GetCommentRevisions(ctx context.Context, commentID int64) (*dto.CommentRevisionsResponse, int, error)
 }
 
 type commentService struct {
+	cfg         *config.Config
 	commentRepo comment.CommentRepository
 	userRepo    user.UserRepository
 }
 
-func NewService(commentRepo comment.CommentRepository, userRepo user.UserRepository) CommentService {
+func NewService(cfg *config.Config, commentRepo comment.CommentRepository, userRepo user.UserRepository) CommentService {
 	return &commentService{
+		cfg:         cfg,
 		commentRepo: commentRepo,
 		userRepo:    userRepo,
 	}
@@ -2,9 +2,13 @@ package comment
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
+	"go-twitter/pkg/etag"
 	"net/http"
+	"time"
 )
 
 func (s *commentService) UpdateComment(ctx context.Context, userID, commentID int64, req dto.UpdateCommentRequest) (int, error) {
@@ -21,15 +25,29 @@ func (s *commentService) UpdateComment(ctx context.Context, userID, commentID in
 		return http.StatusForbidden, nil
 	}
 
+	window := s.cfg.EditingWindow()
+	if time.Since(existingComment.CreatedAt) > window {
+		return http.StatusForbidden, fmt.Errorf("comments can only be edited within %s of posting", window)
+	}
+
+	if req.IfMatch != "" && !etag.Match(req.IfMatch, etag.FromVersion(existingComment.Version)) {
+		return http.StatusPreconditionFailed, errors.New("comment was modified since it was read")
+	}
+
 	comment := &model.CommentModel{
 		ID:      commentID,
 		Content: req.Content,
+		Version: existingComment.Version,
 	}
 
-	err = s.commentRepo.UpdateComment(ctx, comment)
+	updated, err := s.commentRepo.UpdateComment(ctx, comment)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
+	// changed or deleted since it was read above
+	if !updated {
+		return http.StatusPreconditionFailed, errors.New("comment was modified since it was read")
+	}
 
 	return http.StatusOK, nil
 }
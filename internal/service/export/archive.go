@@ -0,0 +1,586 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/pkg/blobstore"
+	"html/template"
+	"io"
+	"path"
+	"time"
+)
+
+// collectArchive gathers everything stored about the user. Credentials such
+// as password hashes, token hashes, client secrets and TOTP secrets are left
+// out. It also returns the blob keys of the user's uploads by their path in
+// the archive.
+func (s *exportService) collectArchive(ctx context.Context, userID int64, now time.Time) (*dto.DataExportArchive, map[string]string, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, errors.New("user not found")
+	}
+	roles, err := s.userRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	totpConfig, err := s.userRepo.GetUserTOTP(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	passkeys, err := s.userRepo.GetWebAuthnCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, err := s.userRepo.GetPersonalAccessTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	posts, err := s.exportRepo.GetPostsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	comments, err := s.exportRepo.GetCommentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	postLikes, err := s.exportRepo.GetPostLikesByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	commentLikes, err := s.exportRepo.GetCommentLikesByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refreshTokens, err := s.exportRepo.GetRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := s.exportRepo.GetLoginAttemptsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identities, err := s.exportRepo.GetUserIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pin, err := s.exportRepo.GetPinnedPost(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	follows, err := s.exportRepo.GetFollowsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	postRevisions, err := s.exportRepo.GetPostRevisionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	commentRevisions, err := s.exportRepo.GetCommentRevisionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	folders, err := s.exportRepo.GetBookmarkFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookmarks, err := s.exportRepo.GetBookmarksByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	votes, err := s.exportRepo.GetPollVotesByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	media, err := s.exportRepo.GetMediaByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	importJobs, err := s.exportRepo.GetImportJobsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	invites, err := s.userRepo.GetInviteCodesByCreator(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	redemptions, err := s.exportRepo.GetInviteRedemptionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	oauthClients, err := s.exportRepo.GetOAuthClientsByOwner(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	oauthGrants, err := s.exportRepo.GetOAuthGrantsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	archive := &dto.DataExportArchive{
+		GeneratedAt: now.Format("2006-01-02 15:04:05"),
+		Profile: dto.ExportProfile{
+			ID:               user.ID,
+			Username:         user.Username,
+			Email:            user.Email,
+			Roles:            append([]string{}, roles...),
+			TwoFactorEnabled: totpConfig != nil && totpConfig.ConfirmedAt.Valid,
+			Passkeys:         []dto.PasskeyResponse{},
+			Identities:       []dto.ExportIdentity{},
+			CreatedAt:        user.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:        user.UpdatedAt.Format("2006-01-02 15:04:05"),
+		},
+		Posts:    []dto.ExportPost{},
+		Comments: []dto.ExportComment{},
+		Likes:    dto.ExportLikes{Posts: []dto.ExportLike{}, Comments: []dto.ExportLike{}},
+		Sessions: dto.ExportSessions{
+			RefreshTokens:        []dto.ExportRefreshToken{},
+			PersonalAccessTokens: []dto.PersonalAccessTokenResponse{},
+			LoginAttempts:        []dto.LoginAttemptResponse{},
+		},
+		Follows:    dto.ExportFollows{Following: []dto.ExportFollow{}, Followers: []dto.ExportFollow{}},
+		Revisions:  dto.ExportRevisions{Posts: []dto.ExportPostRevision{}, Comments: []dto.ExportCommentRevision{}},
+		Bookmarks:  dto.ExportBookmarks{Folders: []dto.BookmarkFolderResponse{}, Posts: []dto.ExportBookmark{}},
+		PollVotes:  []dto.ExportPollVote{},
+		Media:      []dto.ExportMedia{},
+		ImportJobs: []dto.ImportJobResponse{},
+		Invites:    dto.ExportInvites{Codes: []dto.InviteResponse{}},
+		OAuth:      dto.ExportOAuth{Clients: []dto.OAuthClientResponse{}, Grants: []dto.ExportOAuthGrant{}},
+	}
+	files := make(map[string]string)
+
+	for _, passkey := range passkeys {
+		archive.Profile.Passkeys = append(archive.Profile.Passkeys, dto.PasskeyResponse{
+			ID:         passkey.ID,
+			Name:       passkey.Name,
+			LastUsedAt: formatNullTime(passkey.LastUsedAt),
+			CreatedAt:  passkey.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, post := range posts {
+		archive.Posts = append(archive.Posts, dto.ExportPost{
+			ID:        post.ID,
+			Title:     post.Title,
+			Content:   post.Content,
+			CreatedAt: post.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt: post.UpdatedAt.Format("2006-01-02 15:04:05"),
+			DeletedAt: formatNullTime(post.DeletedAt),
+		})
+	}
+	for _, comment := range comments {
+		archive.Comments = append(archive.Comments, dto.ExportComment{
+			ID:        comment.ID,
+			PostID:    comment.PostID,
+			Content:   comment.Content,
+			CreatedAt: comment.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt: comment.UpdatedAt.Format("2006-01-02 15:04:05"),
+			DeletedAt: formatNullTime(comment.DeletedAt),
+		})
+	}
+	for _, like := range postLikes {
+		archive.Likes.Posts = append(archive.Likes.Posts, dto.ExportLike{ID: like.PostID, CreatedAt: like.CreatedAt.Format("2006-01-02 15:04:05")})
+	}
+	for _, like := range commentLikes {
+		archive.Likes.Comments = append(archive.Likes.Comments, dto.ExportLike{ID: like.CommentID, CreatedAt: like.CreatedAt.Format("2006-01-02 15:04:05")})
+	}
+	for _, token := range refreshTokens {
+		archive.Sessions.RefreshTokens = append(archive.Sessions.RefreshTokens, dto.ExportRefreshToken{
+			ID:        token.ID,
+			CreatedAt: token.CreatedAt.Format("2006-01-02 15:04:05"),
+			ExpiresAt: token.ExpiresAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, token := range tokens {
+		archive.Sessions.PersonalAccessTokens = append(archive.Sessions.PersonalAccessTokens, dto.PersonalAccessTokenResponse{
+			ID:          token.ID,
+			Name:        token.Name,
+			TokenPrefix: token.TokenPrefix,
+			Scopes:      token.Scopes,
+			ExpiresAt:   token.ExpiresAt.Format("2006-01-02 15:04:05"),
+			LastUsedAt:  formatNullTime(token.LastUsedAt),
+			CreatedAt:   token.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, attempt := range attempts {
+		archive.Sessions.LoginAttempts = append(archive.Sessions.LoginAttempts, dto.LoginAttemptResponse{
+			ID:        attempt.ID,
+			IPAddress: attempt.IPAddress,
+			UserAgent: attempt.UserAgent,
+			Success:   attempt.Success,
+			CreatedAt: attempt.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, identity := range identities {
+		archive.Profile.Identities = append(archive.Profile.Identities, dto.ExportIdentity{
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	if pin != nil {
+		archive.Profile.PinnedPostID = &pin.PostID
+	}
+	for _, follow := range follows {
+		if follow.FollowerID == userID {
+			archive.Follows.Following = append(archive.Follows.Following, dto.ExportFollow{
+				UserID:    follow.FolloweeID,
+				Username:  follow.Username,
+				CreatedAt: follow.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		} else {
+			archive.Follows.Followers = append(archive.Follows.Followers, dto.ExportFollow{
+				UserID:    follow.FollowerID,
+				Username:  follow.Username,
+				CreatedAt: follow.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+	}
+	for _, revision := range postRevisions {
+		archive.Revisions.Posts = append(archive.Revisions.Posts, dto.ExportPostRevision{
+			ID:        revision.ID,
+			PostID:    revision.PostID,
+			Title:     revision.Title,
+			Content:   revision.Content,
+			CreatedAt: revision.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, revision := range commentRevisions {
+		archive.Revisions.Comments = append(archive.Revisions.Comments, dto.ExportCommentRevision{
+			ID:        revision.ID,
+			CommentID: revision.CommentID,
+			Content:   revision.Content,
+			CreatedAt: revision.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, folder := range folders {
+		archive.Bookmarks.Folders = append(archive.Bookmarks.Folders, dto.BookmarkFolderResponse{
+			ID:        folder.ID,
+			Name:      folder.Name,
+			CreatedAt: folder.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, bookmark := range bookmarks {
+		exported := dto.ExportBookmark{PostID: bookmark.PostID, CreatedAt: bookmark.CreatedAt.Format("2006-01-02 15:04:05")}
+		if bookmark.FolderID.Valid {
+			exported.FolderID = &bookmark.FolderID.Int64
+		}
+		archive.Bookmarks.Posts = append(archive.Bookmarks.Posts, exported)
+	}
+	for _, vote := range votes {
+		archive.PollVotes = append(archive.PollVotes, dto.ExportPollVote{
+			PostID:    vote.PostID,
+			Options:   vote.Options,
+			CreatedAt: vote.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, m := range media {
+		file := fmt.Sprintf("media/%d%s", m.ID, path.Ext(m.BlobKey))
+		files[file] = m.BlobKey
+		archive.Media = append(archive.Media, dto.ExportMedia{
+			ID:               m.ID,
+			File:             file,
+			ContentType:      m.ContentType,
+			SizeBytes:        m.SizeBytes,
+			AltText:          m.AltText,
+			ProcessingStatus: m.ProcessingStatus,
+			CreatedAt:        m.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, job := range importJobs {
+		archive.ImportJobs = append(archive.ImportJobs, dto.ImportJobResponse{
+			ID:             job.ID,
+			Source:         job.Source,
+			Status:         job.Status,
+			TotalItems:     job.TotalItems,
+			ProcessedItems: job.ProcessedItems,
+			ImportedItems:  job.ImportedItems,
+			SkippedItems:   job.SkippedItems,
+			FailedItems:    job.FailedItems,
+			Error:          job.Error.String,
+			CreatedAt:      job.CreatedAt.Format("2006-01-02 15:04:05"),
+			StartedAt:      formatNullTime(job.StartedAt),
+			CompletedAt:    formatNullTime(job.CompletedAt),
+		})
+	}
+	invitees := make(map[int64][]dto.InviteeResponse)
+	for _, redemption := range redemptions {
+		if redemption.UserID == userID {
+			archive.Invites.InvitedBy = &dto.ExportInvitedBy{
+				InviteCodeID: redemption.InviteCodeID,
+				UserID:       redemption.InviterID,
+				JoinedAt:     redemption.CreatedAt.Format("2006-01-02 15:04:05"),
+			}
+			continue
+		}
+		invitees[redemption.InviteCodeID] = append(invitees[redemption.InviteCodeID], dto.InviteeResponse{
+			UserID:   redemption.UserID,
+			Username: redemption.Username,
+			JoinedAt: redemption.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, invite := range invites {
+		archive.Invites.Codes = append(archive.Invites.Codes, dto.InviteResponse{
+			ID:         invite.ID,
+			CodePrefix: invite.CodePrefix,
+			MaxUses:    invite.MaxUses,
+			Uses:       invite.Uses,
+			ExpiresAt:  invite.ExpiresAt.Format("2006-01-02 15:04:05"),
+			RevokedAt:  formatNullTime(invite.RevokedAt),
+			CreatedAt:  invite.CreatedAt.Format("2006-01-02 15:04:05"),
+			Invitees:   append([]dto.InviteeResponse{}, invitees[invite.ID]...),
+		})
+	}
+	for _, client := range oauthClients {
+		archive.OAuth.Clients = append(archive.OAuth.Clients, dto.OAuthClientResponse{
+			ClientID:     client.ClientID,
+			Name:         client.Name,
+			RedirectURIs: client.RedirectURIs,
+			Scopes:       client.Scopes,
+			CreatedAt:    client.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, grant := range oauthGrants {
+		archive.OAuth.Grants = append(archive.OAuth.Grants, dto.ExportOAuthGrant{
+			ClientID:  grant.ClientID,
+			Scopes:    grant.Scopes,
+			CreatedAt: grant.CreatedAt.Format("2006-01-02 15:04:05"),
+			ExpiresAt: grant.ExpiresAt.Format("2006-01-02 15:04:05"),
+			RevokedAt: formatNullTime(grant.RevokedAt),
+		})
+	}
+	return archive, files, nil
+}
+
+// writeArchive writes the ZIP: data.json for machines, index.html for
+// people and the user's uploads, copied from the store by their path in the
+// archive. Uploads missing from the store are left out rather than failing
+// the export.
+func (s *exportService) writeArchive(ctx context.Context, w io.Writer, archive *dto.DataExportArchive, files map[string]string) error {
+	zw := zip.NewWriter(w)
+
+	for i := range archive.Media {
+		name := archive.Media[i].File
+		copied, err := s.copyBlob(ctx, zw, name, files[name])
+		if err != nil {
+			return err
+		}
+		if !copied {
+			archive.Media[i].File = ""
+		}
+	}
+
+	data, err := zw.Create("data.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(data)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return err
+	}
+
+	page, err := zw.Create("index.html")
+	if err != nil {
+		return err
+	}
+	if err := archiveTemplate.Execute(page, archive); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// copyBlob adds the blob to the ZIP as name, reporting false if the store
+// no longer has it.
+func (s *exportService) copyBlob(ctx context.Context, zw *zip.Writer, name, key string) (bool, error) {
+	blob, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer blob.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return false, err
+	}
+	if _, err := io.Copy(f, blob); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func formatNullTime(t sql.NullTime) *string {
+	if !t.Valid {
+		return nil
+	}
+	formatted := t.Time.Format("2006-01-02 15:04:05")
+	return &formatted
+}
+
+var archiveTemplate = template.Must(template.New("index.html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Your data – {{.Profile.Username}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>Your data</h1>
+<p>Generated {{.GeneratedAt}}. The same data is in data.json.</p>
+
+<h2>Profile</h2>
+<table>
+<tr><th>ID</th><td>{{.Profile.ID}}</td></tr>
+<tr><th>Username</th><td>{{.Profile.Username}}</td></tr>
+<tr><th>Email</th><td>{{.Profile.Email}}</td></tr>
+<tr><th>Roles</th><td>{{range $i, $r := .Profile.Roles}}{{if $i}}, {{end}}{{$r}}{{end}}</td></tr>
+<tr><th>Two-factor authentication</th><td>{{if .Profile.TwoFactorEnabled}}enabled{{else}}disabled{{end}}</td></tr>
+<tr><th>Pinned post</th><td>{{with .Profile.PinnedPostID}}{{.}}{{end}}</td></tr>
+<tr><th>Joined</th><td>{{.Profile.CreatedAt}}</td></tr>
+<tr><th>Invited by</th><td>{{with .Invites.InvitedBy}}user {{.UserID}}, joined {{.JoinedAt}}{{end}}</td></tr>
+</table>
+
+<h2>Linked accounts ({{len .Profile.Identities}})</h2>
+<table>
+<tr><th>Provider</th><th>Subject</th><th>Email</th><th>Linked</th></tr>
+{{range .Profile.Identities}}<tr><td>{{.Provider}}</td><td>{{.Subject}}</td><td>{{.Email}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Passkeys ({{len .Profile.Passkeys}})</h2>
+<table>
+<tr><th>Name</th><th>Created</th><th>Last used</th></tr>
+{{range .Profile.Passkeys}}<tr><td>{{.Name}}</td><td>{{.CreatedAt}}</td><td>{{with .LastUsedAt}}{{.}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Posts ({{len .Posts}})</h2>
+<table>
+<tr><th>ID</th><th>Title</th><th>Content</th><th>Created</th><th>Deleted</th></tr>
+{{range .Posts}}<tr><td>{{.ID}}</td><td>{{.Title}}</td><td>{{.Content}}</td><td>{{.CreatedAt}}</td><td>{{with .DeletedAt}}{{.}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Comments ({{len .Comments}})</h2>
+<table>
+<tr><th>ID</th><th>Post</th><th>Content</th><th>Created</th><th>Deleted</th></tr>
+{{range .Comments}}<tr><td>{{.ID}}</td><td>{{.PostID}}</td><td>{{.Content}}</td><td>{{.CreatedAt}}</td><td>{{with .DeletedAt}}{{.}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Post revisions ({{len .Revisions.Posts}})</h2>
+<table>
+<tr><th>Post</th><th>Title</th><th>Content</th><th>Written</th></tr>
+{{range .Revisions.Posts}}<tr><td>{{.PostID}}</td><td>{{.Title}}</td><td>{{.Content}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Comment revisions ({{len .Revisions.Comments}})</h2>
+<table>
+<tr><th>Comment</th><th>Content</th><th>Written</th></tr>
+{{range .Revisions.Comments}}<tr><td>{{.CommentID}}</td><td>{{.Content}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Media ({{len .Media}})</h2>
+<table>
+<tr><th>File</th><th>Type</th><th>Size</th><th>Alt text</th><th>Uploaded</th></tr>
+{{range .Media}}<tr><td>{{if .File}}<a href="{{.File}}">{{.File}}</a>{{else}}missing{{end}}</td><td>{{.ContentType}}</td><td>{{.SizeBytes}}</td><td>{{.AltText}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Poll votes ({{len .PollVotes}})</h2>
+<table>
+<tr><th>Post</th><th>Options</th><th>Voted</th></tr>
+{{range .PollVotes}}<tr><td>{{.PostID}}</td><td>{{range $i, $o := .Options}}{{if $i}}, {{end}}{{$o}}{{end}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Liked posts ({{len .Likes.Posts}})</h2>
+<table>
+<tr><th>Post</th><th>Liked</th></tr>
+{{range .Likes.Posts}}<tr><td>{{.ID}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Liked comments ({{len .Likes.Comments}})</h2>
+<table>
+<tr><th>Comment</th><th>Liked</th></tr>
+{{range .Likes.Comments}}<tr><td>{{.ID}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Following ({{len .Follows.Following}})</h2>
+<table>
+<tr><th>User</th><th>Since</th></tr>
+{{range .Follows.Following}}<tr><td>{{.Username}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Followers ({{len .Follows.Followers}})</h2>
+<table>
+<tr><th>User</th><th>Since</th></tr>
+{{range .Follows.Followers}}<tr><td>{{.Username}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Bookmark folders ({{len .Bookmarks.Folders}})</h2>
+<table>
+<tr><th>ID</th><th>Name</th><th>Created</th></tr>
+{{range .Bookmarks.Folders}}<tr><td>{{.ID}}</td><td>{{.Name}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Bookmarks ({{len .Bookmarks.Posts}})</h2>
+<table>
+<tr><th>Post</th><th>Folder</th><th>Saved</th></tr>
+{{range .Bookmarks.Posts}}<tr><td>{{.PostID}}</td><td>{{with .FolderID}}{{.}}{{end}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>Invite codes ({{len .Invites.Codes}})</h2>
+<table>
+<tr><th>Code</th><th>Uses</th><th>Created</th><th>Expires</th><th>Revoked</th><th>Invited</th></tr>
+{{range .Invites.Codes}}<tr><td>{{.CodePrefix}}…</td><td>{{.Uses}} of {{.MaxUses}}</td><td>{{.CreatedAt}}</td><td>{{.ExpiresAt}}</td><td>{{with .RevokedAt}}{{.}}{{end}}</td><td>{{range $i, $u := .Invitees}}{{if $i}}, {{end}}{{$u.Username}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Imports ({{len .ImportJobs}})</h2>
+<table>
+<tr><th>Source</th><th>Status</th><th>Imported</th><th>Skipped</th><th>Failed</th><th>Requested</th><th>Completed</th></tr>
+{{range .ImportJobs}}<tr><td>{{.Source}}</td><td>{{.Status}}</td><td>{{.ImportedItems}}</td><td>{{.SkippedItems}}</td><td>{{.FailedItems}}</td><td>{{.CreatedAt}}</td><td>{{with .CompletedAt}}{{.}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>OAuth applications you registered ({{len .OAuth.Clients}})</h2>
+<table>
+<tr><th>Name</th><th>Client ID</th><th>Redirect URIs</th><th>Scopes</th><th>Created</th></tr>
+{{range .OAuth.Clients}}<tr><td>{{.Name}}</td><td>{{.ClientID}}</td><td>{{range $i, $u := .RedirectURIs}}{{if $i}}, {{end}}{{$u}}{{end}}</td><td>{{range $i, $s := .Scopes}}{{if $i}}, {{end}}{{$s}}{{end}}</td><td>{{.CreatedAt}}</td></tr>
+{{end}}</table>
+
+<h2>OAuth access you granted ({{len .OAuth.Grants}})</h2>
+<table>
+<tr><th>Client ID</th><th>Scopes</th><th>Granted</th><th>Expires</th><th>Revoked</th></tr>
+{{range .OAuth.Grants}}<tr><td>{{.ClientID}}</td><td>{{range $i, $s := .Scopes}}{{if $i}}, {{end}}{{$s}}{{end}}</td><td>{{.CreatedAt}}</td><td>{{.ExpiresAt}}</td><td>{{with .RevokedAt}}{{.}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Sessions ({{len .Sessions.RefreshTokens}})</h2>
+<table>
+<tr><th>Signed in</th><th>Expires</th></tr>
+{{range .Sessions.RefreshTokens}}<tr><td>{{.CreatedAt}}</td><td>{{.ExpiresAt}}</td></tr>
+{{end}}</table>
+
+<h2>Personal access tokens ({{len .Sessions.PersonalAccessTokens}})</h2>
+<table>
+<tr><th>Name</th><th>Prefix</th><th>Scopes</th><th>Created</th><th>Expires</th><th>Last used</th></tr>
+{{range .Sessions.PersonalAccessTokens}}<tr><td>{{.Name}}</td><td>{{.TokenPrefix}}</td><td>{{range $i, $s := .Scopes}}{{if $i}}, {{end}}{{$s}}{{end}}</td><td>{{.CreatedAt}}</td><td>{{.ExpiresAt}}</td><td>{{with .LastUsedAt}}{{.}}{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Login history ({{len .Sessions.LoginAttempts}})</h2>
+<table>
+<tr><th>Time</th><th>IP address</th><th>Browser</th><th>Result</th></tr>
+{{range .Sessions.LoginAttempts}}<tr><td>{{.CreatedAt}}</td><td>{{.IPAddress}}</td><td>{{.UserAgent}}</td><td>{{if .Success}}success{{else}}failed{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"time"
+)
+
+// RequestDataExport queues an archive of everything stored about the user.
+// Only one export can be in progress at a time.
+func (s *exportService) RequestDataExport(ctx context.Context, userID int64) (*dto.DataExportResponse, int, error) {
+	active, err := s.exportRepo.GetActiveDataExport(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if active != nil {
+		return nil, http.StatusConflict, errors.New("a data export is already in progress")
+	}
+
+	export := &model.DataExportModel{
+		UserID:    userID,
+		Status:    model.DataExportPending,
+		CreatedAt: time.Now(),
+	}
+	export.ID, err = s.exportRepo.CreateDataExport(ctx, export)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return s.toDataExportResponse(export, export.CreatedAt), http.StatusAccepted, nil
+}
+
+func (s *exportService) GetDataExport(ctx context.Context, userID, exportID int64) (*dto.DataExportResponse, int, error) {
+	export, err := s.exportRepo.GetDataExportByID(ctx, exportID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if export == nil || export.UserID != userID {
+		return nil, http.StatusNotFound, errors.New("data export not found")
+	}
+
+	return s.toDataExportResponse(export, time.Now()), http.StatusOK, nil
+}
+
+func (s *exportService) toDataExportResponse(export *model.DataExportModel, now time.Time) *dto.DataExportResponse {
+	response := &dto.DataExportResponse{
+		ID:        export.ID,
+		Status:    export.Status,
+		SizeBytes: export.SizeBytes,
+		CreatedAt: export.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if export.CompletedAt.Valid {
+		completedAt := export.CompletedAt.Time.Format("2006-01-02 15:04:05")
+		response.CompletedAt = &completedAt
+	}
+	if export.ExpiresAt.Valid {
+		expiresAt := export.ExpiresAt.Time.Format("2006-01-02 15:04:05")
+		response.ExpiresAt = &expiresAt
+	}
+
+	// the expiry job may not have deleted an expired archive yet
+	if export.Status == model.DataExportReady && now.Before(export.ExpiresAt.Time) {
+		linkTTL, _, _ := s.cfg.DataExport()
+		linkExpiresAt := now.Add(linkTTL)
+		if linkExpiresAt.After(export.ExpiresAt.Time) {
+			linkExpiresAt = export.ExpiresAt.Time
+		}
+		response.DownloadURL = s.downloadLink(export.ID, linkExpiresAt)
+		response.DownloadURLExpiresAt = linkExpiresAt.Format("2006-01-02 15:04:05")
+	} else if export.Status == model.DataExportReady {
+		response.Status = model.DataExportExpired
+	}
+	return response
+}
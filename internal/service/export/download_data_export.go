@@ -0,0 +1,35 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/model"
+	"io"
+	"net/http"
+	"time"
+)
+
+var errInvalidDownloadLink = errors.New("download link is invalid or has expired")
+
+// OpenDataExport checks a signed download link and opens the archive it
+// points to, returning its size too. The caller must close the reader.
+func (s *exportService) OpenDataExport(ctx context.Context, exportID, expires int64, signature string) (io.ReadCloser, int64, int, error) {
+	now := time.Now()
+	if !s.validSignature(exportID, expires, signature) || !now.Before(time.Unix(expires, 0)) {
+		return nil, 0, http.StatusForbidden, errInvalidDownloadLink
+	}
+
+	export, err := s.exportRepo.GetDataExportByID(ctx, exportID)
+	if err != nil {
+		return nil, 0, http.StatusInternalServerError, err
+	}
+	if export == nil || export.Status != model.DataExportReady || !now.Before(export.ExpiresAt.Time) {
+		return nil, 0, http.StatusGone, errors.New("data export is no longer available")
+	}
+
+	archive, err := s.store.Get(ctx, export.BlobKey.String)
+	if err != nil {
+		return nil, 0, http.StatusInternalServerError, err
+	}
+	return archive, export.SizeBytes, http.StatusOK, nil
+}
@@ -0,0 +1,496 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/internal/repository/user"
+	"go-twitter/pkg/blobstore"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryExportRepository keeps exports in memory and returns fixed content
+// for the archive queries.
+type memoryExportRepository struct {
+	exports          map[int64]*model.DataExportModel
+	nextID           int64
+	posts            []*model.PostModel
+	comments         []*model.CommentModel
+	postLikes        []*model.PostLikeModel
+	commentLikes     []*model.CommentLikeModel
+	refreshTokens    []*model.RefreshTokenModel
+	loginAttempts    []*model.LoginAttemptModel
+	identities       []*model.UserIdentityModel
+	redemptions      []*model.InviteRedemptionModel
+	oauthClients     []*model.OAuthClientModel
+	oauthGrants      []*model.OAuthRefreshTokenModel
+	bookmarkFolders  []*model.BookmarkFolderModel
+	bookmarks        []*model.BookmarkModel
+	pollVotes        []*model.PollVoteModel
+	media            []*model.MediaModel
+	importJobs       []*model.ImportJobModel
+	postRevisions    []*model.PostRevisionModel
+	commentRevisions []*model.CommentRevisionModel
+	pin              *model.PinnedPostModel
+	follows          []*model.FollowModel
+}
+
+func newMemoryExportRepository() *memoryExportRepository {
+	return &memoryExportRepository{exports: make(map[int64]*model.DataExportModel)}
+}
+
+func (r *memoryExportRepository) CreateDataExport(ctx context.Context, export *model.DataExportModel) (int64, error) {
+	r.nextID++
+	stored := *export
+	stored.ID = r.nextID
+	r.exports[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (r *memoryExportRepository) GetDataExportByID(ctx context.Context, id int64) (*model.DataExportModel, error) {
+	if export, ok := r.exports[id]; ok {
+		copied := *export
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryExportRepository) GetActiveDataExport(ctx context.Context, userID int64) (*model.DataExportModel, error) {
+	for _, export := range r.exports {
+		if export.UserID == userID && (export.Status == model.DataExportPending || export.Status == model.DataExportProcessing) {
+			return export, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryExportRepository) ClaimDataExport(ctx context.Context, now, staleBefore time.Time) (*model.DataExportModel, error) {
+	for id := int64(1); id <= r.nextID; id++ {
+		export, ok := r.exports[id]
+		if ok && export.Status == model.DataExportPending {
+			export.Status = model.DataExportProcessing
+			export.StartedAt = sql.NullTime{Time: now, Valid: true}
+			return r.GetDataExportByID(ctx, id)
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryExportRepository) CompleteDataExport(ctx context.Context, id int64, blobKey string, sizeBytes int64, completedAt, expiresAt time.Time) error {
+	export := r.exports[id]
+	export.Status = model.DataExportReady
+	export.BlobKey = sql.NullString{String: blobKey, Valid: true}
+	export.SizeBytes = sizeBytes
+	export.CompletedAt = sql.NullTime{Time: completedAt, Valid: true}
+	export.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+	return nil
+}
+
+func (r *memoryExportRepository) FailDataExport(ctx context.Context, id int64, now time.Time) error {
+	r.exports[id].Status = model.DataExportFailed
+	return nil
+}
+
+func (r *memoryExportRepository) GetExpiredDataExports(ctx context.Context, now time.Time, limit int) ([]*model.DataExportModel, error) {
+	var expired []*model.DataExportModel
+	for _, export := range r.exports {
+		if export.Status == model.DataExportReady && !now.Before(export.ExpiresAt.Time) {
+			expired = append(expired, export)
+		}
+	}
+	return expired, nil
+}
+
+func (r *memoryExportRepository) ExpireDataExport(ctx context.Context, id int64) error {
+	r.exports[id].Status = model.DataExportExpired
+	r.exports[id].BlobKey = sql.NullString{}
+	return nil
+}
+
+func (r *memoryExportRepository) GetPostsByUserID(ctx context.Context, userID int64) ([]*model.PostModel, error) {
+	return r.posts, nil
+}
+
+func (r *memoryExportRepository) GetCommentsByUserID(ctx context.Context, userID int64) ([]*model.CommentModel, error) {
+	return r.comments, nil
+}
+
+func (r *memoryExportRepository) GetPostLikesByUserID(ctx context.Context, userID int64) ([]*model.PostLikeModel, error) {
+	return r.postLikes, nil
+}
+
+func (r *memoryExportRepository) GetCommentLikesByUserID(ctx context.Context, userID int64) ([]*model.CommentLikeModel, error) {
+	return r.commentLikes, nil
+}
+
+func (r *memoryExportRepository) GetRefreshTokensByUserID(ctx context.Context, userID int64) ([]*model.RefreshTokenModel, error) {
+	return r.refreshTokens, nil
+}
+
+func (r *memoryExportRepository) GetLoginAttemptsByUserID(ctx context.Context, userID int64) ([]*model.LoginAttemptModel, error) {
+	return r.loginAttempts, nil
+}
+
+func (r *memoryExportRepository) GetUserIdentitiesByUserID(ctx context.Context, userID int64) ([]*model.UserIdentityModel, error) {
+	return r.identities, nil
+}
+
+func (r *memoryExportRepository) GetInviteRedemptionsByUserID(ctx context.Context, userID int64) ([]*model.InviteRedemptionModel, error) {
+	return r.redemptions, nil
+}
+
+func (r *memoryExportRepository) GetOAuthClientsByOwner(ctx context.Context, userID int64) ([]*model.OAuthClientModel, error) {
+	return r.oauthClients, nil
+}
+
+func (r *memoryExportRepository) GetOAuthGrantsByUserID(ctx context.Context, userID int64) ([]*model.OAuthRefreshTokenModel, error) {
+	return r.oauthGrants, nil
+}
+
+func (r *memoryExportRepository) GetBookmarkFoldersByUserID(ctx context.Context, userID int64) ([]*model.BookmarkFolderModel, error) {
+	return r.bookmarkFolders, nil
+}
+
+func (r *memoryExportRepository) GetBookmarksByUserID(ctx context.Context, userID int64) ([]*model.BookmarkModel, error) {
+	return r.bookmarks, nil
+}
+
+func (r *memoryExportRepository) GetPollVotesByUserID(ctx context.Context, userID int64) ([]*model.PollVoteModel, error) {
+	return r.pollVotes, nil
+}
+
+func (r *memoryExportRepository) GetMediaByUserID(ctx context.Context, userID int64) ([]*model.MediaModel, error) {
+	return r.media, nil
+}
+
+func (r *memoryExportRepository) GetImportJobsByUserID(ctx context.Context, userID int64) ([]*model.ImportJobModel, error) {
+	return r.importJobs, nil
+}
+
+func (r *memoryExportRepository) GetPostRevisionsByUserID(ctx context.Context, userID int64) ([]*model.PostRevisionModel, error) {
+	return r.postRevisions, nil
+}
+
+func (r *memoryExportRepository) GetCommentRevisionsByUserID(ctx context.Context, userID int64) ([]*model.CommentRevisionModel, error) {
+	return r.commentRevisions, nil
+}
+
+func (r *memoryExportRepository) GetFollowsByUserID(ctx context.Context, userID int64) ([]*model.FollowModel, error) {
+	return r.follows, nil
+}
+
+func (r *memoryExportRepository) GetPinnedPost(ctx context.Context, userID int64) (*model.PinnedPostModel, error) {
+	return r.pin, nil
+}
+
+// fakeUserRepository implements the user queries the archive needs; the
+// embedded interface panics on anything else.
+type fakeUserRepository struct {
+	user.UserRepository
+	users       map[int64]*model.UserModel
+	inviteCodes []*model.InviteCodeModel
+}
+
+func (r *fakeUserRepository) GetUserByID(ctx context.Context, id int64) (*model.UserModel, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeUserRepository) GetUserRoles(ctx context.Context, userID int64) ([]string, error) {
+	return []string{model.RoleModerator}, nil
+}
+
+func (r *fakeUserRepository) GetUserTOTP(ctx context.Context, userID int64) (*model.UserTOTPModel, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetWebAuthnCredentialsByUserID(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetPersonalAccessTokensByUserID(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetInviteCodesByCreator(ctx context.Context, userID int64) ([]*model.InviteCodeModel, error) {
+	return r.inviteCodes, nil
+}
+
+func newTestService() (*exportService, *memoryExportRepository, *blobstore.MemoryStore) {
+	exportRepo := newMemoryExportRepository()
+	exportRepo.posts = []*model.PostModel{
+		{ID: 7, UserID: 1, Title: "<script>hello</script>", Content: "first post", CreatedAt: time.Now()},
+	}
+	userRepo := &fakeUserRepository{users: map[int64]*model.UserModel{
+		1: {ID: 1, Username: "alice", Email: "alice@example.com", Password: "hash"},
+	}}
+	store := blobstore.NewMemoryStore()
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, exportRepo, userRepo, store).(*exportService)
+	return service, exportRepo, store
+}
+
+// signedLinkParams splits a download link into the values the download
+// endpoint reads.
+func signedLinkParams(t *testing.T, link string) (int64, int64, string) {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Invalid download link %q: %v", link, err)
+	}
+	id, _ := strconv.ParseInt(strings.Split(u.Path, "/")[2], 10, 64)
+	expires, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+	return id, expires, u.Query().Get("signature")
+}
+
+// unzip returns the content of each file in the archive by name.
+func unzip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Expected a ZIP archive, got %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		r, _ := f.Open()
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	return files
+}
+
+func TestDataExport_BuildAndDownload(t *testing.T) {
+	service, _, _ := newTestService()
+	ctx := context.Background()
+
+	requested, status, err := service.RequestDataExport(ctx, 1)
+	if err != nil || status != http.StatusAccepted || requested.Status != model.DataExportPending {
+		t.Fatalf("Expected pending export, got %+v, %d (%v)", requested, status, err)
+	}
+	if _, status, _ := service.RequestDataExport(ctx, 1); status != http.StatusConflict {
+		t.Errorf("Expected a second export to get %d while one is pending, got %d", http.StatusConflict, status)
+	}
+
+	built, err := service.ProcessDataExports(ctx)
+	if err != nil || built != 1 {
+		t.Fatalf("Expected one export built, got %d (%v)", built, err)
+	}
+
+	if _, status, _ := service.GetDataExport(ctx, 2, requested.ID); status != http.StatusNotFound {
+		t.Errorf("Expected another user's export to be hidden, got %d", status)
+	}
+	ready, status, err := service.GetDataExport(ctx, 1, requested.ID)
+	if err != nil || status != http.StatusOK || ready.Status != model.DataExportReady || ready.DownloadURL == "" {
+		t.Fatalf("Expected ready export with download link, got %+v, %d (%v)", ready, status, err)
+	}
+
+	id, expires, signature := signedLinkParams(t, ready.DownloadURL)
+	archive, size, status, err := service.OpenDataExport(ctx, id, expires, signature)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected archive to open, got %d (%v)", status, err)
+	}
+	data, _ := io.ReadAll(archive)
+	archive.Close()
+	if int64(len(data)) != size {
+		t.Errorf("Expected %d bytes, got %d", size, len(data))
+	}
+
+	files := unzip(t, data)
+	var contents dto.DataExportArchive
+	if err := json.Unmarshal(files["data.json"], &contents); err != nil {
+		t.Fatalf("Expected data.json, got %v", err)
+	}
+	if contents.Profile.Email != "alice@example.com" || len(contents.Posts) != 1 || contents.Posts[0].Content != "first post" {
+		t.Errorf("Unexpected archive contents: %+v", contents)
+	}
+	if strings.Contains(string(files["data.json"]), `"hash"`) {
+		t.Error("Expected the password hash to be left out")
+	}
+	if page := string(files["index.html"]); !strings.Contains(page, "first post") || strings.Contains(page, "<script>") {
+		t.Errorf("Expected escaped HTML summary, got %q", page)
+	}
+}
+
+func TestOpenDataExport_RejectsBadLinks(t *testing.T) {
+	service, exportRepo, store := newTestService()
+	ctx := context.Background()
+
+	requested, _, _ := service.RequestDataExport(ctx, 1)
+	if _, err := service.ProcessDataExports(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ready, _, _ := service.GetDataExport(ctx, 1, requested.ID)
+	id, expires, signature := signedLinkParams(t, ready.DownloadURL)
+
+	tests := []struct {
+		name      string
+		id        int64
+		expires   int64
+		signature string
+	}{
+		{"tampered signature", id, expires, strings.Repeat("0", len(signature))},
+		{"extended expiry", id, expires + 3600, signature},
+		{"other export", id + 1, expires, signature},
+		{"expired link", id, time.Now().Add(-time.Minute).Unix(), service.sign(id, time.Now().Add(-time.Minute).Unix())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, status, _ := service.OpenDataExport(ctx, tt.id, tt.expires, tt.signature); status != http.StatusForbidden {
+				t.Errorf("Expected status %d, got %d", http.StatusForbidden, status)
+			}
+		})
+	}
+
+	// once the archive expires it is deleted and links stop working
+	exportRepo.exports[id].ExpiresAt.Time = time.Now().Add(-time.Second)
+	blobKey := exportRepo.exports[id].BlobKey.String
+	if _, err := service.ProcessDataExports(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, _, status, _ := service.OpenDataExport(ctx, id, expires, signature); status != http.StatusGone {
+		t.Errorf("Expected status %d for an expired archive, got %d", http.StatusGone, status)
+	}
+	if _, err := store.Get(ctx, blobKey); err != blobstore.ErrNotFound {
+		t.Errorf("Expected expired archive to be deleted, got %v", err)
+	}
+}
+
+func TestProcessDataExports_MarksFailures(t *testing.T) {
+	service, exportRepo, _ := newTestService()
+	ctx := context.Background()
+
+	failing, _, _ := service.RequestDataExport(ctx, 99) // no such user
+	working, _, _ := service.RequestDataExport(ctx, 1)
+
+	built, err := service.ProcessDataExports(ctx)
+	if err == nil || built != 1 {
+		t.Errorf("Expected one export built and an error, got %d (%v)", built, err)
+	}
+	if exportRepo.exports[failing.ID].Status != model.DataExportFailed || exportRepo.exports[working.ID].Status != model.DataExportReady {
+		t.Errorf("Unexpected states: %q and %q", exportRepo.exports[failing.ID].Status, exportRepo.exports[working.ID].Status)
+	}
+}
+
+func TestDataExport_CoversEveryTable(t *testing.T) {
+	service, exportRepo, store := newTestService()
+	ctx := context.Background()
+	now := time.Now()
+
+	exportRepo.comments = []*model.CommentModel{{ID: 8, PostID: 7, UserID: 1, Content: "a comment", CreatedAt: now}}
+	exportRepo.postLikes = []*model.PostLikeModel{{ID: 1, PostID: 7, UserID: 1, CreatedAt: now}}
+	exportRepo.commentLikes = []*model.CommentLikeModel{{ID: 1, CommentID: 8, UserID: 1, CreatedAt: now}}
+	exportRepo.refreshTokens = []*model.RefreshTokenModel{{ID: 1, UserID: 1, CreatedAt: now, ExpiresAt: now}}
+	exportRepo.loginAttempts = []*model.LoginAttemptModel{{ID: 1, IPAddress: "192.0.2.1", Success: true, CreatedAt: now}}
+	exportRepo.identities = []*model.UserIdentityModel{{ID: 1, UserID: 1, Provider: "google", Subject: "g-123", CreatedAt: now}}
+	exportRepo.pin = &model.PinnedPostModel{UserID: 1, PostID: 7, PinnedAt: now}
+	exportRepo.follows = []*model.FollowModel{
+		{FollowerID: 1, FolloweeID: 2, Username: "bob", CreatedAt: now},
+		{FollowerID: 3, FolloweeID: 1, Username: "carol", CreatedAt: now},
+	}
+	exportRepo.postRevisions = []*model.PostRevisionModel{{ID: 1, PostID: 7, Title: "old title", Content: "old post", CreatedAt: now}}
+	exportRepo.commentRevisions = []*model.CommentRevisionModel{{ID: 1, CommentID: 8, Content: "old comment", CreatedAt: now}}
+	exportRepo.bookmarkFolders = []*model.BookmarkFolderModel{{ID: 4, UserID: 1, Name: "recipes", CreatedAt: now}}
+	exportRepo.bookmarks = []*model.BookmarkModel{{ID: 1, UserID: 1, PostID: 9, FolderID: sql.NullInt64{Int64: 4, Valid: true}, CreatedAt: now}}
+	exportRepo.pollVotes = []*model.PollVoteModel{{PostID: 9, UserID: 1, Options: []string{"yes", "maybe"}, CreatedAt: now}}
+	exportRepo.media = []*model.MediaModel{
+		{ID: 5, BlobKey: "media/1/abc.png", ContentType: "image/png", AltText: "a cat", CreatedAt: now},
+		{ID: 6, BlobKey: "media/1/gone.png", ContentType: "image/png", CreatedAt: now},
+	}
+	exportRepo.importJobs = []*model.ImportJobModel{{ID: 1, UserID: 1, Source: model.ImportSourceTwitter, Status: model.ImportCompleted, ImportedItems: 3, CreatedAt: now}}
+	exportRepo.redemptions = []*model.InviteRedemptionModel{
+		{ID: 1, InviteCodeID: 2, InviterID: 1, UserID: 3, Username: "carol", CreatedAt: now},
+		{ID: 2, InviteCodeID: 1, InviterID: 2, UserID: 1, Username: "alice", CreatedAt: now},
+	}
+	exportRepo.oauthClients = []*model.OAuthClientModel{{ID: 1, ClientID: "client-1", ClientSecretHash: "client-secret-hash", Name: "My app", OwnerUserID: 1, CreatedAt: now}}
+	exportRepo.oauthGrants = []*model.OAuthRefreshTokenModel{{ID: 1, TokenHash: "grant-token-hash", ClientID: "client-1", UserID: 1, Scopes: []string{"read"}, CreatedAt: now, ExpiresAt: now}}
+	service.userRepo.(*fakeUserRepository).inviteCodes = []*model.InviteCodeModel{{ID: 2, CreatedBy: 1, CodeHash: "invite-code-hash", CodePrefix: "ab12", MaxUses: 1, Uses: 1, CreatedAt: now, ExpiresAt: now}}
+	if err := store.Put(ctx, "media/1/abc.png", strings.NewReader("png bytes")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	requested, _, _ := service.RequestDataExport(ctx, 1)
+	if _, err := service.ProcessDataExports(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	blob, err := store.Get(ctx, exportRepo.exports[requested.ID].BlobKey.String)
+	if err != nil {
+		t.Fatalf("Expected the archive to be stored, got %v", err)
+	}
+	data, _ := io.ReadAll(blob)
+	blob.Close()
+	files := unzip(t, data)
+
+	var contents dto.DataExportArchive
+	if err := json.Unmarshal(files["data.json"], &contents); err != nil {
+		t.Fatalf("Expected data.json, got %v", err)
+	}
+	sections := map[string]int{
+		"comments":          len(contents.Comments),
+		"liked posts":       len(contents.Likes.Posts),
+		"liked comments":    len(contents.Likes.Comments),
+		"sessions":          len(contents.Sessions.RefreshTokens),
+		"login attempts":    len(contents.Sessions.LoginAttempts),
+		"identities":        len(contents.Profile.Identities),
+		"following":         len(contents.Follows.Following),
+		"followers":         len(contents.Follows.Followers),
+		"post revisions":    len(contents.Revisions.Posts),
+		"comment revisions": len(contents.Revisions.Comments),
+		"bookmark folders":  len(contents.Bookmarks.Folders),
+		"bookmarks":         len(contents.Bookmarks.Posts),
+		"poll votes":        len(contents.PollVotes),
+		"import jobs":       len(contents.ImportJobs),
+		"invite codes":      len(contents.Invites.Codes),
+		"invitees":          len(contents.Invites.Codes[0].Invitees),
+		"oauth clients":     len(contents.OAuth.Clients),
+		"oauth grants":      len(contents.OAuth.Grants),
+	}
+	for section, n := range sections {
+		if n != 1 {
+			t.Errorf("Expected 1 entry in %s, got %d", section, n)
+		}
+	}
+	if contents.Profile.PinnedPostID == nil || *contents.Profile.PinnedPostID != 7 {
+		t.Errorf("Expected pinned post 7, got %v", contents.Profile.PinnedPostID)
+	}
+	if contents.Invites.InvitedBy == nil || contents.Invites.InvitedBy.UserID != 2 {
+		t.Errorf("Expected to be invited by user 2, got %+v", contents.Invites.InvitedBy)
+	}
+	if vote := contents.PollVotes[0]; len(vote.Options) != 2 || vote.Options[1] != "maybe" {
+		t.Errorf("Expected both picked options, got %+v", vote)
+	}
+
+	// the upload still in the store is copied, the missing one is listed
+	// without a file
+	if len(contents.Media) != 2 || contents.Media[0].File != "media/5.png" || contents.Media[0].AltText != "a cat" || contents.Media[1].File != "" {
+		t.Fatalf("Unexpected media: %+v", contents.Media)
+	}
+	if string(files["media/5.png"]) != "png bytes" {
+		t.Errorf("Expected the upload in the archive, got %q", files["media/5.png"])
+	}
+
+	for _, secret := range []string{"client-secret-hash", "grant-token-hash", "invite-code-hash"} {
+		if strings.Contains(string(files["data.json"]), secret) || strings.Contains(string(files["index.html"]), secret) {
+			t.Errorf("Expected %s to be left out", secret)
+		}
+	}
+
+	page := string(files["index.html"])
+	for _, heading := range []string{
+		"Linked accounts (1)", "Post revisions (1)", "Comment revisions (1)", "Media (2)", "Poll votes (1)",
+		"Following (1)", "Followers (1)", "Bookmark folders (1)", "Bookmarks (1)", "Invite codes (1)",
+		"Imports (1)", "OAuth applications you registered (1)", "OAuth access you granted (1)",
+	} {
+		if !strings.Contains(page, "<h2>"+heading+"</h2>") {
+			t.Errorf("Expected section %q in index.html", heading)
+		}
+	}
+}
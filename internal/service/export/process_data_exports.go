@@ -0,0 +1,92 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go-twitter/internal/model"
+	"time"
+)
+
+const (
+	// dataExportStaleAfter is how long an export may stay processing before
+	// it is assumed abandoned and built again.
+	dataExportStaleAfter = time.Hour
+	expiredExportBatch   = 100
+)
+
+// ProcessDataExports builds every queued export, then deletes archives past
+// their retention. It returns how many exports were built. An export that
+// cannot be built is marked failed and does not stop the others.
+func (s *exportService) ProcessDataExports(ctx context.Context) (int, error) {
+	built := 0
+	var errs []error
+	for {
+		now := time.Now()
+		export, err := s.exportRepo.ClaimDataExport(ctx, now, now.Add(-dataExportStaleAfter))
+		if err != nil {
+			return built, errors.Join(append(errs, err)...)
+		}
+		if export == nil {
+			break
+		}
+
+		if err := s.buildDataExport(ctx, export); err != nil {
+			errs = append(errs, fmt.Errorf("data export %d: %w", export.ID, err))
+			if err := s.exportRepo.FailDataExport(ctx, export.ID, time.Now()); err != nil {
+				return built, errors.Join(append(errs, err)...)
+			}
+			continue
+		}
+		built++
+	}
+
+	if err := s.deleteExpiredDataExports(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return built, errors.Join(errs...)
+}
+
+func (s *exportService) buildDataExport(ctx context.Context, export *model.DataExportModel) error {
+	now := time.Now()
+	archive, files, err := s.collectArchive(ctx, export.UserID, now)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := s.writeArchive(ctx, &buf, archive, files); err != nil {
+		return err
+	}
+	size := int64(buf.Len())
+
+	key := fmt.Sprintf("data-exports/%d/%d.zip", export.UserID, export.ID)
+	if err := s.store.Put(ctx, key, &buf); err != nil {
+		return err
+	}
+
+	_, retention, _ := s.cfg.DataExport()
+	completedAt := time.Now()
+	return s.exportRepo.CompleteDataExport(ctx, export.ID, key, size, completedAt, completedAt.Add(retention))
+}
+
+func (s *exportService) deleteExpiredDataExports(ctx context.Context) error {
+	for {
+		exports, err := s.exportRepo.GetExpiredDataExports(ctx, time.Now(), expiredExportBatch)
+		if err != nil {
+			return err
+		}
+		for _, export := range exports {
+			if err := s.store.Delete(ctx, export.BlobKey.String); err != nil {
+				return err
+			}
+			if err := s.exportRepo.ExpireDataExport(ctx, export.ID); err != nil {
+				return err
+			}
+		}
+		if len(exports) < expiredExportBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package export
+
+import (
+	"context"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/repository/export"
+	"go-twitter/internal/repository/user"
+	"go-twitter/pkg/blobstore"
+	"io"
+)
+
+type ExportService interface {
+	RequestDataExport(ctx context.Context, userID int64) (*dto.DataExportResponse, int, error)
+	GetDataExport(ctx context.Context, userID, exportID int64) (*dto.DataExportResponse, int, error)
+	OpenDataExport(ctx context.Context, exportID, expires int64, signature string) (io.ReadCloser, int64, int, error)
+	ProcessDataExports(ctx context.Context) (int, error)
+}
+
+type exportService struct {
+	cfg        *config.Config
+	exportRepo export.ExportRepository
+	userRepo   user.UserRepository
+	store      blobstore.Store
+}
+
+func NewService(cfg *config.Config, exportRepo export.ExportRepository, userRepo user.UserRepository, store blobstore.Store) ExportService {
+	return &exportService{
+		cfg:        cfg,
+		exportRepo: exportRepo,
+		userRepo:   userRepo,
+		store:      store,
+	}
+}
@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"go-twitter/pkg/twitterarchive"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTitleLength matches the posts.title column and the post DTO.
+const maxTitleLength = 100
+
+// convertTweet maps a tweet to a post title and content. Shortened t.co
+// links are expanded. Mentions of usernames that exist here stay @mentions;
+// the rest become links to the X profile, since the same name here may be
+// someone else. Hashtags are plain text in posts and are kept as written.
+func convertTweet(tweet twitterarchive.Tweet, isLocalUser func(username string) (bool, error)) (string, string, error) {
+	content := tweet.Text
+	for _, u := range tweet.URLs {
+		if u.Short != "" && u.Expanded != "" {
+			content = strings.ReplaceAll(content, u.Short, u.Expanded)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range tweet.Mentions {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		local, err := isLocalUser(name)
+		if err != nil {
+			return "", "", err
+		}
+		if local {
+			continue
+		}
+		mention := regexp.MustCompile(`(?i)@` + regexp.QuoteMeta(name) + `\b`)
+		content = mention.ReplaceAllLiteralString(content, "https://x.com/"+name)
+	}
+	content = strings.TrimSpace(content)
+
+	// the first line stands in for the title tweets don't have
+	title, _, _ := strings.Cut(content, "\n")
+	title = truncate(strings.Join(strings.Fields(title), " "), maxTitleLength)
+	return title, content, nil
+}
+
+// truncate shortens s to at most n characters, marking the cut with an
+// ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
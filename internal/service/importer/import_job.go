@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+// StartTwitterImport stores the uploaded archive and queues it for the
+// import job. Only one import can be in progress at a time.
+func (s *importService) StartTwitterImport(ctx context.Context, userID int64, archive io.Reader, size int64) (*dto.ImportJobResponse, int, error) {
+	maxSize, _ := s.cfg.Import()
+	if size > maxSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("archive is larger than %d MB", maxSize>>20)
+	}
+
+	active, err := s.importRepo.GetActiveImportJob(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if active != nil {
+		return nil, http.StatusConflict, errors.New("an import is already in progress")
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	key := fmt.Sprintf("imports/%d/%s.zip", userID, hex.EncodeToString(suffix))
+	if err := s.store.Put(ctx, key, io.LimitReader(archive, maxSize)); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	job := &model.ImportJobModel{
+		UserID:    userID,
+		Source:    model.ImportSourceTwitter,
+		Status:    model.ImportPending,
+		BlobKey:   sql.NullString{String: key, Valid: true},
+		CreatedAt: time.Now(),
+	}
+	job.ID, err = s.importRepo.CreateImportJob(ctx, job)
+	if err != nil {
+		s.store.Delete(ctx, key)
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return toImportJobResponse(job), http.StatusAccepted, nil
+}
+
+// GetImportJob reports the job's progress with one page of the items that
+// were skipped or failed.
+func (s *importService) GetImportJob(ctx context.Context, userID, jobID int64, page, pageSize int) (*dto.ImportJobResponse, int, error) {
+	job, err := s.importRepo.GetImportJobByID(ctx, jobID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if job == nil || job.UserID != userID {
+		return nil, http.StatusNotFound, errors.New("import not found")
+	}
+
+	offset := (page - 1) * pageSize
+	items, totalCount, err := s.importRepo.GetImportJobItems(ctx, jobID, pageSize, offset)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	itemResponses := []dto.ImportJobItemResponse{}
+	for _, item := range items {
+		itemResponses = append(itemResponses, dto.ImportJobItemResponse{
+			SourceID: item.SourceID,
+			Outcome:  item.Outcome,
+			Reason:   item.Reason,
+		})
+	}
+
+	response := toImportJobResponse(job)
+	response.Items = &dto.ImportJobItemsResponse{
+		Items:      itemResponses,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int(math.Ceil(float64(totalCount) / float64(pageSize))),
+	}
+	return response, http.StatusOK, nil
+}
+
+func toImportJobResponse(job *model.ImportJobModel) *dto.ImportJobResponse {
+	response := &dto.ImportJobResponse{
+		ID:             job.ID,
+		Source:         job.Source,
+		Status:         job.Status,
+		TotalItems:     job.TotalItems,
+		ProcessedItems: job.ProcessedItems,
+		ImportedItems:  job.ImportedItems,
+		SkippedItems:   job.SkippedItems,
+		FailedItems:    job.FailedItems,
+		Error:          job.Error.String,
+		CreatedAt:      job.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if job.StartedAt.Valid {
+		startedAt := job.StartedAt.Time.Format("2006-01-02 15:04:05")
+		response.StartedAt = &startedAt
+	}
+	if job.CompletedAt.Valid {
+		completedAt := job.CompletedAt.Time.Format("2006-01-02 15:04:05")
+		response.CompletedAt = &completedAt
+	}
+	return response
+}
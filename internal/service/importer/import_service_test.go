@@ -0,0 +1,244 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"go-twitter/internal/config"
+	"go-twitter/internal/model"
+	"go-twitter/internal/repository/user"
+	"go-twitter/pkg/blobstore"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryImportRepository keeps jobs, items and imported posts in memory.
+type memoryImportRepository struct {
+	jobs     map[int64]*model.ImportJobModel
+	nextID   int64
+	items    []*model.ImportJobItemModel
+	posts    []*model.PostModel
+	imported map[string]bool
+}
+
+func newMemoryImportRepository() *memoryImportRepository {
+	return &memoryImportRepository{jobs: make(map[int64]*model.ImportJobModel), imported: make(map[string]bool)}
+}
+
+func (r *memoryImportRepository) CreateImportJob(ctx context.Context, job *model.ImportJobModel) (int64, error) {
+	r.nextID++
+	stored := *job
+	stored.ID = r.nextID
+	r.jobs[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (r *memoryImportRepository) GetImportJobByID(ctx context.Context, id int64) (*model.ImportJobModel, error) {
+	if job, ok := r.jobs[id]; ok {
+		copied := *job
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryImportRepository) GetActiveImportJob(ctx context.Context, userID int64) (*model.ImportJobModel, error) {
+	for _, job := range r.jobs {
+		if job.UserID == userID && (job.Status == model.ImportPending || job.Status == model.ImportProcessing) {
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryImportRepository) ClaimImportJob(ctx context.Context, now, staleBefore time.Time) (*model.ImportJobModel, error) {
+	for id := int64(1); id <= r.nextID; id++ {
+		job, ok := r.jobs[id]
+		if ok && job.Status == model.ImportPending {
+			job.Status = model.ImportProcessing
+			return r.GetImportJobByID(ctx, id)
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryImportRepository) UpdateImportJobProgress(ctx context.Context, job *model.ImportJobModel) error {
+	return nil
+}
+
+func (r *memoryImportRepository) FinishImportJob(ctx context.Context, job *model.ImportJobModel, now time.Time) error {
+	stored := *job
+	stored.BlobKey.Valid = false
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+func (r *memoryImportRepository) CreateImportJobItem(ctx context.Context, item *model.ImportJobItemModel) error {
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (r *memoryImportRepository) GetImportJobItems(ctx context.Context, jobID int64, limit, offset int) ([]*model.ImportJobItemModel, int64, error) {
+	var items []*model.ImportJobItemModel
+	for _, item := range r.items {
+		if item.JobID == jobID {
+			items = append(items, item)
+		}
+	}
+	total := int64(len(items))
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items, total, nil
+}
+
+func (r *memoryImportRepository) ImportPost(ctx context.Context, post *model.PostModel, source, sourceID string) (bool, error) {
+	key := source + ":" + sourceID
+	if r.imported[key] {
+		return false, nil
+	}
+	r.imported[key] = true
+	post.ID = int64(len(r.posts) + 1)
+	r.posts = append(r.posts, post)
+	return true, nil
+}
+
+// fakeUserRepository answers username lookups; the embedded interface
+// panics on anything else.
+type fakeUserRepository struct {
+	user.UserRepository
+	usernames []string
+}
+
+func (r *fakeUserRepository) GetUserByEmailOrUsername(ctx context.Context, email, username string) (*model.UserModel, error) {
+	for i, name := range r.usernames {
+		if strings.EqualFold(name, username) {
+			return &model.UserModel{ID: int64(i + 1), Username: name}, nil
+		}
+	}
+	return nil, nil
+}
+
+func buildArchive(t *testing.T, tweetsJS string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("data/tweets.js")
+	w.Write([]byte(tweetsJS))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const tweetsJS = `window.YTD.tweets.part0 = [
+  { "tweet" : { "id_str" : "1", "created_at" : "Tue Oct 09 08:00:00 +0000 2018",
+    "full_text" : "Hi @Alice and @carol, read https://t.co/x #golang\nsecond line",
+    "entities" : { "user_mentions" : [ { "screen_name" : "alice" }, { "screen_name" : "carol" } ],
+                   "urls" : [ { "url" : "https://t.co/x", "expanded_url" : "https://go.dev" } ] } } },
+  { "tweet" : { "id_str" : "2", "created_at" : "Wed Oct 10 08:00:00 +0000 2018", "full_text" : "RT @carol: not mine" } },
+  { "tweet" : { "id_str" : "3", "created_at" : "someday", "full_text" : "bad date" } }
+]`
+
+func newTestService() (*importService, *memoryImportRepository, *blobstore.MemoryStore) {
+	importRepo := newMemoryImportRepository()
+	store := blobstore.NewMemoryStore()
+	userRepo := &fakeUserRepository{usernames: []string{"alice"}}
+	service := NewService(&config.Config{ImportMaxArchiveMB: 1}, importRepo, userRepo, store).(*importService)
+	return service, importRepo, store
+}
+
+func TestTwitterImport(t *testing.T) {
+	service, importRepo, store := newTestService()
+	ctx := context.Background()
+	archive := buildArchive(t, tweetsJS)
+
+	job, status, err := service.StartTwitterImport(ctx, 1, bytes.NewReader(archive), int64(len(archive)))
+	if err != nil || status != http.StatusAccepted || job.Status != model.ImportPending {
+		t.Fatalf("Expected pending import, got %+v, %d (%v)", job, status, err)
+	}
+	if _, status, _ := service.StartTwitterImport(ctx, 1, bytes.NewReader(archive), int64(len(archive))); status != http.StatusConflict {
+		t.Errorf("Expected a second import to get %d while one is pending, got %d", http.StatusConflict, status)
+	}
+	blobKey := importRepo.jobs[job.ID].BlobKey.String
+
+	completed, err := service.ProcessImportJobs(ctx)
+	if err != nil || completed != 1 {
+		t.Fatalf("Expected one import completed, got %d (%v)", completed, err)
+	}
+
+	if len(importRepo.posts) != 1 {
+		t.Fatalf("Expected 1 post, got %d", len(importRepo.posts))
+	}
+	post := importRepo.posts[0]
+	wantContent := "Hi @Alice and https://x.com/carol, read https://go.dev #golang\nsecond line"
+	if post.Content != wantContent || post.Title != "Hi @Alice and https://x.com/carol, read https://go.dev #golang" {
+		t.Errorf("Unexpected post %q / %q", post.Title, post.Content)
+	}
+	if !post.CreatedAt.Equal(time.Date(2018, 10, 9, 8, 0, 0, 0, time.UTC)) || post.UserID != 1 {
+		t.Errorf("Expected the original timestamp and owner, got %v for user %d", post.CreatedAt, post.UserID)
+	}
+
+	report, status, err := service.GetImportJob(ctx, 1, job.ID, 1, 20)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected import status, got %d (%v)", status, err)
+	}
+	if report.Status != model.ImportCompleted || report.TotalItems != 3 || report.ProcessedItems != 3 ||
+		report.ImportedItems != 1 || report.SkippedItems != 1 || report.FailedItems != 1 {
+		t.Errorf("Unexpected progress: %+v", report)
+	}
+	if report.Items.TotalCount != 2 || report.Items.Items[0].SourceID != "3" || report.Items.Items[1].Reason != "retweets are not imported" {
+		t.Errorf("Unexpected items: %+v", report.Items.Items)
+	}
+	if _, err := store.Get(ctx, blobKey); err != blobstore.ErrNotFound {
+		t.Errorf("Expected the uploaded archive to be deleted, got %v", err)
+	}
+	if _, status, _ := service.GetImportJob(ctx, 2, job.ID, 1, 20); status != http.StatusNotFound {
+		t.Errorf("Expected another user's import to be hidden, got %d", status)
+	}
+
+	// importing the same archive again creates nothing new
+	again, _, _ := service.StartTwitterImport(ctx, 1, bytes.NewReader(archive), int64(len(archive)))
+	if _, err := service.ProcessImportJobs(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	report, _, _ = service.GetImportJob(ctx, 1, again.ID, 1, 20)
+	if len(importRepo.posts) != 1 || report.ImportedItems != 0 || report.SkippedItems != 2 {
+		t.Errorf("Expected the re-import to skip everything, got %d posts and %+v", len(importRepo.posts), report)
+	}
+}
+
+func TestTwitterImport_InvalidArchive(t *testing.T) {
+	service, _, _ := newTestService()
+	ctx := context.Background()
+
+	job, _, _ := service.StartTwitterImport(ctx, 1, strings.NewReader("not a zip"), 9)
+	completed, err := service.ProcessImportJobs(ctx)
+	if err != nil || completed != 0 {
+		t.Fatalf("Expected no completed imports and no error, got %d (%v)", completed, err)
+	}
+
+	report, _, _ := service.GetImportJob(ctx, 1, job.ID, 1, 20)
+	if report.Status != model.ImportFailed || !strings.HasPrefix(report.Error, "archive could not be read") {
+		t.Errorf("Expected the import to fail with a readable error, got %+v", report)
+	}
+
+	if _, status, _ := service.StartTwitterImport(ctx, 1, strings.NewReader(""), 2<<20); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d for an oversized archive, got %d", http.StatusRequestEntityTooLarge, status)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	long := strings.Repeat("é", 150)
+	if got := truncate(long, maxTitleLength); len([]rune(got)) != maxTitleLength || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected %d characters ending in an ellipsis, got %q", maxTitleLength, got)
+	}
+	if got := truncate("short", maxTitleLength); got != "short" {
+		t.Errorf("Expected short strings unchanged, got %q", got)
+	}
+}
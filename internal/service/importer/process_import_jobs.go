@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/twitterarchive"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// importJobStaleAfter is how long an import may go without progress
+	// before it is assumed abandoned and started again.
+	importJobStaleAfter = time.Hour
+	// progressInterval is how many items are processed between progress
+	// updates.
+	progressInterval = 50
+
+	maxSourceIDLength = 64
+	maxReasonLength   = 255
+)
+
+// ProcessImportJobs runs every queued import and returns how many
+// completed. An archive that cannot be read fails its job with an error
+// the user sees; an import interrupted by a server error is failed too and
+// the error returned, without stopping the other jobs.
+func (s *importService) ProcessImportJobs(ctx context.Context) (int, error) {
+	completed := 0
+	var errs []error
+	for {
+		now := time.Now()
+		job, err := s.importRepo.ClaimImportJob(ctx, now, now.Add(-importJobStaleAfter))
+		if err != nil {
+			return completed, errors.Join(append(errs, err)...)
+		}
+		if job == nil {
+			return completed, errors.Join(errs...)
+		}
+
+		runErr := s.runImportJob(ctx, job)
+		if runErr != nil {
+			errs = append(errs, fmt.Errorf("import %d: %w", job.ID, runErr))
+			job.Status = model.ImportFailed
+			job.Error = sql.NullString{String: "import failed, please try again", Valid: true}
+		}
+		if err := s.importRepo.FinishImportJob(ctx, job, time.Now()); err != nil {
+			return completed, errors.Join(append(errs, err)...)
+		}
+		if err := s.store.Delete(ctx, job.BlobKey.String); err != nil {
+			errs = append(errs, err)
+		}
+		if runErr == nil && job.Status == model.ImportCompleted {
+			completed++
+		}
+	}
+}
+
+// runImportJob imports the job's archive, leaving the final status and
+// counters on job. It returns an error only for server failures.
+func (s *importService) runImportJob(ctx context.Context, job *model.ImportJobModel) error {
+	archive, err := s.readArchive(ctx, job.BlobKey.String)
+	if err != nil {
+		var invalid *invalidArchiveError
+		if errors.As(err, &invalid) {
+			job.Status = model.ImportFailed
+			job.Error = sql.NullString{String: truncate(invalid.Error(), maxReasonLength), Valid: true}
+			return nil
+		}
+		return err
+	}
+
+	job.TotalItems = len(archive.Tweets) + len(archive.Errors)
+	for _, itemErr := range archive.Errors {
+		sourceID := itemErr.ID
+		if sourceID == "" {
+			sourceID = fmt.Sprintf("%s#%d", itemErr.File, itemErr.Index)
+		}
+		if err := s.recordItem(ctx, job, sourceID, model.ImportItemFailed, itemErr.Err.Error()); err != nil {
+			return err
+		}
+	}
+
+	// mentions are looked up once per job
+	localUsers := make(map[string]bool)
+	isLocalUser := func(username string) (bool, error) {
+		key := strings.ToLower(username)
+		if local, ok := localUsers[key]; ok {
+			return local, nil
+		}
+		found, err := s.userRepo.GetUserByEmailOrUsername(ctx, "", username)
+		if err != nil {
+			return false, err
+		}
+		localUsers[key] = found != nil && strings.EqualFold(found.Username, username) && !found.DeactivatedAt.Valid
+		return localUsers[key], nil
+	}
+
+	for _, tweet := range archive.Tweets {
+		if err := s.importTweet(ctx, job, tweet, isLocalUser); err != nil {
+			return err
+		}
+		if job.ProcessedItems%progressInterval == 0 {
+			if err := s.importRepo.UpdateImportJobProgress(ctx, job); err != nil {
+				return err
+			}
+		}
+	}
+
+	job.Status = model.ImportCompleted
+	return nil
+}
+
+func (s *importService) importTweet(ctx context.Context, job *model.ImportJobModel, tweet twitterarchive.Tweet, isLocalUser func(string) (bool, error)) error {
+	if tweet.Retweet {
+		return s.recordItem(ctx, job, tweet.ID, model.ImportItemSkipped, "retweets are not imported")
+	}
+
+	title, content, err := convertTweet(tweet, isLocalUser)
+	if err != nil {
+		return err
+	}
+	if content == "" {
+		return s.recordItem(ctx, job, tweet.ID, model.ImportItemFailed, "tweet has no text")
+	}
+
+	post := &model.PostModel{
+		UserID:    job.UserID,
+		Title:     title,
+		Content:   content,
+		CreatedAt: tweet.CreatedAt,
+		UpdatedAt: tweet.CreatedAt,
+	}
+	imported, err := s.importRepo.ImportPost(ctx, post, job.Source, truncate(tweet.ID, maxSourceIDLength))
+	if err != nil {
+		return err
+	}
+	if !imported {
+		return s.recordItem(ctx, job, tweet.ID, model.ImportItemSkipped, "already imported")
+	}
+
+	job.ProcessedItems++
+	job.ImportedItems++
+	return nil
+}
+
+func (s *importService) recordItem(ctx context.Context, job *model.ImportJobModel, sourceID, outcome, reason string) error {
+	err := s.importRepo.CreateImportJobItem(ctx, &model.ImportJobItemModel{
+		JobID:     job.ID,
+		SourceID:  truncate(sourceID, maxSourceIDLength),
+		Outcome:   outcome,
+		Reason:    truncate(reason, maxReasonLength),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	job.ProcessedItems++
+	if outcome == model.ImportItemSkipped {
+		job.SkippedItems++
+	} else {
+		job.FailedItems++
+	}
+	return nil
+}
+
+// invalidArchiveError is an upload that is not a readable archive, as
+// opposed to a failure reading it from the blob store.
+type invalidArchiveError struct {
+	err error
+}
+
+func (e *invalidArchiveError) Error() string {
+	return "archive could not be read: " + e.err.Error()
+}
+
+// readArchive copies the archive to a temporary file, since reading a ZIP
+// needs random access.
+func (s *importService) readArchive(ctx context.Context, key string) (*twitterarchive.Archive, error) {
+	blob, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer blob.Close()
+
+	tmp, err := os.CreateTemp("", "import-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, blob)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := twitterarchive.Read(tmp, size)
+	if err != nil {
+		return nil, &invalidArchiveError{err: err}
+	}
+	return archive, nil
+}
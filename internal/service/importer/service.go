@@ -0,0 +1,33 @@
+package importer
+
+import (
+	"context"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/repository/importer"
+	"go-twitter/internal/repository/user"
+	"go-twitter/pkg/blobstore"
+	"io"
+)
+
+type ImportService interface {
+	StartTwitterImport(ctx context.Context, userID int64, archive io.Reader, size int64) (*dto.ImportJobResponse, int, error)
+	GetImportJob(ctx context.Context, userID, jobID int64, page, pageSize int) (*dto.ImportJobResponse, int, error)
+	ProcessImportJobs(ctx context.Context) (int, error)
+}
+
+type importService struct {
+	cfg        *config.Config
+	importRepo importer.ImportRepository
+	userRepo   user.UserRepository
+	store      blobstore.Store
+}
+
+func NewService(cfg *config.Config, importRepo importer.ImportRepository, userRepo user.UserRepository, store blobstore.Store) ImportService {
+	return &importService{
+		cfg:        cfg,
+		importRepo: importRepo,
+		userRepo:   userRepo,
+		store:      store,
+	}
+}
@@ -2,6 +2,7 @@ package like
 
 import (
 	"context"
+	"errors"
 	"net/http"
 )
 
@@ -15,11 +16,15 @@ func (s *likeService) LikeComment(ctx context.Context, userID, commentID int64)
 		return http.StatusConflict, nil
 	}
 
-	err = s.likeRepo.LikeComment(ctx, commentID, userID)
+	liked, err := s.likeRepo.LikeComment(ctx, commentID, userID)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
+	if !liked {
+		return http.StatusNotFound, errors.New("comment not found")
+	}
+
 	return http.StatusCreated, nil
 }
 
@@ -42,10 +47,14 @@ func (s *likeService) UnlikeComment(ctx context.Context, userID, commentID int64
 }
 
 func (s *likeService) GetCommentLikesCount(ctx context.Context, commentID int64) (int, int, error) {
-	count, err := s.likeRepo.GetCommentLikesCount(ctx, commentID)
+	count, found, err := s.likeRepo.GetCommentLikesCount(ctx, commentID)
 	if err != nil {
 		return 0, http.StatusInternalServerError, err
 	}
 
+	if !found {
+		return 0, http.StatusNotFound, errors.New("comment not found")
+	}
+
 	return count, http.StatusOK, nil
 }
@@ -0,0 +1,177 @@
+package like
+
+import (
+	"context"
+	"database/sql"
+	"go-twitter/internal/config"
+	"go-twitter/internal/model"
+	"go-twitter/internal/repository/post"
+	postService "go-twitter/internal/service/post"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// memoryStore holds posts, comments and likes, and answers like the
+// repositories do: likes of deleted posts and of their comments are hidden.
+type memoryStore struct {
+	posts        map[int64]*model.PostModel
+	comments     map[int64]*model.CommentModel
+	postLikes    map[int64]map[int64]bool
+	commentLikes map[int64]map[int64]bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		posts:        map[int64]*model.PostModel{},
+		comments:     map[int64]*model.CommentModel{},
+		postLikes:    map[int64]map[int64]bool{},
+		commentLikes: map[int64]map[int64]bool{},
+	}
+}
+
+func (s *memoryStore) livePost(id int64) bool {
+	p, ok := s.posts[id]
+	return ok && p.Status == model.PostPublished && !p.DeletedAt.Valid
+}
+
+func (s *memoryStore) liveComment(id int64) bool {
+	c, ok := s.comments[id]
+	return ok && !c.DeletedAt.Valid && s.livePost(c.PostID)
+}
+
+func addLike(likes map[int64]map[int64]bool, id, userID int64) bool {
+	if likes[id] == nil {
+		likes[id] = map[int64]bool{}
+	}
+	if likes[id][userID] {
+		return false
+	}
+	likes[id][userID] = true
+	return true
+}
+
+type memoryLikeRepository struct {
+	*memoryStore
+}
+
+func (r *memoryLikeRepository) LikePost(ctx context.Context, postID, userID int64) (bool, error) {
+	return r.livePost(postID) && addLike(r.postLikes, postID, userID), nil
+}
+
+func (r *memoryLikeRepository) UnlikePost(ctx context.Context, postID, userID int64) error {
+	delete(r.postLikes[postID], userID)
+	return nil
+}
+
+func (r *memoryLikeRepository) IsPostLiked(ctx context.Context, postID, userID int64) (bool, error) {
+	return r.livePost(postID) && r.postLikes[postID][userID], nil
+}
+
+func (r *memoryLikeRepository) GetPostLikesCount(ctx context.Context, postID int64) (int, bool, error) {
+	if !r.livePost(postID) {
+		return 0, false, nil
+	}
+	return len(r.postLikes[postID]), true, nil
+}
+
+func (r *memoryLikeRepository) LikeComment(ctx context.Context, commentID, userID int64) (bool, error) {
+	return r.liveComment(commentID) && addLike(r.commentLikes, commentID, userID), nil
+}
+
+func (r *memoryLikeRepository) UnlikeComment(ctx context.Context, commentID, userID int64) error {
+	delete(r.commentLikes[commentID], userID)
+	return nil
+}
+
+func (r *memoryLikeRepository) IsCommentLiked(ctx context.Context, commentID, userID int64) (bool, error) {
+	return r.liveComment(commentID) && r.commentLikes[commentID][userID], nil
+}
+
+func (r *memoryLikeRepository) GetCommentLikesCount(ctx context.Context, commentID int64) (int, bool, error) {
+	if !r.liveComment(commentID) {
+		return 0, false, nil
+	}
+	return len(r.commentLikes[commentID]), true, nil
+}
+
+// memoryPostRepository only restores posts; other calls are not expected.
+type memoryPostRepository struct {
+	post.PostRepository
+	*memoryStore
+}
+
+func (r *memoryPostRepository) RestorePost(ctx context.Context, id, userID int64, since time.Time) (bool, error) {
+	p, ok := r.posts[id]
+	if !ok || p.UserID != userID || !p.DeletedAt.Valid || !p.DeletedAt.Time.After(since) {
+		return false, nil
+	}
+	p.DeletedAt = sql.NullTime{}
+	return true, nil
+}
+
+// newDeletedPostStore has post 1 by user 1, deleted by its author, with two
+// likes and a comment liked once. Post 2 does not exist.
+func newDeletedPostStore() *memoryStore {
+	store := newMemoryStore()
+	store.posts[1] = &model.PostModel{
+		ID:        1,
+		UserID:    1,
+		Status:    model.PostPublished,
+		DeletedAt: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+	}
+	store.comments[10] = &model.CommentModel{ID: 10, PostID: 1, UserID: 2}
+	store.postLikes[1] = map[int64]bool{2: true, 3: true}
+	store.commentLikes[10] = map[int64]bool{3: true}
+	return store
+}
+
+func TestLikes_NotFoundForDeletedOrMissingPost(t *testing.T) {
+	store := newDeletedPostStore()
+	service := NewService(&memoryLikeRepository{store})
+	ctx := context.Background()
+
+	for _, postID := range []int64{1, 2} {
+		if status, _ := service.LikePost(ctx, 4, postID); status != http.StatusNotFound {
+			t.Errorf("Expected status %d liking post %d, got %d", http.StatusNotFound, postID, status)
+		}
+		if _, status, _ := service.GetPostLikesCount(ctx, postID); status != http.StatusNotFound {
+			t.Errorf("Expected status %d counting likes of post %d, got %d", http.StatusNotFound, postID, status)
+		}
+	}
+
+	// comment 10 is on the deleted post, comment 11 does not exist
+	for _, commentID := range []int64{10, 11} {
+		if status, _ := service.LikeComment(ctx, 4, commentID); status != http.StatusNotFound {
+			t.Errorf("Expected status %d liking comment %d, got %d", http.StatusNotFound, commentID, status)
+		}
+		if _, status, _ := service.GetCommentLikesCount(ctx, commentID); status != http.StatusNotFound {
+			t.Errorf("Expected status %d counting likes of comment %d, got %d", http.StatusNotFound, commentID, status)
+		}
+	}
+
+	if len(store.postLikes[1]) != 2 || len(store.commentLikes[10]) != 1 {
+		t.Error("Expected no likes added to the deleted post or its comment")
+	}
+}
+
+func TestLikes_ShownAgainAfterRestorePost(t *testing.T) {
+	store := newDeletedPostStore()
+	service := NewService(&memoryLikeRepository{store})
+	posts := postService.NewService(&config.Config{}, &memoryPostRepository{memoryStore: store}, nil)
+	ctx := context.Background()
+
+	if status, err := posts.RestorePost(ctx, 1, 1); status != http.StatusOK {
+		t.Fatalf("Expected status %d restoring the post, got %d (%v)", http.StatusOK, status, err)
+	}
+
+	if count, status, _ := service.GetPostLikesCount(ctx, 1); status != http.StatusOK || count != 2 {
+		t.Errorf("Expected the post's 2 likes back, got %d with status %d", count, status)
+	}
+	if count, status, _ := service.GetCommentLikesCount(ctx, 10); status != http.StatusOK || count != 1 {
+		t.Errorf("Expected the comment's like back, got %d with status %d", count, status)
+	}
+	if status, _ := service.LikeComment(ctx, 4, 10); status != http.StatusCreated {
+		t.Errorf("Expected the comment to take likes again, got status %d", status)
+	}
+}
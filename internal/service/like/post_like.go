@@ -2,6 +2,7 @@ package like
 
 import (
 	"context"
+	"errors"
 	"net/http"
 )
 
@@ -15,11 +16,15 @@ func (s *likeService) LikePost(ctx context.Context, userID, postID int64) (int,
 		return http.StatusConflict, nil
 	}
 
-	err = s.likeRepo.LikePost(ctx, postID, userID)
+	liked, err := s.likeRepo.LikePost(ctx, postID, userID)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
+	if !liked {
+		return http.StatusNotFound, errors.New("post not found")
+	}
+
 	return http.StatusCreated, nil
 }
 
@@ -42,10 +47,14 @@ func (s *likeService) UnlikePost(ctx context.Context, userID, postID int64) (int
 }
 
 func (s *likeService) GetPostLikesCount(ctx context.Context, postID int64) (int, int, error) {
-	count, err := s.likeRepo.GetPostLikesCount(ctx, postID)
+	count, found, err := s.likeRepo.GetPostLikesCount(ctx, postID)
 	if err != nil {
 		return 0, http.StatusInternalServerError, err
 	}
 
+	if !found {
+		return 0, http.StatusNotFound, errors.New("post not found")
+	}
+
 	return count, http.StatusOK, nil
 }
@@ -0,0 +1,44 @@
+package media
+
+import (
+	"context"
+	"time"
+)
+
+const orphanedMediaBatch = 100
+
+// CollectOrphanedMedia deletes uploads that no post linked within the
+// orphan TTL, including those left behind by purged accounts. It returns
+// how many were deleted.
+func (s *mediaService) CollectOrphanedMedia(ctx context.Context) (int, error) {
+	_, _, orphanTTL, _ := s.cfg.Media()
+	collected := 0
+	for {
+		media, err := s.mediaRepo.GetOrphanedMedia(ctx, time.Now().Add(-orphanTTL), orphanedMediaBatch)
+		if err != nil {
+			return collected, err
+		}
+		for _, m := range media {
+			deleted, err := s.mediaRepo.DeleteOrphanedMedia(ctx, m.ID)
+			if err != nil {
+				return collected, err
+			}
+			// a post linked it in the meantime
+			if !deleted {
+				continue
+			}
+			if err := s.store.Delete(ctx, m.BlobKey); err != nil {
+				return collected, err
+			}
+			for _, v := range m.Variants {
+				if err := s.store.Delete(ctx, v.BlobKey); err != nil {
+					return collected, err
+				}
+			}
+			collected++
+		}
+		if len(media) < orphanedMediaBatch {
+			return collected, nil
+		}
+	}
+}
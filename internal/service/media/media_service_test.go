@@ -0,0 +1,360 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/blobstore"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryMediaRepository keeps media in memory; published marks the media
+// linked to a readable post.
+type memoryMediaRepository struct {
+	media     map[int64]*model.MediaModel
+	published map[int64]bool
+	nextID    int64
+}
+
+func newMemoryMediaRepository() *memoryMediaRepository {
+	return &memoryMediaRepository{media: make(map[int64]*model.MediaModel), published: make(map[int64]bool)}
+}
+
+func (r *memoryMediaRepository) CreateMedia(ctx context.Context, media *model.MediaModel) (int64, error) {
+	r.nextID++
+	stored := *media
+	stored.ID = r.nextID
+	r.media[stored.ID] = &stored
+	return stored.ID, nil
+}
+
+func (r *memoryMediaRepository) GetPublishedMediaByID(ctx context.Context, id int64) (*model.MediaModel, error) {
+	if !r.published[id] {
+		return nil, nil
+	}
+	return r.media[id], nil
+}
+
+func (r *memoryMediaRepository) GetOrphanedMedia(ctx context.Context, createdBefore time.Time, limit int) ([]*model.MediaModel, error) {
+	var orphaned []*model.MediaModel
+	for id := int64(1); id <= r.nextID && len(orphaned) < limit; id++ {
+		if m, ok := r.media[id]; ok && !r.published[id] && m.CreatedAt.Before(createdBefore) {
+			orphaned = append(orphaned, m)
+		}
+	}
+	return orphaned, nil
+}
+
+func (r *memoryMediaRepository) DeleteOrphanedMedia(ctx context.Context, id int64) (bool, error) {
+	if _, ok := r.media[id]; !ok || r.published[id] {
+		return false, nil
+	}
+	delete(r.media, id)
+	return true, nil
+}
+
+func (r *memoryMediaRepository) GetMediaToProcess(ctx context.Context, staleBefore time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	for id := int64(1); id <= r.nextID && len(ids) < limit; id++ {
+		if m, ok := r.media[id]; ok && m.ProcessingStatus == model.MediaPending {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryMediaRepository) ClaimMediaProcessing(ctx context.Context, id int64, staleBefore time.Time) (*model.MediaModel, error) {
+	m, ok := r.media[id]
+	if !ok || m.ProcessingStatus != model.MediaPending {
+		return nil, nil
+	}
+	m.ProcessingStatus = model.MediaProcessing
+	claimed := *m
+	return &claimed, nil
+}
+
+func (r *memoryMediaRepository) CompleteMediaProcessing(ctx context.Context, media *model.MediaModel) (bool, error) {
+	m, ok := r.media[media.ID]
+	if !ok || m.ProcessingStatus != model.MediaProcessing {
+		return false, nil
+	}
+	stored := *media
+	stored.ProcessingStatus = model.MediaReady
+	r.media[media.ID] = &stored
+	return true, nil
+}
+
+func (r *memoryMediaRepository) FailMediaProcessing(ctx context.Context, id int64) error {
+	if m, ok := r.media[id]; ok && m.ProcessingStatus == model.MediaProcessing {
+		m.ProcessingStatus = model.MediaFailed
+	}
+	return nil
+}
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestService() (*mediaService, *memoryMediaRepository, *blobstore.MemoryStore) {
+	mediaRepo := newMemoryMediaRepository()
+	store := blobstore.NewMemoryStore()
+	cfg := &config.Config{MediaMaxImageMB: 1, MediaMaxVideoMB: 2, MediaOrphanTTL: time.Hour, MediaMaxImagePixels: 4000 * 4000}
+	return NewService(cfg, mediaRepo, store).(*mediaService), mediaRepo, store
+}
+
+func TestUploadMedia(t *testing.T) {
+	service, mediaRepo, store := newTestService()
+	ctx := context.Background()
+
+	uploaded, status, err := service.UploadMedia(ctx, 1, bytes.NewReader(pngHeader), int64(len(pngHeader)), dto.UploadMediaRequest{AltText: "a cat"})
+	if err != nil || status != http.StatusCreated {
+		t.Fatalf("Expected upload to succeed, got %d (%v)", status, err)
+	}
+	if uploaded.ContentType != "image/png" || uploaded.AltText != "a cat" || uploaded.URL != "/media/1" || uploaded.ProcessingStatus != model.MediaPending {
+		t.Errorf("Unexpected upload: %+v", uploaded)
+	}
+
+	stored := mediaRepo.media[uploaded.ID]
+	if !strings.HasSuffix(stored.BlobKey, ".png") || stored.UserID.Int64 != 1 {
+		t.Errorf("Unexpected stored media: %+v", stored)
+	}
+	r, err := store.Get(ctx, stored.BlobKey)
+	if err != nil {
+		t.Fatalf("Expected the file to be stored, got %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	if !bytes.Equal(data, pngHeader) {
+		t.Errorf("Expected the stored file to match the upload, got %q", data)
+	}
+
+	// unattached uploads are not served
+	if _, _, status, _ := service.OpenMedia(ctx, uploaded.ID, ""); status != http.StatusNotFound {
+		t.Errorf("Expected status %d before the media is posted, got %d", http.StatusNotFound, status)
+	}
+	// nor are images still being processed
+	mediaRepo.published[uploaded.ID] = true
+	if _, _, status, _ := service.OpenMedia(ctx, uploaded.ID, ""); status != http.StatusNotFound {
+		t.Errorf("Expected status %d before the media is processed, got %d", http.StatusNotFound, status)
+	}
+	stored.ProcessingStatus = model.MediaReady
+	file, media, status, err := service.OpenMedia(ctx, uploaded.ID, "")
+	if err != nil || status != http.StatusOK || media.ContentType != "image/png" {
+		t.Fatalf("Expected the posted media to open, got %d (%v)", status, err)
+	}
+	file.Close()
+}
+
+func TestUploadMedia_Rejects(t *testing.T) {
+	service, mediaRepo, _ := newTestService()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		file   []byte
+		size   int64
+		status int
+	}{
+		{"empty file", nil, 0, http.StatusBadRequest},
+		{"script disguised as an image", []byte("<html><script>alert(1)</script>"), 31, http.StatusUnsupportedMediaType},
+		{"image the workers cannot decode", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), 16, http.StatusUnsupportedMediaType},
+		{"image over the image limit", pngHeader, 1<<20 + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, status, _ := service.UploadMedia(ctx, 1, bytes.NewReader(tt.file), tt.size, dto.UploadMediaRequest{}); status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+	if len(mediaRepo.media) != 0 {
+		t.Errorf("Expected nothing stored, got %d media", len(mediaRepo.media))
+	}
+}
+
+func TestCollectOrphanedMedia(t *testing.T) {
+	service, mediaRepo, store := newTestService()
+	ctx := context.Background()
+
+	orphan, _, _ := service.UploadMedia(ctx, 1, bytes.NewReader(pngHeader), int64(len(pngHeader)), dto.UploadMediaRequest{})
+	posted, _, _ := service.UploadMedia(ctx, 1, bytes.NewReader(pngHeader), int64(len(pngHeader)), dto.UploadMediaRequest{})
+	recent, _, _ := service.UploadMedia(ctx, 1, bytes.NewReader(pngHeader), int64(len(pngHeader)), dto.UploadMediaRequest{})
+	orphanKey := mediaRepo.media[orphan.ID].BlobKey
+	mediaRepo.media[orphan.ID].CreatedAt = time.Now().Add(-2 * time.Hour)
+	mediaRepo.media[posted.ID].CreatedAt = time.Now().Add(-2 * time.Hour)
+	mediaRepo.published[posted.ID] = true
+
+	collected, err := service.CollectOrphanedMedia(ctx)
+	if err != nil || collected != 1 {
+		t.Fatalf("Expected one upload collected, got %d (%v)", collected, err)
+	}
+	if _, ok := mediaRepo.media[orphan.ID]; ok {
+		t.Error("Expected the orphaned upload to be deleted")
+	}
+	if _, err := store.Get(ctx, orphanKey); err != blobstore.ErrNotFound {
+		t.Errorf("Expected the orphaned file to be deleted, got %v", err)
+	}
+	if _, ok := mediaRepo.media[posted.ID]; !ok {
+		t.Error("Expected posted media to be kept")
+	}
+	if _, ok := mediaRepo.media[recent.ID]; !ok {
+		t.Error("Expected a recent upload to be kept until the orphan TTL")
+	}
+}
+
+// exifJPEG encodes img as a JPEG carrying an EXIF segment with a GPS
+// marker and the given orientation.
+func exifJPEG(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	tiff = append(tiff, "GPS 51.5N 0.1W"...)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := binary.BigEndian.AppendUint16([]byte{0xFF, 0xE1}, uint16(len(segment)+2))
+	jpg := buf.Bytes()
+	return append(append(append([]byte{}, jpg[:2]...), append(app1, segment...)...), jpg[2:]...)
+}
+
+func TestProcessMedia(t *testing.T) {
+	service, mediaRepo, store := newTestService()
+	ctx := context.Background()
+
+	// a 1000x500 photo taken with the camera turned
+	photo := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	for y := 0; y < 500; y++ {
+		for x := 0; x < 1000; x++ {
+			photo.Set(x, y, color.RGBA{uint8(x / 4), uint8(y / 2), 128, 255})
+		}
+	}
+	data := exifJPEG(t, photo, 6)
+	uploaded, _, err := service.UploadMedia(ctx, 1, bytes.NewReader(data), int64(len(data)), dto.UploadMediaRequest{})
+	if err != nil {
+		t.Fatalf("Expected upload to succeed, got %v", err)
+	}
+	uploadKey := mediaRepo.media[uploaded.ID].BlobKey
+
+	// the upload was queued for a worker
+	if id := <-service.queue; id != uploaded.ID {
+		t.Fatalf("Expected media %d queued, got %d", uploaded.ID, id)
+	}
+	if err := service.processMedia(ctx, uploaded.ID); err != nil {
+		t.Fatalf("Expected processing to succeed, got %v", err)
+	}
+
+	media := mediaRepo.media[uploaded.ID]
+	if media.ProcessingStatus != model.MediaReady || media.Width != 500 || media.Height != 1000 {
+		t.Fatalf("Expected a ready, upright 500x1000 image, got %s %dx%d", media.ProcessingStatus, media.Width, media.Height)
+	}
+	if len(media.Blurhash) != 28 {
+		t.Errorf("Expected a 4x3 blurhash, got %q", media.Blurhash)
+	}
+	if _, err := store.Get(ctx, uploadKey); err != blobstore.ErrNotFound {
+		t.Errorf("Expected the upload to be deleted, got %v", err)
+	}
+
+	r, err := store.Get(ctx, media.BlobKey)
+	if err != nil {
+		t.Fatalf("Expected the processed image to be stored, got %v", err)
+	}
+	processed, _ := io.ReadAll(r)
+	if bytes.Contains(processed, []byte("Exif")) || bytes.Contains(processed, []byte("GPS")) {
+		t.Error("Expected the EXIF metadata to be stripped")
+	}
+	if int64(len(processed)) != media.SizeBytes {
+		t.Errorf("Expected size %d, got %d", len(processed), media.SizeBytes)
+	}
+
+	// 1000 pixels tall: medium and small, but no large variant
+	if len(media.Variants) != 2 || media.Variants[0].Name != "medium" || media.Variants[1].Name != "small" {
+		t.Fatalf("Expected medium and small variants, got %+v", media.Variants)
+	}
+	small := media.Variants[1]
+	if small.Width != 160 || small.Height != 320 || small.ContentType != "image/jpeg" {
+		t.Errorf("Unexpected small variant: %+v", small)
+	}
+
+	mediaRepo.published[uploaded.ID] = true
+	file, served, status, err := service.OpenMedia(ctx, uploaded.ID, "small")
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected the small variant to open, got %d (%v)", status, err)
+	}
+	defer file.Close()
+	decoded, err := jpeg.DecodeConfig(file)
+	if err != nil || decoded.Width != 160 || served.SizeBytes != small.SizeBytes {
+		t.Errorf("Expected the 160 pixel wide variant, got %+v (%v)", decoded, err)
+	}
+	if _, _, status, _ := service.OpenMedia(ctx, uploaded.ID, "huge"); status != http.StatusNotFound {
+		t.Errorf("Expected status %d for an unknown variant, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestProcessMedia_RejectsDecompressionBomb(t *testing.T) {
+	service, mediaRepo, store := newTestService()
+	ctx := context.Background()
+
+	// a few kilobytes that would decode to 400 MB
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 10000, 10000))); err != nil {
+		t.Fatal(err)
+	}
+	uploaded, _, err := service.UploadMedia(ctx, 1, bytes.NewReader(buf.Bytes()), int64(buf.Len()), dto.UploadMediaRequest{})
+	if err != nil {
+		t.Fatalf("Expected upload to succeed, got %v", err)
+	}
+	uploadKey := mediaRepo.media[uploaded.ID].BlobKey
+
+	if err := service.processMedia(ctx, uploaded.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status := mediaRepo.media[uploaded.ID].ProcessingStatus; status != model.MediaFailed {
+		t.Errorf("Expected processing to fail, got %s", status)
+	}
+	// the upload is kept for the orphaned media cleanup
+	if _, err := store.Get(ctx, uploadKey); err != nil {
+		t.Errorf("Expected the upload to be kept, got %v", err)
+	}
+}
+
+func TestQueuePendingMedia(t *testing.T) {
+	service, mediaRepo, _ := newTestService()
+	ctx := context.Background()
+
+	for i := 0; i < processingQueueSize+5; i++ {
+		service.UploadMedia(ctx, 1, bytes.NewReader(pngHeader), int64(len(pngHeader)), dto.UploadMediaRequest{})
+	}
+	// the uploads beyond the queue size are left pending
+	if len(service.queue) != processingQueueSize {
+		t.Fatalf("Expected a full queue, got %d", len(service.queue))
+	}
+
+	for len(service.queue) > 0 {
+		<-service.queue
+	}
+	mediaRepo.media[1].ProcessingStatus = model.MediaReady
+	queued, err := service.QueuePendingMedia(ctx)
+	if err != nil || queued != processingQueueSize {
+		t.Errorf("Expected %d media queued, got %d (%v)", processingQueueSize, queued, err)
+	}
+	if id := <-service.queue; id != 2 {
+		t.Errorf("Expected the oldest pending media first, got %d", id)
+	}
+}
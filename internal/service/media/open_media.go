@@ -0,0 +1,55 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/blobstore"
+	"io"
+	"net/http"
+)
+
+// OpenMedia returns the file of media attached to a readable post, or of
+// the named variant of it. The returned model describes the file served.
+// The caller must close it.
+func (s *mediaService) OpenMedia(ctx context.Context, mediaID int64, variant string) (io.ReadCloser, *model.MediaModel, int, error) {
+	media, err := s.mediaRepo.GetPublishedMediaByID(ctx, mediaID)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	if media == nil {
+		return nil, nil, http.StatusNotFound, errors.New("media not found")
+	}
+	switch media.ProcessingStatus {
+	case model.MediaReady:
+	case model.MediaFailed:
+		return nil, nil, http.StatusNotFound, errors.New("media could not be processed")
+	default:
+		return nil, nil, http.StatusNotFound, errors.New("media is still being processed")
+	}
+
+	served := *media
+	if variant != "" {
+		found := false
+		for _, v := range media.Variants {
+			if v.Name == variant {
+				served.BlobKey, served.ContentType, served.SizeBytes = v.BlobKey, v.ContentType, v.SizeBytes
+				served.Width, served.Height = v.Width, v.Height
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, nil, http.StatusNotFound, errors.New("media variant not found")
+		}
+	}
+
+	file, err := s.store.Get(ctx, served.BlobKey)
+	if err == blobstore.ErrNotFound {
+		return nil, nil, http.StatusNotFound, errors.New("media not found")
+	}
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	return file, &served, http.StatusOK, nil
+}
@@ -0,0 +1,237 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/blurhash"
+	"go-twitter/pkg/imageproc"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// mediaVariants are the resized copies rendered for each image, largest
+// first so each is scaled down from the one before. A variant is only
+// rendered when the image is larger than it.
+var mediaVariants = []struct {
+	name    string
+	maxSide int
+}{
+	{"large", 1600},
+	{"medium", 800},
+	{"small", 320},
+}
+
+const (
+	// processingTimeout is how long a worker may hold an image before the
+	// claim is considered abandoned and the image is processed again.
+	processingTimeout = 10 * time.Minute
+
+	// blurhashSide is the size the image is scaled to before hashing; the
+	// placeholder holds far less detail than that.
+	blurhashSide = 64
+
+	originalJPEGQuality = 90
+	variantJPEGQuality  = 85
+)
+
+// StartProcessing starts the workers that process uploaded images until
+// ctx is cancelled. Errors that leave an image pending are passed to
+// onError; the image is retried once its claim goes stale.
+func (s *mediaService) StartProcessing(ctx context.Context, onError func(error)) {
+	workers, _, _ := s.cfg.MediaProcessing()
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					if err := s.processMedia(ctx, id); err != nil {
+						onError(fmt.Errorf("media %d: %w", id, err))
+					}
+				}
+			}
+		}()
+	}
+}
+
+// QueuePendingMedia queues images that are still pending, because the
+// queue was full or the server restarted, and those whose worker gave up.
+// It returns how many were queued.
+func (s *mediaService) QueuePendingMedia(ctx context.Context) (int, error) {
+	ids, err := s.mediaRepo.GetMediaToProcess(ctx, time.Now().Add(-processingTimeout), processingQueueSize)
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for _, id := range ids {
+		if !s.enqueue(id) {
+			break
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// enqueue hands the media to a worker without blocking, reporting whether
+// the queue had room.
+func (s *mediaService) enqueue(id int64) bool {
+	select {
+	case s.queue <- id:
+		return true
+	default:
+		return false
+	}
+}
+
+// processMedia replaces an uploaded image with a copy free of metadata
+// such as EXIF GPS tags, renders its variants and blurhash, and deletes
+// the upload. Images that cannot be decoded, or would decode to more
+// pixels than allowed, are marked failed.
+func (s *mediaService) processMedia(ctx context.Context, id int64) error {
+	media, err := s.mediaRepo.ClaimMediaProcessing(ctx, id, time.Now().Add(-processingTimeout))
+	if err != nil {
+		return err
+	}
+	// processed already, or claimed by another worker
+	if media == nil {
+		return nil
+	}
+
+	file, err := s.store.Get(ctx, media.BlobKey)
+	if err != nil {
+		return err
+	}
+	maxImageSize, _, _, _ := s.cfg.Media()
+	data, err := io.ReadAll(io.LimitReader(file, maxImageSize))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	_, maxPixels, _ := s.cfg.MediaProcessing()
+	img, format, err := imageproc.Decode(data, maxPixels)
+	if err != nil {
+		return s.mediaRepo.FailMediaProcessing(ctx, id)
+	}
+
+	// keys get a fresh suffix so a worker that lost its claim never
+	// overwrites the files of the one that finished
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(media.BlobKey, path.Ext(media.BlobKey)) + "_" + hex.EncodeToString(suffix)
+
+	var written []string
+	put := func(key string, data []byte) error {
+		if err := s.store.Put(ctx, key, bytes.NewReader(data)); err != nil {
+			return err
+		}
+		written = append(written, key)
+		return nil
+	}
+	cleanup := func() {
+		for _, key := range written {
+			s.store.Delete(ctx, key)
+		}
+	}
+
+	processed := *media
+	processed.Width, processed.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	var original []byte
+	if format == "gif" {
+		// re-encoding would keep only the first frame
+		original, err = imageproc.StripGIF(data)
+	} else {
+		original, _, err = encodeImage(img, format, originalJPEGQuality)
+	}
+	if err != nil {
+		return s.mediaRepo.FailMediaProcessing(ctx, id)
+	}
+	processed.BlobKey = base + path.Ext(media.BlobKey)
+	processed.SizeBytes = int64(len(original))
+	if err := put(processed.BlobKey, original); err != nil {
+		cleanup()
+		return err
+	}
+
+	src := img
+	for _, variant := range mediaVariants {
+		if processed.Width <= variant.maxSide && processed.Height <= variant.maxSide {
+			continue
+		}
+		w, h := imageproc.Fit(processed.Width, processed.Height, variant.maxSide)
+		resized := imageproc.Resize(src, w, h)
+		src = resized
+
+		encoded, contentType, err := encodeImage(resized, format, variantJPEGQuality)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		key := base + "_" + variant.name + contentTypeExt(contentType)
+		if err := put(key, encoded); err != nil {
+			cleanup()
+			return err
+		}
+		processed.Variants = append(processed.Variants, model.MediaVariantModel{
+			MediaID:     id,
+			Name:        variant.name,
+			BlobKey:     key,
+			ContentType: contentType,
+			Width:       w,
+			Height:      h,
+			SizeBytes:   int64(len(encoded)),
+		})
+	}
+
+	w, h := imageproc.Fit(processed.Width, processed.Height, blurhashSide)
+	processed.Blurhash, err = blurhash.Encode(imageproc.Resize(src, w, h), 4, 3)
+	if err != nil {
+		cleanup()
+		return err
+	}
+
+	completed, err := s.mediaRepo.CompleteMediaProcessing(ctx, &processed)
+	if err != nil {
+		cleanup()
+		return err
+	}
+	// deleted meanwhile, or another worker finished first
+	if !completed {
+		cleanup()
+		return nil
+	}
+	return s.store.Delete(ctx, media.BlobKey)
+}
+
+// encodeImage encodes JPEGs as JPEG and everything else as PNG, returning
+// the content type written.
+func encodeImage(img image.Image, format string, quality int) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+func contentTypeExt(contentType string) string {
+	return allowedMediaTypes[contentType]
+}
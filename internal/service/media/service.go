@@ -0,0 +1,40 @@
+package media
+
+import (
+	"context"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/internal/repository/media"
+	"go-twitter/pkg/blobstore"
+	"io"
+)
+
+// processingQueueSize bounds how many uploads wait for a processing
+// worker; when it is full, uploads stay pending until QueuePendingMedia
+// picks them up.
+const processingQueueSize = 100
+
+type MediaService interface {
+	UploadMedia(ctx context.Context, userID int64, file io.Reader, size int64, req dto.UploadMediaRequest) (*dto.MediaResponse, int, error)
+	OpenMedia(ctx context.Context, mediaID int64, variant string) (io.ReadCloser, *model.MediaModel, int, error)
+	CollectOrphanedMedia(ctx context.Context) (int, error)
+	StartProcessing(ctx context.Context, onError func(error))
+	QueuePendingMedia(ctx context.Context) (int, error)
+}
+
+type mediaService struct {
+	cfg       *config.Config
+	mediaRepo media.MediaRepository
+	store     blobstore.Store
+	queue     chan int64
+}
+
+func NewService(cfg *config.Config, mediaRepo media.MediaRepository, store blobstore.Store) MediaService {
+	return &mediaService{
+		cfg:       cfg,
+		mediaRepo: mediaRepo,
+		store:     store,
+		queue:     make(chan int64, processingQueueSize),
+	}
+}
@@ -0,0 +1,100 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"io"
+	"net/http"
+	"time"
+)
+
+// allowedMediaTypes maps the content types uploads may have to their file
+// extension. The type is sniffed from the content, never taken from the
+// client. Images must be ones the processing workers can decode and
+// re-encode without their metadata.
+var allowedMediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"video/mp4":  ".mp4",
+}
+
+// sniffLength is how much of the file http.DetectContentType looks at.
+const sniffLength = 512
+
+func (s *mediaService) UploadMedia(ctx context.Context, userID int64, file io.Reader, size int64, req dto.UploadMediaRequest) (*dto.MediaResponse, int, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		if err == io.EOF {
+			return nil, http.StatusBadRequest, errors.New("file is empty")
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	ext, ok := allowedMediaTypes[contentType]
+	if !ok {
+		return nil, http.StatusUnsupportedMediaType, fmt.Errorf("unsupported media type %s", contentType)
+	}
+
+	maxImageSize, maxVideoSize, _, _ := s.cfg.Media()
+	maxSize := maxImageSize
+	if contentType == "video/mp4" {
+		maxSize = maxVideoSize
+	}
+	if size > maxSize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("file is larger than %d MB", maxSize>>20)
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	key := fmt.Sprintf("media/%d/%s%s", userID, hex.EncodeToString(suffix), ext)
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), file), maxSize)
+	if err := s.store.Put(ctx, key, content); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// videos are served as uploaded; images wait for a processing worker
+	status := model.MediaPending
+	if contentType == "video/mp4" {
+		status = model.MediaReady
+	}
+	media := &model.MediaModel{
+		UserID:           sql.NullInt64{Int64: userID, Valid: true},
+		BlobKey:          key,
+		ContentType:      contentType,
+		SizeBytes:        size,
+		AltText:          req.AltText,
+		ProcessingStatus: status,
+		CreatedAt:        time.Now(),
+	}
+	media.ID, err = s.mediaRepo.CreateMedia(ctx, media)
+	if err != nil {
+		s.store.Delete(ctx, key)
+		return nil, http.StatusInternalServerError, err
+	}
+	if status == model.MediaPending {
+		s.enqueue(media.ID)
+	}
+
+	return &dto.MediaResponse{
+		ID:               media.ID,
+		URL:              fmt.Sprintf("/media/%d", media.ID),
+		ContentType:      media.ContentType,
+		SizeBytes:        media.SizeBytes,
+		AltText:          media.AltText,
+		ProcessingStatus: media.ProcessingStatus,
+		Variants:         []dto.MediaVariantResponse{},
+	}, http.StatusCreated, nil
+}
@@ -0,0 +1,134 @@
+package oauth
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const authorizationCodeTTL = 10 * time.Minute
+
+// GetAuthorization validates an authorization request and returns what the
+// user is asked to consent to.
+func (s *oauthService) GetAuthorization(ctx context.Context, req dto.AuthorizeRequest) (*dto.AuthorizeConsentResponse, int, error) {
+	client, redirectURI, scopes, status, err := s.validateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return &dto.AuthorizeConsentResponse{
+		ClientID:    client.ClientID,
+		ClientName:  client.Name,
+		RedirectURI: redirectURI,
+		Scopes:      scopes,
+		State:       req.State,
+	}, http.StatusOK, nil
+}
+
+// Authorize records the user's consent decision and returns where to send
+// the user agent: the client's redirect URI with either a code or an error.
+func (s *oauthService) Authorize(ctx context.Context, userID int64, req dto.AuthorizeDecisionRequest) (*dto.AuthorizeDecisionResponse, int, error) {
+	client, redirectURI, scopes, status, err := s.validateAuthorizeRequest(ctx, req.AuthorizeRequest)
+	if err != nil {
+		return nil, status, err
+	}
+
+	params := url.Values{}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	if !req.Approved {
+		params.Set("error", ErrAccessDenied)
+		return &dto.AuthorizeDecisionResponse{RedirectTo: withQuery(redirectURI, params)}, http.StatusOK, nil
+	}
+
+	code, err := randomToken(32)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	now := time.Now()
+	err = s.oauthRepo.CreateAuthorizationCode(ctx, &model.OAuthAuthorizationCodeModel{
+		CodeHash:      hashToken(code),
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   redirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     now.Add(authorizationCodeTTL),
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	params.Set("code", code)
+	return &dto.AuthorizeDecisionResponse{RedirectTo: withQuery(redirectURI, params)}, http.StatusOK, nil
+}
+
+// validateAuthorizeRequest resolves the client, redirect URI and requested
+// scopes. PKCE with S256 is mandatory for every client.
+func (s *oauthService) validateAuthorizeRequest(ctx context.Context, req dto.AuthorizeRequest) (*model.OAuthClientModel, string, []string, int, error) {
+	if req.ResponseType != "code" {
+		return nil, "", nil, http.StatusBadRequest, newError(ErrUnsupportedResponseType, "only the code response type is supported")
+	}
+
+	client, err := s.oauthRepo.GetClientByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, "", nil, http.StatusInternalServerError, err
+	}
+	if client == nil {
+		return nil, "", nil, http.StatusBadRequest, newError(ErrInvalidClient, "unknown client")
+	}
+
+	redirectURI := req.RedirectURI
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+	if !containsString(client.RedirectURIs, redirectURI) {
+		return nil, "", nil, http.StatusBadRequest, newError(ErrInvalidRequest, "redirect_uri is not registered for this client")
+	}
+
+	if req.CodeChallengeMethod != "S256" || len(req.CodeChallenge) < 43 || len(req.CodeChallenge) > 128 {
+		return nil, "", nil, http.StatusBadRequest, newError(ErrInvalidRequest, "a code_challenge with code_challenge_method S256 is required")
+	}
+
+	scopes := model.UniqueScopes(strings.Fields(req.Scope))
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !model.HasScope(client.Scopes, scope) {
+			return nil, "", nil, http.StatusBadRequest, newError(ErrInvalidScope, "scope "+scope+" is not allowed for this client")
+		}
+	}
+
+	return client, redirectURI, scopes, http.StatusOK, nil
+}
+
+func withQuery(rawURL string, params url.Values) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := parsed.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
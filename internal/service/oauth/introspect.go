@@ -0,0 +1,80 @@
+package oauth
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/pkg/jwt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Introspect implements RFC 7662 for confidential clients. Tokens issued to
+// other clients are reported as inactive. token_type_hint is ignored; both
+// token types are tried.
+func (s *oauthService) Introspect(ctx context.Context, creds dto.OAuthClientCredentials, req dto.TokenActionRequest) (*dto.IntrospectionResponse, int, error) {
+	client, status, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return nil, status, err
+	}
+	if !client.IsConfidential() {
+		return nil, http.StatusUnauthorized, newError(ErrInvalidClient, "only confidential clients may introspect tokens")
+	}
+
+	inactive := &dto.IntrospectionResponse{Active: false}
+
+	if claims, err := jwt.ParseToken(req.Token, s.cfg.JWTOptions()); err == nil {
+		if claims.ClientID != client.ClientID {
+			return inactive, http.StatusOK, nil
+		}
+		revoked, err := s.oauthRepo.IsAccessTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if revoked {
+			return inactive, http.StatusOK, nil
+		}
+
+		return &dto.IntrospectionResponse{
+			Active:    true,
+			Scope:     claims.Scope,
+			ClientID:  claims.ClientID,
+			Username:  claims.Username,
+			TokenType: "Bearer",
+			Exp:       claims.ExpiresAt.Unix(),
+			Iat:       claims.IssuedAt.Unix(),
+			Sub:       claims.Subject,
+			Iss:       claims.Issuer,
+			Aud:       strings.Join(claims.Audience, " "),
+			Jti:       claims.ID,
+		}, http.StatusOK, nil
+	}
+
+	token, err := s.oauthRepo.GetRefreshTokenByHash(ctx, hashToken(req.Token))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if token == nil || token.ClientID != client.ClientID || token.RevokedAt.Valid || !time.Now().Before(token.ExpiresAt) {
+		return inactive, http.StatusOK, nil
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		return inactive, http.StatusOK, nil
+	}
+
+	return &dto.IntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(token.Scopes, " "),
+		ClientID:  token.ClientID,
+		Username:  user.Username,
+		TokenType: "refresh_token",
+		Exp:       token.ExpiresAt.Unix(),
+		Iat:       token.CreatedAt.Unix(),
+		Sub:       strconv.FormatInt(token.UserID, 10),
+	}, http.StatusOK, nil
+}
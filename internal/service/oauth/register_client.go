@@ -0,0 +1,56 @@
+package oauth
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+func (s *oauthService) RegisterClient(ctx context.Context, userID int64, req dto.RegisterOAuthClientRequest) (*dto.OAuthClientResponse, int, error) {
+	for _, redirectURI := range req.RedirectURIs {
+		parsed, err := url.Parse(redirectURI)
+		if err != nil || parsed.Fragment != "" || (parsed.Scheme != "https" && parsed.Hostname() != "localhost" && parsed.Hostname() != "127.0.0.1") {
+			return nil, http.StatusBadRequest, newError(ErrInvalidRequest, "redirect URIs must use https, except for localhost, and must not contain a fragment")
+		}
+	}
+
+	clientID, err := randomToken(16)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	client := &model.OAuthClientModel{
+		ClientID:     clientID,
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Scopes:       model.UniqueScopes(req.Scopes),
+		OwnerUserID:  userID,
+		CreatedAt:    time.Now(),
+	}
+
+	var clientSecret string
+	if req.Confidential {
+		clientSecret, err = randomToken(32)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		client.ClientSecretHash = hashToken(clientSecret)
+	}
+
+	client.ID, err = s.oauthRepo.CreateClient(ctx, client)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.OAuthClientResponse{
+		ClientID:     client.ClientID,
+		ClientSecret: clientSecret,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIs,
+		Scopes:       client.Scopes,
+		CreatedAt:    client.CreatedAt.Format("2006-01-02 15:04:05"),
+	}, http.StatusCreated, nil
+}
@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/pkg/jwt"
+	"net/http"
+	"time"
+)
+
+// Revoke implements RFC 7009. Unknown tokens and tokens belonging to other
+// clients are ignored and still answered with 200, as the RFC requires.
+func (s *oauthService) Revoke(ctx context.Context, creds dto.OAuthClientCredentials, req dto.TokenActionRequest) (int, error) {
+	client, status, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return status, err
+	}
+
+	if claims, err := jwt.ParseToken(req.Token, s.cfg.JWTOptions()); err == nil {
+		if claims.ClientID != client.ClientID {
+			return http.StatusOK, nil
+		}
+		if err := s.oauthRepo.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		return http.StatusOK, nil
+	}
+
+	token, err := s.oauthRepo.GetRefreshTokenByHash(ctx, hashToken(req.Token))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if token == nil || token.ClientID != client.ClientID {
+		return http.StatusOK, nil
+	}
+
+	if _, err := s.oauthRepo.RevokeRefreshToken(ctx, token.ID, time.Now()); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,40 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// randomToken returns n random bytes, hex-encoded. It is used for client
+// IDs, client secrets, authorization codes and refresh tokens.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken is how secrets, codes and refresh tokens are stored at rest.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func secretMatches(secret, secretHash string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(secretHash)) == 1
+}
+
+// verifyCodeChallenge checks a PKCE code_verifier against the S256
+// code_challenge stored with the authorization code (RFC 7636 section 4.6).
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
@@ -0,0 +1,34 @@
+package oauth
+
+import (
+	"context"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/repository/oauth"
+	"go-twitter/internal/repository/user"
+)
+
+type OAuthService interface {
+	RegisterClient(ctx context.Context, userID int64, req dto.RegisterOAuthClientRequest) (*dto.OAuthClientResponse, int, error)
+
+	GetAuthorization(ctx context.Context, req dto.AuthorizeRequest) (*dto.AuthorizeConsentResponse, int, error)
+	Authorize(ctx context.Context, userID int64, req dto.AuthorizeDecisionRequest) (*dto.AuthorizeDecisionResponse, int, error)
+
+	Token(ctx context.Context, creds dto.OAuthClientCredentials, req dto.TokenRequest) (*dto.TokenResponse, int, error)
+	Introspect(ctx context.Context, creds dto.OAuthClientCredentials, req dto.TokenActionRequest) (*dto.IntrospectionResponse, int, error)
+	Revoke(ctx context.Context, creds dto.OAuthClientCredentials, req dto.TokenActionRequest) (int, error)
+}
+
+type oauthService struct {
+	cfg       *config.Config
+	oauthRepo oauth.OAuthRepository
+	userRepo  user.UserRepository
+}
+
+func NewService(cfg *config.Config, oauthRepo oauth.OAuthRepository, userRepo user.UserRepository) OAuthService {
+	return &oauthService{
+		cfg:       cfg,
+		oauthRepo: oauthRepo,
+		userRepo:  userRepo,
+	}
+}
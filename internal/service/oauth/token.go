@@ -0,0 +1,158 @@
+package oauth
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/jwt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+)
+
+func (s *oauthService) Token(ctx context.Context, creds dto.OAuthClientCredentials, req dto.TokenRequest) (*dto.TokenResponse, int, error) {
+	client, status, err := s.authenticateClient(ctx, creds)
+	if err != nil {
+		return nil, status, err
+	}
+
+	switch req.GrantType {
+	case GrantTypeAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, client, req)
+	case GrantTypeRefreshToken:
+		return s.exchangeRefreshToken(ctx, client, req)
+	default:
+		return nil, http.StatusBadRequest, newError(ErrUnsupportedGrantType, "grant_type must be authorization_code or refresh_token")
+	}
+}
+
+func (s *oauthService) exchangeAuthorizationCode(ctx context.Context, client *model.OAuthClientModel, req dto.TokenRequest) (*dto.TokenResponse, int, error) {
+	code, err := s.oauthRepo.ConsumeAuthorizationCode(ctx, hashToken(req.Code), time.Now())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if code == nil || code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI {
+		return nil, http.StatusBadRequest, newError(ErrInvalidGrant, "authorization code is invalid, expired or already used")
+	}
+	if !verifyCodeChallenge(req.CodeVerifier, code.CodeChallenge) {
+		return nil, http.StatusBadRequest, newError(ErrInvalidGrant, "code_verifier does not match the code_challenge")
+	}
+
+	return s.issueTokens(ctx, client, code.UserID, code.Scopes, code.Scopes)
+}
+
+// exchangeRefreshToken rotates the refresh token: the presented token is
+// revoked and a new one is returned with the same scopes. The access token
+// may be narrowed with the scope parameter.
+func (s *oauthService) exchangeRefreshToken(ctx context.Context, client *model.OAuthClientModel, req dto.TokenRequest) (*dto.TokenResponse, int, error) {
+	now := time.Now()
+	token, err := s.oauthRepo.GetRefreshTokenByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if token == nil || token.ClientID != client.ClientID || token.RevokedAt.Valid || !now.Before(token.ExpiresAt) {
+		return nil, http.StatusBadRequest, newError(ErrInvalidGrant, "refresh token is invalid, expired or revoked")
+	}
+
+	accessScopes := token.Scopes
+	if requested := model.UniqueScopes(strings.Fields(req.Scope)); len(requested) > 0 {
+		for _, scope := range requested {
+			if !model.HasScope(token.Scopes, scope) {
+				return nil, http.StatusBadRequest, newError(ErrInvalidScope, "scope "+scope+" was not granted")
+			}
+		}
+		accessScopes = requested
+	}
+
+	revoked, err := s.oauthRepo.RevokeRefreshToken(ctx, token.ID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !revoked {
+		return nil, http.StatusBadRequest, newError(ErrInvalidGrant, "refresh token is invalid, expired or revoked")
+	}
+
+	return s.issueTokens(ctx, client, token.UserID, accessScopes, token.Scopes)
+}
+
+func (s *oauthService) issueTokens(ctx context.Context, client *model.OAuthClientModel, userID int64, accessScopes, refreshScopes []string) (*dto.TokenResponse, int, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		return nil, http.StatusBadRequest, newError(ErrInvalidGrant, "the user no longer exists")
+	}
+	if user.DeactivatedAt.Valid {
+		return nil, http.StatusBadRequest, newError(ErrInvalidGrant, "the user's account is deactivated")
+	}
+
+	opts := s.cfg.JWTOptions()
+	accessToken, err := jwt.CreateOAuthToken(user.ID, user.Username, client.ClientID, accessScopes, opts)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	now := time.Now()
+	err = s.oauthRepo.CreateRefreshToken(ctx, &model.OAuthRefreshTokenModel{
+		TokenHash: hashToken(refreshToken),
+		ClientID:  client.ClientID,
+		UserID:    user.ID,
+		Scopes:    refreshScopes,
+		ExpiresAt: now.Add(s.cfg.RefreshTokenExpiry()),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = jwt.DefaultTTL
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(ttl.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        strings.Join(accessScopes, " "),
+	}, http.StatusOK, nil
+}
+
+// authenticateClient checks the client secret of confidential clients.
+// Public clients identify themselves by client_id alone and must not send a
+// secret.
+func (s *oauthService) authenticateClient(ctx context.Context, creds dto.OAuthClientCredentials) (*model.OAuthClientModel, int, error) {
+	if creds.ClientID == "" {
+		return nil, http.StatusUnauthorized, newError(ErrInvalidClient, "client authentication failed")
+	}
+
+	client, err := s.oauthRepo.GetClientByClientID(ctx, creds.ClientID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if client == nil {
+		return nil, http.StatusUnauthorized, newError(ErrInvalidClient, "client authentication failed")
+	}
+
+	if client.IsConfidential() {
+		if !secretMatches(creds.ClientSecret, client.ClientSecretHash) {
+			return nil, http.StatusUnauthorized, newError(ErrInvalidClient, "client authentication failed")
+		}
+	} else if creds.ClientSecret != "" {
+		return nil, http.StatusUnauthorized, newError(ErrInvalidClient, "client authentication failed")
+	}
+
+	return client, http.StatusOK, nil
+}
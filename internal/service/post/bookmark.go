@@ -0,0 +1,105 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"strconv"
+)
+
+func (s *postService) BookmarkPost(ctx context.Context, userID, postID int64, req dto.BookmarkPostRequest) (int, error) {
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if post == nil || post.Status != model.PostPublished {
+		return http.StatusNotFound, errors.New("post not found")
+	}
+
+	var folderID sql.NullInt64
+	if req.FolderID != nil {
+		folder, err := s.postRepo.GetBookmarkFolderByID(ctx, *req.FolderID)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		// other users' folders are reported as missing, not forbidden
+		if folder == nil || folder.UserID != userID {
+			return http.StatusNotFound, errors.New("bookmark folder not found")
+		}
+		folderID = sql.NullInt64{Int64: folder.ID, Valid: true}
+	}
+
+	created, err := s.postRepo.SaveBookmark(ctx, userID, postID, folderID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !created {
+		return http.StatusOK, nil
+	}
+	return http.StatusCreated, nil
+}
+
+func (s *postService) RemoveBookmark(ctx context.Context, userID, postID int64) (int, error) {
+	deleted, err := s.postRepo.DeleteBookmark(ctx, userID, postID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !deleted {
+		return http.StatusNotFound, errors.New("post is not bookmarked")
+	}
+	return http.StatusOK, nil
+}
+
+// GetBookmarks returns a page of the user's bookmarked posts, all of them
+// or those in folderID when it is not zero. cursor is the next_cursor of
+// the previous page, or empty for the first.
+func (s *postService) GetBookmarks(ctx context.Context, userID, folderID int64, cursor string, limit int) (*dto.BookmarksResponse, int, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var beforeID int64
+	if cursor != "" {
+		var err error
+		beforeID, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil || beforeID < 1 {
+			return nil, http.StatusBadRequest, errors.New("invalid cursor")
+		}
+	}
+
+	var folder sql.NullInt64
+	if folderID != 0 {
+		found, err := s.postRepo.GetBookmarkFolderByID(ctx, folderID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if found == nil || found.UserID != userID {
+			return nil, http.StatusNotFound, errors.New("bookmark folder not found")
+		}
+		folder = sql.NullInt64{Int64: folderID, Valid: true}
+	}
+
+	// one extra row tells whether there is another page
+	bookmarks, posts, usernames, err := s.postRepo.GetBookmarks(ctx, userID, folder, beforeID, limit+1)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	response := &dto.BookmarksResponse{Posts: []dto.PostResponse{}}
+	if len(bookmarks) > limit {
+		bookmarks, posts, usernames = bookmarks[:limit], posts[:limit], usernames[:limit]
+		response.NextCursor = strconv.FormatInt(bookmarks[limit-1].ID, 10)
+	}
+
+	postResponses, err := s.toPostResponses(ctx, posts, usernames, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if postResponses != nil {
+		response.Posts = postResponses
+	}
+	return response, http.StatusOK, nil
+}
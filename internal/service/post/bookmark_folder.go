@@ -0,0 +1,70 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"strings"
+)
+
+func (s *postService) CreateBookmarkFolder(ctx context.Context, userID int64, req dto.CreateBookmarkFolderRequest) (*dto.BookmarkFolderResponse, int, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, http.StatusBadRequest, errors.New("folder name is required")
+	}
+
+	folder := &model.BookmarkFolderModel{UserID: userID, Name: name}
+	id, created, err := s.postRepo.CreateBookmarkFolder(ctx, folder)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !created {
+		return nil, http.StatusConflict, errors.New("a bookmark folder with this name already exists")
+	}
+
+	folder, err = s.postRepo.GetBookmarkFolderByID(ctx, id)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if folder == nil {
+		return nil, http.StatusNotFound, errors.New("bookmark folder not found")
+	}
+	response := toBookmarkFolderResponse(folder)
+	return &response, http.StatusCreated, nil
+}
+
+func (s *postService) GetBookmarkFolders(ctx context.Context, userID int64) ([]dto.BookmarkFolderResponse, int, error) {
+	folders, err := s.postRepo.GetBookmarkFolders(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	responses := []dto.BookmarkFolderResponse{}
+	for _, folder := range folders {
+		responses = append(responses, toBookmarkFolderResponse(folder))
+	}
+	return responses, http.StatusOK, nil
+}
+
+// DeleteBookmarkFolder deletes the folder; its bookmarks are kept outside
+// any folder.
+func (s *postService) DeleteBookmarkFolder(ctx context.Context, userID, folderID int64) (int, error) {
+	deleted, err := s.postRepo.DeleteBookmarkFolder(ctx, userID, folderID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !deleted {
+		return http.StatusNotFound, errors.New("bookmark folder not found")
+	}
+	return http.StatusOK, nil
+}
+
+func toBookmarkFolderResponse(folder *model.BookmarkFolderModel) dto.BookmarkFolderResponse {
+	return dto.BookmarkFolderResponse{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		CreatedAt: folder.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
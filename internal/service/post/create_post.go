@@ -2,6 +2,7 @@ package post
 
 import (
 	"context"
+	"errors"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
 	"net/http"
@@ -9,12 +10,48 @@ import (
 )
 
 func (s *postService) CreatePost(ctx context.Context, userID int64, req dto.CreatePostRequest) (int64, int, error) {
+	status, publishAt, err := resolveSchedule(req.Status, req.PublishAt, time.Now())
+	if err != nil {
+		return 0, http.StatusBadRequest, err
+	}
 	post := &model.PostModel{
-		UserID:    userID,
-		Title:     req.Title,
-		Content:   req.Content,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		UserID:      userID,
+		Title:       req.Title,
+		Content:     req.Content,
+		Status:      status,
+		PublishAt:   publishAt,
+		ReplyPolicy: resolveReplyPolicy(req.ReplyPolicy),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	if req.Poll != nil {
+		if len(req.MediaIDs) > 0 {
+			return 0, http.StatusBadRequest, errors.New("a post cannot have both media and a poll")
+		}
+		poll := &model.PollModel{
+			MultipleChoice: req.Poll.MultipleChoice,
+			ClosesAt:       time.Now().Add(time.Duration(req.Poll.DurationMinutes) * time.Minute),
+		}
+		for _, text := range req.Poll.Options {
+			poll.Options = append(poll.Options, model.PollOptionModel{Text: text})
+		}
+		id, err := s.postRepo.CreatePostWithPoll(ctx, post, poll)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		return id, http.StatusCreated, nil
+	}
+
+	if len(req.MediaIDs) > 0 {
+		id, created, err := s.postRepo.CreatePostWithMedia(ctx, post, req.MediaIDs)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		if !created {
+			return 0, http.StatusBadRequest, errors.New("media not found or already attached to a post")
+		}
+		return id, http.StatusCreated, nil
 	}
 
 	id, err := s.postRepo.CreatePost(ctx, post)
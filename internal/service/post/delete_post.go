@@ -2,24 +2,38 @@ package post
 
 import (
 	"context"
+	"go-twitter/internal/model"
 	"net/http"
 )
 
-func (s *postService) DeletePost(ctx context.Context, userID, postID int64) (int, error) {
+func (s *postService) DeletePost(ctx context.Context, userID, postID int64, roles []string) (int, error) {
 	existingPost, err := s.postRepo.GetPostByID(ctx, postID)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	if existingPost == nil {
+	if existingPost == nil || !visibleTo(existingPost, userID) {
 		return http.StatusNotFound, nil
 	}
 
-	if existingPost.UserID != userID {
+	moderating := existingPost.UserID != userID
+	if moderating && !model.HasPermission(roles, model.PermissionModeratePosts) {
 		return http.StatusForbidden, nil
 	}
 
-	err = s.postRepo.DeletePost(ctx, postID)
+	// record moderator actions on other users' content
+	var auditLog *model.AuditLogModel
+	if moderating {
+		auditLog = &model.AuditLogModel{
+			ActorID:      userID,
+			TargetUserID: existingPost.UserID,
+			Action:       model.AuditActionDeletePost,
+			EntityType:   "post",
+			EntityID:     postID,
+		}
+	}
+
+	err = s.postRepo.DeletePost(ctx, postID, userID, auditLog)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
@@ -0,0 +1,37 @@
+package post
+
+import (
+	"context"
+	"time"
+)
+
+const expiredPollsBatch = 100
+
+// FinalizeExpiredPolls freezes the results of polls that have closed. It
+// returns how many were finalized.
+func (s *postService) FinalizeExpiredPolls(ctx context.Context) (int, error) {
+	finalized := 0
+	for {
+		now := time.Now()
+		postIDs, err := s.postRepo.GetExpiredPolls(ctx, now, expiredPollsBatch)
+		if err != nil {
+			return finalized, err
+		}
+		batchFinalized := 0
+		for _, postID := range postIDs {
+			done, err := s.postRepo.FinalizePoll(ctx, postID, now)
+			if err != nil {
+				return finalized, err
+			}
+			if done {
+				batchFinalized++
+			}
+		}
+		finalized += batchFinalized
+		// stop rather than fetch the same batch again if another server
+		// finalized it meanwhile
+		if len(postIDs) < expiredPollsBatch || batchFinalized == 0 {
+			return finalized, nil
+		}
+	}
+}
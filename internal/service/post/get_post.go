@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
 	"net/http"
 )
 
-func (s *postService) GetPostByID(ctx context.Context, id int64) (*dto.PostResponse, int, error) {
+// GetPostByID returns the post as the viewer sees it; viewerID is zero for
+// anonymous requests.
+func (s *postService) GetPostByID(ctx context.Context, id, viewerID int64) (*dto.PostResponse, int, error) {
 	post, username, err := s.postRepo.GetPostWithUserInfo(ctx, id)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
@@ -17,33 +20,16 @@ func (s *postService) GetPostByID(ctx context.Context, id int64) (*dto.PostRespo
 		return nil, http.StatusNotFound, nil
 	}
 
-	likesCount, err := s.getPostLikesCount(ctx, id)
+	responses, err := s.toPostResponses(ctx, []*model.PostModel{post}, []string{username}, viewerID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
 
-	commentsCount, err := s.getPostCommentsCount(ctx, id)
-	if err != nil {
-		return nil, http.StatusInternalServerError, err
-	}
-
-	response := &dto.PostResponse{
-		ID:            post.ID,
-		UserID:        post.UserID,
-		Username:      username,
-		Title:         post.Title,
-		Content:       post.Content,
-		LikesCount:    likesCount,
-		CommentsCount: commentsCount,
-		CreatedAt:     post.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:     post.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}
-
-	return response, http.StatusOK, nil
+	return &responses[0], http.StatusOK, nil
 }
 
 func (s *postService) getPostLikesCount(ctx context.Context, postID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM post_likes WHERE post_id = ?`
+	query := `SELECT COUNT(*) FROM post_likes l JOIN users u ON l.user_id = u.id WHERE l.post_id = ? AND u.deactivated_at IS NULL`
 	var count int
 	err := s.db.QueryRowContext(ctx, query, postID).Scan(&count)
 	if err != nil {
@@ -56,7 +42,7 @@ func (s *postService) getPostLikesCount(ctx context.Context, postID int64) (int,
 }
 
 func (s *postService) getPostCommentsCount(ctx context.Context, postID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM comments WHERE post_id = ? AND deleted_at IS NULL`
+	query := `SELECT COUNT(*) FROM comments c JOIN users u ON c.user_id = u.id WHERE c.post_id = ? AND c.deleted_at IS NULL AND u.deactivated_at IS NULL`
 	var count int
 	err := s.db.QueryRowContext(ctx, query, postID).Scan(&count)
 	if err != nil {
@@ -7,7 +7,7 @@ import (
 	"net/http"
 )
 
-func (s *postService) GetPosts(ctx context.Context, page, pageSize int) (*dto.PostsResponse, int, error) {
+func (s *postService) GetPosts(ctx context.Context, viewerID int64, page, pageSize int) (*dto.PostsResponse, int, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -27,29 +27,9 @@ func (s *postService) GetPosts(ctx context.Context, page, pageSize int) (*dto.Po
 		return nil, http.StatusInternalServerError, err
 	}
 
-	var postResponses []dto.PostResponse
-	for i, post := range posts {
-		likesCount, err := s.getPostLikesCount(ctx, post.ID)
-		if err != nil {
-			return nil, http.StatusInternalServerError, err
-		}
-
-		commentsCount, err := s.getPostCommentsCount(ctx, post.ID)
-		if err != nil {
-			return nil, http.StatusInternalServerError, err
-		}
-
-		postResponses = append(postResponses, dto.PostResponse{
-			ID:            post.ID,
-			UserID:        post.UserID,
-			Username:      usernames[i],
-			Title:         post.Title,
-			Content:       post.Content,
-			LikesCount:    likesCount,
-			CommentsCount: commentsCount,
-			CreatedAt:     post.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:     post.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
+	postResponses, err := s.toPostResponses(ctx, posts, usernames, viewerID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
@@ -65,7 +45,7 @@ func (s *postService) GetPosts(ctx context.Context, page, pageSize int) (*dto.Po
 	return response, http.StatusOK, nil
 }
 
-func (s *postService) GetPostsByUserID(ctx context.Context, userID int64, page, pageSize int) (*dto.PostsResponse, int, error) {
+func (s *postService) GetPostsByUserID(ctx context.Context, userID, viewerID int64, page, pageSize int) (*dto.PostsResponse, int, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -80,38 +60,28 @@ func (s *postService) GetPostsByUserID(ctx context.Context, userID int64, page,
 		return nil, http.StatusInternalServerError, err
 	}
 
-	totalCount, err := s.postRepo.GetPostsCount(ctx)
+	totalCount, err := s.postRepo.GetPostsCountByUserID(ctx, userID)
 	if err != nil {
 		return nil, http.StatusInternalServerError, err
 	}
 
-	var postResponses []dto.PostResponse
-	for _, post := range posts {
-		likesCount, err := s.getPostLikesCount(ctx, post.ID)
-		if err != nil {
-			return nil, http.StatusInternalServerError, err
-		}
+	postResponses, err := s.toPostResponses(ctx, posts, nil, viewerID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
 
-		commentsCount, err := s.getPostCommentsCount(ctx, post.ID)
+	var pinnedPost *dto.PostResponse
+	if page == 1 {
+		pinnedPost, err = s.getPinnedPostResponse(ctx, userID, viewerID)
 		if err != nil {
 			return nil, http.StatusInternalServerError, err
 		}
-
-		postResponses = append(postResponses, dto.PostResponse{
-			ID:            post.ID,
-			UserID:        post.UserID,
-			Title:         post.Title,
-			Content:       post.Content,
-			LikesCount:    likesCount,
-			CommentsCount: commentsCount,
-			CreatedAt:     post.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:     post.UpdatedAt.Format("2006-01-02 15:04:05"),
-		})
 	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
 
 	response := &dto.PostsResponse{
+		PinnedPost: pinnedPost,
 		Posts:      postResponses,
 		TotalCount: totalCount,
 		Page:       page,
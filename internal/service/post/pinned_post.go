@@ -0,0 +1,72 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+)
+
+// PinPost pins one of the user's published posts to their profile,
+// replacing the post pinned before.
+func (s *postService) PinPost(ctx context.Context, userID int64, req dto.PinPostRequest) (int, error) {
+	post, err := s.postRepo.GetPostByID(ctx, req.PostID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if post == nil || !visibleTo(post, userID) {
+		return http.StatusNotFound, errors.New("post not found")
+	}
+	if post.UserID != userID {
+		return http.StatusForbidden, errors.New("you can only pin your own posts")
+	}
+	if post.Status != model.PostPublished {
+		return http.StatusBadRequest, errors.New("only published posts can be pinned")
+	}
+
+	pinned, err := s.postRepo.PinPost(ctx, userID, req.PostID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	// deleted since it was read above
+	if !pinned {
+		return http.StatusNotFound, errors.New("post not found")
+	}
+	return http.StatusOK, nil
+}
+
+func (s *postService) UnpinPost(ctx context.Context, userID int64) (int, error) {
+	unpinned, err := s.postRepo.UnpinPost(ctx, userID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !unpinned {
+		return http.StatusNotFound, errors.New("no post is pinned")
+	}
+	return http.StatusOK, nil
+}
+
+// GetPinnedPost returns the post the user pinned to their profile as the
+// viewer sees it, or nil if there is none.
+func (s *postService) GetPinnedPost(ctx context.Context, userID, viewerID int64) (*dto.PostResponse, int, error) {
+	response, err := s.getPinnedPostResponse(ctx, userID, viewerID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
+
+// getPinnedPostResponse returns the post the user pinned as the viewer sees
+// it, or nil if there is none.
+func (s *postService) getPinnedPostResponse(ctx context.Context, userID, viewerID int64) (*dto.PostResponse, error) {
+	post, username, err := s.postRepo.GetPinnedPost(ctx, userID)
+	if err != nil || post == nil {
+		return nil, err
+	}
+	responses, err := s.toPostResponses(ctx, []*model.PostModel{post}, []string{username}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
@@ -0,0 +1,35 @@
+package post
+
+import (
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+)
+
+func toMediaResponses(media []*model.MediaModel) []dto.MediaResponse {
+	responses := []dto.MediaResponse{}
+	for _, m := range media {
+		variants := []dto.MediaVariantResponse{}
+		for _, v := range m.Variants {
+			variants = append(variants, dto.MediaVariantResponse{
+				Name:   v.Name,
+				URL:    fmt.Sprintf("/media/%d?variant=%s", m.ID, v.Name),
+				Width:  v.Width,
+				Height: v.Height,
+			})
+		}
+		responses = append(responses, dto.MediaResponse{
+			ID:               m.ID,
+			URL:              fmt.Sprintf("/media/%d", m.ID),
+			ContentType:      m.ContentType,
+			SizeBytes:        m.SizeBytes,
+			AltText:          m.AltText,
+			ProcessingStatus: m.ProcessingStatus,
+			Width:            m.Width,
+			Height:           m.Height,
+			Blurhash:         m.Blurhash,
+			Variants:         variants,
+		})
+	}
+	return responses
+}
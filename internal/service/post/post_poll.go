@@ -0,0 +1,66 @@
+package post
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"time"
+)
+
+// getPollResponses returns the polls of the posts as the viewer sees them;
+// viewerID is zero for anonymous requests.
+func (s *postService) getPollResponses(ctx context.Context, postIDs []int64, viewerID int64) (map[int64]*dto.PollResponse, error) {
+	polls, err := s.postRepo.GetPostPolls(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	votes := map[int64][]int64{}
+	if viewerID != 0 && len(polls) > 0 {
+		votes, err = s.postRepo.GetPollVotes(ctx, postIDs, viewerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	responses := make(map[int64]*dto.PollResponse, len(polls))
+	now := time.Now()
+	for postID, poll := range polls {
+		responses[postID] = toPollResponse(poll, votes[postID], now)
+	}
+	return responses, nil
+}
+
+// toPollResponse hides the counts until the viewer has voted or the poll
+// has closed, so early results don't sway the vote.
+func toPollResponse(poll *model.PollModel, votedOptionIDs []int64, now time.Time) *dto.PollResponse {
+	closed := pollClosed(poll, now)
+	showResults := closed || len(votedOptionIDs) > 0
+
+	response := &dto.PollResponse{
+		Options:        []dto.PollOptionResponse{},
+		MultipleChoice: poll.MultipleChoice,
+		ClosesAt:       poll.ClosesAt.Format("2006-01-02 15:04:05"),
+		Closed:         closed,
+		VotedOptionIDs: []int64{},
+	}
+	if votedOptionIDs != nil {
+		response.VotedOptionIDs = votedOptionIDs
+	}
+	if showResults {
+		totalVoters := poll.TotalVoters
+		response.TotalVoters = &totalVoters
+	}
+	for _, option := range poll.Options {
+		optionResponse := dto.PollOptionResponse{ID: option.ID, Text: option.Text}
+		if showResults {
+			votes := option.VoteCount
+			optionResponse.Votes = &votes
+		}
+		response.Options = append(response.Options, optionResponse)
+	}
+	return response
+}
+
+func pollClosed(poll *model.PollModel, now time.Time) bool {
+	return poll.FinalizedAt.Valid || !now.Before(poll.ClosesAt)
+}
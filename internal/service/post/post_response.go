@@ -0,0 +1,96 @@
+package post
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+)
+
+// toPostResponses builds the responses for the posts as the viewer sees
+// them; viewerID is zero for anonymous requests. usernames is parallel to
+// posts and may be nil.
+func (s *postService) toPostResponses(ctx context.Context, posts []*model.PostModel, usernames []string, viewerID int64) ([]dto.PostResponse, error) {
+	postIDs := make([]int64, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	media, err := s.postRepo.GetPostMedia(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	polls, err := s.getPollResponses(ctx, postIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarked := map[int64]bool{}
+	if viewerID != 0 {
+		bookmarked, err = s.postRepo.GetBookmarkedPostIDs(ctx, viewerID, postIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	viewerUsername, err := s.getViewerUsername(ctx, posts, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	followedAuthors, err := s.getFollowedAuthors(ctx, posts, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []dto.PostResponse
+	for i, post := range posts {
+		likesCount, err := s.getPostLikesCount(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		commentsCount, err := s.getPostCommentsCount(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var username string
+		if usernames != nil {
+			username = usernames[i]
+		}
+
+		var publishAt *string
+		if post.PublishAt.Valid {
+			formatted := post.PublishAt.Time.Format("2006-01-02 15:04:05")
+			publishAt = &formatted
+		}
+
+		// only published posts take comments
+		canReply := post.Status == model.PostPublished && post.CanReply(viewerID, viewerUsername, followedAuthors[post.UserID])
+
+		responses = append(responses, dto.PostResponse{
+			ID:            post.ID,
+			UserID:        post.UserID,
+			Username:      username,
+			Title:         post.Title,
+			Content:       post.Content,
+			LikesCount:    likesCount,
+			CommentsCount: commentsCount,
+			Media:         toMediaResponses(media[post.ID]),
+			Poll:          polls[post.ID],
+			Bookmarked:    bookmarked[post.ID],
+			Status:        post.Status,
+			PublishAt:     publishAt,
+			Edited:        post.EditCount > 0,
+			EditCount:     post.EditCount,
+			Version:       post.Version,
+			ReplyPolicy:   post.ReplyPolicy,
+			RepliesLocked: post.RepliesLocked,
+			CanReply:      canReply,
+			CreatedAt:     post.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:     post.UpdatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return responses, nil
+}
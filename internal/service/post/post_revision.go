@@ -0,0 +1,42 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"net/http"
+)
+
+// GetPostRevisions returns every version of a published post, oldest
+// first, ending with the current one.
+func (s *postService) GetPostRevisions(ctx context.Context, postID int64) (*dto.PostRevisionsResponse, int, error) {
+	post, _, err := s.postRepo.GetPostWithUserInfo(ctx, postID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if post == nil {
+		return nil, http.StatusNotFound, errors.New("post not found")
+	}
+
+	revisions, err := s.postRepo.GetPostRevisions(ctx, postID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	response := &dto.PostRevisionsResponse{}
+	for i, revision := range revisions {
+		response.Revisions = append(response.Revisions, dto.PostRevisionResponse{
+			Revision:  i + 1,
+			Title:     revision.Title,
+			Content:   revision.Content,
+			CreatedAt: revision.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	response.Revisions = append(response.Revisions, dto.PostRevisionResponse{
+		Revision:  len(revisions) + 1,
+		Title:     post.Title,
+		Content:   post.Content,
+		CreatedAt: post.UpdatedAt.Format("2006-01-02 15:04:05"),
+	})
+	return response, http.StatusOK, nil
+}
@@ -0,0 +1,94 @@
+package post
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"math"
+	"net/http"
+	"time"
+)
+
+const duePostsBatch = 100
+
+// resolveSchedule checks the requested status and publish time, returning
+// the status to store and its publish_at. An empty status publishes now.
+func resolveSchedule(status string, publishAt *time.Time, now time.Time) (string, sql.NullTime, error) {
+	switch status {
+	case model.PostScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return "", sql.NullTime{}, errors.New("scheduled posts need a publish_at in the future")
+		}
+		return model.PostScheduled, sql.NullTime{Time: *publishAt, Valid: true}, nil
+	case model.PostDraft:
+		if publishAt != nil {
+			return "", sql.NullTime{}, errors.New("publish_at is only allowed for scheduled posts")
+		}
+		return model.PostDraft, sql.NullTime{}, nil
+	default:
+		if publishAt != nil {
+			return "", sql.NullTime{}, errors.New("publish_at is only allowed for scheduled posts")
+		}
+		return model.PostPublished, sql.NullTime{Time: now, Valid: true}, nil
+	}
+}
+
+// visibleTo reports whether the user may see the post: anyone can see
+// published posts, only the author drafts and scheduled ones.
+func visibleTo(post *model.PostModel, userID int64) bool {
+	return post.Status == model.PostPublished || post.UserID == userID
+}
+
+// GetDrafts returns a page of the user's drafts and scheduled posts.
+func (s *postService) GetDrafts(ctx context.Context, userID int64, page, pageSize int) (*dto.PostsResponse, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	posts, totalCount, err := s.postRepo.GetDrafts(ctx, userID, pageSize, offset)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	postResponses, err := s.toPostResponses(ctx, posts, nil, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+
+	response := &dto.PostsResponse{
+		Posts:      postResponses,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+
+	return response, http.StatusOK, nil
+}
+
+// PublishScheduledPosts publishes the scheduled posts that are due. It
+// returns how many were published.
+func (s *postService) PublishScheduledPosts(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		batchPublished, err := s.postRepo.PublishDuePosts(ctx, time.Now(), duePostsBatch)
+		if err != nil {
+			return published, err
+		}
+		published += batchPublished
+		// a short batch means nothing more is due, or the rest is being
+		// published by another server
+		if batchPublished < duePostsBatch {
+			return published, nil
+		}
+	}
+}
@@ -2,13 +2,17 @@ package post
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"go-twitter/internal/config"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
 	"net/http"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/go-playground/validator/v10"
 )
 
 // Mock PostRepository for testing
@@ -18,10 +22,219 @@ type mockPostRepository struct {
 	getPostsFunc            func(ctx context.Context, limit, offset int) ([]*model.PostModel, error)
 	getPostsByUserIDFunc    func(ctx context.Context, userID int64, limit, offset int) ([]*model.PostModel, error)
 	getPostsCountFunc       func(ctx context.Context) (int64, error)
-	updatePostFunc          func(ctx context.Context, post *model.PostModel) error
-	deletePostFunc          func(ctx context.Context, id int64) error
+	getPostsCountByUserIDFunc func(ctx context.Context, userID int64) (int64, error)
+	updatePostFunc          func(ctx context.Context, post *model.PostModel) (bool, error)
+	deletePostFunc          func(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error
 	getPostWithUserInfoFunc func(ctx context.Context, id int64) (*model.PostModel, string, error)
 	getPostsWithUserInfoFunc func(ctx context.Context, limit, offset int) ([]*model.PostModel, []string, error)
+	createPostWithMediaFunc func(ctx context.Context, post *model.PostModel, mediaIDs []int64) (int64, bool, error)
+	getPostMediaFunc        func(ctx context.Context, postIDs []int64) (map[int64][]*model.MediaModel, error)
+	createPostWithPollFunc  func(ctx context.Context, post *model.PostModel, poll *model.PollModel) (int64, error)
+	getPostPollsFunc        func(ctx context.Context, postIDs []int64) (map[int64]*model.PollModel, error)
+	getPollVotesFunc        func(ctx context.Context, postIDs []int64, userID int64) (map[int64][]int64, error)
+	votePollFunc            func(ctx context.Context, postID, userID int64, optionIDs []int64) (bool, error)
+	getExpiredPollsFunc     func(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	finalizePollFunc        func(ctx context.Context, postID int64, now time.Time) (bool, error)
+	saveBookmarkFunc        func(ctx context.Context, userID, postID int64, folderID sql.NullInt64) (bool, error)
+	deleteBookmarkFunc      func(ctx context.Context, userID, postID int64) (bool, error)
+	getBookmarksFunc        func(ctx context.Context, userID int64, folderID sql.NullInt64, beforeID int64, limit int) ([]*model.BookmarkModel, []*model.PostModel, []string, error)
+	getBookmarkFolderByIDFunc func(ctx context.Context, id int64) (*model.BookmarkFolderModel, error)
+	createBookmarkFolderFunc func(ctx context.Context, folder *model.BookmarkFolderModel) (int64, bool, error)
+	publishDuePostsFunc     func(ctx context.Context, now time.Time, limit int) (int, error)
+	getPostRevisionsFunc    func(ctx context.Context, postID int64) ([]*model.PostRevisionModel, error)
+	getTrashFunc            func(ctx context.Context, userID int64, since time.Time, limit, offset int) ([]*model.TrashItemModel, int64, error)
+	restorePostFunc         func(ctx context.Context, id, userID int64, since time.Time) (bool, error)
+	purgeTrashFunc          func(ctx context.Context, before time.Time, limit int) (int, error)
+	pinPostFunc             func(ctx context.Context, userID, postID int64) (bool, error)
+	unpinPostFunc           func(ctx context.Context, userID int64) (bool, error)
+	getPinnedPostFunc       func(ctx context.Context, userID int64) (*model.PostModel, string, error)
+	setRepliesLockedFunc    func(ctx context.Context, postID int64, locked bool) (bool, error)
+	getUsernameFunc         func(ctx context.Context, userID int64) (string, error)
+	getFollowedUserIDsFunc  func(ctx context.Context, followerID int64, userIDs []int64) (map[int64]bool, error)
+}
+
+func (m *mockPostRepository) SetRepliesLocked(ctx context.Context, postID int64, locked bool) (bool, error) {
+	if m.setRepliesLockedFunc != nil {
+		return m.setRepliesLockedFunc(ctx, postID, locked)
+	}
+	return true, nil
+}
+
+func (m *mockPostRepository) GetUsername(ctx context.Context, userID int64) (string, error) {
+	if m.getUsernameFunc != nil {
+		return m.getUsernameFunc(ctx, userID)
+	}
+	return "", nil
+}
+
+func (m *mockPostRepository) GetFollowedUserIDs(ctx context.Context, followerID int64, userIDs []int64) (map[int64]bool, error) {
+	if m.getFollowedUserIDsFunc != nil {
+		return m.getFollowedUserIDsFunc(ctx, followerID, userIDs)
+	}
+	return map[int64]bool{}, nil
+}
+
+func (m *mockPostRepository) PinPost(ctx context.Context, userID, postID int64) (bool, error) {
+	if m.pinPostFunc != nil {
+		return m.pinPostFunc(ctx, userID, postID)
+	}
+	return true, nil
+}
+
+func (m *mockPostRepository) UnpinPost(ctx context.Context, userID int64) (bool, error) {
+	if m.unpinPostFunc != nil {
+		return m.unpinPostFunc(ctx, userID)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) GetPinnedPost(ctx context.Context, userID int64) (*model.PostModel, string, error) {
+	if m.getPinnedPostFunc != nil {
+		return m.getPinnedPostFunc(ctx, userID)
+	}
+	return nil, "", nil
+}
+
+func (m *mockPostRepository) GetTrash(ctx context.Context, userID int64, since time.Time, limit, offset int) ([]*model.TrashItemModel, int64, error) {
+	if m.getTrashFunc != nil {
+		return m.getTrashFunc(ctx, userID, since, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockPostRepository) RestorePost(ctx context.Context, id, userID int64, since time.Time) (bool, error) {
+	if m.restorePostFunc != nil {
+		return m.restorePostFunc(ctx, id, userID, since)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) PurgeTrash(ctx context.Context, before time.Time, limit int) (int, error) {
+	if m.purgeTrashFunc != nil {
+		return m.purgeTrashFunc(ctx, before, limit)
+	}
+	return 0, nil
+}
+
+func (m *mockPostRepository) GetDrafts(ctx context.Context, userID int64, limit, offset int) ([]*model.PostModel, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *mockPostRepository) PublishDuePosts(ctx context.Context, now time.Time, limit int) (int, error) {
+	if m.publishDuePostsFunc != nil {
+		return m.publishDuePostsFunc(ctx, now, limit)
+	}
+	return 0, nil
+}
+
+func (m *mockPostRepository) GetPostRevisions(ctx context.Context, postID int64) ([]*model.PostRevisionModel, error) {
+	if m.getPostRevisionsFunc != nil {
+		return m.getPostRevisionsFunc(ctx, postID)
+	}
+	return nil, nil
+}
+
+func (m *mockPostRepository) SaveBookmark(ctx context.Context, userID, postID int64, folderID sql.NullInt64) (bool, error) {
+	if m.saveBookmarkFunc != nil {
+		return m.saveBookmarkFunc(ctx, userID, postID, folderID)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) DeleteBookmark(ctx context.Context, userID, postID int64) (bool, error) {
+	if m.deleteBookmarkFunc != nil {
+		return m.deleteBookmarkFunc(ctx, userID, postID)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) GetBookmarks(ctx context.Context, userID int64, folderID sql.NullInt64, beforeID int64, limit int) ([]*model.BookmarkModel, []*model.PostModel, []string, error) {
+	if m.getBookmarksFunc != nil {
+		return m.getBookmarksFunc(ctx, userID, folderID, beforeID, limit)
+	}
+	return nil, nil, nil, nil
+}
+
+func (m *mockPostRepository) GetBookmarkedPostIDs(ctx context.Context, userID int64, postIDs []int64) (map[int64]bool, error) {
+	return map[int64]bool{}, nil
+}
+
+func (m *mockPostRepository) CreateBookmarkFolder(ctx context.Context, folder *model.BookmarkFolderModel) (int64, bool, error) {
+	if m.createBookmarkFolderFunc != nil {
+		return m.createBookmarkFolderFunc(ctx, folder)
+	}
+	return 0, false, nil
+}
+
+func (m *mockPostRepository) GetBookmarkFolderByID(ctx context.Context, id int64) (*model.BookmarkFolderModel, error) {
+	if m.getBookmarkFolderByIDFunc != nil {
+		return m.getBookmarkFolderByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockPostRepository) GetBookmarkFolders(ctx context.Context, userID int64) ([]*model.BookmarkFolderModel, error) {
+	return nil, nil
+}
+
+func (m *mockPostRepository) DeleteBookmarkFolder(ctx context.Context, userID, id int64) (bool, error) {
+	return false, nil
+}
+
+func (m *mockPostRepository) CreatePostWithPoll(ctx context.Context, post *model.PostModel, poll *model.PollModel) (int64, error) {
+	if m.createPostWithPollFunc != nil {
+		return m.createPostWithPollFunc(ctx, post, poll)
+	}
+	return 0, nil
+}
+
+func (m *mockPostRepository) GetPostPolls(ctx context.Context, postIDs []int64) (map[int64]*model.PollModel, error) {
+	if m.getPostPollsFunc != nil {
+		return m.getPostPollsFunc(ctx, postIDs)
+	}
+	return map[int64]*model.PollModel{}, nil
+}
+
+func (m *mockPostRepository) GetPollVotes(ctx context.Context, postIDs []int64, userID int64) (map[int64][]int64, error) {
+	if m.getPollVotesFunc != nil {
+		return m.getPollVotesFunc(ctx, postIDs, userID)
+	}
+	return map[int64][]int64{}, nil
+}
+
+func (m *mockPostRepository) VotePoll(ctx context.Context, postID, userID int64, optionIDs []int64) (bool, error) {
+	if m.votePollFunc != nil {
+		return m.votePollFunc(ctx, postID, userID, optionIDs)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) GetExpiredPolls(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	if m.getExpiredPollsFunc != nil {
+		return m.getExpiredPollsFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockPostRepository) FinalizePoll(ctx context.Context, postID int64, now time.Time) (bool, error) {
+	if m.finalizePollFunc != nil {
+		return m.finalizePollFunc(ctx, postID, now)
+	}
+	return false, nil
+}
+
+func (m *mockPostRepository) CreatePostWithMedia(ctx context.Context, post *model.PostModel, mediaIDs []int64) (int64, bool, error) {
+	if m.createPostWithMediaFunc != nil {
+		return m.createPostWithMediaFunc(ctx, post, mediaIDs)
+	}
+	return 0, false, nil
+}
+
+func (m *mockPostRepository) GetPostMedia(ctx context.Context, postIDs []int64) (map[int64][]*model.MediaModel, error) {
+	if m.getPostMediaFunc != nil {
+		return m.getPostMediaFunc(ctx, postIDs)
+	}
+	return nil, nil
 }
 
 func (m *mockPostRepository) CreatePost(ctx context.Context, post *model.PostModel) (int64, error) {
@@ -59,16 +272,23 @@ func (m *mockPostRepository) GetPostsCount(ctx context.Context) (int64, error) {
 	return 0, nil
 }
 
-func (m *mockPostRepository) UpdatePost(ctx context.Context, post *model.PostModel) error {
+func (m *mockPostRepository) GetPostsCountByUserID(ctx context.Context, userID int64) (int64, error) {
+	if m.getPostsCountByUserIDFunc != nil {
+		return m.getPostsCountByUserIDFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockPostRepository) UpdatePost(ctx context.Context, post *model.PostModel) (bool, error) {
 	if m.updatePostFunc != nil {
 		return m.updatePostFunc(ctx, post)
 	}
-	return nil
+	return true, nil
 }
 
-func (m *mockPostRepository) DeletePost(ctx context.Context, id int64) error {
+func (m *mockPostRepository) DeletePost(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error {
 	if m.deletePostFunc != nil {
-		return m.deletePostFunc(ctx, id)
+		return m.deletePostFunc(ctx, id, deletedBy, auditLog)
 	}
 	return nil
 }
@@ -195,12 +415,13 @@ func TestUpdatePost_Success(t *testing.T) {
 			return &model.PostModel{
 				ID:      postID,
 				UserID:  userID,
+				Status:  model.PostPublished,
 				Title:   "Old Title",
 				Content: "Old Content",
 			}, nil
 		},
-		updatePostFunc: func(ctx context.Context, post *model.PostModel) error {
-			return nil
+		updatePostFunc: func(ctx context.Context, post *model.PostModel) (bool, error) {
+			return true, nil
 		},
 	}
 
@@ -259,6 +480,7 @@ func TestUpdatePost_UnauthorizedUser(t *testing.T) {
 			return &model.PostModel{
 				ID:      postID,
 				UserID:  ownerID,
+				Status:  model.PostPublished,
 				Title:   "Old Title",
 				Content: "Old Content",
 			}, nil
@@ -322,12 +544,13 @@ func TestUpdatePost_UpdateError(t *testing.T) {
 			return &model.PostModel{
 				ID:      postID,
 				UserID:  userID,
+				Status:  model.PostPublished,
 				Title:   "Old Title",
 				Content: "Old Content",
 			}, nil
 		},
-		updatePostFunc: func(ctx context.Context, post *model.PostModel) error {
-			return errors.New("update failed")
+		updatePostFunc: func(ctx context.Context, post *model.PostModel) (bool, error) {
+			return false, errors.New("update failed")
 		},
 	}
 
@@ -360,9 +583,10 @@ func TestDeletePost_Success(t *testing.T) {
 			return &model.PostModel{
 				ID:     postID,
 				UserID: userID,
+				Status: model.PostPublished,
 			}, nil
 		},
-		deletePostFunc: func(ctx context.Context, id int64) error {
+		deletePostFunc: func(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error {
 			return nil
 		},
 	}
@@ -370,7 +594,7 @@ func TestDeletePost_Success(t *testing.T) {
 	cfg := &config.Config{}
 	service := NewService(cfg, mockRepo, nil)
 
-	status, err := service.DeletePost(context.Background(), userID, postID)
+	status, err := service.DeletePost(context.Background(), userID, postID, nil)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -391,7 +615,7 @@ func TestDeletePost_PostNotFound(t *testing.T) {
 	cfg := &config.Config{}
 	service := NewService(cfg, mockRepo, nil)
 
-	status, err := service.DeletePost(context.Background(), 123, 456)
+	status, err := service.DeletePost(context.Background(), 123, 456, nil)
 
 	if err != nil {
 		t.Errorf("Expected no error for not found, got: %v", err)
@@ -412,6 +636,7 @@ func TestDeletePost_UnauthorizedUser(t *testing.T) {
 			return &model.PostModel{
 				ID:     postID,
 				UserID: ownerID,
+				Status: model.PostPublished,
 			}, nil
 		},
 	}
@@ -419,7 +644,7 @@ func TestDeletePost_UnauthorizedUser(t *testing.T) {
 	cfg := &config.Config{}
 	service := NewService(cfg, mockRepo, nil)
 
-	status, err := service.DeletePost(context.Background(), differentUserID, postID)
+	status, err := service.DeletePost(context.Background(), differentUserID, postID, nil)
 
 	if err != nil {
 		t.Errorf("Expected no error for forbidden, got: %v", err)
@@ -439,9 +664,10 @@ func TestDeletePost_DatabaseError(t *testing.T) {
 			return &model.PostModel{
 				ID:     postID,
 				UserID: userID,
+				Status: model.PostPublished,
 			}, nil
 		},
-		deletePostFunc: func(ctx context.Context, id int64) error {
+		deletePostFunc: func(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error {
 			return errors.New("delete failed")
 		},
 	}
@@ -449,7 +675,7 @@ func TestDeletePost_DatabaseError(t *testing.T) {
 	cfg := &config.Config{}
 	service := NewService(cfg, mockRepo, nil)
 
-	status, err := service.DeletePost(context.Background(), userID, postID)
+	status, err := service.DeletePost(context.Background(), userID, postID, nil)
 
 	if err == nil {
 		t.Fatal("Expected error, got nil")
@@ -463,17 +689,18 @@ func TestDeletePost_DatabaseError(t *testing.T) {
 func TestDeletePost_DeleteCalledWithCorrectID(t *testing.T) {
 	userID := int64(123)
 	postID := int64(789)
-	var deletedID int64
+	var deletedID, deletedBy int64
 
 	mockRepo := &mockPostRepository{
 		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
 			return &model.PostModel{
 				ID:     postID,
 				UserID: userID,
+				Status: model.PostPublished,
 			}, nil
 		},
-		deletePostFunc: func(ctx context.Context, id int64) error {
-			deletedID = id
+		deletePostFunc: func(ctx context.Context, id, by int64, auditLog *model.AuditLogModel) error {
+			deletedID, deletedBy = id, by
 			return nil
 		},
 	}
@@ -481,11 +708,14 @@ func TestDeletePost_DeleteCalledWithCorrectID(t *testing.T) {
 	cfg := &config.Config{}
 	service := NewService(cfg, mockRepo, nil)
 
-	service.DeletePost(context.Background(), userID, postID)
+	service.DeletePost(context.Background(), userID, postID, nil)
 
 	if deletedID != postID {
 		t.Errorf("Expected delete to be called with ID %d, got %d", postID, deletedID)
 	}
+	if deletedBy != userID {
+		t.Errorf("Expected the post to be deleted by %d, got %d", userID, deletedBy)
+	}
 }
 
 // Test edge cases
@@ -520,3 +750,955 @@ func TestCreatePost_SetsTimestamps(t *testing.T) {
 		t.Error("UpdatedAt timestamp not set correctly")
 	}
 }
+
+func TestDeletePost_ModeratorCanDeleteAnyPost(t *testing.T) {
+	ownerID := int64(123)
+	moderatorID := int64(999)
+	postID := int64(456)
+	deleted := false
+	var auditLogs []*model.AuditLogModel
+
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return &model.PostModel{
+				ID:     postID,
+				UserID: ownerID,
+				Status: model.PostPublished,
+			}, nil
+		},
+		deletePostFunc: func(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error {
+			deleted = true
+			if auditLog != nil {
+				auditLogs = append(auditLogs, auditLog)
+			}
+			return nil
+		},
+	}
+
+	cfg := &config.Config{}
+	service := NewService(cfg, mockRepo, nil)
+
+	status, err := service.DeletePost(context.Background(), moderatorID, postID, []string{model.RoleModerator})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if !deleted {
+		t.Error("Expected post to be deleted")
+	}
+
+	if len(auditLogs) != 1 {
+		t.Fatalf("Expected 1 audit log entry, got %d", len(auditLogs))
+	}
+
+	log := auditLogs[0]
+	if log.ActorID != moderatorID || log.TargetUserID != ownerID || log.EntityID != postID || log.Action != model.AuditActionDeletePost {
+		t.Errorf("Unexpected audit log entry: %+v", log)
+	}
+}
+
+func TestDeletePost_OwnerDeleteIsNotAudited(t *testing.T) {
+	userID := int64(123)
+	postID := int64(456)
+	var auditLogs []*model.AuditLogModel
+
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return &model.PostModel{
+				ID:     postID,
+				UserID: userID,
+				Status: model.PostPublished,
+			}, nil
+		},
+		deletePostFunc: func(ctx context.Context, id, deletedBy int64, auditLog *model.AuditLogModel) error {
+			if auditLog != nil {
+				auditLogs = append(auditLogs, auditLog)
+			}
+			return nil
+		},
+	}
+
+	cfg := &config.Config{}
+	service := NewService(cfg, mockRepo, nil)
+
+	status, _ := service.DeletePost(context.Background(), userID, postID, []string{model.RoleModerator})
+
+	if status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if len(auditLogs) != 0 {
+		t.Errorf("Expected no audit log entries, got %d", len(auditLogs))
+	}
+}
+
+func TestCreatePost_WithMedia(t *testing.T) {
+	var capturedMediaIDs []int64
+	mockRepo := &mockPostRepository{
+		createPostWithMediaFunc: func(ctx context.Context, post *model.PostModel, mediaIDs []int64) (int64, bool, error) {
+			capturedMediaIDs = mediaIDs
+			return 7, true, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	req := dto.CreatePostRequest{Title: "Photos", Content: "From the trip", MediaIDs: []int64{3, 1}}
+
+	id, status, err := service.CreatePost(context.Background(), 123, req)
+	if err != nil || status != http.StatusCreated || id != 7 {
+		t.Fatalf("Expected post 7 created, got %d, %d (%v)", id, status, err)
+	}
+	if len(capturedMediaIDs) != 2 || capturedMediaIDs[0] != 3 || capturedMediaIDs[1] != 1 {
+		t.Errorf("Expected media IDs in request order, got %v", capturedMediaIDs)
+	}
+}
+
+func TestCreatePost_UnavailableMedia(t *testing.T) {
+	mockRepo := &mockPostRepository{
+		createPostWithMediaFunc: func(ctx context.Context, post *model.PostModel, mediaIDs []int64) (int64, bool, error) {
+			return 0, false, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	req := dto.CreatePostRequest{Title: "Photos", Content: "Someone else's upload", MediaIDs: []int64{9}}
+
+	if _, status, err := service.CreatePost(context.Background(), 123, req); err == nil || status != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusBadRequest, status, err)
+	}
+}
+
+func TestCreatePost_WithPoll(t *testing.T) {
+	var created *model.PollModel
+	mockRepo := &mockPostRepository{
+		createPostWithPollFunc: func(ctx context.Context, post *model.PostModel, poll *model.PollModel) (int64, error) {
+			created = poll
+			return 7, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	req := dto.CreatePostRequest{
+		Title:   "Lunch",
+		Content: "Where should we go?",
+		Poll:    &dto.CreatePollRequest{Options: []string{"Pizza", "Sushi", "Tacos"}, DurationMinutes: 60},
+	}
+	id, status, err := service.CreatePost(context.Background(), 1, req)
+	if err != nil || status != http.StatusCreated || id != 7 {
+		t.Fatalf("Expected the post created, got %d (%v)", status, err)
+	}
+	if len(created.Options) != 3 || created.Options[1].Text != "Sushi" || created.MultipleChoice {
+		t.Errorf("Unexpected poll: %+v", created)
+	}
+	if d := time.Until(created.ClosesAt); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("Expected the poll to close in an hour, got %v", d)
+	}
+
+	req.MediaIDs = []int64{1}
+	if _, status, _ := service.CreatePost(context.Background(), 1, req); status != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a poll with media, got %d", http.StatusBadRequest, status)
+	}
+}
+
+func newPollTestRepo(poll *model.PollModel) *mockPostRepository {
+	return &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			if id != poll.PostID {
+				return nil, nil
+			}
+			return &model.PostModel{ID: id, UserID: 1, Status: model.PostPublished}, nil
+		},
+		getPostPollsFunc: func(ctx context.Context, postIDs []int64) (map[int64]*model.PollModel, error) {
+			return map[int64]*model.PollModel{poll.PostID: poll}, nil
+		},
+	}
+}
+
+func TestVotePoll(t *testing.T) {
+	poll := &model.PollModel{
+		PostID:   5,
+		ClosesAt: time.Now().Add(time.Hour),
+		Options:  []model.PollOptionModel{{ID: 10, Text: "Yes", VoteCount: 3}, {ID: 11, Text: "No", VoteCount: 1}},
+	}
+	mockRepo := newPollTestRepo(poll)
+	voters := map[int64]bool{}
+	mockRepo.votePollFunc = func(ctx context.Context, postID, userID int64, optionIDs []int64) (bool, error) {
+		if voters[userID] {
+			return false, nil
+		}
+		voters[userID] = true
+		return true, nil
+	}
+	mockRepo.getPollVotesFunc = func(ctx context.Context, postIDs []int64, userID int64) (map[int64][]int64, error) {
+		if voters[userID] {
+			return map[int64][]int64{5: {10}}, nil
+		}
+		return map[int64][]int64{}, nil
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		postID    int64
+		optionIDs []int64
+		status    int
+	}{
+		{"post without poll", 6, []int64{10}, http.StatusNotFound},
+		{"two choices in a single choice poll", 5, []int64{10, 11}, http.StatusBadRequest},
+		{"option of another poll", 5, []int64{12}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, status, _ := service.VotePoll(ctx, 2, tt.postID, dto.VotePollRequest{OptionIDs: tt.optionIDs}); status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+
+	result, status, err := service.VotePoll(ctx, 2, 5, dto.VotePollRequest{OptionIDs: []int64{10}})
+	if err != nil || status != http.StatusCreated {
+		t.Fatalf("Expected the vote recorded, got %d (%v)", status, err)
+	}
+	if result.Options[0].Votes == nil || *result.Options[0].Votes != 3 || len(result.VotedOptionIDs) != 1 {
+		t.Errorf("Expected the results shown after voting, got %+v", result)
+	}
+
+	if _, status, _ := service.VotePoll(ctx, 2, 5, dto.VotePollRequest{OptionIDs: []int64{11}}); status != http.StatusConflict {
+		t.Errorf("Expected status %d for a second vote, got %d", http.StatusConflict, status)
+	}
+
+	poll.ClosesAt = time.Now().Add(-time.Minute)
+	if _, status, _ := service.VotePoll(ctx, 3, 5, dto.VotePollRequest{OptionIDs: []int64{10}}); status != http.StatusConflict {
+		t.Errorf("Expected status %d for a closed poll, got %d", http.StatusConflict, status)
+	}
+}
+
+func TestToPollResponse_HidesResultsUntilVotedOrClosed(t *testing.T) {
+	now := time.Now()
+	poll := &model.PollModel{
+		ClosesAt:    now.Add(time.Hour),
+		TotalVoters: 4,
+		Options:     []model.PollOptionModel{{ID: 1, Text: "A", VoteCount: 3}, {ID: 2, Text: "B", VoteCount: 1}},
+	}
+
+	open := toPollResponse(poll, nil, now)
+	if open.Closed || open.TotalVoters != nil || open.Options[0].Votes != nil {
+		t.Errorf("Expected results hidden before voting, got %+v", open)
+	}
+
+	voted := toPollResponse(poll, []int64{2}, now)
+	if voted.TotalVoters == nil || *voted.TotalVoters != 4 || *voted.Options[1].Votes != 1 {
+		t.Errorf("Expected results after voting, got %+v", voted)
+	}
+
+	closed := toPollResponse(poll, nil, now.Add(2*time.Hour))
+	if !closed.Closed || closed.Options[0].Votes == nil || *closed.Options[0].Votes != 3 {
+		t.Errorf("Expected results once the poll closed, got %+v", closed)
+	}
+}
+
+func TestFinalizeExpiredPolls(t *testing.T) {
+	var finalizedIDs []int64
+	mockRepo := &mockPostRepository{
+		getExpiredPollsFunc: func(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+			return []int64{1, 2}, nil
+		},
+		finalizePollFunc: func(ctx context.Context, postID int64, now time.Time) (bool, error) {
+			finalizedIDs = append(finalizedIDs, postID)
+			// another server finalized poll 2 first
+			return postID == 1, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	finalized, err := service.FinalizeExpiredPolls(context.Background())
+	if err != nil || finalized != 1 {
+		t.Errorf("Expected one poll finalized, got %d (%v)", finalized, err)
+	}
+	if len(finalizedIDs) != 2 {
+		t.Errorf("Expected both expired polls tried, got %v", finalizedIDs)
+	}
+}
+
+func newBookmarkTestRepo() *mockPostRepository {
+	return &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			if id != 5 {
+				return nil, nil
+			}
+			return &model.PostModel{ID: 5, UserID: 1, Status: model.PostPublished}, nil
+		},
+		getBookmarkFolderByIDFunc: func(ctx context.Context, id int64) (*model.BookmarkFolderModel, error) {
+			// folder 1 belongs to user 2, folder 2 to user 3
+			switch id {
+			case 1:
+				return &model.BookmarkFolderModel{ID: 1, UserID: 2, Name: "Recipes"}, nil
+			case 2:
+				return &model.BookmarkFolderModel{ID: 2, UserID: 3, Name: "Travel"}, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func TestBookmarkPost(t *testing.T) {
+	mockRepo := newBookmarkTestRepo()
+	saved := map[int64]sql.NullInt64{}
+	mockRepo.saveBookmarkFunc = func(ctx context.Context, userID, postID int64, folderID sql.NullInt64) (bool, error) {
+		_, exists := saved[postID]
+		saved[postID] = folderID
+		return !exists, nil
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+	ctx := context.Background()
+	folder := func(id int64) *int64 { return &id }
+
+	if status, _ := service.BookmarkPost(ctx, 2, 6, dto.BookmarkPostRequest{}); status != http.StatusNotFound {
+		t.Errorf("Expected status %d for a missing post, got %d", http.StatusNotFound, status)
+	}
+	if status, _ := service.BookmarkPost(ctx, 2, 5, dto.BookmarkPostRequest{FolderID: folder(2)}); status != http.StatusNotFound {
+		t.Errorf("Expected status %d for another user's folder, got %d", http.StatusNotFound, status)
+	}
+
+	if status, err := service.BookmarkPost(ctx, 2, 5, dto.BookmarkPostRequest{}); status != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusCreated, status, err)
+	}
+	// bookmarking again moves it into the folder
+	if status, _ := service.BookmarkPost(ctx, 2, 5, dto.BookmarkPostRequest{FolderID: folder(1)}); status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	if saved[5] != (sql.NullInt64{Int64: 1, Valid: true}) {
+		t.Errorf("Expected the bookmark in folder 1, got %v", saved[5])
+	}
+}
+
+func TestGetBookmarks(t *testing.T) {
+	mockRepo := newBookmarkTestRepo()
+	var gotFolder sql.NullInt64
+	var gotBeforeID int64
+	var gotLimit int
+	mockRepo.getBookmarksFunc = func(ctx context.Context, userID int64, folderID sql.NullInt64, beforeID int64, limit int) ([]*model.BookmarkModel, []*model.PostModel, []string, error) {
+		gotFolder, gotBeforeID, gotLimit = folderID, beforeID, limit
+		return nil, nil, nil, nil
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+	ctx := context.Background()
+
+	response, status, err := service.GetBookmarks(ctx, 2, 1, "42", 10)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if gotFolder.Int64 != 1 || gotBeforeID != 42 || gotLimit != 11 {
+		t.Errorf("Unexpected query: folder %v, before %d, limit %d", gotFolder, gotBeforeID, gotLimit)
+	}
+	if response.Posts == nil || response.NextCursor != "" {
+		t.Errorf("Expected an empty last page, got %+v", response)
+	}
+
+	if _, status, _ := service.GetBookmarks(ctx, 2, 0, "abc", 10); status != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an invalid cursor, got %d", http.StatusBadRequest, status)
+	}
+	if _, status, _ := service.GetBookmarks(ctx, 2, 2, "", 10); status != http.StatusNotFound {
+		t.Errorf("Expected status %d for another user's folder, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestCreateBookmarkFolder_DuplicateName(t *testing.T) {
+	mockRepo := &mockPostRepository{
+		createBookmarkFolderFunc: func(ctx context.Context, folder *model.BookmarkFolderModel) (int64, bool, error) {
+			return 0, false, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	_, status, _ := service.CreateBookmarkFolder(context.Background(), 2, dto.CreateBookmarkFolderRequest{Name: "Recipes"})
+	if status != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, status)
+	}
+	if _, status, _ := service.CreateBookmarkFolder(context.Background(), 2, dto.CreateBookmarkFolderRequest{Name: "   "}); status != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a blank name, got %d", http.StatusBadRequest, status)
+	}
+}
+
+func TestCreatePost_Scheduling(t *testing.T) {
+	var created *model.PostModel
+	mockRepo := &mockPostRepository{
+		createPostFunc: func(ctx context.Context, post *model.PostModel) (int64, error) {
+			created = post
+			return 1, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		req           dto.CreatePostRequest
+		wantStatus    int
+		wantPost      string
+		wantPublishAt bool
+	}{
+		{"published by default", dto.CreatePostRequest{Title: "T", Content: "C"}, http.StatusCreated, model.PostPublished, true},
+		{"draft", dto.CreatePostRequest{Title: "T", Content: "C", Status: model.PostDraft}, http.StatusCreated, model.PostDraft, false},
+		{"scheduled", dto.CreatePostRequest{Title: "T", Content: "C", Status: model.PostScheduled, PublishAt: &future}, http.StatusCreated, model.PostScheduled, true},
+		{"scheduled in the past", dto.CreatePostRequest{Title: "T", Content: "C", Status: model.PostScheduled, PublishAt: &past}, http.StatusBadRequest, "", false},
+		{"scheduled without a time", dto.CreatePostRequest{Title: "T", Content: "C", Status: model.PostScheduled}, http.StatusBadRequest, "", false},
+		{"draft with a time", dto.CreatePostRequest{Title: "T", Content: "C", Status: model.PostDraft, PublishAt: &future}, http.StatusBadRequest, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			_, status, _ := service.CreatePost(ctx, 1, tt.req)
+			if status != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, status)
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if created != nil {
+					t.Error("Expected no post created")
+				}
+				return
+			}
+			if created.Status != tt.wantPost || created.PublishAt.Valid != tt.wantPublishAt {
+				t.Errorf("Expected a %s post, got %q with publish_at %v", tt.wantPost, created.Status, created.PublishAt)
+			}
+		})
+	}
+}
+
+func TestUpdatePost_Drafts(t *testing.T) {
+	posts := map[int64]*model.PostModel{
+		1: {ID: 1, UserID: 1, Status: model.PostDraft},
+		2: {ID: 2, UserID: 1, Status: model.PostPublished},
+	}
+	var statusUpdates []string
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return posts[id], nil
+		},
+		updatePostFunc: func(ctx context.Context, post *model.PostModel) (bool, error) {
+			if post.Status != "" {
+				statusUpdates = append(statusUpdates, post.Status)
+			}
+			return true, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+	ctx := context.Background()
+
+	// other users can't tell a draft exists
+	if status, _ := service.UpdatePost(ctx, 2, 1, dto.UpdatePostRequest{Title: "T", Content: "C"}); status != http.StatusNotFound {
+		t.Errorf("Expected status %d for another user's draft, got %d", http.StatusNotFound, status)
+	}
+
+	status, err := service.UpdatePost(ctx, 1, 1, dto.UpdatePostRequest{Title: "T", Content: "C", Status: model.PostPublished})
+	if status != http.StatusOK || err != nil {
+		t.Fatalf("Expected the draft published, got %d (%v)", status, err)
+	}
+	if len(statusUpdates) != 1 || statusUpdates[0] != model.PostPublished {
+		t.Errorf("Expected the content saved with a status update to published, got %v", statusUpdates)
+	}
+
+	status, _ = service.UpdatePost(ctx, 1, 2, dto.UpdatePostRequest{Title: "T", Content: "C", Status: model.PostDraft})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected status %d when unpublishing, got %d", http.StatusBadRequest, status)
+	}
+	if len(statusUpdates) != 1 {
+		t.Errorf("Expected no status update for a published post, got %v", statusUpdates)
+	}
+}
+
+func TestPublishScheduledPosts(t *testing.T) {
+	// two full batches, then the rest
+	batches := []int{duePostsBatch, duePostsBatch, 3}
+	calls := 0
+	mockRepo := &mockPostRepository{
+		publishDuePostsFunc: func(ctx context.Context, now time.Time, limit int) (int, error) {
+			published := batches[calls]
+			calls++
+			return published, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	published, err := service.PublishScheduledPosts(context.Background())
+	if err != nil || published != 2*duePostsBatch+3 {
+		t.Errorf("Expected %d posts published, got %d (%v)", 2*duePostsBatch+3, published, err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 batches, got %d", calls)
+	}
+}
+
+func TestUpdatePost_EditWindow(t *testing.T) {
+	publishedAt := time.Now().Add(-2 * time.Hour)
+	updated := false
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return &model.PostModel{ID: id, UserID: 1, Status: model.PostPublished, PublishAt: sql.NullTime{Time: publishedAt, Valid: true}}, nil
+		},
+		updatePostFunc: func(ctx context.Context, post *model.PostModel) (bool, error) {
+			updated = true
+			return true, nil
+		},
+	}
+	req := dto.UpdatePostRequest{Title: "T", Content: "C"}
+
+	service := NewService(&config.Config{EditWindow: time.Hour}, mockRepo, nil)
+	status, err := service.UpdatePost(context.Background(), 1, 1, req)
+	if status != http.StatusForbidden || err == nil {
+		t.Errorf("Expected status %d after the window, got %d (%v)", http.StatusForbidden, status, err)
+	}
+	if updated {
+		t.Error("Expected the post left unchanged")
+	}
+
+	service = NewService(&config.Config{EditWindow: 3 * time.Hour}, mockRepo, nil)
+	if status, err := service.UpdatePost(context.Background(), 1, 1, req); status != http.StatusOK {
+		t.Errorf("Expected status %d within the window, got %d (%v)", http.StatusOK, status, err)
+	}
+}
+
+func TestGetPostRevisions(t *testing.T) {
+	firstWritten := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockRepo := &mockPostRepository{
+		getPostWithUserInfoFunc: func(ctx context.Context, id int64) (*model.PostModel, string, error) {
+			if id != 1 {
+				return nil, "", nil
+			}
+			return &model.PostModel{ID: 1, Title: "Fixed", Content: "Typo fixed", EditCount: 1, UpdatedAt: firstWritten.Add(time.Minute)}, "alice", nil
+		},
+		getPostRevisionsFunc: func(ctx context.Context, postID int64) ([]*model.PostRevisionModel, error) {
+			return []*model.PostRevisionModel{{ID: 7, PostID: 1, Title: "Fxied", Content: "Typo", CreatedAt: firstWritten}}, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	response, status, err := service.GetPostRevisions(context.Background(), 1)
+	if status != http.StatusOK || err != nil {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if len(response.Revisions) != 2 {
+		t.Fatalf("Expected the earlier and the current version, got %+v", response.Revisions)
+	}
+	first, current := response.Revisions[0], response.Revisions[1]
+	if first.Revision != 1 || first.Title != "Fxied" || first.CreatedAt != "2026-01-02 03:04:05" {
+		t.Errorf("Unexpected first revision %+v", first)
+	}
+	if current.Revision != 2 || current.Title != "Fixed" {
+		t.Errorf("Expected the current version last, got %+v", current)
+	}
+
+	if _, status, _ := service.GetPostRevisions(context.Background(), 2); status != http.StatusNotFound {
+		t.Errorf("Expected status %d for a missing post, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestUpdatePost_IfMatch(t *testing.T) {
+	var gotVersion int
+	updated := true
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return &model.PostModel{ID: id, UserID: 1, Status: model.PostDraft, Version: 4}, nil
+		},
+		updatePostFunc: func(ctx context.Context, post *model.PostModel) (bool, error) {
+			gotVersion = post.Version
+			return updated, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+	ctx := context.Background()
+
+	status, _ := service.UpdatePost(ctx, 1, 1, dto.UpdatePostRequest{Title: "T", Content: "C", IfMatch: `"3"`})
+	if status != http.StatusPreconditionFailed {
+		t.Errorf("Expected status %d for a stale ETag, got %d", http.StatusPreconditionFailed, status)
+	}
+	if gotVersion != 0 {
+		t.Error("Expected no update for a stale ETag")
+	}
+
+	status, err := service.UpdatePost(ctx, 1, 1, dto.UpdatePostRequest{Title: "T", Content: "C", IfMatch: `"4"`})
+	if status != http.StatusOK || err != nil {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if gotVersion != 4 {
+		t.Errorf("Expected the update conditional on version 4, got %d", gotVersion)
+	}
+
+	// another request got in between the read and the write
+	updated = false
+	status, _ = service.UpdatePost(ctx, 1, 1, dto.UpdatePostRequest{Title: "T", Content: "C", IfMatch: `"4"`})
+	if status != http.StatusPreconditionFailed {
+		t.Errorf("Expected status %d for a concurrent update, got %d", http.StatusPreconditionFailed, status)
+	}
+}
+
+func TestGetTrash(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var gotSince time.Time
+	mockRepo := &mockPostRepository{
+		getTrashFunc: func(ctx context.Context, userID int64, since time.Time, limit, offset int) ([]*model.TrashItemModel, int64, error) {
+			gotSince = since
+			return []*model.TrashItemModel{
+				{Type: model.TrashComment, ID: 7, PostID: 3, Content: "C", DeletedAt: deletedAt},
+			}, 1, nil
+		},
+	}
+	service := NewService(&config.Config{TrashRetention: 24 * time.Hour}, mockRepo, nil)
+
+	trash, status, err := service.GetTrash(context.Background(), 1, 1, 10)
+	if status != http.StatusOK || err != nil {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if since := time.Since(gotSince); since < 24*time.Hour || since > 25*time.Hour {
+		t.Errorf("Expected items deleted in the last 24h, got since %v", gotSince)
+	}
+	if len(trash.Items) != 1 || trash.TotalPages != 1 {
+		t.Fatalf("Expected one item on one page, got %+v", trash)
+	}
+	item := trash.Items[0]
+	if item.Type != model.TrashComment || item.ID != 7 || item.PostID != 3 {
+		t.Errorf("Unexpected item %+v", item)
+	}
+	if item.RestorableUntil != "2026-10-02 12:00:00" {
+		t.Errorf("Expected it restorable until 2026-10-02 12:00:00, got %s", item.RestorableUntil)
+	}
+}
+
+func TestRestorePost(t *testing.T) {
+	restorable := true
+	mockRepo := &mockPostRepository{
+		restorePostFunc: func(ctx context.Context, id, userID int64, since time.Time) (bool, error) {
+			return restorable, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	status, err := service.RestorePost(context.Background(), 1, 2)
+	if status != http.StatusOK || err != nil {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+
+	// not the user's, deleted by a moderator, or past retention
+	restorable = false
+	status, err = service.RestorePost(context.Background(), 1, 2)
+	if status != http.StatusNotFound || err == nil {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusNotFound, status, err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	batches := []int{trashPurgeBatch, 5}
+	calls := 0
+	var gotBefore time.Time
+	mockRepo := &mockPostRepository{
+		purgeTrashFunc: func(ctx context.Context, before time.Time, limit int) (int, error) {
+			gotBefore = before
+			purged := batches[calls]
+			calls++
+			return purged, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	purged, err := service.PurgeTrash(context.Background())
+	if err != nil || purged != trashPurgeBatch+5 {
+		t.Errorf("Expected %d items purged, got %d (%v)", trashPurgeBatch+5, purged, err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 batches, got %d", calls)
+	}
+	if age := time.Since(gotBefore); age < config.DefaultTrashRetention {
+		t.Errorf("Expected only items past the default retention, got before %v", gotBefore)
+	}
+}
+
+func TestPinPost(t *testing.T) {
+	posts := map[int64]*model.PostModel{
+		1: {ID: 1, UserID: 1, Status: model.PostPublished},
+		2: {ID: 2, UserID: 2, Status: model.PostPublished},
+		3: {ID: 3, UserID: 1, Status: model.PostDraft},
+		4: {ID: 4, UserID: 2, Status: model.PostDraft},
+	}
+	var pinnedID int64
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return posts[id], nil
+		},
+		pinPostFunc: func(ctx context.Context, userID, postID int64) (bool, error) {
+			pinnedID = postID
+			return true, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	tests := []struct {
+		postID int64
+		want   int
+	}{
+		{1, http.StatusOK},
+		{2, http.StatusForbidden},
+		{3, http.StatusBadRequest},
+		// other users' drafts are missing, not forbidden
+		{4, http.StatusNotFound},
+		{5, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		pinnedID = 0
+		status, _ := service.PinPost(context.Background(), 1, dto.PinPostRequest{PostID: tt.postID})
+		if status != tt.want {
+			t.Errorf("Expected status %d pinning post %d, got %d", tt.want, tt.postID, status)
+		}
+		if pinned := pinnedID == tt.postID; pinned != (tt.want == http.StatusOK) {
+			t.Errorf("Unexpected pin of post %d: %v", tt.postID, pinned)
+		}
+	}
+}
+
+func TestUnpinPost(t *testing.T) {
+	unpinned := true
+	mockRepo := &mockPostRepository{
+		unpinPostFunc: func(ctx context.Context, userID int64) (bool, error) {
+			return unpinned, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	if status, err := service.UnpinPost(context.Background(), 1); status != http.StatusOK || err != nil {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+
+	unpinned = false
+	if status, _ := service.UnpinPost(context.Background(), 1); status != http.StatusNotFound {
+		t.Errorf("Expected status %d with nothing pinned, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestGetPostsByUserID_PinnedPostOnFirstPage(t *testing.T) {
+	lookups := 0
+	mockRepo := &mockPostRepository{
+		getPinnedPostFunc: func(ctx context.Context, userID int64) (*model.PostModel, string, error) {
+			lookups++
+			return nil, "", nil
+		},
+		getPostsCountFunc: func(ctx context.Context) (int64, error) {
+			return 50, nil
+		},
+		getPostsCountByUserIDFunc: func(ctx context.Context, userID int64) (int64, error) {
+			if userID != 1 {
+				t.Errorf("Expected user 1's posts counted, got user %d", userID)
+			}
+			return 15, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	response, status, err := service.GetPostsByUserID(context.Background(), 1, 0, 1, 10)
+	if status != http.StatusOK || err != nil {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if response.TotalCount != 15 || response.TotalPages != 2 {
+		t.Errorf("Expected the user's 15 posts on 2 pages, got %d on %d", response.TotalCount, response.TotalPages)
+	}
+	if response.PinnedPost != nil {
+		t.Error("Expected no pinned post when none is pinned")
+	}
+
+	service.GetPostsByUserID(context.Background(), 1, 0, 2, 10)
+	if lookups != 1 {
+		t.Errorf("Expected the pinned post looked up for the first page only, got %d lookups", lookups)
+	}
+}
+
+func TestCreatePost_ReplyPolicy(t *testing.T) {
+	var created *model.PostModel
+	mockRepo := &mockPostRepository{
+		createPostFunc: func(ctx context.Context, post *model.PostModel) (int64, error) {
+			created = post
+			return 1, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	tests := []struct {
+		policy string
+		want   int
+		saved  string
+	}{
+		{"", http.StatusCreated, model.ReplyEveryone},
+		{model.ReplyFollowers, http.StatusCreated, model.ReplyFollowers},
+		{model.ReplyMentionedOnly, http.StatusCreated, model.ReplyMentionedOnly},
+		{model.ReplyNobody, http.StatusCreated, model.ReplyNobody},
+	}
+	for _, tt := range tests {
+		created = nil
+		_, status, _ := service.CreatePost(context.Background(), 1, dto.CreatePostRequest{Title: "Title", Content: "Content", ReplyPolicy: tt.policy})
+		if status != tt.want {
+			t.Errorf("Expected status %d for policy %q, got %d", tt.want, tt.policy, status)
+		}
+		if created == nil || created.ReplyPolicy != tt.saved {
+			t.Errorf("Expected reply policy %q saved for %q, got %+v", tt.saved, tt.policy, created)
+		}
+	}
+
+	validate := validator.New()
+	for _, req := range []any{
+		dto.CreatePostRequest{Title: "Title", Content: "Content", ReplyPolicy: "friends"},
+		dto.UpdatePostRequest{Title: "Title", Content: "Content", ReplyPolicy: "friends"},
+	} {
+		if err := validate.Struct(req); err == nil {
+			t.Errorf("Expected %T to reject an unknown reply policy", req)
+		}
+	}
+}
+
+func TestUpdatePost_ReplyPolicy(t *testing.T) {
+	var updated *model.PostModel
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return &model.PostModel{ID: id, UserID: 1, Status: model.PostDraft, ReplyPolicy: model.ReplyNobody}, nil
+		},
+		updatePostFunc: func(ctx context.Context, post *model.PostModel) (bool, error) {
+			updated = post
+			return true, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	req := dto.UpdatePostRequest{Title: "Title", Content: "Content"}
+	if status, err := service.UpdatePost(context.Background(), 1, 2, req); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if updated.ReplyPolicy != model.ReplyNobody {
+		t.Errorf("Expected the reply policy kept, got %q", updated.ReplyPolicy)
+	}
+
+	req.ReplyPolicy = model.ReplyEveryone
+	service.UpdatePost(context.Background(), 1, 2, req)
+	if updated.ReplyPolicy != model.ReplyEveryone {
+		t.Errorf("Expected the reply policy changed, got %q", updated.ReplyPolicy)
+	}
+}
+
+func TestSetRepliesLocked(t *testing.T) {
+	posts := map[int64]*model.PostModel{
+		1: {ID: 1, UserID: 1, Status: model.PostPublished, ReplyPolicy: model.ReplyEveryone},
+		2: {ID: 2, UserID: 2, Status: model.PostPublished, ReplyPolicy: model.ReplyEveryone},
+		3: {ID: 3, UserID: 1, Status: model.PostPublished, ReplyPolicy: model.ReplyEveryone, RepliesLocked: true},
+		4: {ID: 4, UserID: 2, Status: model.PostDraft, ReplyPolicy: model.ReplyEveryone},
+	}
+	var lockedID int64
+	mockRepo := &mockPostRepository{
+		getPostByIDFunc: func(ctx context.Context, id int64) (*model.PostModel, error) {
+			return posts[id], nil
+		},
+		setRepliesLockedFunc: func(ctx context.Context, postID int64, locked bool) (bool, error) {
+			lockedID = postID
+			return true, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	tests := []struct {
+		postID int64
+		want   int
+		locks  bool
+	}{
+		{1, http.StatusOK, true},
+		{2, http.StatusForbidden, false},
+		// already locked
+		{3, http.StatusOK, false},
+		{4, http.StatusNotFound, false},
+		{5, http.StatusNotFound, false},
+	}
+	for _, tt := range tests {
+		lockedID = 0
+		status, _ := service.SetRepliesLocked(context.Background(), 1, tt.postID, true)
+		if status != tt.want {
+			t.Errorf("Expected status %d locking post %d, got %d", tt.want, tt.postID, status)
+		}
+		if locks := lockedID == tt.postID; locks != tt.locks {
+			t.Errorf("Unexpected lock of post %d: %v", tt.postID, locks)
+		}
+	}
+}
+
+func TestGetViewerUsername_OnlyForMentionedOnlyPosts(t *testing.T) {
+	lookups := 0
+	mockRepo := &mockPostRepository{
+		getUsernameFunc: func(ctx context.Context, userID int64) (string, error) {
+			lookups++
+			return "alice", nil
+		},
+	}
+	service := &postService{cfg: &config.Config{}, postRepo: mockRepo}
+
+	everyone := &model.PostModel{UserID: 2, ReplyPolicy: model.ReplyEveryone}
+	mentioned := &model.PostModel{UserID: 2, ReplyPolicy: model.ReplyMentionedOnly, Content: "hi @Alice!"}
+	own := &model.PostModel{UserID: 1, ReplyPolicy: model.ReplyMentionedOnly}
+
+	service.getViewerUsername(context.Background(), []*model.PostModel{everyone, own}, 1)
+	service.getViewerUsername(context.Background(), []*model.PostModel{mentioned}, 0)
+	if lookups != 0 {
+		t.Errorf("Expected no username lookups, got %d", lookups)
+	}
+
+	username, _ := service.getViewerUsername(context.Background(), []*model.PostModel{everyone, mentioned}, 1)
+	if lookups != 1 || username != "alice" {
+		t.Fatalf("Expected one lookup returning alice, got %d returning %q", lookups, username)
+	}
+	if !mentioned.CanReply(1, username, false) || mentioned.CanReply(3, "bob", false) {
+		t.Error("Expected only mentioned users to be able to reply")
+	}
+
+	post := &model.PostModel{Title: "cc @bob", Content: "thanks @alice_w, @John.Doe and @mary-jane. Not alice or @ann.lee-smith"}
+	for username, want := range map[string]bool{
+		"bob": true, "ALICE_W": true, "john.doe": true, "mary-jane": true, "ann.lee-smith": true,
+		"alice": false, "ali": false, "john": false, "mary": false, "ann.lee": false,
+	} {
+		if got := post.Mentions(username); got != want {
+			t.Errorf("Expected Mentions(%q) to be %v, got %v", username, want, got)
+		}
+	}
+}
+
+func TestGetFollowedAuthors_OnlyForFollowersPosts(t *testing.T) {
+	var lookups [][]int64
+	mockRepo := &mockPostRepository{
+		getFollowedUserIDsFunc: func(ctx context.Context, followerID int64, userIDs []int64) (map[int64]bool, error) {
+			lookups = append(lookups, userIDs)
+			return map[int64]bool{2: true}, nil
+		},
+	}
+	service := &postService{cfg: &config.Config{}, postRepo: mockRepo}
+
+	everyone := &model.PostModel{UserID: 2, ReplyPolicy: model.ReplyEveryone}
+	followed := &model.PostModel{UserID: 2, ReplyPolicy: model.ReplyFollowers}
+	unfollowed := &model.PostModel{UserID: 3, ReplyPolicy: model.ReplyFollowers}
+	own := &model.PostModel{UserID: 1, ReplyPolicy: model.ReplyFollowers}
+
+	service.getFollowedAuthors(context.Background(), []*model.PostModel{everyone, own}, 1)
+	service.getFollowedAuthors(context.Background(), []*model.PostModel{followed}, 0)
+	if len(lookups) != 0 {
+		t.Errorf("Expected no follow lookups, got %v", lookups)
+	}
+
+	posts := []*model.PostModel{everyone, followed, unfollowed, own, followed}
+	followedAuthors, _ := service.getFollowedAuthors(context.Background(), posts, 1)
+	if len(lookups) != 1 || !slices.Equal(lookups[0], []int64{2, 3}) {
+		t.Fatalf("Expected one lookup of authors 2 and 3, got %v", lookups)
+	}
+	if !followed.CanReply(1, "", followedAuthors[followed.UserID]) || unfollowed.CanReply(1, "", followedAuthors[unfollowed.UserID]) {
+		t.Error("Expected only followers of the author to be able to reply")
+	}
+	if !own.CanReply(1, "", false) {
+		t.Error("Expected the author to be able to reply without following themselves")
+	}
+}
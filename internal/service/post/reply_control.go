@@ -0,0 +1,81 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/model"
+	"net/http"
+	"slices"
+)
+
+// resolveReplyPolicy returns the reply policy a post is saved with,
+// everyone when none is given.
+func resolveReplyPolicy(policy string) string {
+	if policy == "" {
+		return model.ReplyEveryone
+	}
+	return policy
+}
+
+// SetRepliesLocked locks or unlocks the thread of one of the user's posts.
+// Locking takes effect whatever the reply policy and at any time, also
+// after the post can no longer be edited.
+func (s *postService) SetRepliesLocked(ctx context.Context, userID, postID int64, locked bool) (int, error) {
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if post == nil || !visibleTo(post, userID) {
+		return http.StatusNotFound, errors.New("post not found")
+	}
+	if post.UserID != userID {
+		return http.StatusForbidden, errors.New("you can only lock replies to your own posts")
+	}
+	if post.RepliesLocked == locked {
+		return http.StatusOK, nil
+	}
+
+	updated, err := s.postRepo.SetRepliesLocked(ctx, postID, locked)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	// deleted since it was read above
+	if !updated {
+		return http.StatusNotFound, errors.New("post not found")
+	}
+	return http.StatusOK, nil
+}
+
+// getViewerUsername returns the viewer's username if any of the posts
+// needs it to tell whether the viewer can reply, and an empty string
+// otherwise.
+func (s *postService) getViewerUsername(ctx context.Context, posts []*model.PostModel, viewerID int64) (string, error) {
+	if viewerID == 0 {
+		return "", nil
+	}
+	for _, post := range posts {
+		if post.ReplyPolicy == model.ReplyMentionedOnly && post.UserID != viewerID && !post.RepliesLocked {
+			return s.postRepo.GetUsername(ctx, viewerID)
+		}
+	}
+	return "", nil
+}
+
+// getFollowedAuthors returns which authors of the posts the viewer follows,
+// looked up only for posts limited to followers that the viewer did not
+// write.
+func (s *postService) getFollowedAuthors(ctx context.Context, posts []*model.PostModel, viewerID int64) (map[int64]bool, error) {
+	if viewerID == 0 {
+		return nil, nil
+	}
+	var authorIDs []int64
+	for _, post := range posts {
+		if post.ReplyPolicy == model.ReplyFollowers && post.UserID != viewerID && !post.RepliesLocked && !slices.Contains(authorIDs, post.UserID) {
+			authorIDs = append(authorIDs, post.UserID)
+		}
+	}
+	if len(authorIDs) == 0 {
+		return nil, nil
+	}
+	return s.postRepo.GetFollowedUserIDs(ctx, viewerID, authorIDs)
+}
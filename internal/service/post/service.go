@@ -10,11 +10,29 @@ import (
 
 type PostService interface {
 	CreatePost(ctx context.Context, userID int64, req dto.CreatePostRequest) (int64, int, error)
-	GetPostByID(ctx context.Context, id int64) (*dto.PostResponse, int, error)
-	GetPosts(ctx context.Context, page, pageSize int) (*dto.PostsResponse, int, error)
-	GetPostsByUserID(ctx context.Context, userID int64, page, pageSize int) (*dto.PostsResponse, int, error)
+	GetPostByID(ctx context.Context, id, viewerID int64) (*dto.PostResponse, int, error)
+	GetPosts(ctx context.Context, viewerID int64, page, pageSize int) (*dto.PostsResponse, int, error)
+	GetPostsByUserID(ctx context.Context, userID, viewerID int64, page, pageSize int) (*dto.PostsResponse, int, error)
 	UpdatePost(ctx context.Context, userID, postID int64, req dto.UpdatePostRequest) (int, error)
-	DeletePost(ctx context.Context, userID, postID int64) (int, error)
+	DeletePost(ctx context.Context, userID, postID int64, roles []string) (int, error)
+	GetTrash(ctx context.Context, userID int64, page, pageSize int) (*dto.TrashResponse, int, error)
+	RestorePost(ctx context.Context, userID, postID int64) (int, error)
+	PurgeTrash(ctx context.Context) (int, error)
+	PinPost(ctx context.Context, userID int64, req dto.PinPostRequest) (int, error)
+	UnpinPost(ctx context.Context, userID int64) (int, error)
+	GetPinnedPost(ctx context.Context, userID, viewerID int64) (*dto.PostResponse, int, error)
+	SetRepliesLocked(ctx context.Context, userID, postID int64, locked bool) (int, error)
+	GetPostRevisions(ctx context.Context, postID int64) (*dto.PostRevisionsResponse, int, error)
+	GetDrafts(ctx context.Context, userID int64, page, pageSize int) (*dto.PostsResponse, int, error)
+	PublishScheduledPosts(ctx context.Context) (int, error)
+	VotePoll(ctx context.Context, userID, postID int64, req dto.VotePollRequest) (*dto.PollResponse, int, error)
+	FinalizeExpiredPolls(ctx context.Context) (int, error)
+	BookmarkPost(ctx context.Context, userID, postID int64, req dto.BookmarkPostRequest) (int, error)
+	RemoveBookmark(ctx context.Context, userID, postID int64) (int, error)
+	GetBookmarks(ctx context.Context, userID, folderID int64, cursor string, limit int) (*dto.BookmarksResponse, int, error)
+	CreateBookmarkFolder(ctx context.Context, userID int64, req dto.CreateBookmarkFolderRequest) (*dto.BookmarkFolderResponse, int, error)
+	GetBookmarkFolders(ctx context.Context, userID int64) ([]dto.BookmarkFolderResponse, int, error)
+	DeleteBookmarkFolder(ctx context.Context, userID, folderID int64) (int, error)
 }
 
 type postService struct {
@@ -0,0 +1,91 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"math"
+	"net/http"
+	"time"
+)
+
+const trashPurgeBatch = 100
+
+// GetTrash returns a page of the posts and comments the user deleted that
+// can still be restored.
+func (s *postService) GetTrash(ctx context.Context, userID int64, page, pageSize int) (*dto.TrashResponse, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+
+	retention, _ := s.cfg.Trash()
+	items, totalCount, err := s.postRepo.GetTrash(ctx, userID, time.Now().Add(-retention), pageSize, offset)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	itemResponses := make([]dto.TrashItemResponse, 0, len(items))
+	for _, item := range items {
+		itemResponses = append(itemResponses, dto.TrashItemResponse{
+			Type:            item.Type,
+			ID:              item.ID,
+			PostID:          item.PostID,
+			Title:           item.Title,
+			Content:         item.Content,
+			DeletedAt:       item.DeletedAt.Format("2006-01-02 15:04:05"),
+			RestorableUntil: item.DeletedAt.Add(retention).Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+
+	response := &dto.TrashResponse{
+		Items:      itemResponses,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+
+	return response, http.StatusOK, nil
+}
+
+// RestorePost takes a post the user deleted out of the trash.
+func (s *postService) RestorePost(ctx context.Context, userID, postID int64) (int, error) {
+	retention, _ := s.cfg.Trash()
+	restored, err := s.postRepo.RestorePost(ctx, postID, userID, time.Now().Add(-retention))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !restored {
+		return http.StatusNotFound, errors.New("post not found in trash")
+	}
+	return http.StatusOK, nil
+}
+
+// PurgeTrash hard-deletes the posts and comments that were deleted longer
+// than the retention period ago. It returns how many were deleted.
+func (s *postService) PurgeTrash(ctx context.Context) (int, error) {
+	retention, _ := s.cfg.Trash()
+	before := time.Now().Add(-retention)
+
+	purged := 0
+	for {
+		batchPurged, err := s.postRepo.PurgeTrash(ctx, before, trashPurgeBatch)
+		if err != nil {
+			return purged, err
+		}
+		purged += batchPurged
+		// each round takes up to a batch of posts and a batch of comments,
+		// so fewer than one batch in all means neither has more to purge;
+		// rows claimed by another server's purge are skipped, not counted
+		if batchPurged < trashPurgeBatch {
+			return purged, nil
+		}
+	}
+}
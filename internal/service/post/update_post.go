@@ -2,9 +2,14 @@ package post
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
+	"go-twitter/pkg/etag"
 	"net/http"
+	"time"
 )
 
 func (s *postService) UpdatePost(ctx context.Context, userID, postID int64, req dto.UpdatePostRequest) (int, error) {
@@ -13,7 +18,7 @@ func (s *postService) UpdatePost(ctx context.Context, userID, postID int64, req
 		return http.StatusInternalServerError, err
 	}
 
-	if existingPost == nil {
+	if existingPost == nil || !visibleTo(existingPost, userID) {
 		return http.StatusNotFound, nil
 	}
 
@@ -21,16 +26,62 @@ func (s *postService) UpdatePost(ctx context.Context, userID, postID int64, req
 		return http.StatusForbidden, nil
 	}
 
+	// drafts and scheduled posts can be edited until they are published
+	window := s.cfg.EditingWindow()
+	if existingPost.Status == model.PostPublished && existingPost.PublishAt.Valid && time.Since(existingPost.PublishAt.Time) > window {
+		return http.StatusForbidden, fmt.Errorf("posts can only be edited within %s of publishing", window)
+	}
+
+	// a publish_at alone reschedules the post in its current status
+	rescheduling := req.Status != "" || req.PublishAt != nil
+	var status string
+	var publishAt sql.NullTime
+	if rescheduling && existingPost.Status == model.PostPublished {
+		if req.Status != model.PostPublished || req.PublishAt != nil {
+			return http.StatusBadRequest, errors.New("published posts cannot be unpublished or rescheduled")
+		}
+		rescheduling = false
+	}
+	if rescheduling {
+		target := req.Status
+		if target == "" {
+			target = existingPost.Status
+		}
+		status, publishAt, err = resolveSchedule(target, req.PublishAt, time.Now())
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+
+	replyPolicy := existingPost.ReplyPolicy
+	if req.ReplyPolicy != "" {
+		replyPolicy = req.ReplyPolicy
+	}
+
+	if req.IfMatch != "" && !etag.Match(req.IfMatch, etag.FromVersion(existingPost.Version)) {
+		return http.StatusPreconditionFailed, errors.New("post was modified since it was read")
+	}
+
 	post := &model.PostModel{
-		ID:      postID,
-		Title:   req.Title,
-		Content: req.Content,
+		ID:          postID,
+		Title:       req.Title,
+		Content:     req.Content,
+		Version:     existingPost.Version,
+		ReplyPolicy: replyPolicy,
+	}
+	if rescheduling {
+		post.Status = status
+		post.PublishAt = publishAt
 	}
 
-	err = s.postRepo.UpdatePost(ctx, post)
+	updated, err := s.postRepo.UpdatePost(ctx, post)
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
+	// changed, published or deleted since it was read above
+	if !updated {
+		return http.StatusPreconditionFailed, errors.New("post was modified since it was read")
+	}
 
 	return http.StatusOK, nil
 }
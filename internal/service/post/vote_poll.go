@@ -0,0 +1,56 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"slices"
+	"time"
+)
+
+func (s *postService) VotePoll(ctx context.Context, userID, postID int64, req dto.VotePollRequest) (*dto.PollResponse, int, error) {
+	post, err := s.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if post == nil || post.Status != model.PostPublished {
+		return nil, http.StatusNotFound, errors.New("post not found")
+	}
+
+	polls, err := s.postRepo.GetPostPolls(ctx, []int64{postID})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	poll := polls[postID]
+	if poll == nil {
+		return nil, http.StatusNotFound, errors.New("post has no poll")
+	}
+	if pollClosed(poll, time.Now()) {
+		return nil, http.StatusConflict, errors.New("poll is closed")
+	}
+
+	if !poll.MultipleChoice && len(req.OptionIDs) > 1 {
+		return nil, http.StatusBadRequest, errors.New("poll allows a single choice")
+	}
+	for _, optionID := range req.OptionIDs {
+		if !slices.ContainsFunc(poll.Options, func(o model.PollOptionModel) bool { return o.ID == optionID }) {
+			return nil, http.StatusBadRequest, errors.New("invalid poll option")
+		}
+	}
+
+	voted, err := s.postRepo.VotePoll(ctx, postID, userID, req.OptionIDs)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !voted {
+		return nil, http.StatusConflict, errors.New("already voted in this poll")
+	}
+
+	responses, err := s.getPollResponses(ctx, []int64{postID}, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return responses[postID], http.StatusCreated, nil
+}
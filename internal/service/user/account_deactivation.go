@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"time"
+)
+
+// purgeBatchSize bounds how many accounts one pass of the purge job loads
+// at a time.
+const purgeBatchSize = 100
+
+var errAccountDeleted = errors.New("this account has been deleted")
+
+// DeactivateAccount hides the user's posts, comments and likes right away
+// and schedules the account for deletion once the grace period ends.
+func (s *userService) DeactivateAccount(ctx context.Context, userID int64) (*dto.DeactivateAccountResponse, int, error) {
+	grace, _ := s.cfg.AccountDeletion()
+	now := time.Now()
+	purgeAfter := now.Add(grace)
+
+	deactivated, err := s.userRepo.DeactivateUser(ctx, userID, now, purgeAfter)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !deactivated {
+		return nil, http.StatusConflict, errors.New("account is already deactivated")
+	}
+
+	return &dto.DeactivateAccountResponse{
+		DeactivatedAt: now.Format("2006-01-02 15:04:05"),
+		PurgeAfter:    purgeAfter.Format("2006-01-02 15:04:05"),
+	}, http.StatusOK, nil
+}
+
+// restoreAccount reactivates a deactivated account during its grace period.
+// Afterwards the account only waits for the purge job and cannot be used.
+func (s *userService) restoreAccount(ctx context.Context, user *model.UserModel, now time.Time) (int, error) {
+	if !user.PurgeAfter.Valid || !now.Before(user.PurgeAfter.Time) {
+		return http.StatusForbidden, errAccountDeleted
+	}
+
+	restored, err := s.userRepo.ReactivateUser(ctx, user.ID, now)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !restored {
+		return http.StatusForbidden, errAccountDeleted
+	}
+	user.DeactivatedAt.Valid = false
+	user.PurgeAfter.Valid = false
+	return http.StatusOK, nil
+}
+
+// PurgeDeactivatedAccounts deletes every account whose grace period is over
+// and returns how many were purged.
+func (s *userService) PurgeDeactivatedAccounts(ctx context.Context) (int, error) {
+	now := time.Now()
+	purged := 0
+	for {
+		ids, err := s.userRepo.GetUsersDueForPurge(ctx, now, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, id := range ids {
+			ok, err := s.userRepo.PurgeUser(ctx, id, now)
+			if err != nil {
+				return purged, fmt.Errorf("purge user %d: %w", id, err)
+			}
+			if ok {
+				purged++
+			}
+		}
+
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+func (s *userService) FollowUser(ctx context.Context, userID, followeeID int64) (int, error) {
+	if userID == followeeID {
+		return http.StatusBadRequest, errors.New("you cannot follow yourself")
+	}
+
+	isFollowing, err := s.userRepo.IsFollowing(ctx, userID, followeeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if isFollowing {
+		return http.StatusConflict, nil
+	}
+
+	followed, err := s.userRepo.FollowUser(ctx, userID, followeeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !followed {
+		return http.StatusNotFound, errors.New("user not found")
+	}
+	return http.StatusCreated, nil
+}
+
+func (s *userService) UnfollowUser(ctx context.Context, userID, followeeID int64) (int, error) {
+	isFollowing, err := s.userRepo.IsFollowing(ctx, userID, followeeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !isFollowing {
+		return http.StatusNotFound, nil
+	}
+
+	if err := s.userRepo.UnfollowUser(ctx, userID, followeeID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
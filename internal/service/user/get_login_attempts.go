@@ -0,0 +1,40 @@
+package user
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"math"
+	"net/http"
+)
+
+func (s *userService) GetLoginAttempts(ctx context.Context, userID int64, page, pageSize int) (*dto.LoginAttemptsResponse, int, error) {
+	offset := (page - 1) * pageSize
+
+	attempts, totalCount, err := s.userRepo.GetLoginAttemptsByUserID(ctx, userID, pageSize, offset)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	attemptResponses := []dto.LoginAttemptResponse{}
+	for _, attempt := range attempts {
+		attemptResponses = append(attemptResponses, dto.LoginAttemptResponse{
+			ID:        attempt.ID,
+			IPAddress: attempt.IPAddress,
+			UserAgent: attempt.UserAgent,
+			Success:   attempt.Success,
+			CreatedAt: attempt.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+
+	response := &dto.LoginAttemptsResponse{
+		LoginAttempts: attemptResponses,
+		TotalCount:    totalCount,
+		Page:          page,
+		PageSize:      pageSize,
+		TotalPages:    totalPages,
+	}
+
+	return response, http.StatusOK, nil
+}
@@ -12,15 +12,16 @@ func (s *userService) GetUserByID(ctx context.Context, id int64) (*dto.GetUserRe
 		return nil, http.StatusInternalServerError, err
 	}
 
-	if user == nil {
+	if user == nil || user.DeactivatedAt.Valid {
 		return nil, http.StatusNotFound, nil
 	}
 
 	response := &dto.GetUserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+		ID:           user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		CreatedAt:    user.CreatedAt.Format("2006-01-02 15:04:05"),
+		LastModified: user.UpdatedAt,
 	}
 
 	return response, http.StatusOK, nil
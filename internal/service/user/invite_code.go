@@ -0,0 +1,147 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInviteExpiryDays = 7
+	inviteCodePrefixLength  = 4
+)
+
+func (s *userService) CreateInvite(ctx context.Context, userID int64, req dto.CreateInviteRequest) (*dto.CreateInviteResponse, int, error) {
+	roles, err := s.userRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	maxUses := req.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+
+	// regular users hand out single-use codes from a fixed quota
+	if !model.HasPermission(roles, model.PermissionManageInvites) {
+		if maxUses > 1 {
+			return nil, http.StatusForbidden, errors.New("only admins can create multi-use invites")
+		}
+		count, err := s.userRepo.CountInviteCodesByCreator(ctx, userID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if count >= s.cfg.InviteCodeQuota() {
+			return nil, http.StatusForbidden, errors.New("invite quota reached")
+		}
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultInviteExpiryDays
+	}
+
+	now := time.Now()
+	invite := &model.InviteCodeModel{
+		CreatedBy:  userID,
+		CodeHash:   hashInviteCode(code),
+		CodePrefix: code[:inviteCodePrefixLength],
+		MaxUses:    maxUses,
+		ExpiresAt:  now.AddDate(0, 0, days),
+		CreatedAt:  now,
+	}
+	invite.ID, err = s.userRepo.CreateInviteCode(ctx, invite)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.CreateInviteResponse{
+		InviteResponse: toInviteResponse(invite, nil),
+		Code:           code,
+	}, http.StatusCreated, nil
+}
+
+// GetInvites lists the user's invite codes with the users who joined
+// through each of them.
+func (s *userService) GetInvites(ctx context.Context, userID int64) ([]dto.InviteResponse, int, error) {
+	invites, err := s.userRepo.GetInviteCodesByCreator(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	redemptions, err := s.userRepo.GetInviteRedemptionsByInviter(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	byInvite := make(map[int64][]*model.InviteRedemptionModel)
+	for _, redemption := range redemptions {
+		byInvite[redemption.InviteCodeID] = append(byInvite[redemption.InviteCodeID], redemption)
+	}
+
+	responses := []dto.InviteResponse{}
+	for _, invite := range invites {
+		responses = append(responses, toInviteResponse(invite, byInvite[invite.ID]))
+	}
+	return responses, http.StatusOK, nil
+}
+
+func (s *userService) RevokeInvite(ctx context.Context, userID, inviteID int64) (int, error) {
+	revoked, err := s.userRepo.RevokeInviteCode(ctx, userID, inviteID, time.Now())
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !revoked {
+		return http.StatusNotFound, errors.New("invite not found")
+	}
+	return http.StatusOK, nil
+}
+
+// generateInviteCode returns a 16 character base32 code, easy to read out
+// or type from a message.
+func generateInviteCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
+
+func hashInviteCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashSecret(normalized)
+}
+
+func toInviteResponse(invite *model.InviteCodeModel, redemptions []*model.InviteRedemptionModel) dto.InviteResponse {
+	response := dto.InviteResponse{
+		ID:         invite.ID,
+		CodePrefix: invite.CodePrefix,
+		MaxUses:    invite.MaxUses,
+		Uses:       invite.Uses,
+		ExpiresAt:  invite.ExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:  invite.CreatedAt.Format("2006-01-02 15:04:05"),
+		Invitees:   []dto.InviteeResponse{},
+	}
+	if invite.RevokedAt.Valid {
+		revokedAt := invite.RevokedAt.Time.Format("2006-01-02 15:04:05")
+		response.RevokedAt = &revokedAt
+	}
+	for _, redemption := range redemptions {
+		response.Invitees = append(response.Invitees, dto.InviteeResponse{
+			UserID:   redemption.UserID,
+			Username: redemption.Username,
+			JoinedAt: redemption.CreatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	return response
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"context"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/jwt"
+	"go-twitter/pkg/refreshtoken"
+	"net/http"
+	"time"
+)
+
+// issueTokens returns an access token and refresh token for a user who has
+// completed every login step. Logging in to a deactivated account restores
+// it.
+func (s *userService) issueTokens(ctx context.Context, user *model.UserModel) (*dto.LoginResponse, int, error) {
+	now := time.Now()
+	reactivated := false
+	if user.DeactivatedAt.Valid {
+		if status, err := s.restoreAccount(ctx, user, now); err != nil {
+			return nil, status, err
+		}
+		reactivated = true
+	}
+
+	// generate access token
+	roles, err := s.userRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	accessToken, err := jwt.CreateToken(user.ID, user.Username, roles, s.cfg.JWTOptions())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	// get refresh token if exist
+	refreshToken, err := s.userRepo.GetRefreshToken(ctx, user.ID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if refreshToken != nil {
+		return &dto.LoginResponse{Token: accessToken, RefreshToken: refreshToken.RefreshToken, Reactivated: reactivated}, http.StatusOK, nil
+	}
+	// generate refresh token
+	refreshTokenString, err := refreshtoken.GenerateRefreshToken()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	// save refresh token
+	err = s.userRepo.StoreRefreshToken(ctx, &model.RefreshTokenModel{
+		UserID:       user.ID,
+		RefreshToken: refreshTokenString,
+		ExpiresAt:    now.Add(s.cfg.RefreshTokenExpiry()),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	// return access token and refresh token
+	return &dto.LoginResponse{Token: accessToken, RefreshToken: refreshTokenString, Reactivated: reactivated}, http.StatusOK, nil
+}
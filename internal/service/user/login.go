@@ -6,57 +6,86 @@ import (
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
 	"go-twitter/pkg/jwt"
-	"go-twitter/pkg/refreshtoken"
 	"net/http"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-func (s *userService) Login(ctx context.Context, req dto.LoginRequest) (string, string, int, error) {
+func (s *userService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, int, error) {
+	now := time.Now()
+	if status, err := s.checkLoginThrottle(ctx, req.Email, req.IPAddress, now); err != nil {
+		return nil, status, err
+	}
+
 	// check user exist
 	userExist, err := s.userRepo.GetUserByEmailOrUsername(ctx, req.Email, "")
 	if err != nil {
-		return "", "", http.StatusInternalServerError, err
+		return nil, http.StatusInternalServerError, err
 	}
 	if userExist == nil {
-		return "", "", http.StatusBadRequest, errors.New("Please check your credentials and try again")
+		if req.Passkey == nil {
+			compareDummyPassword(req.Password)
+		}
+		if err := s.recordLoginAttempt(ctx, 0, req.Email, req.IPAddress, req.UserAgent, false, now); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return nil, http.StatusBadRequest, errors.New("Please check your credentials and try again")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(userExist.Password), []byte(req.Password)); err != nil {
-		return "", "", http.StatusBadRequest, errors.New("Please check your credentials and try again")
+	ok, userVerified, err := s.checkFirstFactor(ctx, userExist, req)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !ok {
+		if err := s.recordLoginAttempt(ctx, userExist.ID, req.Email, req.IPAddress, req.UserAgent, false, now); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return nil, http.StatusBadRequest, errors.New("Please check your credentials and try again")
 	}
-	// generate access token
 
-	accessToken, err := jwt.CreateToken(userExist.ID, userExist.Username, s.cfg.SecreetJwt)
-	if err != nil {
-		return "", "", http.StatusInternalServerError, err
+	// a passkey that verified the user is already two factors
+	if userVerified {
+		if err := s.recordLoginAttempt(ctx, userExist.ID, req.Email, req.IPAddress, req.UserAgent, true, now); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return s.issueTokens(ctx, userExist)
 	}
-	// get refresh token if exist
-	now := time.Now()
-	refreshToken, err := s.userRepo.GetRefreshToken(ctx, userExist.ID, now)
-	if err != nil {
-		return "", "", http.StatusInternalServerError, err
+	return s.completeLogin(ctx, userExist, req.Email, req.IPAddress, req.UserAgent, now)
+}
+
+// checkFirstFactor verifies the password, or the passkey when one is sent
+// instead. It also reports whether a passkey verified the user.
+func (s *userService) checkFirstFactor(ctx context.Context, user *model.UserModel, req dto.LoginRequest) (bool, bool, error) {
+	if req.Passkey != nil {
+		return s.verifyPasskey(ctx, user.ID, req.Passkey)
 	}
-	if refreshToken != nil {
-		return accessToken, refreshToken.RefreshToken, http.StatusOK, nil
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return false, false, nil
 	}
-	// generate refresh token
-	refreshTokenString, err := refreshtoken.GenerateRefreshToken()
-	if err != nil {
-		return "", "", http.StatusInternalServerError, err
-	}
-	// save refresh token
-	err = s.userRepo.StoreRefreshToken(ctx, &model.RefreshTokenModel{
-		UserID: userExist.ID,
-		RefreshToken: refreshTokenString,
-		ExpiresAt: now.Add(time.Hour * 24 * 7),
-		CreatedAt: now,
-		UpdatedAt: now,
-	})
+	return true, false, nil
+}
+
+// completeLogin finishes a login whose first factor succeeded. With 2FA
+// enabled the first factor only earns a challenge token, and the login
+// counts as successful once LoginMFA accepts the second factor; recording it
+// any earlier would let each correct password reset the lockout on guessing
+// codes.
+func (s *userService) completeLogin(ctx context.Context, user *model.UserModel, email, ip, userAgent string, now time.Time) (*dto.LoginResponse, int, error) {
+	totpConfig, err := s.userRepo.GetUserTOTP(ctx, user.ID)
 	if err != nil {
-		return "", "", http.StatusInternalServerError, err
+		return nil, http.StatusInternalServerError, err
+	}
+	if totpConfig != nil && totpConfig.ConfirmedAt.Valid {
+		mfaToken, err := jwt.CreateMFAToken(user.ID, user.Username, s.cfg.JWTOptions(), s.cfg.MFAChallengeExpiry())
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return &dto.LoginResponse{MFARequired: true, MFAToken: mfaToken}, http.StatusOK, nil
+	}
+
+	if err := s.recordLoginAttempt(ctx, user.ID, email, ip, userAgent, true, now); err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
-	// return access token and refresh token
-	return accessToken, refreshTokenString, http.StatusOK, nil
+	return s.issueTokens(ctx, user)
 }
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/mailer"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var errInvalidMagicLink = errors.New("sign-in link is invalid, expired or already used")
+
+// RequestMagicLink emails a one-time sign-in link. The response is the same
+// whether or not the email belongs to an account; requests for unknown
+// emails are recorded so they are rate limited like real ones.
+func (s *userService) RequestMagicLink(ctx context.Context, req dto.MagicLinkRequest) (int, error) {
+	now := time.Now()
+	maxRequests, window := s.cfg.MagicLinkRateLimit()
+
+	count, err := s.userRepo.CountMagicLinkTokensByEmail(ctx, req.Email, now.Add(-window))
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if count >= maxRequests {
+		return http.StatusTooManyRequests, errors.New("too many sign-in links requested, try again later")
+	}
+
+	user, err := s.userRepo.GetUserByEmailOrUsername(ctx, req.Email, "")
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	linkToken := &model.MagicLinkTokenModel{
+		Email:           req.Email,
+		TokenHash:       hashSecret(token),
+		FingerprintHash: hashSecret(req.Fingerprint),
+		ExpiresAt:       now.Add(s.cfg.MagicLinkExpiry()),
+		CreatedAt:       now,
+	}
+	if user != nil {
+		linkToken.UserID = sql.NullInt64{Int64: user.ID, Valid: true}
+	}
+	if err := s.userRepo.CreateMagicLinkToken(ctx, linkToken); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if user == nil {
+		return http.StatusAccepted, nil
+	}
+
+	link, err := magicLinkURL(s.cfg.MagicLinkURL, token)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your sign-in link",
+		Body: fmt.Sprintf("Hi %s,\n\nUse this link to sign in. It works once, within %s, in the browser you requested it from:\n\n%s\n\nIf you did not ask to sign in, you can ignore this email.\n",
+			user.Username, s.cfg.MagicLinkExpiry(), link),
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusAccepted, nil
+}
+
+// ConsumeMagicLink exchanges an emailed token for a login. The token is
+// spent by the first attempt, even one from the wrong browser.
+func (s *userService) ConsumeMagicLink(ctx context.Context, req dto.ConsumeMagicLinkRequest) (*dto.LoginResponse, int, error) {
+	now := time.Now()
+	token, err := s.userRepo.ConsumeMagicLinkToken(ctx, hashSecret(req.Token), now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if token == nil || !token.UserID.Valid || !now.Before(token.ExpiresAt) {
+		return nil, http.StatusBadRequest, errInvalidMagicLink
+	}
+	if subtle.ConstantTimeCompare([]byte(token.FingerprintHash), []byte(hashSecret(req.Fingerprint))) != 1 {
+		return nil, http.StatusBadRequest, errors.New("sign-in link must be opened in the browser that requested it")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, token.UserID.Int64)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		return nil, http.StatusBadRequest, errInvalidMagicLink
+	}
+
+	return s.completeLogin(ctx, user, user.Email, req.IPAddress, req.UserAgent, now)
+}
+
+func magicLinkURL(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
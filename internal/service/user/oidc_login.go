@@ -0,0 +1,220 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const oidcLoginStateTTL = 10 * time.Minute
+
+// StartOIDCLogin returns the provider URL to send the user to. The state and
+// nonce are remembered server side so the callback can only be completed
+// once, for the redirect this call started.
+func (s *userService) StartOIDCLogin(ctx context.Context, providerName string) (*dto.OIDCAuthorizationResponse, int, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, http.StatusNotFound, errors.New("unknown login provider")
+	}
+
+	state, err := randomHex(32)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	nonce, err := randomHex(32)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+
+	now := time.Now()
+	err = s.userRepo.CreateOIDCLoginState(ctx, &model.OIDCLoginStateModel{
+		StateHash: hashSecret(state),
+		Provider:  providerName,
+		Nonce:     nonce,
+		ExpiresAt: now.Add(oidcLoginStateTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.OIDCAuthorizationResponse{AuthorizationURL: authURL}, http.StatusOK, nil
+}
+
+// CompleteOIDCLogin exchanges the provider's code, verifies the ID token and
+// signs the user in. A first login with a new provider is linked to the
+// account with the same verified email, or creates a new account.
+func (s *userService) CompleteOIDCLogin(ctx context.Context, providerName string, req dto.OIDCCallbackRequest) (*dto.LoginResponse, int, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return nil, http.StatusNotFound, errors.New("unknown login provider")
+	}
+
+	now := time.Now()
+	state, err := s.userRepo.ConsumeOIDCLoginState(ctx, hashSecret(req.State), now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if state == nil || state.Provider != providerName {
+		return nil, http.StatusBadRequest, errors.New("login session is invalid or expired, please try again")
+	}
+
+	rawIDToken, err := provider.Exchange(ctx, req.Code)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+	idToken, err := provider.VerifyIDToken(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("provider returned an invalid ID token")
+	}
+
+	user, status, err := s.userForIdentity(ctx, providerName, idToken.Subject, idToken.Email, idToken.EmailVerified, now)
+	if err != nil {
+		return nil, status, err
+	}
+
+	return s.completeLogin(ctx, user, user.Email, req.IPAddress, req.UserAgent, now)
+}
+
+func (s *userService) userForIdentity(ctx context.Context, provider, subject, email string, emailVerified bool, now time.Time) (*model.UserModel, int, error) {
+	identity, err := s.userRepo.GetUserIdentity(ctx, provider, subject)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.GetUserByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if user == nil {
+			return nil, http.StatusUnauthorized, errors.New("linked account no longer exists")
+		}
+		return user, http.StatusOK, nil
+	}
+
+	// only a verified email may claim an existing account
+	if email == "" || !emailVerified {
+		return nil, http.StatusBadRequest, errors.New("provider did not return a verified email")
+	}
+
+	user, err := s.userRepo.GetUserByEmailOrUsername(ctx, email, "")
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		// provider sign-ups bypass invites, so only open registration allows them
+		if s.cfg.Registration() != config.RegistrationOpen {
+			return nil, http.StatusForbidden, errors.New("registration is closed")
+		}
+		user, err = s.createOIDCUser(ctx, email, now)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	err = s.userRepo.CreateUserIdentity(ctx, &model.UserIdentityModel{
+		UserID:    user.ID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     email,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return user, http.StatusOK, nil
+}
+
+// createOIDCUser registers a user who signed up through a provider. The
+// password is a random value nobody knows, so the account can only sign in
+// through a linked provider.
+func (s *userService) createOIDCUser(ctx context.Context, email string, now time.Time) (*model.UserModel, error) {
+	username, err := s.availableUsername(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.UserModel{
+		Username:  username,
+		Email:     email,
+		Password:  string(hashedPassword),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	user.ID, err = s.userRepo.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// availableUsername derives a username from the email's local part, adding a
+// random suffix when it is taken.
+func (s *userService) availableUsername(ctx context.Context, email string) (string, error) {
+	base := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(strings.SplitN(email, "@", 2)[0]))
+	if len(base) < 3 {
+		base += "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		existing, err := s.userRepo.GetUserByEmailOrUsername(ctx, "", candidate)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+		suffix, err := randomHex(3)
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + suffix
+	}
+	return "", errors.New("could not find a free username")
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,278 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/webauthn"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	passkeyChallengeTTL = 5 * time.Minute
+	maxPasskeys         = 20
+)
+
+var errInvalidPasskeyChallenge = errors.New("passkey challenge is invalid or expired, please try again")
+
+// BeginPasskeyRegistration returns the options for creating a new passkey.
+// The user's existing passkeys are excluded so an authenticator is not
+// registered twice.
+func (s *userService) BeginPasskeyRegistration(ctx context.Context, userID int64) (*dto.PasskeyCreationOptions, int, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		return nil, http.StatusNotFound, errors.New("user not found")
+	}
+
+	credentials, err := s.userRepo.GetWebAuthnCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(credentials) >= maxPasskeys {
+		return nil, http.StatusBadRequest, errors.New("passkey limit reached, delete an unused passkey first")
+	}
+
+	challenge, err := s.createWebAuthnChallenge(ctx, userID, model.WebAuthnCeremonyRegistration)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	rp := s.cfg.WebAuthn()
+	return &dto.PasskeyCreationOptions{
+		Challenge: webauthn.EncodeToString(challenge),
+		RP:        dto.PasskeyRelyingParty{ID: rp.RPID, Name: rp.RPName},
+		User: dto.PasskeyUser{
+			ID:          webauthn.EncodeToString([]byte(strconv.FormatInt(user.ID, 10))),
+			Name:        user.Email,
+			DisplayName: user.Username,
+		},
+		PubKeyCredParams: []dto.PasskeyCredentialParameter{
+			{Type: "public-key", Alg: webauthn.AlgES256},
+			{Type: "public-key", Alg: webauthn.AlgRS256},
+		},
+		ExcludeCredentials:     credentialDescriptors(credentials),
+		AuthenticatorSelection: dto.PasskeyAuthenticatorSelection{ResidentKey: "preferred", UserVerification: "preferred"},
+		Attestation:            "none",
+		Timeout:                passkeyChallengeTTL.Milliseconds(),
+	}, http.StatusOK, nil
+}
+
+func (s *userService) FinishPasskeyRegistration(ctx context.Context, userID int64, req dto.FinishPasskeyRegistrationRequest) (*dto.PasskeyResponse, int, error) {
+	credentialID, err1 := webauthn.DecodeString(req.ID)
+	clientDataJSON, err2 := webauthn.DecodeString(req.ClientDataJSON)
+	attestationObject, err3 := webauthn.DecodeString(req.AttestationObject)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, http.StatusBadRequest, errors.New("passkey fields must be base64url encoded")
+	}
+
+	challenge, status, err := s.consumeWebAuthnChallenge(ctx, userID, model.WebAuthnCeremonyRegistration, clientDataJSON)
+	if err != nil {
+		return nil, status, err
+	}
+
+	verified, err := webauthn.VerifyRegistration(s.cfg.WebAuthn(), challenge, clientDataJSON, attestationObject)
+	if err != nil || !bytes.Equal(verified.ID, credentialID) {
+		return nil, http.StatusBadRequest, errors.New("passkey registration could not be verified")
+	}
+
+	existing, err := s.userRepo.GetWebAuthnCredentialByCredentialID(ctx, verified.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if existing != nil {
+		return nil, http.StatusConflict, errors.New("passkey is already registered")
+	}
+
+	credential := &model.WebAuthnCredentialModel{
+		UserID:       userID,
+		Name:         req.Name,
+		CredentialID: verified.ID,
+		PublicKey:    verified.PublicKey,
+		SignCount:    verified.SignCount,
+		CreatedAt:    time.Now(),
+	}
+	credential.ID, err = s.userRepo.CreateWebAuthnCredential(ctx, credential)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	response := toPasskeyResponse(credential)
+	return &response, http.StatusCreated, nil
+}
+
+func (s *userService) GetPasskeys(ctx context.Context, userID int64) ([]dto.PasskeyResponse, int, error) {
+	credentials, err := s.userRepo.GetWebAuthnCredentialsByUserID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	responses := []dto.PasskeyResponse{}
+	for _, credential := range credentials {
+		responses = append(responses, toPasskeyResponse(credential))
+	}
+	return responses, http.StatusOK, nil
+}
+
+func (s *userService) DeletePasskey(ctx context.Context, userID, passkeyID int64) (int, error) {
+	deleted, err := s.userRepo.DeleteWebAuthnCredential(ctx, userID, passkeyID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !deleted {
+		return http.StatusNotFound, errors.New("passkey not found")
+	}
+	return http.StatusOK, nil
+}
+
+// BeginPasskeyLogin returns the options for signing in with a passkey, as
+// the first factor through Login or the second through LoginMFA. Unknown
+// emails get the same response as accounts without passkeys.
+func (s *userService) BeginPasskeyLogin(ctx context.Context, req dto.BeginPasskeyLoginRequest) (*dto.PasskeyRequestOptions, int, error) {
+	user, err := s.userRepo.GetUserByEmailOrUsername(ctx, req.Email, "")
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	var (
+		challenge   []byte
+		credentials []*model.WebAuthnCredentialModel
+	)
+	if user != nil {
+		credentials, err = s.userRepo.GetWebAuthnCredentialsByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		challenge, err = s.createWebAuthnChallenge(ctx, user.ID, model.WebAuthnCeremonyAuthentication)
+	} else {
+		challenge, err = newWebAuthnChallenge()
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.PasskeyRequestOptions{
+		Challenge:        webauthn.EncodeToString(challenge),
+		RPID:             s.cfg.WebAuthn().RPID,
+		AllowCredentials: credentialDescriptors(credentials),
+		UserVerification: "preferred",
+		Timeout:          passkeyChallengeTTL.Milliseconds(),
+	}, http.StatusOK, nil
+}
+
+// verifyPasskey checks a login assertion from one of the user's passkeys and
+// records its new signature counter. It reports whether the assertion is
+// valid and whether the authenticator verified the user, e.g. by biometrics
+// or PIN. Only storage failures are returned as errors.
+func (s *userService) verifyPasskey(ctx context.Context, userID int64, assertion *dto.PasskeyAssertion) (bool, bool, error) {
+	credentialID, err1 := webauthn.DecodeString(assertion.ID)
+	clientDataJSON, err2 := webauthn.DecodeString(assertion.ClientDataJSON)
+	authenticatorData, err3 := webauthn.DecodeString(assertion.AuthenticatorData)
+	signature, err4 := webauthn.DecodeString(assertion.Signature)
+	if errors.Join(err1, err2, err3, err4) != nil {
+		return false, false, nil
+	}
+
+	challenge, status, err := s.consumeWebAuthnChallenge(ctx, userID, model.WebAuthnCeremonyAuthentication, clientDataJSON)
+	if err != nil {
+		if status == http.StatusInternalServerError {
+			return false, false, err
+		}
+		return false, false, nil
+	}
+
+	credential, err := s.userRepo.GetWebAuthnCredentialByCredentialID(ctx, credentialID)
+	if err != nil {
+		return false, false, err
+	}
+	if credential == nil || credential.UserID != userID {
+		return false, false, nil
+	}
+
+	verified, err := webauthn.VerifyAssertion(s.cfg.WebAuthn(), challenge, credential.PublicKey, credential.SignCount, clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		return false, false, nil
+	}
+
+	if err := s.userRepo.UpdateWebAuthnSignCount(ctx, credential.ID, verified.SignCount, time.Now()); err != nil {
+		return false, false, err
+	}
+	return true, verified.UserVerified, nil
+}
+
+func (s *userService) createWebAuthnChallenge(ctx context.Context, userID int64, ceremony string) ([]byte, error) {
+	challenge, err := newWebAuthnChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.userRepo.CreateWebAuthnChallenge(ctx, &model.WebAuthnChallengeModel{
+		UserID:        userID,
+		ChallengeHash: hashSecret(string(challenge)),
+		Ceremony:      ceremony,
+		ExpiresAt:     now.Add(passkeyChallengeTTL),
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// consumeWebAuthnChallenge spends the challenge named in clientDataJSON,
+// which must have been issued to userID for the given ceremony.
+func (s *userService) consumeWebAuthnChallenge(ctx context.Context, userID int64, ceremony string, clientDataJSON []byte) ([]byte, int, error) {
+	challenge, err := webauthn.Challenge(clientDataJSON)
+	if err != nil {
+		return nil, http.StatusBadRequest, errInvalidPasskeyChallenge
+	}
+
+	stored, err := s.userRepo.ConsumeWebAuthnChallenge(ctx, hashSecret(string(challenge)), time.Now())
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if stored == nil || stored.UserID != userID || stored.Ceremony != ceremony {
+		return nil, http.StatusBadRequest, errInvalidPasskeyChallenge
+	}
+	return challenge, http.StatusOK, nil
+}
+
+func newWebAuthnChallenge() ([]byte, error) {
+	challenge := make([]byte, webauthn.ChallengeLength)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func credentialDescriptors(credentials []*model.WebAuthnCredentialModel) []dto.PasskeyCredentialDescriptor {
+	descriptors := []dto.PasskeyCredentialDescriptor{}
+	for _, credential := range credentials {
+		descriptors = append(descriptors, dto.PasskeyCredentialDescriptor{
+			Type: "public-key",
+			ID:   webauthn.EncodeToString(credential.CredentialID),
+		})
+	}
+	return descriptors
+}
+
+func toPasskeyResponse(credential *model.WebAuthnCredentialModel) dto.PasskeyResponse {
+	response := dto.PasskeyResponse{
+		ID:        credential.ID,
+		Name:      credential.Name,
+		CreatedAt: credential.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if credential.LastUsedAt.Valid {
+		lastUsedAt := credential.LastUsedAt.Time.Format("2006-01-02 15:04:05")
+		response.LastUsedAt = &lastUsedAt
+	}
+	return response
+}
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/pat"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultPersonalAccessTokenDays = 90
+	maxPersonalAccessTokens        = 50
+)
+
+func (s *userService) CreatePersonalAccessToken(ctx context.Context, userID int64, req dto.CreatePersonalAccessTokenRequest) (*dto.CreatePersonalAccessTokenResponse, int, error) {
+	existing, err := s.userRepo.GetPersonalAccessTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(existing) >= maxPersonalAccessTokens {
+		return nil, http.StatusBadRequest, errors.New("personal access token limit reached, delete an unused token first")
+	}
+
+	tokenString, err := pat.Generate()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultPersonalAccessTokenDays
+	}
+
+	now := time.Now()
+	token := &model.PersonalAccessTokenModel{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenHash:   pat.Hash(tokenString),
+		TokenPrefix: pat.DisplayPrefix(tokenString),
+		Scopes:      model.UniqueScopes(req.Scopes),
+		ExpiresAt:   now.AddDate(0, 0, days),
+		CreatedAt:   now,
+	}
+
+	token.ID, err = s.userRepo.CreatePersonalAccessToken(ctx, token)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.CreatePersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: toPersonalAccessTokenResponse(token),
+		Token:                       tokenString,
+	}, http.StatusCreated, nil
+}
+
+func (s *userService) GetPersonalAccessTokens(ctx context.Context, userID int64) ([]dto.PersonalAccessTokenResponse, int, error) {
+	tokens, err := s.userRepo.GetPersonalAccessTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	responses := []dto.PersonalAccessTokenResponse{}
+	for _, token := range tokens {
+		responses = append(responses, toPersonalAccessTokenResponse(token))
+	}
+	return responses, http.StatusOK, nil
+}
+
+func (s *userService) DeletePersonalAccessToken(ctx context.Context, userID, tokenID int64) (int, error) {
+	deleted, err := s.userRepo.DeletePersonalAccessToken(ctx, userID, tokenID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !deleted {
+		return http.StatusNotFound, errors.New("personal access token not found")
+	}
+	return http.StatusOK, nil
+}
+
+func toPersonalAccessTokenResponse(token *model.PersonalAccessTokenModel) dto.PersonalAccessTokenResponse {
+	response := dto.PersonalAccessTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.Scopes,
+		ExpiresAt:   token.ExpiresAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:   token.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if token.LastUsedAt.Valid {
+		lastUsedAt := token.LastUsedAt.Time.Format("2006-01-02 15:04:05")
+		response.LastUsedAt = &lastUsedAt
+	}
+	return response
+}
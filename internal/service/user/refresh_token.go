@@ -33,7 +33,12 @@ func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequ
 		return "", "", http.StatusUnauthorized, nil
 	}
 
-	token, err := jwt.CreateToken(user.ID, user.Username, s.cfg.SecreetJwt)
+	roles, err := s.userRepo.GetUserRoles(ctx, user.ID)
+	if err != nil {
+		return "", "", http.StatusInternalServerError, err
+	}
+
+	token, err := jwt.CreateToken(user.ID, user.Username, roles, s.cfg.JWTOptions())
 	if err != nil {
 		return "", "", http.StatusInternalServerError, err
 	}
@@ -48,7 +53,7 @@ func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequ
 		return "", "", http.StatusInternalServerError, err
 	}
 
-	refreshTokenExpiry := time.Now().Add(7 * 24 * time.Hour)
+	refreshTokenExpiry := time.Now().Add(s.cfg.RefreshTokenExpiry())
 	refreshTokenModelNew := &model.RefreshTokenModel{
 		UserID:       user.ID,
 		RefreshToken: newRefreshToken,
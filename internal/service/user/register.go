@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"errors"
+	"go-twitter/internal/config"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
 	"net/http"
@@ -12,6 +13,14 @@ import (
 )
 
 func (s *userService) Register(ctx context.Context, req dto.RegisterRequest) (int64, int, error) {
+	mode := s.cfg.Registration()
+	if mode == config.RegistrationClosed {
+		return 0, http.StatusForbidden, errors.New("registration is closed")
+	}
+	if mode == config.RegistrationInviteOnly && req.InviteCode == "" {
+		return 0, http.StatusForbidden, errors.New("an invite code is required to register")
+	}
+
 	// check user already exists
 	userExists, err := s.userRepo.GetUserByEmailOrUsername(ctx, req.Email, req.Username)
 	if err != nil {
@@ -37,7 +46,18 @@ func (s *userService) Register(ctx context.Context, req dto.RegisterRequest) (in
 		UpdatedAt: now,
 	}
 
-	// save user
+	// save user, spending the invite in invite-only mode
+	if mode == config.RegistrationInviteOnly {
+		id, ok, err := s.userRepo.CreateInvitedUser(ctx, user, hashInviteCode(req.InviteCode), now)
+		if err != nil {
+			return 0, http.StatusInternalServerError, err
+		}
+		if !ok {
+			return 0, http.StatusBadRequest, errors.New("invite code is invalid, expired or used up")
+		}
+		return id, http.StatusOK, nil
+	}
+
 	id, err := s.userRepo.CreateUser(ctx, user)
 	if err != nil {
 		return 0, http.StatusInternalServerError, err
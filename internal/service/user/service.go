@@ -5,24 +5,72 @@ import (
 	"go-twitter/internal/config"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/repository/user"
+	"go-twitter/pkg/mailer"
+	"go-twitter/pkg/oidc"
+	"net/http"
+	"time"
 )
 
 type UserService interface {
 	Register(ctx context.Context, req dto.RegisterRequest) (int64, int, error)
-	Login(ctx context.Context, req dto.LoginRequest) (string, string, int, error)
+	Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, int, error)
+	LoginMFA(ctx context.Context, req dto.LoginMFARequest) (*dto.LoginResponse, int, error)
 	RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (string, string, int, error)
 	Logout(ctx context.Context, req dto.LogoutRequest) (int, error)
 	GetUserByID(ctx context.Context, id int64) (*dto.GetUserResponse, int, error)
+	GetLoginAttempts(ctx context.Context, userID int64, page, pageSize int) (*dto.LoginAttemptsResponse, int, error)
+
+	AssignRole(ctx context.Context, userID int64, role string) (*dto.UserRolesResponse, int, error)
+	RemoveRole(ctx context.Context, userID int64, role string) (*dto.UserRolesResponse, int, error)
+
+	EnrollTOTP(ctx context.Context, userID int64) (*dto.EnrollTOTPResponse, int, error)
+	ConfirmTOTP(ctx context.Context, userID int64, req dto.ConfirmTOTPRequest) (*dto.ConfirmTOTPResponse, int, error)
+	DisableTOTP(ctx context.Context, userID int64, req dto.DisableTOTPRequest) (int, error)
+
+	CreatePersonalAccessToken(ctx context.Context, userID int64, req dto.CreatePersonalAccessTokenRequest) (*dto.CreatePersonalAccessTokenResponse, int, error)
+	GetPersonalAccessTokens(ctx context.Context, userID int64) ([]dto.PersonalAccessTokenResponse, int, error)
+	DeletePersonalAccessToken(ctx context.Context, userID, tokenID int64) (int, error)
+
+	StartOIDCLogin(ctx context.Context, provider string) (*dto.OIDCAuthorizationResponse, int, error)
+	CompleteOIDCLogin(ctx context.Context, provider string, req dto.OIDCCallbackRequest) (*dto.LoginResponse, int, error)
+
+	RequestMagicLink(ctx context.Context, req dto.MagicLinkRequest) (int, error)
+	ConsumeMagicLink(ctx context.Context, req dto.ConsumeMagicLinkRequest) (*dto.LoginResponse, int, error)
+
+	BeginPasskeyRegistration(ctx context.Context, userID int64) (*dto.PasskeyCreationOptions, int, error)
+	FinishPasskeyRegistration(ctx context.Context, userID int64, req dto.FinishPasskeyRegistrationRequest) (*dto.PasskeyResponse, int, error)
+	GetPasskeys(ctx context.Context, userID int64) ([]dto.PasskeyResponse, int, error)
+	DeletePasskey(ctx context.Context, userID, passkeyID int64) (int, error)
+	BeginPasskeyLogin(ctx context.Context, req dto.BeginPasskeyLoginRequest) (*dto.PasskeyRequestOptions, int, error)
+
+	CreateInvite(ctx context.Context, userID int64, req dto.CreateInviteRequest) (*dto.CreateInviteResponse, int, error)
+	GetInvites(ctx context.Context, userID int64) ([]dto.InviteResponse, int, error)
+	RevokeInvite(ctx context.Context, userID, inviteID int64) (int, error)
+
+	FollowUser(ctx context.Context, userID, followeeID int64) (int, error)
+	UnfollowUser(ctx context.Context, userID, followeeID int64) (int, error)
+
+	DeactivateAccount(ctx context.Context, userID int64) (*dto.DeactivateAccountResponse, int, error)
+	PurgeDeactivatedAccounts(ctx context.Context) (int, error)
 }
 
 type userService struct {
 	cfg *config.Config
 	userRepo user.UserRepository
+	oidcProviders map[string]*oidc.Provider
+	mailer mailer.Mailer
 }
 
-func NewService(cfg *config.Config, userRepo user.UserRepository) UserService {
+func NewService(cfg *config.Config, userRepo user.UserRepository, mailer mailer.Mailer) UserService {
+	oidcProviders := make(map[string]*oidc.Provider)
+	for _, providerConfig := range cfg.OIDCProviders {
+		oidcProviders[providerConfig.Name] = oidc.NewProvider(providerConfig, &http.Client{Timeout: 10 * time.Second})
+	}
+
 	return &userService{
 		cfg:      cfg,
 		userRepo: userRepo,
+		oidcProviders: oidcProviders,
+		mailer: mailer,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,220 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go-twitter/internal/config"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"go-twitter/pkg/jwt"
+	"go-twitter/pkg/totp"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+func (s *userService) EnrollTOTP(ctx context.Context, userID int64) (*dto.EnrollTOTPResponse, int, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		return nil, http.StatusNotFound, errors.New("user not found")
+	}
+
+	existing, err := s.userRepo.GetUserTOTP(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if existing != nil && existing.ConfirmedAt.Valid {
+		return nil, http.StatusConflict, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = s.userRepo.UpsertUserTOTP(ctx, userID, secret)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.EnrollTOTPResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.KeyURI(s.totpIssuer(), user.Email, secret),
+	}, http.StatusOK, nil
+}
+
+func (s *userService) ConfirmTOTP(ctx context.Context, userID int64, req dto.ConfirmTOTPRequest) (*dto.ConfirmTOTPResponse, int, error) {
+	existing, err := s.userRepo.GetUserTOTP(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if existing == nil {
+		return nil, http.StatusBadRequest, errors.New("two-factor enrollment has not been started")
+	}
+	if existing.ConfirmedAt.Valid {
+		return nil, http.StatusConflict, errors.New("two-factor authentication is already enabled")
+	}
+
+	step, ok := totp.Validate(existing.Secret, req.Code, time.Now())
+	if !ok {
+		return nil, http.StatusBadRequest, errors.New("invalid two-factor code")
+	}
+
+	codes, hashes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = s.userRepo.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = s.userRepo.ConfirmUserTOTP(ctx, userID, step)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return &dto.ConfirmTOTPResponse{RecoveryCodes: codes}, http.StatusOK, nil
+}
+
+func (s *userService) DisableTOTP(ctx context.Context, userID int64, req dto.DisableTOTPRequest) (int, error) {
+	existing, err := s.userRepo.GetUserTOTP(ctx, userID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if existing == nil || !existing.ConfirmedAt.Valid {
+		return http.StatusBadRequest, errors.New("two-factor authentication is not enabled")
+	}
+
+	ok, err := s.verifySecondFactor(ctx, existing, req.Code)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !ok {
+		return http.StatusBadRequest, errors.New("invalid two-factor code")
+	}
+
+	err = s.userRepo.DeleteUserTOTP(ctx, userID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+// LoginMFA completes a login started by Login for users with 2FA enabled.
+// The second factor is a TOTP or recovery code, or one of the user's
+// passkeys.
+func (s *userService) LoginMFA(ctx context.Context, req dto.LoginMFARequest) (*dto.LoginResponse, int, error) {
+	claims, err := jwt.ParseMFAToken(req.MFAToken, s.cfg.JWTOptions())
+	if err != nil {
+		return nil, http.StatusUnauthorized, errors.New("invalid or expired MFA token")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if user == nil {
+		return nil, http.StatusUnauthorized, errors.New("invalid or expired MFA token")
+	}
+
+	existing, err := s.userRepo.GetUserTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if existing == nil || !existing.ConfirmedAt.Valid {
+		return nil, http.StatusUnauthorized, errors.New("invalid or expired MFA token")
+	}
+
+	// second-factor guesses share the account's failure budget
+	now := time.Now()
+	if status, err := s.checkLoginThrottle(ctx, user.Email, req.IPAddress, now); err != nil {
+		return nil, status, err
+	}
+
+	// each challenge allows a single attempt, so a mistyped code means
+	// starting over with the first factor
+	fresh, err := s.userRepo.UseMFAToken(ctx, claims.ID, claims.ExpiresAt.Time, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !fresh {
+		return nil, http.StatusUnauthorized, errors.New("invalid or expired MFA token")
+	}
+
+	var ok bool
+	if req.Passkey != nil {
+		ok, _, err = s.verifyPasskey(ctx, user.ID, req.Passkey)
+	} else {
+		ok, err = s.verifySecondFactor(ctx, existing, req.Code)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !ok {
+		if err := s.recordLoginAttempt(ctx, user.ID, user.Email, req.IPAddress, req.UserAgent, false, now); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		return nil, http.StatusBadRequest, errors.New("invalid two-factor code")
+	}
+
+	if err := s.recordLoginAttempt(ctx, user.ID, user.Email, req.IPAddress, req.UserAgent, true, now); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return s.issueTokens(ctx, user)
+}
+
+// verifySecondFactor accepts either a current TOTP code, which may only be
+// used once, or an unused recovery code, which is spent on success.
+func (s *userService) verifySecondFactor(ctx context.Context, totpConfig *model.UserTOTPModel, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if step, ok := totp.Validate(totpConfig.Secret, code, time.Now()); ok {
+		return s.userRepo.UseTOTPStep(ctx, totpConfig.UserID, step)
+	}
+
+	return s.userRepo.UseRecoveryCode(ctx, totpConfig.UserID, hashRecoveryCode(code))
+}
+
+func (s *userService) totpIssuer() string {
+	if s.cfg.TOTPIssuer == "" {
+		return config.DefaultTOTPIssuer
+	}
+	return s.cfg.TOTPIssuer
+}
+
+// generateRecoveryCodes returns codes formatted for display along with the
+// hashes that are stored.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(b)
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// Recovery codes carry 40 bits of randomness and are single use, so a fast
+// hash is enough to keep them unreadable at rest.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
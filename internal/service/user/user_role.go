@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"go-twitter/internal/dto"
+	"go-twitter/internal/model"
+	"net/http"
+)
+
+// Role changes take effect on the user's next login or token refresh, since
+// roles are carried in the access token.
+func (s *userService) AssignRole(ctx context.Context, userID int64, role string) (*dto.UserRolesResponse, int, error) {
+	if !model.IsValidRole(role) {
+		return nil, http.StatusBadRequest, errors.New("unknown role")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if user == nil {
+		return nil, http.StatusNotFound, nil
+	}
+
+	err = s.userRepo.AddUserRole(ctx, userID, role)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return s.getUserRoles(ctx, userID)
+}
+
+func (s *userService) RemoveRole(ctx context.Context, userID int64, role string) (*dto.UserRolesResponse, int, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if user == nil {
+		return nil, http.StatusNotFound, nil
+	}
+
+	err = s.userRepo.RemoveUserRole(ctx, userID, role)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return s.getUserRoles(ctx, userID)
+}
+
+func (s *userService) getUserRoles(ctx context.Context, userID int64) (*dto.UserRolesResponse, int, error) {
+	roles, err := s.userRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if roles == nil {
+		roles = []string{}
+	}
+
+	return &dto.UserRolesResponse{Roles: roles}, http.StatusOK, nil
+}
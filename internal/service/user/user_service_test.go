@@ -2,11 +2,19 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"go-twitter/internal/config"
 	"go-twitter/internal/dto"
 	"go-twitter/internal/model"
+	"go-twitter/pkg/jwt"
+	"go-twitter/pkg/pat"
+	"go-twitter/pkg/totp"
+	"go-twitter/pkg/webauthn"
+	"go-twitter/pkg/webauthn/webauthntest"
 	"net/http"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,14 +23,61 @@ import (
 
 // Mock UserRepository for testing
 type mockUserRepository struct {
-	getUserByEmailOrUsernameFunc func(ctx context.Context, email, username string) (*model.UserModel, error)
-	createUserFunc               func(ctx context.Context, user *model.UserModel) (int64, error)
-	getRefreshTokenFunc          func(ctx context.Context, id int64, now time.Time) (*model.RefreshTokenModel, error)
-	storeRefreshTokenFunc        func(ctx context.Context, model *model.RefreshTokenModel) error
-	getUserByIDFunc              func(ctx context.Context, id int64) (*model.UserModel, error)
-	getRefreshTokenByTokenFunc   func(ctx context.Context, token string) (*model.RefreshTokenModel, error)
-	deleteRefreshTokenFunc       func(ctx context.Context, token string) error
-	updateUserFunc               func(ctx context.Context, user *model.UserModel) error
+	getUserByEmailOrUsernameFunc            func(ctx context.Context, email, username string) (*model.UserModel, error)
+	createUserFunc                          func(ctx context.Context, user *model.UserModel) (int64, error)
+	getRefreshTokenFunc                     func(ctx context.Context, id int64, now time.Time) (*model.RefreshTokenModel, error)
+	storeRefreshTokenFunc                   func(ctx context.Context, model *model.RefreshTokenModel) error
+	getUserByIDFunc                         func(ctx context.Context, id int64) (*model.UserModel, error)
+	getRefreshTokenByTokenFunc              func(ctx context.Context, token string) (*model.RefreshTokenModel, error)
+	deleteRefreshTokenFunc                  func(ctx context.Context, token string) error
+	updateUserFunc                          func(ctx context.Context, user *model.UserModel) error
+	getUserRolesFunc                        func(ctx context.Context, userID int64) ([]string, error)
+	addUserRoleFunc                         func(ctx context.Context, userID int64, role string) error
+	removeUserRoleFunc                      func(ctx context.Context, userID int64, role string) error
+	getUserTOTPFunc                         func(ctx context.Context, userID int64) (*model.UserTOTPModel, error)
+	upsertUserTOTPFunc                      func(ctx context.Context, userID int64, secret string) error
+	confirmUserTOTPFunc                     func(ctx context.Context, userID, step int64) error
+	useTOTPStepFunc                         func(ctx context.Context, userID, step int64) (bool, error)
+	deleteUserTOTPFunc                      func(ctx context.Context, userID int64) error
+	replaceRecoveryCodesFunc                func(ctx context.Context, userID int64, codeHashes []string) error
+	useRecoveryCodeFunc                     func(ctx context.Context, userID int64, codeHash string) (bool, error)
+	useMFATokenFunc                         func(ctx context.Context, jti string, expiresAt, now time.Time) (bool, error)
+	createLoginAttemptFunc                  func(ctx context.Context, attempt *model.LoginAttemptModel) error
+	getFailedLoginsByEmailFunc              func(ctx context.Context, email string, since time.Time) (int, time.Time, error)
+	getFailedLoginsByIPFunc                 func(ctx context.Context, ip string, since time.Time) (int, time.Time, error)
+	getLoginAttemptsByUserIDFunc            func(ctx context.Context, userID int64, limit, offset int) ([]*model.LoginAttemptModel, int64, error)
+	createPersonalAccessTokenFunc           func(ctx context.Context, token *model.PersonalAccessTokenModel) (int64, error)
+	getPersonalAccessTokensByUserIDFunc     func(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error)
+	getPersonalAccessTokenByHashFunc        func(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error)
+	deletePersonalAccessTokenFunc           func(ctx context.Context, userID, tokenID int64) (bool, error)
+	touchPersonalAccessTokenFunc            func(ctx context.Context, tokenID int64, usedAt time.Time) error
+	getUserIdentityFunc                     func(ctx context.Context, provider, subject string) (*model.UserIdentityModel, error)
+	createUserIdentityFunc                  func(ctx context.Context, identity *model.UserIdentityModel) error
+	createOIDCLoginStateFunc                func(ctx context.Context, state *model.OIDCLoginStateModel) error
+	consumeOIDCLoginStateFunc               func(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginStateModel, error)
+	createMagicLinkTokenFunc                func(ctx context.Context, token *model.MagicLinkTokenModel) error
+	countMagicLinkTokensByEmailFunc         func(ctx context.Context, email string, since time.Time) (int, error)
+	consumeMagicLinkTokenFunc               func(ctx context.Context, tokenHash string, now time.Time) (*model.MagicLinkTokenModel, error)
+	createWebAuthnCredentialFunc            func(ctx context.Context, credential *model.WebAuthnCredentialModel) (int64, error)
+	getWebAuthnCredentialsByUserIDFunc      func(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error)
+	getWebAuthnCredentialByCredentialIDFunc func(ctx context.Context, credentialID []byte) (*model.WebAuthnCredentialModel, error)
+	updateWebAuthnSignCountFunc             func(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error
+	deleteWebAuthnCredentialFunc            func(ctx context.Context, userID, id int64) (bool, error)
+	createWebAuthnChallengeFunc             func(ctx context.Context, challenge *model.WebAuthnChallengeModel) error
+	consumeWebAuthnChallengeFunc            func(ctx context.Context, challengeHash string, now time.Time) (*model.WebAuthnChallengeModel, error)
+	createInviteCodeFunc                    func(ctx context.Context, invite *model.InviteCodeModel) (int64, error)
+	countInviteCodesByCreatorFunc           func(ctx context.Context, userID int64) (int, error)
+	getInviteCodesByCreatorFunc             func(ctx context.Context, userID int64) ([]*model.InviteCodeModel, error)
+	getInviteRedemptionsByInviterFunc       func(ctx context.Context, inviterID int64) ([]*model.InviteRedemptionModel, error)
+	revokeInviteCodeFunc                    func(ctx context.Context, userID, inviteID int64, now time.Time) (bool, error)
+	createInvitedUserFunc                   func(ctx context.Context, user *model.UserModel, codeHash string, now time.Time) (int64, bool, error)
+	followUserFunc                          func(ctx context.Context, followerID, followeeID int64) (bool, error)
+	unfollowUserFunc                        func(ctx context.Context, followerID, followeeID int64) error
+	isFollowingFunc                         func(ctx context.Context, followerID, followeeID int64) (bool, error)
+	deactivateUserFunc                      func(ctx context.Context, userID int64, now, purgeAfter time.Time) (bool, error)
+	reactivateUserFunc                      func(ctx context.Context, userID int64, now time.Time) (bool, error)
+	getUsersDueForPurgeFunc                 func(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	purgeUserFunc                           func(ctx context.Context, userID int64, now time.Time) (bool, error)
 }
 
 func (m *mockUserRepository) GetUserByEmailOrUsername(ctx context.Context, email, username string) (*model.UserModel, error) {
@@ -81,6 +136,335 @@ func (m *mockUserRepository) UpdateUser(ctx context.Context, user *model.UserMod
 	return nil
 }
 
+func (m *mockUserRepository) GetUserRoles(ctx context.Context, userID int64) ([]string, error) {
+	if m.getUserRolesFunc != nil {
+		return m.getUserRolesFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) AddUserRole(ctx context.Context, userID int64, role string) error {
+	if m.addUserRoleFunc != nil {
+		return m.addUserRoleFunc(ctx, userID, role)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) RemoveUserRole(ctx context.Context, userID int64, role string) error {
+	if m.removeUserRoleFunc != nil {
+		return m.removeUserRoleFunc(ctx, userID, role)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) GetUserTOTP(ctx context.Context, userID int64) (*model.UserTOTPModel, error) {
+	if m.getUserTOTPFunc != nil {
+		return m.getUserTOTPFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) UpsertUserTOTP(ctx context.Context, userID int64, secret string) error {
+	if m.upsertUserTOTPFunc != nil {
+		return m.upsertUserTOTPFunc(ctx, userID, secret)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) ConfirmUserTOTP(ctx context.Context, userID, step int64) error {
+	if m.confirmUserTOTPFunc != nil {
+		return m.confirmUserTOTPFunc(ctx, userID, step)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) UseTOTPStep(ctx context.Context, userID, step int64) (bool, error) {
+	if m.useTOTPStepFunc != nil {
+		return m.useTOTPStepFunc(ctx, userID, step)
+	}
+	return true, nil
+}
+
+func (m *mockUserRepository) UseMFAToken(ctx context.Context, jti string, expiresAt, now time.Time) (bool, error) {
+	if m.useMFATokenFunc != nil {
+		return m.useMFATokenFunc(ctx, jti, expiresAt, now)
+	}
+	return true, nil
+}
+
+func (m *mockUserRepository) DeleteUserTOTP(ctx context.Context, userID int64) error {
+	if m.deleteUserTOTPFunc != nil {
+		return m.deleteUserTOTPFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) ReplaceRecoveryCodes(ctx context.Context, userID int64, codeHashes []string) error {
+	if m.replaceRecoveryCodesFunc != nil {
+		return m.replaceRecoveryCodesFunc(ctx, userID, codeHashes)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) (bool, error) {
+	if m.useRecoveryCodeFunc != nil {
+		return m.useRecoveryCodeFunc(ctx, userID, codeHash)
+	}
+	return false, nil
+}
+
+func (m *mockUserRepository) CreateLoginAttempt(ctx context.Context, attempt *model.LoginAttemptModel) error {
+	if m.createLoginAttemptFunc != nil {
+		return m.createLoginAttemptFunc(ctx, attempt)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) GetFailedLoginsByEmail(ctx context.Context, email string, since time.Time) (int, time.Time, error) {
+	if m.getFailedLoginsByEmailFunc != nil {
+		return m.getFailedLoginsByEmailFunc(ctx, email, since)
+	}
+	return 0, time.Time{}, nil
+}
+
+func (m *mockUserRepository) GetFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int, time.Time, error) {
+	if m.getFailedLoginsByIPFunc != nil {
+		return m.getFailedLoginsByIPFunc(ctx, ip, since)
+	}
+	return 0, time.Time{}, nil
+}
+
+func (m *mockUserRepository) GetLoginAttemptsByUserID(ctx context.Context, userID int64, limit, offset int) ([]*model.LoginAttemptModel, int64, error) {
+	if m.getLoginAttemptsByUserIDFunc != nil {
+		return m.getLoginAttemptsByUserIDFunc(ctx, userID, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockUserRepository) CreatePersonalAccessToken(ctx context.Context, token *model.PersonalAccessTokenModel) (int64, error) {
+	if m.createPersonalAccessTokenFunc != nil {
+		return m.createPersonalAccessTokenFunc(ctx, token)
+	}
+	return 1, nil
+}
+
+func (m *mockUserRepository) GetPersonalAccessTokensByUserID(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error) {
+	if m.getPersonalAccessTokensByUserIDFunc != nil {
+		return m.getPersonalAccessTokensByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (*model.PersonalAccessTokenModel, error) {
+	if m.getPersonalAccessTokenByHashFunc != nil {
+		return m.getPersonalAccessTokenByHashFunc(ctx, tokenHash)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) DeletePersonalAccessToken(ctx context.Context, userID, tokenID int64) (bool, error) {
+	if m.deletePersonalAccessTokenFunc != nil {
+		return m.deletePersonalAccessTokenFunc(ctx, userID, tokenID)
+	}
+	return false, nil
+}
+
+func (m *mockUserRepository) TouchPersonalAccessToken(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	if m.touchPersonalAccessTokenFunc != nil {
+		return m.touchPersonalAccessTokenFunc(ctx, tokenID, usedAt)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*model.UserIdentityModel, error) {
+	if m.getUserIdentityFunc != nil {
+		return m.getUserIdentityFunc(ctx, provider, subject)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) CreateUserIdentity(ctx context.Context, identity *model.UserIdentityModel) error {
+	if m.createUserIdentityFunc != nil {
+		return m.createUserIdentityFunc(ctx, identity)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) CreateOIDCLoginState(ctx context.Context, state *model.OIDCLoginStateModel) error {
+	if m.createOIDCLoginStateFunc != nil {
+		return m.createOIDCLoginStateFunc(ctx, state)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) ConsumeOIDCLoginState(ctx context.Context, stateHash string, now time.Time) (*model.OIDCLoginStateModel, error) {
+	if m.consumeOIDCLoginStateFunc != nil {
+		return m.consumeOIDCLoginStateFunc(ctx, stateHash, now)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) CreateMagicLinkToken(ctx context.Context, token *model.MagicLinkTokenModel) error {
+	if m.createMagicLinkTokenFunc != nil {
+		return m.createMagicLinkTokenFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) CountMagicLinkTokensByEmail(ctx context.Context, email string, since time.Time) (int, error) {
+	if m.countMagicLinkTokensByEmailFunc != nil {
+		return m.countMagicLinkTokensByEmailFunc(ctx, email, since)
+	}
+	return 0, nil
+}
+
+func (m *mockUserRepository) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (*model.MagicLinkTokenModel, error) {
+	if m.consumeMagicLinkTokenFunc != nil {
+		return m.consumeMagicLinkTokenFunc(ctx, tokenHash, now)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) CreateWebAuthnCredential(ctx context.Context, credential *model.WebAuthnCredentialModel) (int64, error) {
+	if m.createWebAuthnCredentialFunc != nil {
+		return m.createWebAuthnCredentialFunc(ctx, credential)
+	}
+	return 1, nil
+}
+
+func (m *mockUserRepository) GetWebAuthnCredentialsByUserID(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error) {
+	if m.getWebAuthnCredentialsByUserIDFunc != nil {
+		return m.getWebAuthnCredentialsByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (*model.WebAuthnCredentialModel, error) {
+	if m.getWebAuthnCredentialByCredentialIDFunc != nil {
+		return m.getWebAuthnCredentialByCredentialIDFunc(ctx, credentialID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) UpdateWebAuthnSignCount(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error {
+	if m.updateWebAuthnSignCountFunc != nil {
+		return m.updateWebAuthnSignCountFunc(ctx, id, signCount, usedAt)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) DeleteWebAuthnCredential(ctx context.Context, userID, id int64) (bool, error) {
+	if m.deleteWebAuthnCredentialFunc != nil {
+		return m.deleteWebAuthnCredentialFunc(ctx, userID, id)
+	}
+	return false, nil
+}
+
+func (m *mockUserRepository) CreateWebAuthnChallenge(ctx context.Context, challenge *model.WebAuthnChallengeModel) error {
+	if m.createWebAuthnChallengeFunc != nil {
+		return m.createWebAuthnChallengeFunc(ctx, challenge)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string, now time.Time) (*model.WebAuthnChallengeModel, error) {
+	if m.consumeWebAuthnChallengeFunc != nil {
+		return m.consumeWebAuthnChallengeFunc(ctx, challengeHash, now)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) CreateInviteCode(ctx context.Context, invite *model.InviteCodeModel) (int64, error) {
+	if m.createInviteCodeFunc != nil {
+		return m.createInviteCodeFunc(ctx, invite)
+	}
+	return 1, nil
+}
+
+func (m *mockUserRepository) CountInviteCodesByCreator(ctx context.Context, userID int64) (int, error) {
+	if m.countInviteCodesByCreatorFunc != nil {
+		return m.countInviteCodesByCreatorFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *mockUserRepository) GetInviteCodesByCreator(ctx context.Context, userID int64) ([]*model.InviteCodeModel, error) {
+	if m.getInviteCodesByCreatorFunc != nil {
+		return m.getInviteCodesByCreatorFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) GetInviteRedemptionsByInviter(ctx context.Context, inviterID int64) ([]*model.InviteRedemptionModel, error) {
+	if m.getInviteRedemptionsByInviterFunc != nil {
+		return m.getInviteRedemptionsByInviterFunc(ctx, inviterID)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) RevokeInviteCode(ctx context.Context, userID, inviteID int64, now time.Time) (bool, error) {
+	if m.revokeInviteCodeFunc != nil {
+		return m.revokeInviteCodeFunc(ctx, userID, inviteID, now)
+	}
+	return false, nil
+}
+
+func (m *mockUserRepository) CreateInvitedUser(ctx context.Context, user *model.UserModel, codeHash string, now time.Time) (int64, bool, error) {
+	if m.createInvitedUserFunc != nil {
+		return m.createInvitedUserFunc(ctx, user, codeHash, now)
+	}
+	return 0, false, nil
+}
+
+func (m *mockUserRepository) FollowUser(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	if m.followUserFunc != nil {
+		return m.followUserFunc(ctx, followerID, followeeID)
+	}
+	return true, nil
+}
+
+func (m *mockUserRepository) UnfollowUser(ctx context.Context, followerID, followeeID int64) error {
+	if m.unfollowUserFunc != nil {
+		return m.unfollowUserFunc(ctx, followerID, followeeID)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) IsFollowing(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	if m.isFollowingFunc != nil {
+		return m.isFollowingFunc(ctx, followerID, followeeID)
+	}
+	return false, nil
+}
+
+func (m *mockUserRepository) DeactivateUser(ctx context.Context, userID int64, now, purgeAfter time.Time) (bool, error) {
+	if m.deactivateUserFunc != nil {
+		return m.deactivateUserFunc(ctx, userID, now, purgeAfter)
+	}
+	return true, nil
+}
+
+func (m *mockUserRepository) ReactivateUser(ctx context.Context, userID int64, now time.Time) (bool, error) {
+	if m.reactivateUserFunc != nil {
+		return m.reactivateUserFunc(ctx, userID, now)
+	}
+	return true, nil
+}
+
+func (m *mockUserRepository) GetUsersDueForPurge(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	if m.getUsersDueForPurgeFunc != nil {
+		return m.getUsersDueForPurgeFunc(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockUserRepository) PurgeUser(ctx context.Context, userID int64, now time.Time) (bool, error) {
+	if m.purgeUserFunc != nil {
+		return m.purgeUserFunc(ctx, userID, now)
+	}
+	return false, nil
+}
+
 // Test Register
 func TestRegister_Success(t *testing.T) {
 	mockRepo := &mockUserRepository{
@@ -96,7 +480,7 @@ func TestRegister_Success(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.RegisterRequest{
 		Username: "testuser",
@@ -134,7 +518,7 @@ func TestRegister_UserAlreadyExists(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.RegisterRequest{
 		Username: "testuser",
@@ -172,7 +556,7 @@ func TestRegister_DatabaseErrorOnCheck(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.RegisterRequest{
 		Username: "testuser",
@@ -209,7 +593,7 @@ func TestRegister_DatabaseErrorOnCreate(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.RegisterRequest{
 		Username: "testuser",
@@ -249,7 +633,7 @@ func TestRegister_PasswordIsHashed(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	plainPassword := "mySecurePassword123"
 	req := dto.RegisterRequest{
@@ -301,14 +685,14 @@ func TestLogin_Success(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.LoginRequest{
 		Email:    "test@example.com",
 		Password: "password123",
 	}
 
-	accessToken, refreshToken, status, err := service.Login(context.Background(), req)
+	response, status, err := service.Login(context.Background(), req)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -318,11 +702,11 @@ func TestLogin_Success(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
 	}
 
-	if accessToken == "" {
+	if response == nil || response.Token == "" {
 		t.Error("Expected access token, got empty string")
 	}
 
-	if refreshToken == "" {
+	if response == nil || response.RefreshToken == "" {
 		t.Error("Expected refresh token, got empty string")
 	}
 }
@@ -338,14 +722,14 @@ func TestLogin_UserNotFound(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.LoginRequest{
 		Email:    "nonexistent@example.com",
 		Password: "password123",
 	}
 
-	accessToken, refreshToken, status, err := service.Login(context.Background(), req)
+	response, status, err := service.Login(context.Background(), req)
 
 	if err == nil {
 		t.Fatal("Expected error, got nil")
@@ -355,7 +739,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
 	}
 
-	if accessToken != "" || refreshToken != "" {
+	if response != nil {
 		t.Error("Expected empty tokens")
 	}
 }
@@ -378,14 +762,14 @@ func TestLogin_WrongPassword(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.LoginRequest{
 		Email:    "test@example.com",
 		Password: "wrongPassword",
 	}
 
-	accessToken, refreshToken, status, err := service.Login(context.Background(), req)
+	response, status, err := service.Login(context.Background(), req)
 
 	if err == nil {
 		t.Fatal("Expected error, got nil")
@@ -395,7 +779,7 @@ func TestLogin_WrongPassword(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
 	}
 
-	if accessToken != "" || refreshToken != "" {
+	if response != nil {
 		t.Error("Expected empty tokens")
 	}
 }
@@ -427,14 +811,14 @@ func TestLogin_ExistingRefreshToken(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.LoginRequest{
 		Email:    "test@example.com",
 		Password: "password123",
 	}
 
-	accessToken, refreshToken, status, err := service.Login(context.Background(), req)
+	response, status, err := service.Login(context.Background(), req)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -444,11 +828,11 @@ func TestLogin_ExistingRefreshToken(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
 	}
 
-	if refreshToken != existingRefreshToken {
-		t.Errorf("Expected refresh token %s, got %s", existingRefreshToken, refreshToken)
+	if response.RefreshToken != existingRefreshToken {
+		t.Errorf("Expected refresh token %s, got %s", existingRefreshToken, response.RefreshToken)
 	}
 
-	if accessToken == "" {
+	if response.Token == "" {
 		t.Error("Expected access token, got empty string")
 	}
 }
@@ -464,14 +848,14 @@ func TestLogin_DatabaseError(t *testing.T) {
 		SecreetJwt: "test-secret",
 	}
 
-	service := NewService(cfg, mockRepo)
+	service := NewService(cfg, mockRepo, nil)
 
 	req := dto.LoginRequest{
 		Email:    "test@example.com",
 		Password: "password123",
 	}
 
-	_, _, status, err := service.Login(context.Background(), req)
+	_, status, err := service.Login(context.Background(), req)
 
 	if err == nil {
 		t.Fatal("Expected error, got nil")
@@ -481,3 +865,1034 @@ func TestLogin_DatabaseError(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, status)
 	}
 }
+
+func TestLogin_EmbedsRolesInAccessToken(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+
+	mockRepo := &mockUserRepository{
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			return &model.UserModel{
+				ID:       123,
+				Username: "testuser",
+				Email:    "test@example.com",
+				Password: string(hashedPassword),
+			}, nil
+		},
+		getUserRolesFunc: func(ctx context.Context, userID int64) ([]string, error) {
+			return []string{model.RoleModerator}, nil
+		},
+	}
+
+	cfg := &config.Config{
+		SecreetJwt: "test-secret",
+	}
+
+	service := NewService(cfg, mockRepo, nil)
+
+	response, status, err := service.Login(context.Background(), dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected successful login, got status %d and error %v", status, err)
+	}
+
+	claims, err := jwt.ParseToken(response.Token, cfg.JWTOptions())
+	if err != nil {
+		t.Fatalf("Expected access token to parse, got: %v", err)
+	}
+
+	if len(claims.Roles) != 1 || claims.Roles[0] != model.RoleModerator {
+		t.Errorf("Expected roles [moderator], got %v", claims.Roles)
+	}
+}
+
+// Test AssignRole
+func TestAssignRole_UnknownRole(t *testing.T) {
+	service := NewService(&config.Config{}, &mockUserRepository{}, nil)
+
+	_, status, err := service.AssignRole(context.Background(), 1, "superuser")
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+	}
+}
+
+func TestAssignRole_UserNotFound(t *testing.T) {
+	service := NewService(&config.Config{}, &mockUserRepository{}, nil)
+
+	_, status, err := service.AssignRole(context.Background(), 1, model.RoleModerator)
+
+	if err != nil {
+		t.Errorf("Expected no error for not found, got: %v", err)
+	}
+
+	if status != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestAssignRole_Success(t *testing.T) {
+	var roles []string
+
+	mockRepo := &mockUserRepository{
+		getUserByIDFunc: func(ctx context.Context, id int64) (*model.UserModel, error) {
+			return &model.UserModel{ID: id}, nil
+		},
+		addUserRoleFunc: func(ctx context.Context, userID int64, role string) error {
+			roles = append(roles, role)
+			return nil
+		},
+		getUserRolesFunc: func(ctx context.Context, userID int64) ([]string, error) {
+			return roles, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	response, status, err := service.AssignRole(context.Background(), 1, model.RoleAdmin)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if len(response.Roles) != 1 || response.Roles[0] != model.RoleAdmin {
+		t.Errorf("Expected roles [admin], got %v", response.Roles)
+	}
+}
+
+// Test two-factor login
+func newTwoFactorRepo(secret string) *mockUserRepository {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &model.UserModel{
+		ID:       123,
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: string(hashedPassword),
+	}
+
+	return &mockUserRepository{
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			return user, nil
+		},
+		getUserByIDFunc: func(ctx context.Context, id int64) (*model.UserModel, error) {
+			return user, nil
+		},
+		getUserTOTPFunc: func(ctx context.Context, userID int64) (*model.UserTOTPModel, error) {
+			return &model.UserTOTPModel{
+				UserID:      userID,
+				Secret:      secret,
+				ConfirmedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			}, nil
+		},
+	}
+}
+
+func TestLogin_TwoFactorReturnsChallenge(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	service := NewService(cfg, newTwoFactorRepo(secret), nil)
+
+	response, status, err := service.Login(context.Background(), dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected first step to succeed, got status %d and error %v", status, err)
+	}
+
+	if !response.MFARequired || response.MFAToken == "" {
+		t.Fatalf("Expected MFA challenge, got %+v", response)
+	}
+
+	if response.Token != "" || response.RefreshToken != "" {
+		t.Error("Expected no access or refresh token before the second step")
+	}
+
+	if _, err := jwt.ParseToken(response.MFAToken, cfg.JWTOptions()); err == nil {
+		t.Error("Expected MFA token to be unusable as an access token")
+	}
+}
+
+func TestLoginMFA_ValidCodeIssuesTokens(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	service := NewService(cfg, newTwoFactorRepo(secret), nil)
+
+	first, _, _ := service.Login(context.Background(), dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	})
+
+	code, _ := totp.GenerateCode(secret, time.Now())
+	response, status, err := service.LoginMFA(context.Background(), dto.LoginMFARequest{
+		MFAToken: first.MFAToken,
+		Code:     code,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if response.Token == "" || response.RefreshToken == "" {
+		t.Errorf("Expected access and refresh tokens, got %+v", response)
+	}
+}
+
+func TestLoginMFA_ReplayedCodeRejected(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	mockRepo := newTwoFactorRepo(secret)
+	mockRepo.useTOTPStepFunc = func(ctx context.Context, userID, step int64) (bool, error) {
+		return false, nil // step already used
+	}
+	service := NewService(cfg, mockRepo, nil)
+
+	mfaToken, _ := jwt.CreateMFAToken(123, "testuser", cfg.JWTOptions(), time.Minute)
+	code, _ := totp.GenerateCode(secret, time.Now())
+
+	_, status, err := service.LoginMFA(context.Background(), dto.LoginMFARequest{
+		MFAToken: mfaToken,
+		Code:     code,
+	})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+	}
+}
+
+func TestLoginMFA_RecoveryCode(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	mockRepo := newTwoFactorRepo(secret)
+
+	codes, hashes, _ := generateRecoveryCodes(2)
+	used := map[string]bool{}
+	mockRepo.useRecoveryCodeFunc = func(ctx context.Context, userID int64, codeHash string) (bool, error) {
+		for _, h := range hashes {
+			if h == codeHash && !used[h] {
+				used[h] = true
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	service := NewService(cfg, mockRepo, nil)
+
+	mfaToken, _ := jwt.CreateMFAToken(123, "testuser", cfg.JWTOptions(), time.Minute)
+
+	_, status, err := service.LoginMFA(context.Background(), dto.LoginMFARequest{MFAToken: mfaToken, Code: strings.ToUpper(codes[0])})
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected recovery code to be accepted, got status %d and error %v", status, err)
+	}
+
+	_, status, _ = service.LoginMFA(context.Background(), dto.LoginMFARequest{MFAToken: mfaToken, Code: codes[0]})
+	if status != http.StatusBadRequest {
+		t.Errorf("Expected reused recovery code to be rejected with %d, got %d", http.StatusBadRequest, status)
+	}
+}
+
+func TestLoginMFA_InvalidChallengeToken(t *testing.T) {
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	service := NewService(cfg, newTwoFactorRepo("JBSWY3DPEHPK3PXP"), nil)
+
+	accessToken, _ := jwt.CreateToken(123, "testuser", nil, cfg.JWTOptions())
+
+	_, status, err := service.LoginMFA(context.Background(), dto.LoginMFARequest{MFAToken: accessToken, Code: "123456"})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if status != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, status)
+	}
+}
+
+func TestDisableTOTP_RequiresValidCode(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	deleted := false
+	mockRepo := newTwoFactorRepo(secret)
+	mockRepo.deleteUserTOTPFunc = func(ctx context.Context, userID int64) error {
+		deleted = true
+		return nil
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	status, err := service.DisableTOTP(context.Background(), 123, dto.DisableTOTPRequest{Code: "not-a-code"})
+	if err == nil || status != http.StatusBadRequest || deleted {
+		t.Fatalf("Expected invalid code to be rejected, got status %d, error %v, deleted %v", status, err, deleted)
+	}
+
+	code, _ := totp.GenerateCode(secret, time.Now())
+	status, err = service.DisableTOTP(context.Background(), 123, dto.DisableTOTPRequest{Code: code})
+	if err != nil || status != http.StatusOK || !deleted {
+		t.Errorf("Expected 2FA to be disabled, got status %d, error %v, deleted %v", status, err, deleted)
+	}
+}
+
+func TestConfirmTOTP_ReturnsRecoveryCodes(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	var storedHashes []string
+	confirmed := false
+
+	mockRepo := &mockUserRepository{
+		getUserTOTPFunc: func(ctx context.Context, userID int64) (*model.UserTOTPModel, error) {
+			return &model.UserTOTPModel{UserID: userID, Secret: secret}, nil
+		},
+		replaceRecoveryCodesFunc: func(ctx context.Context, userID int64, codeHashes []string) error {
+			storedHashes = codeHashes
+			return nil
+		},
+		confirmUserTOTPFunc: func(ctx context.Context, userID, step int64) error {
+			confirmed = true
+			return nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	code, _ := totp.GenerateCode(secret, time.Now())
+	response, status, err := service.ConfirmTOTP(context.Background(), 123, dto.ConfirmTOTPRequest{Code: code})
+
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected confirmation to succeed, got status %d and error %v", status, err)
+	}
+
+	if !confirmed {
+		t.Error("Expected enrollment to be confirmed")
+	}
+
+	if len(response.RecoveryCodes) != recoveryCodeCount || len(storedHashes) != recoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d returned and %d stored", recoveryCodeCount, len(response.RecoveryCodes), len(storedHashes))
+	}
+
+	for i, code := range response.RecoveryCodes {
+		if storedHashes[i] == code || storedHashes[i] != hashRecoveryCode(code) {
+			t.Errorf("Expected recovery code %d to be stored hashed", i)
+		}
+	}
+}
+
+// Test login throttling
+func TestLoginRetryAfter(t *testing.T) {
+	now := time.Now()
+	lockout := 15 * time.Minute
+
+	tests := []struct {
+		name     string
+		failures int
+		last     time.Time
+		want     time.Duration
+	}{
+		{"no failures", 0, time.Time{}, 0},
+		{"first failure backs off one second", 1, now, time.Second},
+		{"backoff doubles", 3, now, 4 * time.Second},
+		{"backoff already elapsed", 3, now.Add(-5 * time.Second), 0},
+		{"threshold locks out", 5, now, lockout},
+		{"lockout elapsed", 5, now.Add(-lockout), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loginRetryAfter(tt.failures, 5, tt.last, lockout, now); got != tt.want {
+				t.Errorf("loginRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogin_LockedOut(t *testing.T) {
+	lookedUp := false
+	mockRepo := &mockUserRepository{
+		getFailedLoginsByEmailFunc: func(ctx context.Context, email string, since time.Time) (int, time.Time, error) {
+			return 5, time.Now().Add(-time.Minute), nil
+		},
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			lookedUp = true
+			return nil, nil
+		},
+	}
+
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+	_, status, err := service.Login(context.Background(), dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+
+	if status != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, status)
+	}
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+	if lookedUp {
+		t.Error("Expected locked out login not to check credentials")
+	}
+}
+
+func TestLogin_IPBackoff(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		getFailedLoginsByIPFunc: func(ctx context.Context, ip string, since time.Time) (int, time.Time, error) {
+			if ip != "203.0.113.7" {
+				t.Errorf("Expected IP 203.0.113.7, got %s", ip)
+			}
+			return 3, time.Now(), nil
+		},
+	}
+
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+	_, status, _ := service.Login(context.Background(), dto.LoginRequest{
+		Email:     "test@example.com",
+		Password:  "password123",
+		IPAddress: "203.0.113.7",
+	})
+
+	if status != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, status)
+	}
+}
+
+func TestLogin_RecordsAttempts(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	var attempts []*model.LoginAttemptModel
+	mockRepo := &mockUserRepository{
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			if email != "test@example.com" {
+				return nil, nil
+			}
+			return &model.UserModel{ID: 1, Email: email, Username: "testuser", Password: string(hashedPassword)}, nil
+		},
+		createLoginAttemptFunc: func(ctx context.Context, attempt *model.LoginAttemptModel) error {
+			attempts = append(attempts, attempt)
+			return nil
+		},
+	}
+
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+	ctx := context.Background()
+	service.Login(ctx, dto.LoginRequest{Email: "unknown@example.com", Password: "password123", IPAddress: "203.0.113.7"})
+	service.Login(ctx, dto.LoginRequest{Email: "test@example.com", Password: "wrongpassword", IPAddress: "203.0.113.7"})
+	service.Login(ctx, dto.LoginRequest{Email: "test@example.com", Password: "password123", IPAddress: "203.0.113.7", UserAgent: "test-agent"})
+
+	if len(attempts) != 3 {
+		t.Fatalf("Expected 3 recorded attempts, got %d", len(attempts))
+	}
+	if attempts[0].UserID.Valid || attempts[0].Success {
+		t.Error("Expected unknown email to be recorded as a failure without a user")
+	}
+	if attempts[1].UserID.Int64 != 1 || attempts[1].Success {
+		t.Error("Expected wrong password to be recorded as a failure for the user")
+	}
+	if !attempts[2].Success || attempts[2].UserAgent != "test-agent" || attempts[2].IPAddress != "203.0.113.7" {
+		t.Error("Expected successful login to be recorded with client details")
+	}
+}
+
+func TestLogin_ThrottleIgnoresEmailCase(t *testing.T) {
+	var attempts []*model.LoginAttemptModel
+	mockRepo := &mockUserRepository{
+		createLoginAttemptFunc: func(ctx context.Context, attempt *model.LoginAttemptModel) error {
+			attempts = append(attempts, attempt)
+			return nil
+		},
+		getFailedLoginsByEmailFunc: func(ctx context.Context, email string, since time.Time) (int, time.Time, error) {
+			failures, last := 0, time.Time{}
+			for _, attempt := range attempts {
+				if attempt.Email == email && !attempt.Success {
+					failures++
+					last = attempt.CreatedAt
+				}
+			}
+			return failures, last, nil
+		},
+	}
+
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+	ctx := context.Background()
+	service.Login(ctx, dto.LoginRequest{Email: " Test@Example.COM ", Password: "wrongpassword"})
+
+	if len(attempts) != 1 || attempts[0].Email != "test@example.com" {
+		t.Fatalf("Expected the attempt recorded under the lower-case email, got %+v", attempts)
+	}
+	if _, status, _ := service.Login(ctx, dto.LoginRequest{Email: "TEST@example.com", Password: "wrongpassword"}); status != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d for the same email in another case, got %d", http.StatusTooManyRequests, status)
+	}
+}
+
+func TestLogin_TwoFactorSuccessRecordedAfterSecondStep(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	mockRepo := newTwoFactorRepo(secret)
+	var attempts []*model.LoginAttemptModel
+	mockRepo.createLoginAttemptFunc = func(ctx context.Context, attempt *model.LoginAttemptModel) error {
+		attempts = append(attempts, attempt)
+		return nil
+	}
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+	ctx := context.Background()
+
+	first, _, _ := service.Login(ctx, dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if len(attempts) != 0 {
+		t.Fatalf("Expected no attempt recorded for the password step alone, got %d", len(attempts))
+	}
+
+	service.LoginMFA(ctx, dto.LoginMFARequest{MFAToken: first.MFAToken, Code: "not-a-code"})
+	if len(attempts) != 1 || attempts[0].Success {
+		t.Fatalf("Expected one failed attempt for a wrong code, got %+v", attempts)
+	}
+
+	second, _, _ := service.Login(ctx, dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	code, _ := totp.GenerateCode(secret, time.Now())
+	if _, status, err := service.LoginMFA(ctx, dto.LoginMFARequest{MFAToken: second.MFAToken, Code: code}); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if len(attempts) != 2 || !attempts[1].Success {
+		t.Errorf("Expected the success recorded once the second step passed, got %+v", attempts)
+	}
+}
+
+func TestLoginMFA_ChallengeTokenIsSingleUse(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	mockRepo := newTwoFactorRepo(secret)
+	used := map[string]bool{}
+	mockRepo.useMFATokenFunc = func(ctx context.Context, jti string, expiresAt, now time.Time) (bool, error) {
+		if used[jti] {
+			return false, nil
+		}
+		used[jti] = true
+		return true, nil
+	}
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+	ctx := context.Background()
+
+	first, _, _ := service.Login(ctx, dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if _, status, _ := service.LoginMFA(ctx, dto.LoginMFARequest{MFAToken: first.MFAToken, Code: "not-a-code"}); status != http.StatusBadRequest {
+		t.Fatalf("Expected status %d for a wrong code, got %d", http.StatusBadRequest, status)
+	}
+
+	code, _ := totp.GenerateCode(secret, time.Now())
+	if _, status, _ := service.LoginMFA(ctx, dto.LoginMFARequest{MFAToken: first.MFAToken, Code: code}); status != http.StatusUnauthorized {
+		t.Errorf("Expected a spent challenge token to be rejected with %d, got %d", http.StatusUnauthorized, status)
+	}
+}
+
+func TestGetLoginAttempts(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		getLoginAttemptsByUserIDFunc: func(ctx context.Context, userID int64, limit, offset int) ([]*model.LoginAttemptModel, int64, error) {
+			if userID != 1 || limit != 10 || offset != 10 {
+				t.Errorf("Unexpected arguments userID=%d limit=%d offset=%d", userID, limit, offset)
+			}
+			return []*model.LoginAttemptModel{
+				{ID: 7, IPAddress: "203.0.113.7", Success: false, CreatedAt: time.Now()},
+			}, 11, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	response, status, err := service.GetLoginAttempts(context.Background(), 1, 2, 10)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	if len(response.LoginAttempts) != 1 || response.LoginAttempts[0].IPAddress != "203.0.113.7" {
+		t.Errorf("Unexpected login attempts: %+v", response.LoginAttempts)
+	}
+	if response.TotalPages != 2 {
+		t.Errorf("Expected 2 total pages, got %d", response.TotalPages)
+	}
+}
+
+// Test personal access tokens
+func TestCreatePersonalAccessToken(t *testing.T) {
+	var stored *model.PersonalAccessTokenModel
+	mockRepo := &mockUserRepository{
+		createPersonalAccessTokenFunc: func(ctx context.Context, token *model.PersonalAccessTokenModel) (int64, error) {
+			stored = token
+			return 9, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	response, status, err := service.CreatePersonalAccessToken(context.Background(), 1, dto.CreatePersonalAccessTokenRequest{
+		Name:   "deploy bot",
+		Scopes: []string{model.ScopePostsWrite, model.ScopeRead, model.ScopePostsWrite},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, status)
+	}
+	if response.ID != 9 || !pat.IsPersonalAccessToken(response.Token) {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+	if stored.TokenHash != pat.Hash(response.Token) || strings.Contains(stored.TokenHash, response.Token) {
+		t.Error("Expected only the token hash to be stored")
+	}
+	if len(stored.Scopes) != 2 {
+		t.Errorf("Expected duplicate scopes to be dropped, got %v", stored.Scopes)
+	}
+	if days := stored.ExpiresAt.Sub(stored.CreatedAt).Hours() / 24; days != defaultPersonalAccessTokenDays {
+		t.Errorf("Expected default expiry of %d days, got %v", defaultPersonalAccessTokenDays, days)
+	}
+}
+
+func TestCreatePersonalAccessToken_LimitReached(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		getPersonalAccessTokensByUserIDFunc: func(ctx context.Context, userID int64) ([]*model.PersonalAccessTokenModel, error) {
+			return make([]*model.PersonalAccessTokenModel, maxPersonalAccessTokens), nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	_, status, err := service.CreatePersonalAccessToken(context.Background(), 1, dto.CreatePersonalAccessTokenRequest{
+		Name:   "one too many",
+		Scopes: []string{model.ScopeRead},
+	})
+
+	if status != http.StatusBadRequest || err == nil {
+		t.Errorf("Expected status %d with error, got %d and %v", http.StatusBadRequest, status, err)
+	}
+}
+
+func TestDeletePersonalAccessToken_NotFound(t *testing.T) {
+	service := NewService(&config.Config{}, &mockUserRepository{}, nil)
+	status, err := service.DeletePersonalAccessToken(context.Background(), 1, 42)
+
+	if status != http.StatusNotFound || err == nil {
+		t.Errorf("Expected status %d with error, got %d and %v", http.StatusNotFound, status, err)
+	}
+}
+
+// Test OIDC login
+func TestStartOIDCLogin_UnknownProvider(t *testing.T) {
+	service := NewService(&config.Config{}, &mockUserRepository{}, nil)
+	_, status, err := service.StartOIDCLogin(context.Background(), "nope")
+
+	if status != http.StatusNotFound || err == nil {
+		t.Errorf("Expected status %d with error, got %d and %v", http.StatusNotFound, status, err)
+	}
+}
+
+func TestUserForIdentity_RequiresVerifiedEmail(t *testing.T) {
+	lookedUp := false
+	mockRepo := &mockUserRepository{
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			lookedUp = true
+			return &model.UserModel{ID: 1, Email: email}, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil).(*userService)
+	_, status, err := service.userForIdentity(context.Background(), "acme", "sub-1", "victim@example.com", false, time.Now())
+
+	if status != http.StatusBadRequest || err == nil {
+		t.Errorf("Expected status %d with error, got %d and %v", http.StatusBadRequest, status, err)
+	}
+	if lookedUp {
+		t.Error("Expected unverified email not to be matched against existing accounts")
+	}
+}
+
+func TestAvailableUsername(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			if username == "jane_doe" {
+				return &model.UserModel{ID: 1, Username: username}, nil
+			}
+			return nil, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil).(*userService)
+	username, err := service.availableUsername(context.Background(), "Jane_Doe+news@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(username, "jane_doenews") {
+		t.Errorf("Expected username derived from the email, got %q", username)
+	}
+
+	username, _ = service.availableUsername(context.Background(), "jane_doe@example.com")
+	if username == "jane_doe" || !strings.HasPrefix(username, "jane_doe_") {
+		t.Errorf("Expected taken username to get a suffix, got %q", username)
+	}
+}
+
+func TestRequestMagicLink_RateLimitedPerEmail(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		countMagicLinkTokensByEmailFunc: func(ctx context.Context, email string, since time.Time) (int, error) {
+			return config.DefaultMagicLinkMaxRequests, nil
+		},
+		createMagicLinkTokenFunc: func(ctx context.Context, token *model.MagicLinkTokenModel) error {
+			t.Error("Expected no token to be created when rate limited")
+			return nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	status, err := service.RequestMagicLink(context.Background(), dto.MagicLinkRequest{Email: "test@example.com", Fingerprint: "browser"})
+	if err == nil || status != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusTooManyRequests, status, err)
+	}
+}
+
+func TestConsumeMagicLink_Expired(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		consumeMagicLinkTokenFunc: func(ctx context.Context, tokenHash string, now time.Time) (*model.MagicLinkTokenModel, error) {
+			return &model.MagicLinkTokenModel{
+				UserID:          sql.NullInt64{Int64: 1, Valid: true},
+				TokenHash:       tokenHash,
+				FingerprintHash: hashSecret("browser"),
+				ExpiresAt:       now.Add(-time.Second),
+			}, nil
+		},
+	}
+
+	service := NewService(&config.Config{}, mockRepo, nil)
+	_, status, err := service.ConsumeMagicLink(context.Background(), dto.ConsumeMagicLinkRequest{Token: "token", Fingerprint: "browser"})
+	if err == nil || status != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusBadRequest, status, err)
+	}
+}
+
+// withPasskey registers a software authenticator's credential for user 123
+// and backs the challenge and credential lookups with it.
+func withPasskey(t *testing.T, mockRepo *mockUserRepository, rp webauthn.Config) *webauthntest.Authenticator {
+	authenticator := webauthntest.NewAuthenticator(rp.RPID, rp.Origin)
+	challenge := []byte("registration-challenge")
+	reg := authenticator.Register(challenge)
+	verified, err := webauthn.VerifyRegistration(rp, challenge, reg.ClientDataJSON, reg.AttestationObject)
+	if err != nil {
+		t.Fatalf("Failed to register passkey: %v", err)
+	}
+
+	credential := &model.WebAuthnCredentialModel{ID: 1, UserID: 123, CredentialID: verified.ID, PublicKey: verified.PublicKey}
+	challenges := map[string]*model.WebAuthnChallengeModel{}
+	mockRepo.createWebAuthnChallengeFunc = func(ctx context.Context, challenge *model.WebAuthnChallengeModel) error {
+		challenges[challenge.ChallengeHash] = challenge
+		return nil
+	}
+	mockRepo.consumeWebAuthnChallengeFunc = func(ctx context.Context, challengeHash string, now time.Time) (*model.WebAuthnChallengeModel, error) {
+		challenge := challenges[challengeHash]
+		delete(challenges, challengeHash)
+		return challenge, nil
+	}
+	mockRepo.getWebAuthnCredentialsByUserIDFunc = func(ctx context.Context, userID int64) ([]*model.WebAuthnCredentialModel, error) {
+		return []*model.WebAuthnCredentialModel{credential}, nil
+	}
+	mockRepo.getWebAuthnCredentialByCredentialIDFunc = func(ctx context.Context, credentialID []byte) (*model.WebAuthnCredentialModel, error) {
+		return credential, nil
+	}
+	mockRepo.updateWebAuthnSignCountFunc = func(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error {
+		credential.SignCount = signCount
+		return nil
+	}
+	return authenticator
+}
+
+func passkeyAssertion(t *testing.T, service UserService, authenticator *webauthntest.Authenticator) *dto.PasskeyAssertion {
+	options, _, err := service.BeginPasskeyLogin(context.Background(), dto.BeginPasskeyLoginRequest{Email: "test@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	challenge, _ := webauthn.DecodeString(options.Challenge)
+	resp := authenticator.Assert(challenge)
+	return &dto.PasskeyAssertion{
+		ID:                webauthn.EncodeToString(resp.CredentialID),
+		ClientDataJSON:    webauthn.EncodeToString(resp.ClientDataJSON),
+		AuthenticatorData: webauthn.EncodeToString(resp.AuthenticatorData),
+		Signature:         webauthn.EncodeToString(resp.Signature),
+	}
+}
+
+func TestLogin_PasskeyWithoutUserVerificationStillNeedsTwoFactor(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	mockRepo := newTwoFactorRepo(secret)
+	authenticator := withPasskey(t, mockRepo, cfg.WebAuthn())
+	service := NewService(cfg, mockRepo, nil)
+
+	authenticator.UserVerified = false
+	response, status, err := service.Login(context.Background(), dto.LoginRequest{
+		Email:   "test@example.com",
+		Passkey: passkeyAssertion(t, service, authenticator),
+	})
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d (%v)", http.StatusOK, status, err)
+	}
+	if !response.MFARequired {
+		t.Errorf("Expected an MFA challenge, got %+v", response)
+	}
+
+	authenticator.UserVerified = true
+	response, _, err = service.Login(context.Background(), dto.LoginRequest{
+		Email:   "test@example.com",
+		Passkey: passkeyAssertion(t, service, authenticator),
+	})
+	if err != nil || response.Token == "" {
+		t.Errorf("Expected a user-verifying passkey to issue tokens, got %+v (%v)", response, err)
+	}
+}
+
+func TestLoginMFA_Passkey(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	cfg := &config.Config{SecreetJwt: "test-secret"}
+	mockRepo := newTwoFactorRepo(secret)
+	authenticator := withPasskey(t, mockRepo, cfg.WebAuthn())
+	service := NewService(cfg, mockRepo, nil)
+
+	mfaToken, _ := jwt.CreateMFAToken(123, "testuser", cfg.JWTOptions(), time.Minute)
+	assertion := passkeyAssertion(t, service, authenticator)
+
+	response, status, err := service.LoginMFA(context.Background(), dto.LoginMFARequest{MFAToken: mfaToken, Passkey: assertion})
+	if err != nil || status != http.StatusOK || response.Token == "" {
+		t.Fatalf("Expected tokens, got %+v, %d (%v)", response, status, err)
+	}
+
+	// the challenge was spent
+	_, status, err = service.LoginMFA(context.Background(), dto.LoginMFARequest{MFAToken: mfaToken, Passkey: assertion})
+	if err == nil || status != http.StatusBadRequest {
+		t.Errorf("Expected replayed assertion to get %d, got %d (%v)", http.StatusBadRequest, status, err)
+	}
+}
+
+func TestRegister_Closed(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		createUserFunc: func(ctx context.Context, user *model.UserModel) (int64, error) {
+			t.Error("Expected no user to be created")
+			return 0, nil
+		},
+	}
+	service := NewService(&config.Config{RegistrationMode: config.RegistrationClosed}, mockRepo, nil)
+
+	_, status, err := service.Register(context.Background(), dto.RegisterRequest{
+		Email:    "test@example.com",
+		Username: "testuser",
+		Password: "password123",
+	})
+	if err == nil || status != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusForbidden, status, err)
+	}
+}
+
+func TestRegister_InviteOnly(t *testing.T) {
+	code := "ABCDEFGHIJKLMNOP"
+	mockRepo := &mockUserRepository{
+		createInvitedUserFunc: func(ctx context.Context, user *model.UserModel, codeHash string, now time.Time) (int64, bool, error) {
+			if codeHash != hashInviteCode(code) {
+				return 0, false, nil
+			}
+			return 7, true, nil
+		},
+	}
+	service := NewService(&config.Config{RegistrationMode: config.RegistrationInviteOnly}, mockRepo, nil)
+
+	tests := []struct {
+		name           string
+		inviteCode     string
+		expectedStatus int
+	}{
+		{"missing code", "", http.StatusForbidden},
+		{"unknown code", "ZZZZZZZZZZZZZZZZ", http.StatusBadRequest},
+		{"valid code typed loosely", "abcd-efgh-ijkl-mnop", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, status, _ := service.Register(context.Background(), dto.RegisterRequest{
+				Email:      "test@example.com",
+				Username:   "testuser",
+				Password:   "password123",
+				InviteCode: tt.inviteCode,
+			})
+			if status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, status)
+			}
+			if tt.expectedStatus == http.StatusOK && id != 7 {
+				t.Errorf("Expected user ID 7, got %d", id)
+			}
+		})
+	}
+}
+
+func TestCreateInvite_QuotaForRegularUsers(t *testing.T) {
+	roles := []string{}
+	mockRepo := &mockUserRepository{
+		getUserRolesFunc: func(ctx context.Context, userID int64) ([]string, error) {
+			return roles, nil
+		},
+		countInviteCodesByCreatorFunc: func(ctx context.Context, userID int64) (int, error) {
+			return 2, nil
+		},
+	}
+	service := NewService(&config.Config{InviteQuota: 2}, mockRepo, nil)
+
+	if _, status, _ := service.CreateInvite(context.Background(), 1, dto.CreateInviteRequest{}); status != http.StatusForbidden {
+		t.Errorf("Expected exhausted quota to get %d, got %d", http.StatusForbidden, status)
+	}
+
+	roles = []string{model.RoleAdmin}
+	response, status, err := service.CreateInvite(context.Background(), 1, dto.CreateInviteRequest{MaxUses: 50})
+	if err != nil || status != http.StatusCreated {
+		t.Fatalf("Expected admin invite to be created, got %d (%v)", status, err)
+	}
+	if response.MaxUses != 50 || len(response.Code) != 16 || response.CodePrefix != response.Code[:4] {
+		t.Errorf("Unexpected invite: %+v", response)
+	}
+}
+
+func TestUserForIdentity_NoSignupUnlessRegistrationOpen(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		createUserFunc: func(ctx context.Context, user *model.UserModel) (int64, error) {
+			t.Error("Expected no user to be created")
+			return 0, nil
+		},
+	}
+	service := NewService(&config.Config{RegistrationMode: config.RegistrationInviteOnly}, mockRepo, nil).(*userService)
+
+	_, status, err := service.userForIdentity(context.Background(), "acme", "subject", "new@example.com", true, time.Now())
+	if err == nil || status != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d (%v)", http.StatusForbidden, status, err)
+	}
+}
+
+func TestLogin_ReactivatesDuringGracePeriod(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	purgeAfter := time.Now().Add(time.Hour)
+
+	reactivated := false
+	mockRepo := &mockUserRepository{
+		getUserByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (*model.UserModel, error) {
+			return &model.UserModel{
+				ID:            123,
+				Username:      "testuser",
+				Email:         "test@example.com",
+				Password:      string(hashedPassword),
+				DeactivatedAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+				PurgeAfter:    sql.NullTime{Time: purgeAfter, Valid: true},
+			}, nil
+		},
+		reactivateUserFunc: func(ctx context.Context, userID int64, now time.Time) (bool, error) {
+			reactivated = userID == 123
+			return true, nil
+		},
+	}
+	service := NewService(&config.Config{SecreetJwt: "test-secret"}, mockRepo, nil)
+
+	response, status, err := service.Login(context.Background(), dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected login to succeed, got %d (%v)", status, err)
+	}
+	if !reactivated || !response.Reactivated || response.Token == "" {
+		t.Errorf("Expected the account to be reactivated, got %+v", response)
+	}
+
+	// once the grace period is over the account only waits for the purge
+	purgeAfter = time.Now().Add(-time.Minute)
+	reactivated = false
+	_, status, err = service.Login(context.Background(), dto.LoginRequest{Email: "test@example.com", Password: "password123"})
+	if err == nil || status != http.StatusForbidden || reactivated {
+		t.Errorf("Expected status %d without reactivation, got %d (%v)", http.StatusForbidden, status, err)
+	}
+}
+
+func TestDeactivateAccount(t *testing.T) {
+	var grace time.Duration
+	mockRepo := &mockUserRepository{
+		deactivateUserFunc: func(ctx context.Context, userID int64, now, purgeAfter time.Time) (bool, error) {
+			grace = purgeAfter.Sub(now)
+			return userID == 123, nil
+		},
+	}
+	service := NewService(&config.Config{AccountDeletionGrace: 48 * time.Hour}, mockRepo, nil)
+
+	_, status, err := service.DeactivateAccount(context.Background(), 123)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("Expected deactivation to succeed, got %d (%v)", status, err)
+	}
+	if grace != 48*time.Hour {
+		t.Errorf("Expected a 48h grace period, got %v", grace)
+	}
+
+	_, status, _ = service.DeactivateAccount(context.Background(), 456)
+	if status != http.StatusConflict {
+		t.Errorf("Expected already deactivated account to get %d, got %d", http.StatusConflict, status)
+	}
+}
+
+func TestPurgeDeactivatedAccounts_WorksThroughBatches(t *testing.T) {
+	due := make([]int64, purgeBatchSize+1)
+	for i := range due {
+		due[i] = int64(i + 1)
+	}
+	mockRepo := &mockUserRepository{
+		getUsersDueForPurgeFunc: func(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+			return slices.Clone(due[:min(limit, len(due))]), nil
+		},
+		purgeUserFunc: func(ctx context.Context, userID int64, now time.Time) (bool, error) {
+			due = slices.DeleteFunc(due, func(id int64) bool { return id == userID })
+			return true, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	purged, err := service.PurgeDeactivatedAccounts(context.Background())
+	if err != nil || purged != purgeBatchSize+1 || len(due) != 0 {
+		t.Errorf("Expected every due account purged, got %d (%v), %d left", purged, err, len(due))
+	}
+}
+
+func TestFollowUser(t *testing.T) {
+	following := map[int64]bool{3: true}
+	mockRepo := &mockUserRepository{
+		isFollowingFunc: func(ctx context.Context, followerID, followeeID int64) (bool, error) {
+			return following[followeeID], nil
+		},
+		followUserFunc: func(ctx context.Context, followerID, followeeID int64) (bool, error) {
+			// user 4 does not exist or is deactivated
+			return followeeID != 4, nil
+		},
+	}
+	service := NewService(&config.Config{}, mockRepo, nil)
+
+	tests := []struct {
+		followeeID int64
+		want       int
+	}{
+		{1, http.StatusBadRequest},
+		{2, http.StatusCreated},
+		{3, http.StatusConflict},
+		{4, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if status, _ := service.FollowUser(context.Background(), 1, tt.followeeID); status != tt.want {
+			t.Errorf("Expected status %d following user %d, got %d", tt.want, tt.followeeID, status)
+		}
+	}
+
+	if status, _ := service.UnfollowUser(context.Background(), 1, 2); status != http.StatusNotFound {
+		t.Errorf("Expected status %d unfollowing a user not followed, got %d", http.StatusNotFound, status)
+	}
+	if status, _ := service.UnfollowUser(context.Background(), 1, 3); status != http.StatusOK {
+		t.Errorf("Expected status %d unfollowing a followed user, got %d", http.StatusOK, status)
+	}
+}
@@ -0,0 +1,53 @@
+package blurhash
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	white := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(white, white.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	hash, err := Encode(white, 4, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// size flag, maximum AC value, 4 character DC and 2 characters for
+	// each of the 11 AC components
+	if len(hash) != 28 {
+		t.Fatalf("Expected 28 characters, got %d (%q)", len(hash), hash)
+	}
+	if hash[0] != 'L' {
+		t.Errorf("Expected the 4x3 size flag L, got %q", hash[0])
+	}
+	// 0xFFFFFF in base 83
+	if hash[2:6] != "TSUA" {
+		t.Errorf("Expected a white DC component, got %q", hash[2:6])
+	}
+
+	// a gradient carries AC energy a flat image doesn't
+	gradient := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for x := 0; x < 32; x++ {
+		for y := 0; y < 32; y++ {
+			gradient.Set(x, y, color.RGBA{uint8(x * 8), 0, 0, 255})
+		}
+	}
+	gradientHash, _ := Encode(gradient, 4, 3)
+	if gradientHash[1] <= hash[1] {
+		t.Errorf("Expected a larger AC maximum for a gradient, got %q vs %q", gradientHash, hash)
+	}
+}
+
+func TestEncode_InvalidComponents(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	if _, err := Encode(img, 0, 3); !errors.Is(err, ErrInvalidComponents) {
+		t.Errorf("Expected ErrInvalidComponents, got %v", err)
+	}
+	if _, err := Encode(img, 4, 10); !errors.Is(err, ErrInvalidComponents) {
+		t.Errorf("Expected ErrInvalidComponents, got %v", err)
+	}
+}
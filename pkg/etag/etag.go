@@ -0,0 +1,75 @@
+// Package etag formats the entity tags of versioned resources and
+// evaluates If-Match and If-None-Match headers against them, so writes
+// based on a stale read can be rejected and unchanged reads revalidated.
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// digestLength is how many bytes of the SHA-256 of a body go into its tag.
+const digestLength = 16
+
+// FromVersion returns the strong entity tag of a resource version.
+func FromVersion(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// WithDigest returns the strong entity tag of a response body. When the
+// resource has a version tag, the digest is appended to it, as in "3.9f2c";
+// the body can change without a new version, e.g. when its like count does.
+func WithDigest(tag string, body []byte) string {
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:digestLength])
+	if len(tag) >= 2 && tag[0] == '"' && tag[len(tag)-1] == '"' {
+		return tag[:len(tag)-1] + "." + digest + `"`
+	}
+	return `"` + digest + `"`
+}
+
+// Match reports whether the If-Match header value matches tag: it is "*"
+// or lists tag. Comparison is strong, so weak tags never match. A listed
+// tag carrying a body digest matches on its version alone.
+func Match(header, tag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if withoutDigest(strings.TrimSpace(candidate)) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NoneMatch reports whether the If-None-Match header value matches tag,
+// meaning the client's copy is current. Comparison is weak, as RFC 9110
+// requires for If-None-Match.
+func NoneMatch(header, tag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutDigest strips the body digest WithDigest added to a version tag.
+func withoutDigest(tag string) string {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return tag
+	}
+	if i := strings.IndexByte(tag, '.'); i > 0 {
+		return tag[:i] + `"`
+	}
+	return tag
+}
@@ -0,0 +1,79 @@
+package etag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tag := FromVersion(3)
+	if tag != `"3"` {
+		t.Fatalf("Expected \"3\", got %s", tag)
+	}
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"3"`, true},
+		{`"2", "3"`, true},
+		{`*`, true},
+		{`"2"`, false},
+		{`W/"3"`, false},
+		{`3`, false},
+		{`"3.9f2c"`, true},
+		{`"2.9f2c"`, false},
+		{`W/"3.9f2c"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.header, tag); got != tt.want {
+			t.Errorf("Match(%q) = %v, expected %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestWithDigest(t *testing.T) {
+	body := []byte(`{"id":1}`)
+
+	versioned := WithDigest(FromVersion(3), body)
+	if !strings.HasPrefix(versioned, `"3.`) || !strings.HasSuffix(versioned, `"`) {
+		t.Errorf("Expected the version with a digest, got %s", versioned)
+	}
+	if !Match(versioned, FromVersion(3)) {
+		t.Errorf("Expected %s to match version 3", versioned)
+	}
+
+	plain := WithDigest("", body)
+	if strings.Contains(plain, ".") || len(plain) != 2*digestLength+2 {
+		t.Errorf("Expected a quoted digest, got %s", plain)
+	}
+	if plain != WithDigest("", []byte(`{"id":1}`)) {
+		t.Error("Expected the same body to get the same tag")
+	}
+	if plain == WithDigest("", []byte(`{"id":2}`)) {
+		t.Error("Expected a different body to get a different tag")
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tag := `"3.9f2c"`
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"3.9f2c"`, true},
+		{`W/"3.9f2c"`, true},
+		{`"1", "3.9f2c"`, true},
+		{`*`, true},
+		{`"3"`, false},
+		{`"3.0000"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := NoneMatch(tt.header, tag); got != tt.want {
+			t.Errorf("NoneMatch(%q) = %v, expected %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+)
+
+var errInvalidGIF = errors.New("imageproc: invalid GIF")
+
+// StripGIF removes comments and application extensions, which can carry
+// XMP metadata, from a GIF without decoding it. The looping extension is
+// kept so animations still repeat.
+func StripGIF(data []byte) ([]byte, error) {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return nil, errInvalidGIF
+	}
+
+	i := 13
+	if packed := data[10]; packed&0x80 != 0 {
+		i += 3 << ((packed & 0x07) + 1)
+	}
+	if i > len(data) {
+		return nil, errInvalidGIF
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:i])
+
+	for i < len(data) {
+		start := i
+		switch data[i] {
+		case 0x21: // extension
+			if i+2 > len(data) {
+				return nil, errInvalidGIF
+			}
+			label := data[i+1]
+			end, err := skipSubBlocks(data, i+2)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			// keep graphic control and plain text; of the application
+			// extensions, only looping
+			keep := label == 0xF9 || label == 0x01
+			if label == 0xFF && start+14 <= len(data) && data[start+2] == 11 {
+				app := string(data[start+3 : start+14])
+				keep = app == "NETSCAPE2.0" || app == "ANIMEXTS1.0"
+			}
+			if keep {
+				out.Write(data[start:i])
+			}
+		case 0x2C: // image descriptor
+			if i+10 > len(data) {
+				return nil, errInvalidGIF
+			}
+			i += 10
+			if packed := data[start+9]; packed&0x80 != 0 {
+				i += 3 << ((packed & 0x07) + 1)
+			}
+			// LZW minimum code size, then the image data
+			end, err := skipSubBlocks(data, i+1)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			out.Write(data[start:i])
+		case 0x3B: // trailer
+			out.WriteByte(0x3B)
+			return out.Bytes(), nil
+		default:
+			return nil, errInvalidGIF
+		}
+	}
+	return nil, errInvalidGIF
+}
+
+// skipSubBlocks returns the offset after the data sub-blocks starting at i.
+func skipSubBlocks(data []byte, i int) (int, error) {
+	for {
+		if i >= len(data) {
+			return 0, errInvalidGIF
+		}
+		size := int(data[i])
+		i += 1 + size
+		if size == 0 {
+			return i, nil
+		}
+	}
+}
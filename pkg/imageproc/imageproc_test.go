@@ -0,0 +1,156 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF APP1 segment with the orientation tag
+// after the JPEG's SOI marker.
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, app1...)
+	return append(out, jpg[2:]...)
+}
+
+func TestDecode_OrientsJPEG(t *testing.T) {
+	// a 4x2 image, red on the left half
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{0, 0, 255, 255}
+			if x < 2 {
+				c = color.RGBA{255, 0, 0, 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, src, &jpeg.Options{Quality: 100})
+	data := withOrientation(buf.Bytes(), 6)
+
+	if got := JPEGOrientation(data); got != 6 {
+		t.Fatalf("Expected orientation 6, got %d", got)
+	}
+
+	img, format, err := Decode(data, 100)
+	if err != nil || format != "jpeg" {
+		t.Fatalf("Expected a JPEG, got %q (%v)", format, err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 4 {
+		t.Fatalf("Expected the image turned to 2x4, got %dx%d", b.Dx(), b.Dy())
+	}
+	// turned clockwise, the red half ends up on top
+	if r, _, b, _ := img.At(1, 0).RGBA(); r < b {
+		t.Errorf("Expected red at the top, got %v", img.At(1, 0))
+	}
+}
+
+func TestDecode_RejectsTooManyPixels(t *testing.T) {
+	data := encodePNG(t, image.NewGray(image.Rect(0, 0, 100, 100)))
+	if _, _, err := Decode(data, 100*100-1); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("Expected ErrTooManyPixels, got %v", err)
+	}
+	if _, _, err := Decode(data, 100*100); err != nil {
+		t.Errorf("Expected an image at the limit to decode, got %v", err)
+	}
+	if _, _, err := Decode([]byte("not an image"), 100); err == nil {
+		t.Error("Expected an error for a non-image")
+	}
+}
+
+func TestFitAndResize(t *testing.T) {
+	if w, h := Fit(4000, 3000, 800); w != 800 || h != 600 {
+		t.Errorf("Expected 800x600, got %dx%d", w, h)
+	}
+	if w, h := Fit(1000, 4000, 800); w != 200 || h != 800 {
+		t.Errorf("Expected 200x800, got %dx%d", w, h)
+	}
+	if w, h := Fit(300, 200, 800); w != 300 || h != 200 {
+		t.Errorf("Expected small images unchanged, got %dx%d", w, h)
+	}
+
+	// alternating black and white columns average to grey
+	src := image.NewGray(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x += 2 {
+			src.SetGray(x, y, color.Gray{255})
+		}
+	}
+	dst := Resize(src, 2, 2)
+	if r, _, _, _ := dst.At(0, 0).RGBA(); r>>8 != 127 {
+		t.Errorf("Expected grey, got %v", dst.At(0, 0))
+	}
+}
+
+func TestStripGIF(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{
+		Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 2, 2), palette), image.NewPaletted(image.Rect(0, 0, 2, 2), palette)},
+		Delay: []int{10, 10},
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// insert a comment and an XMP application extension before the frames
+	header := 13
+	if data[10]&0x80 != 0 {
+		header += 3 << ((data[10] & 0x07) + 1)
+	}
+	comment := append([]byte{0x21, 0xFE, 6}, []byte("secret")...)
+	comment = append(comment, 0)
+	xmp := append([]byte{0x21, 0xFF, 11}, []byte("XMP DataXMP")...)
+	xmp = append(xmp, 3, 'g', 'p', 's', 0)
+	tagged := append(append(append(append([]byte{}, data[:header]...), comment...), xmp...), data[header:]...)
+
+	stripped, err := StripGIF(tagged)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bytes.Contains(stripped, []byte("secret")) || bytes.Contains(stripped, []byte("XMP")) {
+		t.Error("Expected the comment and XMP to be removed")
+	}
+	if !bytes.Contains(stripped, []byte("NETSCAPE2.0")) {
+		t.Error("Expected the looping extension to be kept")
+	}
+	decoded, err := gif.DecodeAll(bytes.NewReader(stripped))
+	if err != nil || len(decoded.Image) != 2 {
+		t.Errorf("Expected a valid two frame GIF, got %v", err)
+	}
+
+	if _, err := StripGIF(tagged[:len(tagged)-5]); err == nil {
+		t.Error("Expected an error for a truncated GIF")
+	}
+}
@@ -1,20 +1,179 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func CreateToken(id int64, username, secretKey string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id": id,
-		"username": username,
-		"exp": time.Now().Add(60 * time.Minute).Unix(),
-	})
-	tokenString, err := token.SignedString([]byte(secretKey))
+// DefaultTTL is used when Options.TTL is not set.
+const DefaultTTL = 60 * time.Minute
+
+// Token uses keep short-lived login challenge tokens from being accepted as
+// access tokens and vice versa.
+const (
+	TokenUseAccess = "access"
+	TokenUseMFA    = "mfa"
+)
+
+// Claims is the access token payload. It is produced by CreateToken and
+// consumed by the auth middleware, so both sides agree on the claim names.
+type Claims struct {
+	UserID   int64    `json:"user_id"`
+	Username string   `json:"username"`
+	Roles    []string `json:"roles,omitempty"`
+	TokenUse string   `json:"token_use"`
+	// ClientID and Scope are only set on tokens issued to OAuth clients,
+	// following the RFC 9068 claim names.
+	ClientID string `json:"client_id,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Options controls how tokens are signed and which registered claims are
+// issued and required on parse.
+type Options struct {
+	Secret   string
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
+
+func CreateToken(id int64, username string, roles []string, opts Options) (string, error) {
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return createToken(Claims{
+		UserID:   id,
+		Username: username,
+		Roles:    roles,
+		TokenUse: TokenUseAccess,
+	}, opts, ttl)
+}
+
+// CreateOAuthToken issues an access token on behalf of the user to an OAuth
+// client. It carries the granted scopes and never the user's roles.
+func CreateOAuthToken(id int64, username, clientID string, scopes []string, opts Options) (string, error) {
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return createToken(Claims{
+		UserID:   id,
+		Username: username,
+		TokenUse: TokenUseAccess,
+		ClientID: clientID,
+		Scope:    strings.Join(scopes, " "),
+	}, opts, ttl)
+}
+
+// CreateMFAToken issues the challenge token returned by the first login step
+// when the user has two-factor authentication enabled.
+func CreateMFAToken(id int64, username string, opts Options, ttl time.Duration) (string, error) {
+	return createToken(Claims{
+		UserID:   id,
+		Username: username,
+		TokenUse: TokenUseMFA,
+	}, opts, ttl)
+}
+
+// ParseToken verifies an access token's signature and its exp, nbf, iat,
+// iss and aud claims, and checks that sub matches user_id.
+func ParseToken(tokenString string, opts Options) (*Claims, error) {
+	return parseToken(tokenString, opts, TokenUseAccess)
+}
+
+// ParseMFAToken is ParseToken for login challenge tokens. Challenges are
+// single use, so they must carry a jti to be spent by.
+func ParseMFAToken(tokenString string, opts Options) (*Claims, error) {
+	claims, err := parseToken(tokenString, opts, TokenUseMFA)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ID == "" {
+		return nil, errors.New("token is missing jti claim")
+	}
+	return claims, nil
+}
+
+func createToken(claims Claims, opts Options, ttl time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        jti,
+		Subject:   strconv.FormatInt(claims.UserID, 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	if opts.Issuer != "" {
+		claims.Issuer = opts.Issuer
+	}
+	if opts.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{opts.Audience}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(opts.Secret))
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
-}	
\ No newline at end of file
+}
+
+func parseToken(tokenString string, opts Options, tokenUse string) (*Claims, error) {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if opts.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(opts.Audience))
+	}
+
+	var claims Claims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(opts.Secret), nil
+	}, parserOptions...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	if claims.NotBefore == nil {
+		return nil, errors.New("token is missing nbf claim")
+	}
+	if claims.UserID == 0 || claims.Subject != strconv.FormatInt(claims.UserID, 10) {
+		return nil, errors.New("token subject does not match user_id")
+	}
+	if claims.TokenUse != tokenUse {
+		return nil, errors.New("token cannot be used here")
+	}
+
+	return &claims, nil
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -14,7 +14,7 @@ func TestCreateToken_Success(t *testing.T) {
 	secretKey := "test-secret-key"
 
 	// Act
-	token, err := CreateToken(id, username, secretKey)
+	token, err := CreateToken(id, username, nil, Options{Secret: secretKey})
 
 	// Assert
 	if err != nil {
@@ -44,8 +44,8 @@ func TestCreateToken_Success(t *testing.T) {
 	}
 
 	// Verify ID claim
-	if claimID, ok := claims["id"].(float64); !ok || int64(claimID) != id {
-		t.Errorf("Expected id claim to be %d, got %v", id, claims["id"])
+	if claimID, ok := claims["user_id"].(float64); !ok || int64(claimID) != id {
+		t.Errorf("Expected user_id claim to be %d, got %v", id, claims["user_id"])
 	}
 
 	// Verify username claim
@@ -72,8 +72,8 @@ func TestCreateToken_DifferentUsers(t *testing.T) {
 	// Test that different users get different tokens
 	secretKey := "test-secret-key"
 
-	token1, err1 := CreateToken(1, "user1", secretKey)
-	token2, err2 := CreateToken(2, "user2", secretKey)
+	token1, err1 := CreateToken(1, "user1", nil, Options{Secret: secretKey})
+	token2, err2 := CreateToken(2, "user2", nil, Options{Secret: secretKey})
 
 	if err1 != nil || err2 != nil {
 		t.Fatalf("Expected no errors, got: %v, %v", err1, err2)
@@ -90,7 +90,7 @@ func TestCreateToken_VerifySigningMethod(t *testing.T) {
 	username := "testuser"
 	secretKey := "test-secret-key"
 
-	token, _ := CreateToken(id, username, secretKey)
+	token, _ := CreateToken(id, username, nil, Options{Secret: secretKey})
 
 	parsedToken, _ := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
 		// Verify signing method
@@ -117,7 +117,7 @@ func TestCreateToken_InvalidSecretKeyVerification(t *testing.T) {
 	secretKey := "correct-secret"
 	wrongSecret := "wrong-secret"
 
-	token, err := CreateToken(id, username, secretKey)
+	token, err := CreateToken(id, username, nil, Options{Secret: secretKey})
 	if err != nil {
 		t.Fatalf("Expected no error creating token, got: %v", err)
 	}
@@ -165,7 +165,7 @@ func TestCreateToken_EmptyValues(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := CreateToken(tt.id, tt.username, tt.secretKey)
+			token, err := CreateToken(tt.id, tt.username, nil, Options{Secret: tt.secretKey})
 
 			if tt.wantError && err == nil {
 				t.Error("Expected error, got nil")
@@ -188,7 +188,7 @@ func TestCreateToken_LongValues(t *testing.T) {
 	longUsername := string(make([]byte, 1000)) // Very long username
 	longSecret := string(make([]byte, 1000))   // Very long secret
 
-	token, err := CreateToken(id, longUsername, longSecret)
+	token, err := CreateToken(id, longUsername, nil, Options{Secret: longSecret})
 
 	if err != nil {
 		t.Fatalf("Expected no error with long values, got: %v", err)
@@ -205,7 +205,7 @@ func TestCreateToken_NegativeID(t *testing.T) {
 	username := "testuser"
 	secretKey := "secret"
 
-	token, err := CreateToken(id, username, secretKey)
+	token, err := CreateToken(id, username, nil, Options{Secret: secretKey})
 
 	if err != nil {
 		t.Fatalf("Expected no error, got: %v", err)
@@ -217,8 +217,8 @@ func TestCreateToken_NegativeID(t *testing.T) {
 	})
 
 	claims, _ := parsedToken.Claims.(jwt.MapClaims)
-	if claimID, ok := claims["id"].(float64); !ok || int64(claimID) != id {
-		t.Errorf("Expected id claim to be %d, got %v", id, claims["id"])
+	if claimID, ok := claims["user_id"].(float64); !ok || int64(claimID) != id {
+		t.Errorf("Expected user_id claim to be %d, got %v", id, claims["user_id"])
 	}
 }
 
@@ -237,7 +237,7 @@ func TestCreateToken_SpecialCharactersInUsername(t *testing.T) {
 
 	for _, username := range tests {
 		t.Run(username, func(t *testing.T) {
-			token, err := CreateToken(1, username, secretKey)
+			token, err := CreateToken(1, username, nil, Options{Secret: secretKey})
 
 			if err != nil {
 				t.Errorf("Expected no error for username %q, got: %v", username, err)
@@ -255,3 +255,198 @@ func TestCreateToken_SpecialCharactersInUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateToken_RegisteredClaims(t *testing.T) {
+	opts := Options{
+		Secret:   "secret",
+		Issuer:   "go-twitter",
+		Audience: "go-twitter-api",
+		TTL:      15 * time.Minute,
+	}
+
+	token, err := CreateToken(42, "testuser", []string{"admin"}, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	claims, err := ParseToken(token, opts)
+	if err != nil {
+		t.Fatalf("Expected token to parse, got: %v", err)
+	}
+
+	if claims.UserID != 42 || claims.Subject != "42" {
+		t.Errorf("Expected user_id and sub to be 42, got %d and %q", claims.UserID, claims.Subject)
+	}
+
+	if claims.Issuer != opts.Issuer {
+		t.Errorf("Expected issuer %q, got %q", opts.Issuer, claims.Issuer)
+	}
+
+	if len(claims.Audience) != 1 || claims.Audience[0] != opts.Audience {
+		t.Errorf("Expected audience %q, got %v", opts.Audience, claims.Audience)
+	}
+
+	if claims.ID == "" {
+		t.Error("Expected jti claim to be set")
+	}
+
+	if claims.IssuedAt == nil || claims.NotBefore == nil {
+		t.Error("Expected iat and nbf claims to be set")
+	}
+
+	if len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+		t.Errorf("Expected roles [admin], got %v", claims.Roles)
+	}
+
+	diff := claims.ExpiresAt.Sub(time.Now().Add(opts.TTL)).Abs()
+	if diff > 5*time.Second {
+		t.Errorf("Expected expiration to follow the configured TTL, got %v", claims.ExpiresAt)
+	}
+}
+
+func TestCreateToken_UniqueTokenIDs(t *testing.T) {
+	opts := Options{Secret: "secret"}
+
+	token1, _ := CreateToken(1, "user", nil, opts)
+	token2, _ := CreateToken(1, "user", nil, opts)
+
+	claims1, err1 := ParseToken(token1, opts)
+	claims2, err2 := ParseToken(token2, opts)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("Expected no errors, got: %v, %v", err1, err2)
+	}
+
+	if claims1.ID == claims2.ID {
+		t.Error("Expected every token to get its own jti")
+	}
+}
+
+func TestParseToken_RejectsMismatchedIssuerAndAudience(t *testing.T) {
+	opts := Options{Secret: "secret", Issuer: "go-twitter", Audience: "go-twitter-api"}
+
+	token, err := CreateToken(1, "user", nil, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{name: "Wrong issuer", opts: Options{Secret: "secret", Issuer: "someone-else", Audience: "go-twitter-api"}},
+		{name: "Wrong audience", opts: Options{Secret: "secret", Issuer: "go-twitter", Audience: "another-api"}},
+		{name: "Wrong secret", opts: Options{Secret: "other", Issuer: "go-twitter", Audience: "go-twitter-api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseToken(token, tt.opts); err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestParseToken_RejectsNotYetValidToken(t *testing.T) {
+	secretKey := "secret"
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "1",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(2 * time.Hour)),
+		},
+	})
+	tokenString, _ := token.SignedString([]byte(secretKey))
+
+	if _, err := ParseToken(tokenString, Options{Secret: secretKey}); err == nil {
+		t.Error("Expected error for token used before nbf, got nil")
+	}
+}
+
+func TestParseToken_RejectsMissingNotBefore(t *testing.T) {
+	secretKey := "secret"
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "1",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	tokenString, _ := token.SignedString([]byte(secretKey))
+
+	if _, err := ParseToken(tokenString, Options{Secret: secretKey}); err == nil {
+		t.Error("Expected error for token without nbf, got nil")
+	}
+}
+
+func TestParseToken_RejectsLegacyIDClaim(t *testing.T) {
+	// Tokens issued before the claim schema was unified carried "id"
+	secretKey := "secret"
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":       float64(1),
+		"username": "user",
+		"iat":      time.Now().Unix(),
+		"nbf":      time.Now().Unix(),
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte(secretKey))
+
+	if _, err := ParseToken(tokenString, Options{Secret: secretKey}); err == nil {
+		t.Error("Expected error for token without user_id, got nil")
+	}
+}
+
+func TestParseToken_TokenUseSeparation(t *testing.T) {
+	opts := Options{Secret: "secret"}
+
+	mfaToken, err := CreateMFAToken(1, "user", opts, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	accessToken, err := CreateToken(1, "user", nil, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := ParseToken(mfaToken, opts); err == nil {
+		t.Error("Expected MFA challenge token to be rejected as an access token")
+	}
+
+	if _, err := ParseMFAToken(accessToken, opts); err == nil {
+		t.Error("Expected access token to be rejected as an MFA challenge token")
+	}
+
+	claims, err := ParseMFAToken(mfaToken, opts)
+	if err != nil {
+		t.Fatalf("Expected MFA token to parse, got: %v", err)
+	}
+
+	if claims.UserID != 1 || claims.TokenUse != TokenUseMFA {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+}
+
+func TestCreateOAuthToken(t *testing.T) {
+	opts := Options{Secret: "secret"}
+
+	tokenString, err := CreateOAuthToken(1, "user", "client-abc", []string{"read", "posts:write"}, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	claims, err := ParseToken(tokenString, opts)
+	if err != nil {
+		t.Fatalf("Expected OAuth token to parse as an access token, got: %v", err)
+	}
+
+	if claims.ClientID != "client-abc" || claims.Scope != "read posts:write" {
+		t.Errorf("Expected client and scope claims, got %q and %q", claims.ClientID, claims.Scope)
+	}
+	if len(claims.Roles) != 0 {
+		t.Errorf("Expected no roles on OAuth token, got %v", claims.Roles)
+	}
+}
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	msg := Format("no-reply@example.com", Message{
+		To:      "user@example.com",
+		Subject: "Hello\r\nBcc: attacker@example.com",
+		Body:    "line one\nline two",
+	})
+
+	got := string(msg)
+	if strings.Contains(got, "\r\nBcc:") {
+		t.Errorf("Expected header injection to be stripped, got %q", got)
+	}
+	if !strings.Contains(got, "To: user@example.com\r\n") {
+		t.Errorf("Expected To header, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\r\n\r\nline one\r\nline two") {
+		t.Errorf("Expected CRLF body after headers, got %q", got)
+	}
+}
+
+func TestLogMailer(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewLogMailer(&buf).Send(context.Background(), Message{To: "user@example.com", Subject: "Hi", Body: "body"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "user@example.com") || !strings.Contains(buf.String(), "body") {
+		t.Errorf("Expected message to be logged, got %q", buf.String())
+	}
+}
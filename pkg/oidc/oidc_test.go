@@ -0,0 +1,137 @@
+package oidc
+
+import (
+	"context"
+	"go-twitter/pkg/oidc/oidctest"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestProvider(t *testing.T) (*oidctest.Server, *Provider) {
+	t.Helper()
+	server := oidctest.NewServer("client-id", "client-secret")
+	t.Cleanup(server.Close)
+
+	provider := NewProvider(Config{
+		Name:         "stub",
+		Issuer:       server.Issuer(),
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "https://app.example.com/callback",
+	}, server.Client())
+	return server, provider
+}
+
+// signIn follows the authorization redirect and returns the code.
+func signIn(t *testing.T, server *oidctest.Server, provider *Provider, nonce string) string {
+	t.Helper()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", nonce)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("Authorization request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Failed to parse redirect: %v", err)
+	}
+	if location.Query().Get("state") != "state-1" {
+		t.Errorf("Expected state to round-trip, got %q", location.Query().Get("state"))
+	}
+	return location.Query().Get("code")
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	server, provider := newTestProvider(t)
+	server.SignIn(oidctest.Identity{Subject: "user-1", Email: "a@example.com", EmailVerified: true, Name: "A"})
+
+	code := signIn(t, server, provider, "nonce-1")
+	rawIDToken, err := provider.Exchange(context.Background(), code)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	idToken, err := provider.VerifyIDToken(context.Background(), rawIDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if idToken.Subject != "user-1" || idToken.Email != "a@example.com" || !idToken.EmailVerified {
+		t.Errorf("Unexpected ID token: %+v", idToken)
+	}
+
+	if _, err := provider.Exchange(context.Background(), code); err == nil {
+		t.Error("Expected code reuse to fail")
+	}
+}
+
+func TestVerifyIDToken_Rejects(t *testing.T) {
+	server, provider := newTestProvider(t)
+	now := time.Now()
+	valid := jwt.MapClaims{
+		"iss":   server.Issuer(),
+		"aud":   "client-id",
+		"sub":   "user-1",
+		"nonce": "nonce-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+	}
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+
+	if _, err := provider.VerifyIDToken(context.Background(), server.SignIDToken(valid), "nonce-1"); err != nil {
+		t.Fatalf("Expected valid token to verify, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		nonce  string
+	}{
+		{"wrong nonce", valid, "nonce-2"},
+		{"wrong audience", with("aud", "other-client"), "nonce-1"},
+		{"wrong issuer", with("iss", "https://evil.example.com"), "nonce-1"},
+		{"expired", with("exp", now.Add(-time.Minute).Unix()), "nonce-1"},
+		{"no subject", with("sub", ""), "nonce-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.VerifyIDToken(context.Background(), server.SignIDToken(tt.claims), tt.nonce); err == nil {
+				t.Error("Expected verification to fail")
+			}
+		})
+	}
+
+	// signed by a different key with the same kid
+	other := oidctest.NewServer("client-id", "client-secret")
+	defer other.Close()
+	if _, err := provider.VerifyIDToken(context.Background(), other.SignIDToken(valid), "nonce-1"); err == nil {
+		t.Error("Expected token signed by another key to fail")
+	}
+}
+
+func TestDiscovery_IssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer("client-id", "client-secret")
+	defer server.Close()
+
+	provider := NewProvider(Config{Issuer: server.Issuer() + "/", ClientID: "client-id"}, server.Client())
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce"); err == nil {
+		t.Error("Expected discovery to reject a mismatched issuer")
+	}
+}
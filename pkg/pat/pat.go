@@ -0,0 +1,44 @@
+// Package pat generates and hashes personal access tokens. Tokens carry a
+// fixed prefix so they can be told apart from JWTs and spotted by secret
+// scanners; only their SHA-256 hash is ever stored.
+package pat
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	Prefix = "gtp_"
+
+	// DisplayLength is how many leading characters of a token are kept in
+	// plain text so users can recognise it in listings.
+	DisplayLength = 12
+)
+
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Prefix + hex.EncodeToString(b), nil
+}
+
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
+
+// DisplayPrefix returns the non-secret leading part of a token.
+func DisplayPrefix(token string) string {
+	if len(token) <= DisplayLength {
+		return token
+	}
+	return token[:DisplayLength]
+}
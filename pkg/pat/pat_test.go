@@ -0,0 +1,47 @@
+package pat
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	a, err := Generate()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, _ := Generate()
+
+	if !IsPersonalAccessToken(a) {
+		t.Errorf("Expected %q to carry the %q prefix", a, Prefix)
+	}
+	if len(a) != len(Prefix)+64 {
+		t.Errorf("Expected token length %d, got %d", len(Prefix)+64, len(a))
+	}
+	if a == b {
+		t.Error("Expected distinct tokens")
+	}
+}
+
+func TestHash(t *testing.T) {
+	token, _ := Generate()
+
+	if Hash(token) != Hash(token) {
+		t.Error("Expected hash to be deterministic")
+	}
+	if len(Hash(token)) != 64 {
+		t.Errorf("Expected 64 hex characters, got %d", len(Hash(token)))
+	}
+	if strings.Contains(Hash(token), token[len(Prefix):]) {
+		t.Error("Expected hash not to contain the token")
+	}
+}
+
+func TestIsPersonalAccessToken(t *testing.T) {
+	if IsPersonalAccessToken("eyJhbGciOiJIUzI1NiJ9.e30.sig") {
+		t.Error("Expected JWT not to be treated as a personal access token")
+	}
+	if DisplayPrefix("gtp_0123456789abcdef") != "gtp_01234567" {
+		t.Errorf("Unexpected display prefix %q", DisplayPrefix("gtp_0123456789abcdef"))
+	}
+}
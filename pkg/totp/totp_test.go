@@ -0,0 +1,132 @@
+package totp
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 6238 Appendix B. The seeds are the ASCII strings
+// "1234567890" repeated to the hash output length.
+func TestHOTP_RFC6238Vectors(t *testing.T) {
+	seed20 := []byte("12345678901234567890")
+	seed32 := []byte("12345678901234567890123456789012")
+	seed64 := []byte("1234567890123456789012345678901234567890123456789012345678901234")
+
+	tests := []struct {
+		unix   int64
+		hash   func() hash.Hash
+		key    []byte
+		expect string
+	}{
+		{59, sha1.New, seed20, "94287082"},
+		{59, sha256.New, seed32, "46119246"},
+		{59, sha512.New, seed64, "90693936"},
+		{1111111109, sha1.New, seed20, "07081804"},
+		{1111111109, sha256.New, seed32, "68084774"},
+		{1111111109, sha512.New, seed64, "25091201"},
+		{1111111111, sha1.New, seed20, "14050471"},
+		{1111111111, sha256.New, seed32, "67062674"},
+		{1111111111, sha512.New, seed64, "99943326"},
+		{1234567890, sha1.New, seed20, "89005924"},
+		{1234567890, sha256.New, seed32, "91819424"},
+		{1234567890, sha512.New, seed64, "93441116"},
+		{2000000000, sha1.New, seed20, "69279037"},
+		{2000000000, sha256.New, seed32, "90698825"},
+		{2000000000, sha512.New, seed64, "38618901"},
+		{20000000000, sha1.New, seed20, "65353130"},
+		{20000000000, sha256.New, seed32, "77737706"},
+		{20000000000, sha512.New, seed64, "47863826"},
+	}
+
+	for _, tt := range tests {
+		step := uint64(Step(time.Unix(tt.unix, 0)))
+		if got := hotp(tt.hash, tt.key, step, 8); got != tt.expect {
+			t.Errorf("T=%d: expected %s, got %s", tt.unix, tt.expect, got)
+		}
+	}
+}
+
+func TestGenerateCode_MatchesRFCVectorWithSixDigits(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+
+	code, err := GenerateCode(secret, time.Unix(1111111109, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// The 8-digit RFC value is 07081804; a 6-digit code keeps the low digits.
+	if code != "081804" {
+		t.Errorf("Expected 081804, got %s", code)
+	}
+}
+
+func TestValidate_AcceptsSkewAndReturnsStep(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	previous, _ := GenerateCode(secret, now.Add(-Period))
+
+	step, ok := Validate(secret, previous, now)
+	if !ok {
+		t.Fatal("Expected code from the previous period to be accepted")
+	}
+
+	if step != Step(now)-1 {
+		t.Errorf("Expected step %d, got %d", Step(now)-1, step)
+	}
+}
+
+func TestValidate_RejectsOutOfWindowAndMalformedCodes(t *testing.T) {
+	secret, _ := GenerateSecret()
+	now := time.Unix(1700000000, 0)
+	stale, _ := GenerateCode(secret, now.Add(-3*Period))
+
+	tests := []struct {
+		name string
+		code string
+	}{
+		{name: "Stale code", code: stale},
+		{name: "Too short", code: "123"},
+		{name: "Empty", code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(secret, tt.code, now); ok {
+				t.Errorf("Expected %q to be rejected", tt.code)
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidSecret(t *testing.T) {
+	if _, ok := Validate("not base32!", "123456", time.Now()); ok {
+		t.Error("Expected invalid secret to be rejected")
+	}
+}
+
+func TestKeyURI(t *testing.T) {
+	uri := KeyURI("go-twitter", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/") {
+		t.Fatalf("Expected otpauth URI, got %s", uri)
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("Expected valid URI, got: %v", err)
+	}
+
+	if parsed.Query().Get("secret") != "JBSWY3DPEHPK3PXP" || parsed.Query().Get("issuer") != "go-twitter" {
+		t.Errorf("Unexpected query parameters: %s", parsed.RawQuery)
+	}
+}
@@ -0,0 +1,219 @@
+// Package twitterarchive reads tweets from the ZIP archive Twitter/X lets
+// users download from their account settings. Tweets live in data/tweets.js
+// (data/tweet.js in older archives), split into tweets-part1.js and so on
+// for large accounts. Each file is a JavaScript assignment of a JSON array:
+//
+//	window.YTD.tweets.part0 = [ { "tweet": { ... } }, ... ]
+package twitterarchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// CreatedAtLayout is the timestamp format used throughout the archive.
+const CreatedAtLayout = "Mon Jan 02 15:04:05 -0700 2006"
+
+// maxFileSize bounds how much of a single tweets file is read, so a
+// malicious archive cannot exhaust memory by claiming a huge entry.
+const maxFileSize = 512 << 20
+
+var (
+	ErrNoTweets = errors.New("twitterarchive: archive has no data/tweets.js")
+
+	tweetsFile = regexp.MustCompile(`^tweets?(-part\d+)?\.js$`)
+)
+
+type Tweet struct {
+	ID        string
+	Text      string
+	CreatedAt time.Time
+	// Retweet is set for retweets, whose text was written by someone else.
+	Retweet           bool
+	InReplyToStatusID string
+	Hashtags          []string
+	Mentions          []string
+	URLs              []URL
+}
+
+// URL is a link shortened to t.co in the tweet text.
+type URL struct {
+	Short    string
+	Expanded string
+}
+
+// ItemError describes a tweet that could not be read. The rest of the
+// archive is still usable.
+type ItemError struct {
+	File  string
+	Index int
+	ID    string
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	if e.ID != "" {
+		return fmt.Sprintf("%s: tweet %s: %v", e.File, e.ID, e.Err)
+	}
+	return fmt.Sprintf("%s: item %d: %v", e.File, e.Index, e.Err)
+}
+
+type Archive struct {
+	// Tweets are ordered oldest first.
+	Tweets []Tweet
+	Errors []*ItemError
+}
+
+// Read parses every tweets file in the archive. Tweets that cannot be
+// decoded are reported in Archive.Errors; only an unreadable archive or
+// tweets file fails the whole read.
+func Read(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("twitterarchive: %w", err)
+	}
+
+	var files []*zip.File
+	for _, f := range zr.File {
+		if path.Dir(f.Name) == "data" && tweetsFile.MatchString(path.Base(f.Name)) {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, ErrNoTweets
+	}
+
+	archive := &Archive{}
+	for _, f := range files {
+		if err := readFile(f, archive); err != nil {
+			return nil, fmt.Errorf("twitterarchive: %s: %w", f.Name, err)
+		}
+	}
+
+	sort.SliceStable(archive.Tweets, func(i, j int) bool {
+		return archive.Tweets[i].CreatedAt.Before(archive.Tweets[j].CreatedAt)
+	})
+	return archive, nil
+}
+
+type rawTweet struct {
+	IDStr             string `json:"id_str"`
+	FullText          string `json:"full_text"`
+	CreatedAt         string `json:"created_at"`
+	Retweeted         bool   `json:"retweeted"`
+	InReplyToStatusID string `json:"in_reply_to_status_id_str"`
+	Entities          struct {
+		Hashtags []struct {
+			Text string `json:"text"`
+		} `json:"hashtags"`
+		UserMentions []struct {
+			ScreenName string `json:"screen_name"`
+		} `json:"user_mentions"`
+		URLs []struct {
+			URL         string `json:"url"`
+			ExpandedURL string `json:"expanded_url"`
+		} `json:"urls"`
+		Media []struct {
+			URL         string `json:"url"`
+			ExpandedURL string `json:"expanded_url"`
+		} `json:"media"`
+	} `json:"entities"`
+}
+
+func readFile(f *zip.File, archive *Archive) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxFileSize {
+		return errors.New("file too large")
+	}
+
+	// strip the "window.YTD.tweets.part0 =" assignment
+	start := bytes.IndexByte(data, '[')
+	if start < 0 {
+		return errors.New("no tweet array found")
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data[start:], &items); err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		tweet, err := decodeTweet(item)
+		if err != nil {
+			archive.Errors = append(archive.Errors, &ItemError{File: f.Name, Index: i, ID: tweet.ID, Err: err})
+			continue
+		}
+		archive.Tweets = append(archive.Tweets, tweet)
+	}
+	return nil
+}
+
+// decodeTweet accepts both the current {"tweet": {...}} wrapping and the
+// bare objects of older archives. The returned tweet carries its ID even
+// when decoding fails, when the ID could be read.
+func decodeTweet(item json.RawMessage) (Tweet, error) {
+	var wrapped struct {
+		Tweet *rawTweet `json:"tweet"`
+	}
+	if err := json.Unmarshal(item, &wrapped); err != nil {
+		return Tweet{}, err
+	}
+	raw := wrapped.Tweet
+	if raw == nil {
+		raw = &rawTweet{}
+		if err := json.Unmarshal(item, raw); err != nil {
+			return Tweet{}, err
+		}
+	}
+
+	tweet := Tweet{
+		ID:                raw.IDStr,
+		Text:              html.UnescapeString(raw.FullText),
+		InReplyToStatusID: raw.InReplyToStatusID,
+	}
+	if tweet.ID == "" {
+		return tweet, errors.New("missing id_str")
+	}
+
+	createdAt, err := time.Parse(CreatedAtLayout, raw.CreatedAt)
+	if err != nil {
+		return tweet, fmt.Errorf("invalid created_at %q", raw.CreatedAt)
+	}
+	tweet.CreatedAt = createdAt.UTC()
+
+	// archives mark the account's own retweets by their "RT @" prefix;
+	// the retweeted flag is rarely set
+	tweet.Retweet = raw.Retweeted || (len(tweet.Text) > 4 && tweet.Text[:4] == "RT @")
+
+	for _, hashtag := range raw.Entities.Hashtags {
+		tweet.Hashtags = append(tweet.Hashtags, hashtag.Text)
+	}
+	for _, mention := range raw.Entities.UserMentions {
+		tweet.Mentions = append(tweet.Mentions, mention.ScreenName)
+	}
+	for _, u := range raw.Entities.URLs {
+		tweet.URLs = append(tweet.URLs, URL{Short: u.URL, Expanded: u.ExpandedURL})
+	}
+	for _, u := range raw.Entities.Media {
+		tweet.URLs = append(tweet.URLs, URL{Short: u.URL, Expanded: u.ExpandedURL})
+	}
+	return tweet, nil
+}
@@ -0,0 +1,118 @@
+package twitterarchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func buildArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+const tweetsJS = `window.YTD.tweets.part0 = [ {
+  "tweet" : {
+    "id_str" : "200",
+    "full_text" : "Hello @bob, see https://t.co/abc &amp; #golang",
+    "created_at" : "Wed Oct 10 20:19:24 +0000 2018",
+    "entities" : {
+      "hashtags" : [ { "text" : "golang", "indices" : [ "40", "47" ] } ],
+      "user_mentions" : [ { "screen_name" : "bob", "indices" : [ "6", "10" ] } ],
+      "urls" : [ { "url" : "https://t.co/abc", "expanded_url" : "https://go.dev" } ]
+    }
+  }
+}, {
+  "tweet" : {
+    "id_str" : "100",
+    "full_text" : "RT @alice: an older retweet",
+    "created_at" : "Tue Oct 09 08:00:00 +0000 2018",
+    "entities" : { }
+  }
+}, {
+  "tweet" : {
+    "id_str" : "300",
+    "full_text" : "broken date",
+    "created_at" : "yesterday"
+  }
+} ]`
+
+func TestRead(t *testing.T) {
+	r := buildArchive(t, map[string]string{
+		"data/tweets.js":         tweetsJS,
+		"data/tweets-part1.js":   `window.YTD.tweets.part1 = [ { "tweet" : { "id_str" : "400", "full_text" : "later", "created_at" : "Thu Oct 11 10:00:00 +0000 2018" } } ]`,
+		"data/tweets_media/a.js": `not a tweets file`,
+		"data/account.js":        `window.YTD.account.part0 = []`,
+	})
+
+	archive, err := Read(r, r.Size())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(archive.Tweets) != 3 {
+		t.Fatalf("Expected 3 tweets, got %d", len(archive.Tweets))
+	}
+	ids := []string{archive.Tweets[0].ID, archive.Tweets[1].ID, archive.Tweets[2].ID}
+	if ids[0] != "100" || ids[1] != "200" || ids[2] != "400" {
+		t.Errorf("Expected tweets oldest first, got %v", ids)
+	}
+
+	tweet := archive.Tweets[1]
+	if tweet.Text != "Hello @bob, see https://t.co/abc & #golang" {
+		t.Errorf("Expected unescaped text, got %q", tweet.Text)
+	}
+	if !tweet.CreatedAt.Equal(time.Date(2018, 10, 10, 20, 19, 24, 0, time.UTC)) {
+		t.Errorf("Unexpected created_at %v", tweet.CreatedAt)
+	}
+	if len(tweet.Mentions) != 1 || tweet.Mentions[0] != "bob" || len(tweet.Hashtags) != 1 || tweet.Hashtags[0] != "golang" {
+		t.Errorf("Unexpected entities: %+v", tweet)
+	}
+	if len(tweet.URLs) != 1 || tweet.URLs[0].Expanded != "https://go.dev" {
+		t.Errorf("Unexpected URLs: %+v", tweet.URLs)
+	}
+	if !archive.Tweets[0].Retweet || tweet.Retweet {
+		t.Error("Expected only the RT to be marked as a retweet")
+	}
+
+	if len(archive.Errors) != 1 || archive.Errors[0].ID != "300" {
+		t.Errorf("Expected tweet 300 to be reported, got %v", archive.Errors)
+	}
+}
+
+func TestRead_OlderArchive(t *testing.T) {
+	r := buildArchive(t, map[string]string{
+		"data/tweet.js": `window.YTD.tweet.part0 = [ { "id_str" : "1", "full_text" : "bare", "created_at" : "Wed Oct 10 20:19:24 +0000 2018" } ]`,
+	})
+
+	archive, err := Read(r, r.Size())
+	if err != nil || len(archive.Tweets) != 1 || archive.Tweets[0].Text != "bare" {
+		t.Errorf("Expected the bare tweet, got %+v (%v)", archive, err)
+	}
+}
+
+func TestRead_NoTweets(t *testing.T) {
+	r := buildArchive(t, map[string]string{"data/account.js": `[]`})
+	if _, err := Read(r, r.Size()); !errors.Is(err, ErrNoTweets) {
+		t.Errorf("Expected ErrNoTweets, got %v", err)
+	}
+
+	notZip := bytes.NewReader([]byte("not a zip"))
+	if _, err := Read(notZip, notZip.Size()); err == nil {
+		t.Error("Expected an error for a non-ZIP upload")
+	}
+}
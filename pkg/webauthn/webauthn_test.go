@@ -0,0 +1,104 @@
+package webauthn_test
+
+import (
+	"bytes"
+	"errors"
+	"go-twitter/pkg/webauthn"
+	"go-twitter/pkg/webauthn/webauthntest"
+	"testing"
+)
+
+var testConfig = webauthn.Config{RPID: "example.com", RPName: "Example", Origin: "https://example.com"}
+
+func register(t *testing.T, authenticator *webauthntest.Authenticator) *webauthn.Credential {
+	t.Helper()
+	challenge := []byte("registration-challenge-0123456789")
+	reg := authenticator.Register(challenge)
+
+	credential, err := webauthn.VerifyRegistration(testConfig, challenge, reg.ClientDataJSON, reg.AttestationObject)
+	if err != nil {
+		t.Fatalf("Expected registration to verify, got %v", err)
+	}
+	return credential
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(testConfig.RPID, testConfig.Origin)
+	credential := register(t, authenticator)
+
+	if !bytes.Equal(credential.ID, authenticator.CredentialID) || !credential.UserVerified {
+		t.Errorf("Unexpected credential: %+v", credential)
+	}
+
+	challenge := []byte("assertion-challenge-0123456789ab")
+	got, err := webauthn.Challenge(authenticator.Assert(challenge).ClientDataJSON)
+	if err != nil || !bytes.Equal(got, challenge) {
+		t.Errorf("Expected challenge %q, got %q (%v)", challenge, got, err)
+	}
+
+	resp := authenticator.Assert(challenge)
+	assertion, err := webauthn.VerifyAssertion(testConfig, challenge, credential.PublicKey, 1, resp.ClientDataJSON, resp.AuthenticatorData, resp.Signature)
+	if err != nil {
+		t.Fatalf("Expected assertion to verify, got %v", err)
+	}
+	if assertion.SignCount != 2 {
+		t.Errorf("Expected sign count 2, got %d", assertion.SignCount)
+	}
+
+	// a replayed response does not advance the counter
+	_, err = webauthn.VerifyAssertion(testConfig, challenge, credential.PublicKey, assertion.SignCount, resp.ClientDataJSON, resp.AuthenticatorData, resp.Signature)
+	if !errors.Is(err, webauthn.ErrSignCount) {
+		t.Errorf("Expected ErrSignCount, got %v", err)
+	}
+}
+
+func TestVerifyAssertion_Rejects(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(testConfig.RPID, testConfig.Origin)
+	credential := register(t, authenticator)
+	challenge := []byte("assertion-challenge-0123456789ab")
+
+	tests := []struct {
+		name   string
+		modify func(a *webauthntest.Authenticator)
+		check  func(resp *webauthntest.AssertionResponse) []byte
+	}{
+		{"wrong origin", func(a *webauthntest.Authenticator) { a.Origin = "https://evil.example" }, nil},
+		{"wrong relying party", func(a *webauthntest.Authenticator) { a.RPID = "evil.example" }, nil},
+		{"wrong challenge", nil, func(*webauthntest.AssertionResponse) []byte { return []byte("other") }},
+		{"other key", func(a *webauthntest.Authenticator) {
+			other := webauthntest.NewAuthenticator(a.RPID, a.Origin)
+			*a = *other
+			a.SignCount = 10
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := *authenticator
+			if tt.modify != nil {
+				tt.modify(&a)
+			}
+			resp := a.Assert(challenge)
+			expected := challenge
+			if tt.check != nil {
+				expected = tt.check(resp)
+			}
+
+			_, err := webauthn.VerifyAssertion(testConfig, expected, credential.PublicKey, 0, resp.ClientDataJSON, resp.AuthenticatorData, resp.Signature)
+			if !errors.Is(err, webauthn.ErrVerification) {
+				t.Errorf("Expected ErrVerification, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyRegistration_RejectsAssertionResponse(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(testConfig.RPID, testConfig.Origin)
+	challenge := []byte("registration-challenge-0123456789")
+	resp := authenticator.Assert(challenge)
+
+	_, err := webauthn.VerifyRegistration(testConfig, challenge, resp.ClientDataJSON, resp.AuthenticatorData)
+	if !errors.Is(err, webauthn.ErrVerification) {
+		t.Errorf("Expected ErrVerification for a get response, got %v", err)
+	}
+}
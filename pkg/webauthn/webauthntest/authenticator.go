@@ -0,0 +1,133 @@
+// Package webauthntest provides a software authenticator for tests. It
+// produces the same registration and assertion responses a browser would
+// return from navigator.credentials, signed with an in-memory P-256 key.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"go-twitter/pkg/webauthn"
+)
+
+// Authenticator holds one ES256 credential. Fields may be changed between
+// ceremonies to simulate other origins, relying parties or cloned devices.
+type Authenticator struct {
+	RPID         string
+	Origin       string
+	UserVerified bool
+	SignCount    uint32
+
+	CredentialID []byte
+	key          *ecdsa.PrivateKey
+}
+
+// Registration mirrors the fields of an AuthenticatorAttestationResponse.
+type Registration struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AttestationObject []byte
+}
+
+// AssertionResponse mirrors the fields of an AuthenticatorAssertionResponse.
+type AssertionResponse struct {
+	CredentialID      []byte
+	ClientDataJSON    []byte
+	AuthenticatorData []byte
+	Signature         []byte
+}
+
+func NewAuthenticator(rpID, origin string) *Authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return &Authenticator{
+		RPID:         rpID,
+		Origin:       origin,
+		UserVerified: true,
+		CredentialID: id,
+		key:          key,
+	}
+}
+
+// Register answers a credential creation request with "none" attestation.
+func (a *Authenticator) Register(challenge []byte) *Registration {
+	publicKey, err := a.key.PublicKey.Bytes()
+	if err != nil {
+		panic(err)
+	}
+	coseKey := encodeMap(
+		1, 2, // kty: EC2
+		3, webauthn.AlgES256,
+		-1, 1, // crv: P-256
+		-2, publicKey[1:33],
+		-3, publicKey[33:65],
+	)
+
+	attested := make([]byte, 16, 18+len(a.CredentialID)+len(coseKey)) // zero AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.CredentialID)))
+	attested = append(attested, a.CredentialID...)
+	attested = append(attested, coseKey...)
+
+	authData := a.authenticatorData(webauthn.FlagAttestedCredentialData, attested)
+	attestationObject := encodeMap(
+		"fmt", "none",
+		"attStmt", encodedMap{},
+		"authData", authData,
+	)
+
+	return &Registration{
+		CredentialID:      a.CredentialID,
+		ClientDataJSON:    a.clientData("webauthn.create", challenge),
+		AttestationObject: attestationObject,
+	}
+}
+
+// Assert answers a credential request, advancing the signature counter.
+func (a *Authenticator) Assert(challenge []byte) *AssertionResponse {
+	a.SignCount++
+	authData := a.authenticatorData(0, nil)
+	clientDataJSON := a.clientData("webauthn.get", challenge)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return &AssertionResponse{
+		CredentialID:      a.CredentialID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         signature,
+	}
+}
+
+func (a *Authenticator) authenticatorData(flags byte, extra []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	flags |= webauthn.FlagUserPresent
+	if a.UserVerified {
+		flags |= webauthn.FlagUserVerified
+	}
+
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.SignCount)
+	return append(data, extra...)
+}
+
+func (a *Authenticator) clientData(typ string, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": webauthn.EncodeToString(challenge),
+		"origin":    a.Origin,
+	})
+	return data
+}
@@ -0,0 +1,58 @@
+package webauthntest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// encodedMap is an already encoded CBOR map, used to nest maps while keeping
+// key order deterministic.
+type encodedMap []byte
+
+// encodeMap encodes alternating keys and values as a CBOR map. Supported
+// values are ints, strings, byte slices and nested encoded maps.
+func encodeMap(pairs ...any) encodedMap {
+	if len(pairs)%2 != 0 {
+		panic("webauthntest: odd number of map items")
+	}
+	out := cborHeader(5, uint64(len(pairs)/2))
+	for _, item := range pairs {
+		out = append(out, encodeItem(item)...)
+	}
+	return out
+}
+
+func encodeItem(item any) []byte {
+	switch v := item.(type) {
+	case int:
+		if v < 0 {
+			return cborHeader(1, uint64(-1-v))
+		}
+		return cborHeader(0, uint64(v))
+	case string:
+		return append(cborHeader(3, uint64(len(v))), v...)
+	case []byte:
+		return append(cborHeader(2, uint64(len(v))), v...)
+	case encodedMap:
+		if len(v) == 0 {
+			return cborHeader(5, 0)
+		}
+		return v
+	}
+	panic(fmt.Sprintf("webauthntest: cannot encode %T", item))
+}
+
+func cborHeader(major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return []byte{major | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major | 25}, uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major | 26}, uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64([]byte{major | 27}, arg)
+}